@@ -0,0 +1,300 @@
+// Package artwork provides an on-disk cache of resized album artwork.
+package artwork
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// thumbnailJPEGQuality is the JPEG quality used for opaque thumbnails.
+const thumbnailJPEGQuality = 85
+
+// thumbnailTempPrefix prefixes thumbnails that are still being written.
+const thumbnailTempPrefix = ".thumb-"
+
+// thumbnailCacheLimit is the total size of the cached thumbnails above
+// which the least recently used ones are removed.
+const thumbnailCacheLimit = 64 << 20
+
+// thumbnailNamePattern matches cache file names (<sha256 of source>-<size>).
+var thumbnailNamePattern = regexp.MustCompile(`^[0-9a-f]{64}-[0-9]+$`)
+
+// ThumbnailCache implements ports.ThumbnailCache with one file per thumbnail.
+// Files are written to a temporary name and renamed, so concurrent requests
+// for the same thumbnail are safe without locking.
+// The cache keeps up to limit bytes of thumbnails, removing the least recently
+// used ones beyond that. Use times are kept as file modification times, so they
+// carry over to the next run.
+//
+// Thread-safe: The index of cached files is protected by a mutex.
+type ThumbnailCache struct {
+	dir   string
+	limit int64
+
+	mu      sync.Mutex
+	indexed bool                     // Whether the files already in dir were indexed
+	entries *list.List               // Cached files, most recently used first
+	index   map[string]*list.Element // Elements of entries by file name
+	size    int64                    // Total size of the cached files
+}
+
+// thumbnailEntry is a file in the thumbnail cache.
+type thumbnailEntry struct {
+	name string
+	size int64
+}
+
+// NewThumbnailCache creates a thumbnail cache stored in dir, holding up to
+// thumbnailCacheLimit bytes.
+// The directory is created when the first thumbnail is written.
+func NewThumbnailCache(dir string) *ThumbnailCache {
+	return &ThumbnailCache{
+		dir:     dir,
+		limit:   thumbnailCacheLimit,
+		entries: list.New(),
+		index:   make(map[string]*list.Element),
+	}
+}
+
+// Thumbnail returns the image scaled to fit within size x size pixels.
+// Caching is best effort: if the cache cannot be written, the thumbnail is still returned.
+func (c *ThumbnailCache) Thumbnail(imageData []byte, size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, domain.NewValidationError("size", size, "must be positive")
+	}
+
+	sum := sha256.Sum256(imageData)
+	name := fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), size)
+	cachePath := filepath.Join(c.dir, name)
+	if cached, err := os.ReadFile(cachePath); err == nil {
+		c.touch(name, int64(len(cached)))
+		return cached, nil
+	}
+
+	// Small images are used as they are; decoding the header is enough to tell
+	config, _, err := image.DecodeConfig(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width <= size && config.Height <= size {
+		return imageData, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	thumbnail, err := encodeThumbnail(scaleToFit(src, size))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.store(cachePath, thumbnail) == nil {
+		c.added(name, int64(len(thumbnail)))
+	}
+	return thumbnail, nil
+}
+
+// scaleToFit scales an image down so that its longer side is size pixels.
+func scaleToFit(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width >= height {
+		height = max(1, height*size/width)
+		width = size
+	} else {
+		width = max(1, width*size/height)
+		height = size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+// encodeThumbnail encodes opaque images as JPEG and images with transparency as PNG.
+func encodeThumbnail(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// store writes a thumbnail to the cache atomically.
+func (c *ThumbnailCache) store(cachePath string, data []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(c.dir, thumbnailTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name()) // No-op once renamed
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), cachePath)
+}
+
+// touch marks a cached thumbnail as just used.
+func (c *ThumbnailCache) touch(name string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.indexInternal()
+	c.useInternal(name, size)
+
+	// Best effort: the use time only orders eviction after a restart
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.dir, name), now, now)
+}
+
+// added records a thumbnail that was just written and removes the least
+// recently used thumbnails if the cache is over its limit.
+func (c *ThumbnailCache) added(name string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.indexInternal()
+	c.useInternal(name, size)
+
+	for c.size > c.limit && c.entries.Len() > 1 {
+		oldest := c.entries.Back()
+		entry := oldest.Value.(thumbnailEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.name)); err != nil && !os.IsNotExist(err) {
+			return // Try again after the next thumbnail
+		}
+		c.forgetInternal(oldest)
+	}
+}
+
+// useInternal moves a thumbnail to the front of the index, adding it if needed.
+// Must be called with mutex lock held.
+func (c *ThumbnailCache) useInternal(name string, size int64) {
+	if element, ok := c.index[name]; ok {
+		entry := element.Value.(thumbnailEntry)
+		c.size += size - entry.size
+		element.Value = thumbnailEntry{name: name, size: size}
+		c.entries.MoveToFront(element)
+		return
+	}
+	c.index[name] = c.entries.PushFront(thumbnailEntry{name: name, size: size})
+	c.size += size
+}
+
+// forgetInternal removes a thumbnail from the index.
+// Must be called with mutex lock held.
+func (c *ThumbnailCache) forgetInternal(element *list.Element) {
+	entry := c.entries.Remove(element).(thumbnailEntry)
+	delete(c.index, entry.name)
+	c.size -= entry.size
+}
+
+// indexInternal indexes the thumbnails left in the directory by earlier runs,
+// most recently used (modified) first. Only done once.
+// Must be called with mutex lock held.
+func (c *ThumbnailCache) indexInternal() {
+	if c.indexed {
+		return
+	}
+	c.indexed = true
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type cachedFile struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]cachedFile, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !thumbnailNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{name: entry.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b cachedFile) int { return a.modTime.Compare(b.modTime) })
+
+	for _, file := range files {
+		if _, ok := c.index[file.name]; !ok {
+			c.index[file.name] = c.entries.PushFront(thumbnailEntry{name: file.name, size: file.size})
+			c.size += file.size
+		}
+	}
+}
+
+// Clear removes all cached thumbnails.
+// Only files created by the cache are removed, so a misconfigured directory is left intact.
+func (c *ThumbnailCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Files that cannot be removed are indexed again on next use
+	c.entries.Init()
+	clear(c.index)
+	c.size = 0
+	c.indexed = false
+
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() ||
+			(!thumbnailNamePattern.MatchString(name) && !strings.HasPrefix(name, thumbnailTempPrefix)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify that ThumbnailCache implements the ThumbnailCache interface
+var _ ports.ThumbnailCache = (*ThumbnailCache)(nil)
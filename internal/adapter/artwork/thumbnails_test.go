@@ -0,0 +1,169 @@
+package artwork
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testImage encodes a solid image of the given size, as PNG with transparency or as JPEG.
+func testImage(t *testing.T, width, height int, transparent bool) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	fill := color.NRGBA{R: 200, G: 40, B: 40, A: 255}
+	if transparent {
+		fill.A = 128
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, fill)
+		}
+	}
+
+	var buf bytes.Buffer
+	if transparent {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+// cachedFiles returns the names of the files in the cache directory.
+func cachedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestThumbnailCache_ResizesAndCaches(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "thumbnails")
+	cache := NewThumbnailCache(dir)
+	source := testImage(t, 1000, 500, false)
+
+	thumbnail, err := cache.Thumbnail(source, 200)
+	require.NoError(t, err)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 200, config.Width)
+	assert.Equal(t, 100, config.Height)
+	require.Len(t, cachedFiles(t, dir), 1)
+
+	// The second request is served from disk
+	cached, err := cache.Thumbnail(source, 200)
+	require.NoError(t, err)
+	assert.Equal(t, thumbnail, cached)
+
+	// Another size is cached separately
+	_, err = cache.Thumbnail(source, 100)
+	require.NoError(t, err)
+	assert.Len(t, cachedFiles(t, dir), 2)
+}
+
+func TestThumbnailCache_TransparentImageStaysPNG(t *testing.T) {
+	cache := NewThumbnailCache(t.TempDir())
+
+	thumbnail, err := cache.Thumbnail(testImage(t, 300, 600, true), 100)
+	require.NoError(t, err)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 50, config.Width)
+	assert.Equal(t, 100, config.Height)
+}
+
+func TestThumbnailCache_SmallImageUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewThumbnailCache(dir)
+	source := testImage(t, 64, 64, false)
+
+	thumbnail, err := cache.Thumbnail(source, 128)
+	require.NoError(t, err)
+	assert.Equal(t, source, thumbnail)
+	assert.Empty(t, cachedFiles(t, dir))
+}
+
+func TestThumbnailCache_InvalidInput(t *testing.T) {
+	cache := NewThumbnailCache(t.TempDir())
+
+	_, err := cache.Thumbnail([]byte("not an image"), 100)
+	assert.Error(t, err)
+
+	_, err = cache.Thumbnail(testImage(t, 10, 10, false), 0)
+	assert.Error(t, err)
+}
+
+func TestThumbnailCache_Clear(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewThumbnailCache(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.txt"), []byte("unrelated"), 0600))
+
+	_, err := cache.Thumbnail(testImage(t, 400, 400, false), 100)
+	require.NoError(t, err)
+	require.Len(t, cachedFiles(t, dir), 2)
+
+	require.NoError(t, cache.Clear())
+	assert.Equal(t, []string{"keep.txt"}, cachedFiles(t, dir))
+
+	// Clearing a cache that was never written is not an error
+	assert.NoError(t, NewThumbnailCache(filepath.Join(dir, "missing")).Clear())
+}
+
+func TestThumbnailCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	images := make([][]byte, 3)
+	names := make([]string, 3)
+	for i := range images {
+		images[i] = testImage(t, 400+i, 400, false)
+		sum := sha256.Sum256(images[i])
+		names[i] = fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), 100)
+	}
+
+	cache := NewThumbnailCache(dir)
+	first, err := cache.Thumbnail(images[0], 100)
+	require.NoError(t, err)
+	cache.limit = int64(len(first))*2 + int64(len(first))/2 // Room for two thumbnails
+
+	_, err = cache.Thumbnail(images[1], 100)
+	require.NoError(t, err)
+	_, err = cache.Thumbnail(images[0], 100) // Used again, so the second is now the oldest
+	require.NoError(t, err)
+	_, err = cache.Thumbnail(images[2], 100)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{names[0], names[2]}, cachedFiles(t, dir))
+
+	// A new cache orders the files of earlier runs by their use time
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, names[2]), past, past))
+	restarted := NewThumbnailCache(dir)
+	restarted.limit = cache.limit
+	_, err = restarted.Thumbnail(images[1], 100)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{names[0], names[1]}, cachedFiles(t, dir))
+}
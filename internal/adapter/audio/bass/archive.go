@@ -0,0 +1,59 @@
+// Package bass provides reading of tracks stored inside ZIP archives.
+package bass
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// maxArchiveEntrySize is the largest archive entry that is loaded into memory.
+// Tracker modules are rarely more than a few megabytes.
+const maxArchiveEntrySize = 64 << 20
+
+// readArchiveEntry reads the entry a virtual path points to (e.g. "pack.zip!/song.xm").
+// Returns domain.ErrFileNotFound if the archive or the entry does not exist.
+func readArchiveEntry(virtualPath string) ([]byte, error) {
+	archivePath, entryName, ok := domain.SplitArchivePath(virtualPath)
+	if !ok {
+		return nil, domain.ErrInvalidFilePath
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, domain.NewAudioEngineError("read_archive", virtualPath, 0, "failed to open archive", err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.Name != entryName {
+			continue
+		}
+		if file.UncompressedSize64 > maxArchiveEntrySize {
+			return nil, domain.NewAudioEngineError("read_archive", virtualPath, 0, "archive entry is too large", nil)
+		}
+
+		entry, err := file.Open()
+		if err != nil {
+			return nil, domain.NewAudioEngineError("read_archive", virtualPath, 0, "failed to open archive entry", err)
+		}
+		defer entry.Close()
+
+		// The declared size is not trusted; never read more than the limit
+		data, err := io.ReadAll(io.LimitReader(entry, maxArchiveEntrySize+1))
+		if err != nil {
+			return nil, domain.NewAudioEngineError("read_archive", virtualPath, 0, "failed to read archive entry", err)
+		}
+		if len(data) > maxArchiveEntrySize {
+			return nil, domain.NewAudioEngineError("read_archive", virtualPath, 0, "archive entry is too large", nil)
+		}
+		return data, nil
+	}
+
+	return nil, domain.ErrFileNotFound
+}
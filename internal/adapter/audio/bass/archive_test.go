@@ -0,0 +1,82 @@
+package bass
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// writeTestArchive creates a ZIP archive containing the given entries.
+func writeTestArchive(t *testing.T, entries map[string][]byte) string {
+	archivePath := filepath.Join(t.TempDir(), "pack.zip")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	writer := zip.NewWriter(f)
+	for name, data := range entries {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return archivePath
+}
+
+func TestReadArchiveEntry(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"song.xm":        []byte("Extended Module: song"),
+		"sub/tune.mod":   []byte("mod data"),
+		"unrelated.text": []byte("text"),
+	})
+
+	data, err := readArchiveEntry(domain.ArchiveEntryPath(archivePath, "song.xm"))
+	require.NoError(t, err)
+	assert.Equal(t, "Extended Module: song", string(data))
+
+	data, err = readArchiveEntry(domain.ArchiveEntryPath(archivePath, "sub/tune.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "mod data", string(data))
+}
+
+func TestReadArchiveEntry_NotFound(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{"song.xm": []byte("x")})
+
+	_, err := readArchiveEntry(domain.ArchiveEntryPath(archivePath, "missing.xm"))
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+
+	_, err = readArchiveEntry(domain.ArchiveEntryPath(filepath.Join(t.TempDir(), "gone.zip"), "song.xm"))
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+}
+
+func TestReadArchiveEntry_NotAnArchivePath(t *testing.T) {
+	_, err := readArchiveEntry("/music/song.xm")
+	assert.ErrorIs(t, err, domain.ErrInvalidFilePath)
+}
+
+func TestReadArchiveEntry_CorruptArchive(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "broken.zip")
+	require.NoError(t, os.WriteFile(archivePath, []byte("not a zip"), 0600))
+
+	_, err := readArchiveEntry(domain.ArchiveEntryPath(archivePath, "song.xm"))
+	var engineErr *domain.AudioEngineError
+	assert.ErrorAs(t, err, &engineErr)
+}
+
+func TestExtractMetadata_ArchiveEntryNotModule(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{"music/notes.wav": []byte("RIFF")})
+
+	track, err := extractMetadata(domain.ArchiveEntryPath(archivePath, "music/notes.wav"), domain.DefaultTagCharset)
+	require.NoError(t, err)
+	assert.Equal(t, "notes.wav", track.Title)
+	assert.Equal(t, ".wav", track.FileFormat)
+	assert.False(t, track.IsMOD)
+	assert.Equal(t, int64(4), track.FileSize)
+}
@@ -42,6 +42,37 @@ func bassMusicLoad(filePath string, flags int) (int64, error) {
 	return int64(handle), nil
 }
 
+// memoryBuffer is a copy of file data in C memory.
+// BASS reads streams created from memory for as long as they exist, and Go
+// memory must not be retained by C, so the data is copied and freed explicitly.
+type memoryBuffer struct {
+	ptr  unsafe.Pointer
+	size int
+}
+
+// newMemoryBuffer copies data into C memory.
+func newMemoryBuffer(data []byte) *memoryBuffer {
+	return &memoryBuffer{ptr: C.CBytes(data), size: len(data)}
+}
+
+// free releases the C memory. It is safe to call on nil or more than once.
+func (b *memoryBuffer) free() {
+	if b == nil || b.ptr == nil {
+		return
+	}
+	C.free(b.ptr)
+	b.ptr = nil
+}
+
+// bassMusicLoadMemory loads a MOD music file from memory.
+func bassMusicLoadMemory(buffer *memoryBuffer, flags int) (int64, error) {
+	handle := C.BASS_MusicLoad(1, buffer.ptr, 0, C.DWORD(buffer.size), C.DWORD(flags), 1)
+	if handle == 0 {
+		return 0, createBassError("load_music", "", C.BASS_ErrorGetCode())
+	}
+	return int64(handle), nil
+}
+
 // bassMusicFree frees a MOD music handle.
 func bassMusicFree(handle int64) bool {
 	return C.BASS_MusicFree(C.DWORD(handle)) != 0
@@ -59,6 +90,16 @@ func bassStreamCreateFile(filePath string, flags int) (int64, error) {
 	return int64(handle), nil
 }
 
+// bassStreamCreateMemory loads a stream from memory.
+// The buffer must stay allocated until the stream is freed.
+func bassStreamCreateMemory(buffer *memoryBuffer, flags int) (int64, error) {
+	handle := C.BASS_StreamCreateFile(1, buffer.ptr, 0, C.QWORD(buffer.size), C.DWORD(flags))
+	if handle == 0 {
+		return 0, createBassError("load_stream", "", C.BASS_ErrorGetCode())
+	}
+	return int64(handle), nil
+}
+
 // bassStreamFree frees a stream handle.
 func bassStreamFree(handle int64) bool {
 	return C.BASS_StreamFree(C.DWORD(handle)) != 0
@@ -164,6 +205,28 @@ func bassChannelSlideAttribute(handle int64, attrib ChannelAttributes, value flo
 	return C.BASS_ChannelSlideAttribute(C.DWORD(handle), C.DWORD(attrib), C.float(value), C.DWORD(timeMs)) != 0
 }
 
+// channelInfo holds the fields of BASS_CHANNELINFO used by the engine.
+type channelInfo struct {
+	freq    int         // Default playback rate
+	chans   int         // Number of channels
+	ctype   ChannelType // Type of channel
+	origres int         // Original resolution (bits per sample, with origResFloat flag)
+}
+
+// bassChannelGetInfo gets information about a channel.
+func bassChannelGetInfo(handle int64) (channelInfo, error) {
+	var info C.BASS_CHANNELINFO
+	if C.BASS_ChannelGetInfo(C.DWORD(handle), &info) == 0 {
+		return channelInfo{}, createBassError("get_info", "", C.BASS_ErrorGetCode())
+	}
+	return channelInfo{
+		freq:    int(info.freq),
+		chans:   int(info.chans),
+		ctype:   ChannelType(info.ctype),
+		origres: int(info.origres),
+	}, nil
+}
+
 // bassChannelGetTags gets channel tags (for MOD files).
 func bassChannelGetTags(handle int64, tag Tag) string {
 	tags := C.BASS_ChannelGetTags(C.DWORD(handle), C.DWORD(tag))
@@ -191,5 +254,5 @@ func createBassError(op, path string, code C.int) error {
 	errorCode := ErrorCode(code)
 	message := errorCodeToMessage(errorCode)
 
-	return domain.NewAudioEngineError(op, path, int(code), message, nil)
+	return domain.NewAudioEngineError(op, path, int(code), message, errorCodeToDomainError(errorCode))
 }
@@ -0,0 +1,169 @@
+// Package bass provides decoding of tags stored in legacy charsets.
+package bass
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// charsetEncodings maps the supported legacy charsets to their decoders.
+var charsetEncodings = map[domain.Charset]encoding.Encoding{
+	domain.CharsetWindows1252: charmap.Windows1252,
+	domain.CharsetWindows1250: charmap.Windows1250,
+	domain.CharsetWindows1251: charmap.Windows1251,
+	domain.CharsetKOI8R:       charmap.KOI8R,
+	domain.CharsetShiftJIS:    japanese.ShiftJIS,
+	domain.CharsetGBK:         simplifiedchinese.GBK,
+	domain.CharsetBig5:        traditionalchinese.Big5,
+	domain.CharsetEUCKR:       korean.EUCKR,
+}
+
+// decodeLegacyText converts tag bytes of unknown encoding to UTF-8.
+// UTF-8 is kept as is; Shift-JIS and Cyrillic text are detected; anything
+// else is decoded with the fallback charset (Windows-1252 if it does not fit).
+func decodeLegacyText(text string, fallback domain.Charset) string {
+	if isASCII(text) || utf8.ValidString(text) {
+		return text
+	}
+
+	b := []byte(text)
+	if decoded, ok := decodeCharset(b, detectCharset(b, fallback)); ok {
+		return decoded
+	}
+	decoded, _ := decodeCharset(b, domain.CharsetWindows1252)
+	return decoded
+}
+
+// decodeLatin1Text re-decodes text that the tag library read as Latin-1
+// (ID3v2 frames with encoding 0). Old taggers wrote text in the system
+// codepage or UTF-8 into these frames, which shows up as mojibake.
+// Text with characters outside Latin-1 was not decoded as Latin-1 and is kept.
+// Windows-1252 equals Latin-1 for printable characters, so the default fallback
+// leaves correct Latin-1 text unchanged.
+func decodeLatin1Text(text string, fallback domain.Charset) string {
+	b := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xFF {
+			return text
+		}
+		b = append(b, byte(r))
+	}
+	if isASCII(text) {
+		return text
+	}
+
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	if decoded, ok := decodeCharset(b, detectCharset(b, fallback)); ok {
+		return decoded
+	}
+	return text
+}
+
+// detectCharset guesses the charset of non-UTF-8 text. Shift-JIS and
+// Cyrillic can be recognized from the bytes; otherwise the fallback is returned.
+// A Cyrillic fallback is kept for Cyrillic text, as both charsets look alike.
+func detectCharset(b []byte, fallback domain.Charset) domain.Charset {
+	if !fallback.IsValid() {
+		fallback = domain.DefaultTagCharset
+	}
+
+	switch {
+	case looksLikeShiftJIS(b):
+		return domain.CharsetShiftJIS
+	case looksLikeCyrillic(b):
+		if fallback == domain.CharsetKOI8R {
+			return fallback
+		}
+		return domain.CharsetWindows1251
+	}
+	return fallback
+}
+
+// looksLikeShiftJIS reports whether all non-ASCII bytes form valid Shift-JIS
+// characters, including at least two double-byte Japanese characters.
+// A single pair is not enough, as accented Latin-1 letters followed by ASCII can form one.
+func looksLikeShiftJIS(b []byte) bool {
+	pairs := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c < 0x80, c >= 0xA1 && c <= 0xDF: // ASCII or half-width katakana
+		case c >= 0x81 && c <= 0x9F, c >= 0xE0 && c <= 0xEF: // Lead byte
+			if i+1 >= len(b) {
+				return false
+			}
+			trail := b[i+1]
+			if trail < 0x40 || trail == 0x7F || trail > 0xFC {
+				return false
+			}
+			r, ok := decodeCharset(b[i:i+2], domain.CharsetShiftJIS)
+			if !ok || !isJapanese([]rune(r)[0]) {
+				return false
+			}
+			pairs++
+			i++
+		default:
+			return false
+		}
+	}
+	return pairs >= 2
+}
+
+// isJapanese reports whether a rune is kana, a CJK ideograph or a full-width form.
+func isJapanese(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// looksLikeCyrillic reports whether most letters are in the upper half of
+// the byte range, where single-byte Cyrillic charsets keep their letters.
+// Accented Latin letters are too sparse in Western text to reach this.
+func looksLikeCyrillic(b []byte) bool {
+	letters, high := 0, 0
+	for _, c := range b {
+		switch {
+		case c >= 0xC0 || c == 0xA8 || c == 0xB8: // А-я, Ё and ё in Windows-1251
+			letters++
+			high++
+		case c < 0x80 && unicode.IsLetter(rune(c)):
+			letters++
+		}
+	}
+	return high >= 2 && high*10 >= letters*6
+}
+
+// decodeCharset decodes bytes in the given charset.
+// Returns false if the charset is unknown or the bytes are not valid in it.
+func decodeCharset(b []byte, charset domain.Charset) (string, bool) {
+	enc, ok := charsetEncodings[charset]
+	if !ok {
+		return "", false
+	}
+	decoded, err := enc.NewDecoder().Bytes(b)
+	if err != nil || strings.ContainsRune(string(decoded), utf8.RuneError) {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// isASCII reports whether the text only contains ASCII characters.
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package bass
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// latin1 returns the text the tag library produces when it reads bytes as Latin-1.
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+var (
+	// "Привет мир" in Windows-1251
+	cyrillic1251 = []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2, ' ', 0xEC, 0xE8, 0xF0}
+	// "こんにちは" in Shift-JIS
+	japaneseSJIS = []byte{0x82, 0xB1, 0x82, 0xF1, 0x82, 0xC9, 0x82, 0xBF, 0x82, 0xCD}
+	// "Café Olé" in Windows-1252
+	western1252 = []byte{'C', 'a', 'f', 0xE9, ' ', 'O', 'l', 0xE9}
+	// "Žluťoučký kůň" in Windows-1250
+	czech1250 = []byte{0x8E, 'l', 'u', 0x9D, 'o', 'u', 0xE8, 'k', 0xFD, ' ', 'k', 0xF9, 0xF2}
+)
+
+func TestDecodeLegacyText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		fallback domain.Charset
+		want     string
+	}{
+		{"ASCII", "Purple Motion", domain.DefaultTagCharset, "Purple Motion"},
+		{"UTF-8 kept", "Привет", domain.DefaultTagCharset, "Привет"},
+		{"Cyrillic detected", string(cyrillic1251), domain.DefaultTagCharset, "Привет мир"},
+		{"Shift-JIS detected", string(japaneseSJIS), domain.DefaultTagCharset, "こんにちは"},
+		{"Western fallback", string(western1252), domain.DefaultTagCharset, "Café Olé"},
+		{"Central European fallback", string(czech1250), domain.CharsetWindows1250, "Žluťoučký kůň"},
+		{"invalid fallback bytes", string(western1252), domain.CharsetShiftJIS, "Café Olé"},
+		{"unknown fallback", string(western1252), "ebcdic", "Café Olé"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeLegacyText(tt.text, tt.fallback))
+		})
+	}
+}
+
+func TestDecodeLatin1Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		fallback domain.Charset
+		want     string
+	}{
+		{"Latin-1 kept", "Motörhead", domain.DefaultTagCharset, "Motörhead"},
+		{"Unicode kept", "Привет", domain.DefaultTagCharset, "Привет"},
+		{"Cyrillic mojibake", latin1(cyrillic1251), domain.DefaultTagCharset, "Привет мир"},
+		{"Shift-JIS mojibake", latin1(japaneseSJIS), domain.DefaultTagCharset, "こんにちは"},
+		{"UTF-8 in Latin-1 frame", latin1([]byte("Sigur Rós")), domain.DefaultTagCharset, "Sigur Rós"},
+		{"fallback", latin1(czech1250), domain.CharsetWindows1250, "Žluťoučký kůň"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeLatin1Text(tt.text, tt.fallback))
+		})
+	}
+}
+
+func TestDetectCharset_KOI8R(t *testing.T) {
+	// Cyrillic text keeps a Cyrillic fallback
+	assert.Equal(t, domain.CharsetKOI8R, detectCharset(cyrillic1251, domain.CharsetKOI8R))
+	assert.Equal(t, domain.CharsetWindows1251, detectCharset(cyrillic1251, domain.CharsetWindows1250))
+}
@@ -7,6 +7,8 @@ package bass
 */
 import "C"
 
+import "github.com/tejashwikalptaru/gotune/internal/domain"
+
 // InitFlags represents BASS library initialization flags.
 type InitFlags int
 
@@ -30,10 +32,36 @@ const (
 	musicPreScan     = C.BASS_MUSIC_PRESCAN
 	streamAutoFree   = C.BASS_STREAM_AUTOFREE
 	streamDecodeOnly = C.BASS_STREAM_DECODE
+	streamPreScan    = C.BASS_STREAM_PRESCAN
 	posReset         = C.BASS_MUSIC_POSRESET
 	posResetEx       = C.BASS_MUSIC_POSRESETEX
 )
 
+// ChannelType represents channel types reported by BASS_ChannelGetInfo.
+type ChannelType int
+
+const (
+	ChannelTypeStreamVorbis   ChannelType = C.BASS_CTYPE_STREAM_VORBIS
+	ChannelTypeStreamMP1      ChannelType = C.BASS_CTYPE_STREAM_MP1
+	ChannelTypeStreamMP2      ChannelType = C.BASS_CTYPE_STREAM_MP2
+	ChannelTypeStreamMP3      ChannelType = C.BASS_CTYPE_STREAM_MP3
+	ChannelTypeStreamAIFF     ChannelType = C.BASS_CTYPE_STREAM_AIFF
+	ChannelTypeStreamCA       ChannelType = C.BASS_CTYPE_STREAM_CA  // CoreAudio codec (macOS)
+	ChannelTypeStreamMF       ChannelType = C.BASS_CTYPE_STREAM_MF  // Media Foundation codec (Windows)
+	ChannelTypeStreamWAV      ChannelType = C.BASS_CTYPE_STREAM_WAV // WAVE flag, LOWORD=codec
+	ChannelTypeStreamWAVPCM   ChannelType = C.BASS_CTYPE_STREAM_WAV_PCM
+	ChannelTypeStreamWAVFloat ChannelType = C.BASS_CTYPE_STREAM_WAV_FLOAT
+	ChannelTypeMusicMOD       ChannelType = C.BASS_CTYPE_MUSIC_MOD
+	ChannelTypeMusicMTM       ChannelType = C.BASS_CTYPE_MUSIC_MTM
+	ChannelTypeMusicS3M       ChannelType = C.BASS_CTYPE_MUSIC_S3M
+	ChannelTypeMusicXM        ChannelType = C.BASS_CTYPE_MUSIC_XM
+	ChannelTypeMusicIT        ChannelType = C.BASS_CTYPE_MUSIC_IT
+	ChannelTypeMusicMO3       ChannelType = C.BASS_CTYPE_MUSIC_MO3 // MO3 flag
+)
+
+// origResFloat is set in BASS_CHANNELINFO.origres for floating-point sources.
+const origResFloat = C.BASS_ORIGRES_FLOAT
+
 // Tag represents metadata tag types for BASS_ChannelGetTags.
 type Tag int
 
@@ -195,3 +223,18 @@ func errorCodeToMessage(code ErrorCode) string {
 	}
 	return "unknown error"
 }
+
+// errorCodeToDomainError maps BASS error codes for unreadable files to domain errors.
+// Returns nil for other codes.
+func errorCodeToDomainError(code ErrorCode) error {
+	switch code {
+	case ErrorCODEC, ErrorFORMAT:
+		return domain.ErrUnsupportedCodec
+	case ErrorFILEFORM, ErrorEMPTY:
+		// BASS reports an unrecognised header as an unsupported format; since the
+		// extension is supported, the file is most likely damaged
+		return domain.ErrCorruptFile
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,361 @@
+// Package bass provides technical metadata parsing from audio container headers.
+package bass
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+)
+
+// containerTailSize is how much of the end of an Ogg file is searched for the last page.
+const containerTailSize = 64 * 1024
+
+// readContainerInfo reads technical metadata from the headers of FLAC, Ogg and MP4 files.
+// These formats are not decoded by BASS without plugins, and their headers
+// record exact values, so no decoding is needed.
+// Returns false if the file is not one of these formats or the headers are malformed.
+func readContainerInfo(filePath string) (technicalInfo, bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return technicalInfo{}, false
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return technicalInfo{}, false
+	}
+	src := io.NewSectionReader(file, 0, stat.Size())
+
+	magic := make([]byte, 8)
+	if _, err := src.ReadAt(magic, 0); err != nil {
+		return technicalInfo{}, false
+	}
+
+	var info technicalInfo
+	var ok bool
+	var audioSize func(*io.SectionReader) int64
+	switch {
+	case bytes.HasPrefix(magic, []byte("fLaC")):
+		info, ok = readFLACInfo(src)
+		audioSize = flacAudioSize
+	case bytes.HasPrefix(magic, []byte("OggS")):
+		info, ok = readOggInfo(src)
+		audioSize = oggAudioSize
+	case bytes.Equal(magic[4:8], []byte("ftyp")):
+		info, ok = readMP4Info(src)
+		audioSize = mp4AudioSize
+	default:
+		return technicalInfo{}, false
+	}
+
+	if ok && info.bitRate == 0 && info.duration > 0 {
+		// Average over the audio data; tags often embed pictures of several hundred KB
+		if size := audioSize(src); size > 0 {
+			info.bitRate = int(float64(size) * 8 / info.duration.Seconds() / 1000)
+		}
+	}
+	return info, ok
+}
+
+// readFLACInfo reads the STREAMINFO block, which FLAC requires to come first.
+func readFLACInfo(src *io.SectionReader) (technicalInfo, bool) {
+	header := make([]byte, 4+4+34)
+	if _, err := src.ReadAt(header, 0); err != nil {
+		return technicalInfo{}, false
+	}
+	if header[4]&0x7F != 0 { // Block type 0 is STREAMINFO
+		return technicalInfo{}, false
+	}
+
+	// Bytes 10-17 of STREAMINFO pack sample rate (20 bits), channels - 1 (3 bits),
+	// bits per sample - 1 (5 bits) and total samples (36 bits)
+	packed := binary.BigEndian.Uint64(header[8+10 : 8+18])
+	sampleRate := int(packed >> 44)
+	if sampleRate == 0 {
+		return technicalInfo{}, false
+	}
+	totalSamples := packed & (1<<36 - 1)
+
+	return technicalInfo{
+		codec:      "FLAC",
+		sampleRate: sampleRate,
+		channels:   int(packed>>41&0x07) + 1,
+		bitDepth:   int(packed>>36&0x1F) + 1,
+		duration:   samplesToDuration(totalSamples, sampleRate),
+	}, true
+}
+
+// flacAudioSize returns the size of the audio frames, which follow the last metadata block.
+// Returns 0 if the metadata blocks are malformed.
+func flacAudioSize(src *io.SectionReader) int64 {
+	header := make([]byte, 4)
+	for offset := int64(4); offset < src.Size(); {
+		if _, err := src.ReadAt(header, offset); err != nil {
+			return 0
+		}
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4 + length
+		if header[0]&0x80 != 0 { // Last metadata block
+			return max(src.Size()-offset, 0)
+		}
+	}
+	return 0
+}
+
+// readOggInfo reads the identification header of an Ogg Vorbis or Opus stream
+// and takes the duration from the granule position of the last page.
+func readOggInfo(src *io.SectionReader) (technicalInfo, bool) {
+	// The identification header is the only packet on the first page
+	page := make([]byte, 27+255)
+	n, _ := src.ReadAt(page, 0)
+	if n < 27 {
+		return technicalInfo{}, false
+	}
+	segments := int(page[26])
+	bodyOffset := int64(27 + segments)
+	serial := binary.LittleEndian.Uint32(page[14:18])
+
+	head := make([]byte, 30)
+	if n, _ := src.ReadAt(head, bodyOffset); n < 19 {
+		return technicalInfo{}, false
+	}
+
+	var info technicalInfo
+	var preSkip uint64
+	switch {
+	case bytes.HasPrefix(head, []byte("\x01vorbis")):
+		info.codec = "Vorbis"
+		info.channels = int(head[11])
+		info.sampleRate = int(binary.LittleEndian.Uint32(head[12:16]))
+		// Nominal bitrate; the file size average is used when it is not set
+		if nominal := int32(binary.LittleEndian.Uint32(head[20:24])); nominal > 0 {
+			info.bitRate = int(nominal / 1000)
+		}
+	case bytes.HasPrefix(head, []byte("OpusHead")):
+		info.codec = "Opus"
+		info.channels = int(head[9])
+		preSkip = uint64(binary.LittleEndian.Uint16(head[10:12]))
+		// Opus always decodes at 48 kHz; the header records the original input rate
+		info.sampleRate = int(binary.LittleEndian.Uint32(head[12:16]))
+		if info.sampleRate == 0 {
+			info.sampleRate = 48000
+		}
+	default:
+		return technicalInfo{}, false
+	}
+	if info.sampleRate == 0 {
+		return technicalInfo{}, false
+	}
+
+	granuleRate := info.sampleRate
+	if info.codec == "Opus" {
+		granuleRate = 48000
+	}
+	if granule, ok := lastOggGranule(src, serial); ok && granule > preSkip {
+		info.duration = samplesToDuration(granule-preSkip, granuleRate)
+	}
+
+	return info, true
+}
+
+// lastOggGranule finds the granule position of the last page of a logical stream.
+func lastOggGranule(src *io.SectionReader, serial uint32) (uint64, bool) {
+	start := max(src.Size()-containerTailSize, 0)
+	tail := make([]byte, src.Size()-start)
+	if _, err := src.ReadAt(tail, start); err != nil && err != io.EOF {
+		return 0, false
+	}
+
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if len(tail)-i < 27 {
+			continue
+		}
+		granule := binary.LittleEndian.Uint64(tail[i+6 : i+14])
+		// Pages that end no packet have granule position -1
+		if binary.LittleEndian.Uint32(tail[i+14:i+18]) == serial && granule != ^uint64(0) {
+			return granule, true
+		}
+	}
+	return 0, false
+}
+
+// oggAudioSize returns the size of the pages from the first audio page on.
+// Header pages, which hold the comments and any embedded pictures, have granule position 0;
+// pages that end no packet have -1.
+// Returns 0 if no audio page is found.
+func oggAudioSize(src *io.SectionReader) int64 {
+	header := make([]byte, 27+255)
+	for offset := int64(0); offset+27 <= src.Size(); {
+		if _, err := src.ReadAt(header[:27], offset); err != nil || !bytes.HasPrefix(header, []byte("OggS")) {
+			return 0
+		}
+		granule := binary.LittleEndian.Uint64(header[6:14])
+		if granule != 0 && granule != ^uint64(0) {
+			return src.Size() - offset
+		}
+
+		segments := int(header[26])
+		if _, err := src.ReadAt(header[27:27+segments], offset+27); err != nil {
+			return 0
+		}
+		offset += int64(27 + segments)
+		for _, lacing := range header[27 : 27+segments] {
+			offset += int64(lacing)
+		}
+	}
+	return 0
+}
+
+// mp4Containers lists the MP4 atoms that are searched for the audio track.
+var mp4Containers = map[string]bool{
+	"moov": true, "trak": true, "mdia": true, "minf": true, "stbl": true,
+}
+
+// mp4AudioCodecs maps sample entry types to codec names.
+var mp4AudioCodecs = map[string]string{
+	"mp4a": "AAC",
+	"alac": "ALAC",
+	"fLaC": "FLAC",
+	"Opus": "Opus",
+	"ac-3": "AC-3",
+	"ec-3": "E-AC-3",
+	".mp3": "MP3",
+}
+
+// mp4LosslessCodecs lists the codecs whose sample size is the source bit depth.
+var mp4LosslessCodecs = map[string]bool{"ALAC": true, "FLAC": true}
+
+// readMP4Info reads the first audio track's media header and sample description.
+func readMP4Info(src *io.SectionReader) (technicalInfo, bool) {
+	var info technicalInfo
+	var timescale, units uint64
+	found := false
+
+	var walk func(offset, end int64)
+	walk = func(offset, end int64) {
+		for offset+8 <= end && !found {
+			header := make([]byte, 16)
+			if _, err := src.ReadAt(header[:8], offset); err != nil {
+				return
+			}
+			size := int64(binary.BigEndian.Uint32(header[:4]))
+			name := string(header[4:8])
+			headerSize := int64(8)
+			switch size {
+			case 0:
+				size = end - offset
+			case 1:
+				if _, err := src.ReadAt(header[8:16], offset+8); err != nil {
+					return
+				}
+				size = int64(binary.BigEndian.Uint64(header[8:16]))
+				headerSize = 16
+			}
+			if size < headerSize || offset+size > end {
+				return
+			}
+
+			body := offset + headerSize
+			switch {
+			case mp4Containers[name]:
+				walk(body, offset+size)
+			case name == "mdhd":
+				timescale, units = readMP4MediaHeader(src, body)
+			case name == "stsd" && timescale > 0:
+				found = readMP4SampleEntry(src, body, &info)
+			}
+			offset += size
+		}
+	}
+	walk(0, src.Size())
+
+	if !found {
+		return technicalInfo{}, false
+	}
+	info.duration = samplesToDuration(units, int(timescale))
+	return info, true
+}
+
+// mp4AudioSize returns the size of the media data (mdat) atoms, which hold the audio samples.
+// Returns 0 if the top-level atoms are malformed.
+func mp4AudioSize(src *io.SectionReader) int64 {
+	var total int64
+	header := make([]byte, 16)
+	for offset := int64(0); offset+8 <= src.Size(); {
+		if _, err := src.ReadAt(header[:8], offset); err != nil {
+			return 0
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			size = src.Size() - offset
+		case 1:
+			if _, err := src.ReadAt(header[8:16], offset+8); err != nil {
+				return 0
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if size < headerSize {
+			return 0
+		}
+
+		if string(header[4:8]) == "mdat" {
+			total += min(size, src.Size()-offset) - headerSize
+		}
+		offset += size
+	}
+	return total
+}
+
+// readMP4MediaHeader returns the time scale and duration of an mdhd atom.
+func readMP4MediaHeader(src *io.SectionReader, body int64) (uint64, uint64) {
+	data := make([]byte, 32)
+	if n, _ := src.ReadAt(data, body); n < 24 {
+		return 0, 0
+	}
+	if data[0] == 1 { // Version 1 uses 64-bit times
+		return uint64(binary.BigEndian.Uint32(data[20:24])), binary.BigEndian.Uint64(data[24:32])
+	}
+	return uint64(binary.BigEndian.Uint32(data[12:16])), uint64(binary.BigEndian.Uint32(data[16:20]))
+}
+
+// readMP4SampleEntry reads the first entry of an stsd atom if it describes audio.
+func readMP4SampleEntry(src *io.SectionReader, body int64, info *technicalInfo) bool {
+	// Full box header (4) and entry count (4), then the entry:
+	// size (4), type (4), reserved (6), data reference index (2), version (2),
+	// revision (2), vendor (4), channels (2), sample size (2), compression (2),
+	// packet size (2), sample rate (16.16 fixed point)
+	data := make([]byte, 8+36)
+	if _, err := src.ReadAt(data, body); err != nil {
+		return false
+	}
+
+	entry := data[8:]
+	codec, ok := mp4AudioCodecs[string(entry[4:8])]
+	if !ok {
+		return false
+	}
+
+	info.codec = codec
+	info.channels = int(binary.BigEndian.Uint16(entry[24:26]))
+	info.sampleRate = int(binary.BigEndian.Uint32(entry[32:36]) >> 16)
+	if mp4LosslessCodecs[codec] {
+		info.bitDepth = int(binary.BigEndian.Uint16(entry[26:28]))
+	}
+	return true
+}
+
+// samplesToDuration converts a sample count at the given rate to a duration.
+func samplesToDuration(samples uint64, rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	seconds := samples / uint64(rate)
+	remainder := samples % uint64(rate)
+	return time.Duration(seconds)*time.Second + time.Duration(remainder)*time.Second/time.Duration(rate)
+}
@@ -0,0 +1,164 @@
+package bass
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeContainerTestFile writes data to a file in a temporary directory.
+func writeContainerTestFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+// testOggPage builds an Ogg page with a single segment (checksums are not verified).
+func testOggPage(granule uint64, serial uint32, body []byte) []byte {
+	page := []byte("OggS\x00\x00")
+	page = binary.LittleEndian.AppendUint64(page, granule)
+	page = binary.LittleEndian.AppendUint32(page, serial)
+	page = append(page, make([]byte, 8)...) // Sequence number and checksum
+	page = append(page, 1, byte(len(body)))
+	return append(page, body...)
+}
+
+// testMP4Atom builds an MP4 atom from a name and payload parts.
+func testMP4Atom(name string, parts ...[]byte) []byte {
+	payload := bytes.Join(parts, nil)
+	atom := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	atom = append(atom, name...)
+	return append(atom, payload...)
+}
+
+func TestReadContainerInfo_FLAC(t *testing.T) {
+	// STREAMINFO: 96 kHz, 2 channels, 24 bits, 96000 * 3.5 samples
+	streamInfo := make([]byte, 34)
+	packed := uint64(96000)<<44 | uint64(2-1)<<41 | uint64(24-1)<<36 | uint64(336000)
+	binary.BigEndian.PutUint64(streamInfo[10:18], packed)
+
+	// STREAMINFO, then a large picture block, then 100 kbps of audio frames
+	data := append([]byte("fLaC\x00\x00\x00\x22"), streamInfo...)
+	data = append(data, 0x80|6, 0x03, 0x0D, 0x40) // Last block: PICTURE, 200000 bytes
+	data = append(data, make([]byte, 200000)...)
+	data = append(data, make([]byte, 43750)...)
+	path := writeContainerTestFile(t, "song.flac", data)
+
+	info, ok := readContainerInfo(path)
+	require.True(t, ok)
+	assert.Equal(t, "FLAC", info.codec)
+	assert.Equal(t, 96000, info.sampleRate)
+	assert.Equal(t, 2, info.channels)
+	assert.Equal(t, 24, info.bitDepth)
+	assert.Equal(t, 3500*time.Millisecond, info.duration)
+	assert.Equal(t, 100, info.bitRate, "The picture is not counted")
+}
+
+func TestReadContainerInfo_OggVorbis(t *testing.T) {
+	head := []byte("\x01vorbis\x00\x00\x00\x00\x02")
+	head = binary.LittleEndian.AppendUint32(head, 44100)
+	head = binary.LittleEndian.AppendUint32(head, 0)
+	head = binary.LittleEndian.AppendUint32(head, 192000) // Nominal bitrate
+	head = append(head, make([]byte, 6)...)
+
+	var data []byte
+	data = append(data, testOggPage(0, 7, head)...)
+	data = append(data, testOggPage(44100, 7, make([]byte, 100))...)
+	data = append(data, testOggPage(88200*2, 7, make([]byte, 100))...)
+	data = append(data, testOggPage(^uint64(0), 7, make([]byte, 10))...) // Page that ends no packet
+	path := writeContainerTestFile(t, "song.ogg", data)
+
+	info, ok := readContainerInfo(path)
+	require.True(t, ok)
+	assert.Equal(t, "Vorbis", info.codec)
+	assert.Equal(t, 44100, info.sampleRate)
+	assert.Equal(t, 2, info.channels)
+	assert.Equal(t, 192, info.bitRate)
+	assert.Equal(t, 4*time.Second, info.duration)
+	assert.Zero(t, info.bitDepth)
+}
+
+func TestReadContainerInfo_OggOpus(t *testing.T) {
+	head := []byte("OpusHead\x01\x01")
+	head = binary.LittleEndian.AppendUint16(head, 312) // Pre-skip
+	head = binary.LittleEndian.AppendUint32(head, 44100)
+	head = append(head, 0, 0, 0)
+
+	var data []byte
+	data = append(data, testOggPage(0, 9, head)...)
+	// Comment header pages, as with an embedded picture
+	for i := 0; i < 20; i++ {
+		data = append(data, testOggPage(0, 9, make([]byte, 255))...)
+	}
+	// 1 kbps of audio pages
+	for i := 1; i <= 4; i++ {
+		data = append(data, testOggPage(uint64(i*48000), 9, make([]byte, 222))...)
+	}
+	data = append(data, testOggPage(48000*10+312, 9, make([]byte, 222))...)
+	// Another logical stream's page after the Opus stream must not be used
+	data = append(data, testOggPage(5, 10, make([]byte, 222))...)
+	path := writeContainerTestFile(t, "song.opus", data)
+
+	info, ok := readContainerInfo(path)
+	require.True(t, ok)
+	assert.Equal(t, "Opus", info.codec)
+	assert.Equal(t, 44100, info.sampleRate)
+	assert.Equal(t, 1, info.channels)
+	assert.Equal(t, 10*time.Second, info.duration)
+	assert.Equal(t, 1, info.bitRate, "The header pages are not counted")
+}
+
+func TestReadContainerInfo_MP4(t *testing.T) {
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:16], 44100)    // Time scale
+	binary.BigEndian.PutUint32(mdhd[16:20], 44100*65) // Duration
+
+	entry := make([]byte, 28)
+	binary.BigEndian.PutUint16(entry[16:18], 2)         // Channels
+	binary.BigEndian.PutUint16(entry[18:20], 16)        // Sample size
+	binary.BigEndian.PutUint32(entry[24:28], 44100<<16) // Sample rate
+	stsd := testMP4Atom("stsd", make([]byte, 4), binary.BigEndian.AppendUint32(nil, 1), testMP4Atom("alac", entry))
+
+	trak := testMP4Atom("trak", testMP4Atom("mdia", testMP4Atom("mdhd", mdhd), testMP4Atom("minf", testMP4Atom("stbl", stsd))))
+	data := bytes.Join([][]byte{
+		testMP4Atom("ftyp", []byte("M4A "), make([]byte, 4)),
+		testMP4Atom("moov", testMP4Atom("mvhd", make([]byte, 100)), trak,
+			testMP4Atom("udta", make([]byte, 300000))), // Tags with cover art
+		testMP4Atom("mdat", make([]byte, 65*16000)), // 128 kbps
+	}, nil)
+	path := writeContainerTestFile(t, "song.m4a", data)
+
+	info, ok := readContainerInfo(path)
+	require.True(t, ok)
+	assert.Equal(t, "ALAC", info.codec)
+	assert.Equal(t, 44100, info.sampleRate)
+	assert.Equal(t, 2, info.channels)
+	assert.Equal(t, 16, info.bitDepth)
+	assert.Equal(t, 65*time.Second, info.duration)
+	assert.Equal(t, 128, info.bitRate, "Only the media data is counted")
+}
+
+func TestReadContainerInfo_OtherFormats(t *testing.T) {
+	path := writeContainerTestFile(t, "song.mp3", append([]byte("ID3\x04\x00\x00"), make([]byte, 100)...))
+
+	_, ok := readContainerInfo(path)
+	assert.False(t, ok, "MP3 is read by the engine")
+
+	_, ok = readContainerInfo(writeContainerTestFile(t, "short.flac", []byte("fLaC")))
+	assert.False(t, ok)
+}
+
+func TestCodecName(t *testing.T) {
+	assert.Equal(t, "MP3", codecName(ChannelTypeStreamMP3))
+	assert.Equal(t, "PCM", codecName(ChannelTypeStreamWAVPCM))
+	assert.Equal(t, "WAV", codecName(ChannelTypeStreamWAV|0x0002))
+	assert.Equal(t, "XM", codecName(ChannelTypeMusicXM))
+	assert.Equal(t, "MO3", codecName(ChannelTypeMusicIT|ChannelTypeMusicMO3))
+	assert.Empty(t, codecName(ChannelTypeStreamCA))
+}
@@ -0,0 +1,121 @@
+// Package bass provides folder cover art lookup for audio files.
+package bass
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// folderCoverNames lists cover image base names in order of preference.
+var folderCoverNames = []string{
+	"cover", "folder", "front", "album", "albumart", "albumartlarge", "thumb", "albumartsmall",
+}
+
+// folderCoverExtensions lists the image formats accepted as folder covers.
+var folderCoverExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// maxFolderCoverSize limits the size of folder cover images that are loaded.
+const maxFolderCoverSize = 16 << 20 // 16 MiB
+
+// folderCoverCache remembers the cover of the most recent directory.
+// Folder scans visit the tracks of one directory after another, so this
+// avoids listing the same directory for every track without growing unbounded.
+var folderCoverCache struct {
+	mu        sync.Mutex
+	dir       string
+	dirMod    time.Time
+	coverPath string // Empty if the directory has no cover
+}
+
+// findFolderCover returns the path of the preferred cover image in a directory.
+// Matching is case-insensitive (Cover.JPG and cover.jpg are both found).
+func findFolderCover(dir string) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+
+	best, bestRank := "", len(folderCoverNames)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		name := strings.ToLower(entry.Name())
+		ext := filepath.Ext(name)
+		if !isFolderCoverExtension(ext) {
+			continue
+		}
+
+		base := strings.TrimSuffix(name, ext)
+		for rank, coverName := range folderCoverNames {
+			// "front" also matches names like "front cover.jpg"
+			matches := base == coverName || (coverName == "front" && strings.HasPrefix(base, "front"))
+			if matches && rank < bestRank {
+				best, bestRank = entry.Name(), rank
+			}
+		}
+	}
+
+	if best == "" {
+		return "", false
+	}
+	return filepath.Join(dir, best), true
+}
+
+// isFolderCoverExtension checks if a lower-case extension is an accepted image format.
+func isFolderCoverExtension(ext string) bool {
+	for _, coverExt := range folderCoverExtensions {
+		if ext == coverExt {
+			return true
+		}
+	}
+	return false
+}
+
+// folderCoverPath returns the path of the cover image found next to the audio file, or "".
+// Tracks only hold the path; the image is read when it is displayed (see readFolderCover).
+func folderCoverPath(filePath string) string {
+	dir := filepath.Dir(filePath)
+
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return ""
+	}
+
+	cache := &folderCoverCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Adding or removing files changes the directory modification time
+	if cache.dir != dir || !cache.dirMod.Equal(dirInfo.ModTime()) {
+		coverPath, _ := findFolderCover(dir)
+		cache.dir = dir
+		cache.dirMod = dirInfo.ModTime()
+		cache.coverPath = coverPath
+	}
+	return cache.coverPath
+}
+
+// readFolderCover reads a folder cover image.
+// Returns nil if the image has been removed or is larger than maxFolderCoverSize.
+func readFolderCover(coverPath string) ([]byte, error) {
+	info, err := os.Stat(coverPath)
+	if os.IsNotExist(err) || (err == nil && info.Size() > maxFolderCoverSize) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewAudioEngineError("read_album_art", coverPath, 0, "failed to read folder cover", err)
+	}
+
+	data, err := os.ReadFile(coverPath)
+	if err != nil {
+		return nil, domain.NewAudioEngineError("read_album_art", coverPath, 0, "failed to read folder cover", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package bass
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCoverTestFiles creates files containing their own names in a new directory.
+func writeCoverTestFiles(t *testing.T, names ...string) string {
+	dir := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+	return dir
+}
+
+func TestFindFolderCover(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"cover preferred over folder", []string{"folder.jpg", "Cover.JPG"}, "Cover.JPG"},
+		{"folder png", []string{"song.mp3", "folder.png"}, "folder.png"},
+		{"front prefix", []string{"Front Cover.jpeg"}, "Front Cover.jpeg"},
+		{"unsupported format ignored", []string{"cover.bmp", "albumartsmall.jpg"}, "albumartsmall.jpg"},
+		{"unrelated image ignored", []string{"back.jpg", "scan01.png"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeCoverTestFiles(t, tt.files...)
+
+			path, found := findFolderCover(dir)
+			if tt.want == "" {
+				assert.False(t, found)
+				return
+			}
+			require.True(t, found)
+			assert.Equal(t, filepath.Join(dir, tt.want), path)
+		})
+	}
+}
+
+func TestFindFolderCover_IgnoresDirectories(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "cover.jpg"), 0755))
+
+	_, found := findFolderCover(dir)
+	assert.False(t, found)
+}
+
+func TestFolderCoverPath(t *testing.T) {
+	dir := writeCoverTestFiles(t, "folder.jpg")
+	track := filepath.Join(dir, "song.mp3")
+
+	assert.Equal(t, filepath.Join(dir, "folder.jpg"), folderCoverPath(track))
+
+	// A better match added later is picked up
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cover.png"), []byte("cover.png"), 0600))
+	later := time.Now().Add(time.Minute) // Don't rely on the file system's timestamp resolution
+	require.NoError(t, os.Chtimes(dir, later, later))
+	assert.Equal(t, filepath.Join(dir, "cover.png"), folderCoverPath(track))
+
+	// Another directory without a cover returns nothing
+	assert.Empty(t, folderCoverPath(filepath.Join(t.TempDir(), "song.mp3")))
+}
+
+func TestReadFolderCover(t *testing.T) {
+	dir := writeCoverTestFiles(t, "cover.jpg")
+
+	data, err := readFolderCover(filepath.Join(dir, "cover.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cover.jpg"), data)
+
+	// A cover removed since the scan is not an error
+	data, err = readFolderCover(filepath.Join(dir, "folder.jpg"))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
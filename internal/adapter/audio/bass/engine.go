@@ -23,6 +23,7 @@ type Engine struct {
 	device      int
 	frequency   int
 	flags       int
+	tagCharset  domain.Charset // Fallback for legacy tags
 
 	// Track management
 	tracks map[domain.TrackHandle]*trackInfo
@@ -33,13 +34,15 @@ type Engine struct {
 type trackInfo struct {
 	handle   int64 // BASS channel handle
 	filePath string
-	isMOD    bool // True if this is a MOD/tracker file
+	isMOD    bool          // True if this is a MOD/tracker file
+	buffer   *memoryBuffer // File data for tracks loaded from memory, nil otherwise
 }
 
 // NewEngine creates a new BASS audio engine.
 func NewEngine() *Engine {
 	return &Engine{
-		tracks: make(map[domain.TrackHandle]*trackInfo),
+		tracks:     make(map[domain.TrackHandle]*trackInfo),
+		tagCharset: domain.DefaultTagCharset,
 	}
 }
 
@@ -127,28 +130,44 @@ func (e *Engine) Load(filePath string) (domain.TrackHandle, error) {
 	// Determine if this is a MOD file
 	isMOD := isModFile(filePath)
 
+	loadMusic := func(flags int) (int64, error) { return bassMusicLoad(filePath, flags) }
+	loadStream := func(flags int) (int64, error) { return bassStreamCreateFile(filePath, flags) }
+
+	// Tracks inside archives are loaded from memory, so no temporary file is needed
+	var buffer *memoryBuffer
+	if _, _, ok := domain.SplitArchivePath(filePath); ok {
+		data, err := readArchiveEntry(filePath)
+		if err != nil {
+			return domain.InvalidTrackHandle, err
+		}
+		buffer = newMemoryBuffer(data)
+		loadMusic = func(flags int) (int64, error) { return bassMusicLoadMemory(buffer, flags) }
+		loadStream = func(flags int) (int64, error) { return bassStreamCreateMemory(buffer, flags) }
+	}
+
 	var bassHandle int64
 	var err error
 
 	if isMOD {
 		// Load as MOD music
-		bassHandle, err = bassMusicLoad(filePath, musicPreScan|musicRamps|streamAutoFree|posReset|posResetEx)
+		bassHandle, err = loadMusic(musicPreScan | musicRamps | streamAutoFree | posReset | posResetEx)
 	} else {
 		// Load as regular stream
-		bassHandle, err = bassStreamCreateFile(filePath, streamAutoFree|posReset|posResetEx)
+		bassHandle, err = loadStream(streamAutoFree | posReset | posResetEx)
 	}
 
 	if err != nil {
 		// Try the opposite method as fallback
 		if isMOD {
-			bassHandle, err = bassStreamCreateFile(filePath, streamAutoFree|posReset|posResetEx)
+			bassHandle, err = loadStream(streamAutoFree | posReset | posResetEx)
 			isMOD = false
 		} else {
-			bassHandle, err = bassMusicLoad(filePath, musicPreScan|musicRamps|streamAutoFree|posReset|posResetEx)
+			bassHandle, err = loadMusic(musicPreScan | musicRamps | streamAutoFree | posReset | posResetEx)
 			isMOD = true
 		}
 
 		if err != nil {
+			buffer.free()
 			return domain.InvalidTrackHandle, err
 		}
 	}
@@ -161,6 +180,7 @@ func (e *Engine) Load(filePath string) (domain.TrackHandle, error) {
 		handle:   bassHandle,
 		filePath: filePath,
 		isMOD:    isMOD,
+		buffer:   buffer,
 	}
 
 	return handle, nil
@@ -187,15 +207,19 @@ func (e *Engine) unloadInternal(handle domain.TrackHandle) error {
 
 	// Stop the channel first
 	if err := bassChannelStop(track.handle); err != nil {
+		// Stopping only fails for freed channels (e.g. auto-freed at the end),
+		// which no longer read their memory
+		track.buffer.free()
 		return err
 	}
 
-	// Free the channel
+	// Free the channel, then the memory it was reading
 	if track.isMOD {
 		bassMusicFree(track.handle)
 	} else {
 		bassStreamFree(track.handle)
 	}
+	track.buffer.free()
 
 	// Remove from the map
 	delete(e.tracks, handle)
@@ -449,7 +473,38 @@ func (e *Engine) GetVolume(handle domain.TrackHandle) (float64, error) {
 func (e *Engine) GetMetadata(filePath string) (*domain.MusicTrack, error) {
 	// Metadata extraction is handled by the metadata.go file
 	// This is a separate concern from playback
-	return extractMetadata(filePath)
+	e.mu.RLock()
+	charset := e.tagCharset
+	e.mu.RUnlock()
+
+	return extractMetadata(filePath, charset)
+}
+
+// GetAlbumArt reads the artwork embedded in a track's file, or else its folder cover.
+// Tracks inside archives and tracker modules have no artwork.
+func (e *Engine) GetAlbumArt(track domain.MusicTrack) ([]byte, error) {
+	if track.FilePath == "" {
+		return nil, domain.ErrInvalidFilePath
+	}
+	if _, _, ok := domain.SplitArchivePath(track.FilePath); ok || isModFile(track.FilePath) {
+		return nil, nil
+	}
+
+	art, err := readEmbeddedArt(track.FilePath)
+	if err != nil || len(art) > 0 {
+		return art, err
+	}
+	if track.Metadata != nil && track.Metadata.CoverPath != "" {
+		return readFolderCover(track.Metadata.CoverPath)
+	}
+	return nil, nil
+}
+
+// SetTagCharset sets the charset used for legacy tags when detection is inconclusive.
+func (e *Engine) SetTagCharset(charset domain.Charset) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tagCharset = charset
 }
 
 // GetLoadedTracksCount returns the number of currently loaded tracks (for debugging).
@@ -485,5 +540,8 @@ func (e *Engine) GetFFTData(handle domain.TrackHandle) ([]float32, error) {
 	return buffer, nil
 }
 
-// Verify that Engine implements the AudioEngine interface
-var _ ports.AudioEngine = (*Engine)(nil)
+// Verify that Engine implements the AudioEngine and TagCharsetDecoder interfaces
+var (
+	_ ports.AudioEngine       = (*Engine)(nil)
+	_ ports.TagCharsetDecoder = (*Engine)(nil)
+)
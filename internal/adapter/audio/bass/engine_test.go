@@ -543,6 +543,29 @@ func TestBassEngine_GetMetadata(t *testing.T) {
 	}
 }
 
+func TestBassEngine_GetMetadataTechnicalInfo(t *testing.T) {
+	testFile := getTestAudioFile(t)
+	if testFile == "" {
+		t.Skip("No test audio file available")
+	}
+
+	engine := NewEngine()
+	// Decoding the headers requires an initialized BASS
+	initEngineOrSkip(t, engine)
+	defer engine.Shutdown()
+
+	metadata, err := engine.GetMetadata(testFile)
+	require.NoError(t, err)
+	require.NotNil(t, metadata.Metadata)
+
+	// The generated WAV is 1 second of 16-bit mono at 44.1 kHz
+	assert.Equal(t, "PCM", metadata.Metadata.Codec)
+	assert.Equal(t, 44100, metadata.Metadata.SampleRate)
+	assert.Equal(t, 1, metadata.Metadata.Channels)
+	assert.Equal(t, 16, metadata.Metadata.BitDepth)
+	assert.Equal(t, time.Second, metadata.Duration)
+}
+
 func TestBassEngine_GetMetadataInvalidPath(t *testing.T) {
 	engine := NewEngine()
 
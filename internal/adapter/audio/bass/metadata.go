@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -32,16 +33,30 @@ func isModFile(filePath string) bool {
 
 // extractMetadata extracts metadata from an audio file.
 // This handles both MOD files and regular audio files.
-func extractMetadata(filePath string) (*domain.MusicTrack, error) {
+// Legacy tags that are not Unicode are decoded with charset when detection is inconclusive.
+func extractMetadata(filePath string, charset domain.Charset) (*domain.MusicTrack, error) {
 	if filePath == "" {
 		return nil, domain.ErrInvalidFilePath
 	}
 
+	// Tracks inside archives are read from memory
+	if _, entryName, ok := domain.SplitArchivePath(filePath); ok {
+		return extractArchiveMetadata(filePath, entryName, charset)
+	}
+
 	// Check if a file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	info, statErr := os.Stat(filePath)
+	if os.IsNotExist(statErr) {
 		return nil, domain.ErrFileNotFound
 	}
 
+	// Files that cannot be opened cannot be played either
+	file, openErr := os.Open(filePath)
+	if openErr != nil {
+		return nil, domain.NewAudioEngineError("read_metadata", filePath, 0, "failed to open file", openErr)
+	}
+	file.Close()
+
 	filename := filepath.Base(filePath)
 	ext := filepath.Ext(filePath)
 	isMOD := isModFile(filePath)
@@ -55,44 +70,95 @@ func extractMetadata(filePath string) (*domain.MusicTrack, error) {
 		IsMOD:      isMOD,
 		Metadata:   &domain.TrackMetadata{},
 	}
+	if statErr == nil {
+		track.FileSize = info.Size()
+	}
 
 	if isMOD {
 		// Extract MOD metadata
-		return extractMODMetadata(track)
+		return extractMODMetadata(track, charset, func(flags int) (int64, error) {
+			return bassMusicLoad(filePath, flags)
+		})
 	}
 
 	// Extract regular audio file metadata
-	return extractAudioMetadata(track)
+	track, err := extractAudioMetadata(track, charset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Codec, bitrate, sample rate, channels and duration
+	extractTechnicalInfo(track)
+
+	// Fall back to a cover image in the track's folder (cover.jpg, folder.png, ...).
+	// Only its path is kept, so tracks of an album do not each hold a copy of the image
+	if len(track.Metadata.AlbumArt) == 0 {
+		track.Metadata.CoverPath = folderCoverPath(filePath)
+	}
+
+	return track, nil
 }
 
-// extractMODMetadata extracts metadata from a MOD/tracker file.
-func extractMODMetadata(track *domain.MusicTrack) (*domain.MusicTrack, error) {
-	// Load the MOD file to extract tags
-	handle, err := bassMusicLoad(track.FilePath, streamDecodeOnly|streamAutoFree)
+// extractArchiveMetadata extracts metadata from an entry of a ZIP archive.
+// Only tracker modules are scanned inside archives; other formats get basic metadata.
+func extractArchiveMetadata(filePath, entryName string, charset domain.Charset) (*domain.MusicTrack, error) {
+	data, err := readArchiveEntry(filePath)
 	if err != nil {
-		// If loading fails, return basic metadata
+		return nil, err
+	}
+
+	// Entry names always use forward slashes
+	track := &domain.MusicTrack{
+		ID:         generateTrackID(),
+		FilePath:   filePath,
+		Title:      path.Base(entryName),
+		FileFormat: path.Ext(entryName),
+		IsMOD:      isModFile(entryName),
+		FileSize:   int64(len(data)),
+		Metadata:   &domain.TrackMetadata{},
+	}
+	if !track.IsMOD {
 		return track, nil
 	}
+
+	buffer := newMemoryBuffer(data)
+	defer buffer.free()
+
+	return extractMODMetadata(track, charset, func(flags int) (int64, error) {
+		return bassMusicLoadMemory(buffer, flags)
+	})
+}
+
+// extractMODMetadata extracts metadata from a MOD/tracker file loaded with load.
+// Module texts have no defined encoding, so they are decoded as legacy text.
+func extractMODMetadata(track *domain.MusicTrack, charset domain.Charset, load func(flags int) (int64, error)) (*domain.MusicTrack, error) {
+	// Load the MOD file to extract tags
+	// Prescanning calculates the exact playback length
+	handle, err := load(streamDecodeOnly | streamAutoFree | musicPreScan)
+	if err != nil {
+		// A module BASS cannot load cannot be played
+		return nil, err
+	}
 	defer bassMusicFree(handle)
 
 	// Extract MOD-specific tags
-	name := strings.TrimSpace(bassChannelGetTags(handle, TagMusicNAME))
+	name := strings.TrimSpace(decodeLegacyText(bassChannelGetTags(handle, TagMusicNAME), charset))
 	if name != "" {
 		track.Title = name
 	}
 
-	author := strings.TrimSpace(bassChannelGetTags(handle, TagMusicAUTH))
+	author := strings.TrimSpace(decodeLegacyText(bassChannelGetTags(handle, TagMusicAUTH), charset))
 	if author != "" {
 		track.Artist = author
 		track.Metadata.Composer = author
 	}
 
-	message := strings.TrimSpace(bassChannelGetTags(handle, TagMusicMESSAGE))
+	message := strings.TrimSpace(decodeLegacyText(bassChannelGetTags(handle, TagMusicMESSAGE), charset))
 	if message != "" {
 		track.Metadata.Comment = message
 	}
 
-	instrument := strings.TrimSpace(bassChannelGetTags(handle, TagMusicINST))
+	instrument := strings.TrimSpace(decodeLegacyText(bassChannelGetTags(handle, TagMusicINST), charset))
 	if instrument != "" {
 		// Store instrument info in comment if comment is empty
 		if track.Metadata.Comment == "" {
@@ -100,15 +166,16 @@ func extractMODMetadata(track *domain.MusicTrack) (*domain.MusicTrack, error) {
 		}
 	}
 
-	// Get duration
-	lengthBytes := bassChannelGetLength(handle)
-	track.Duration = bassChannelBytes2Seconds(handle, lengthBytes)
+	// Get format, channels and duration
+	readChannelInfo(handle).applyTo(track)
 
 	return track, nil
 }
 
 // extractAudioMetadata extracts metadata from a regular audio file.
-func extractAudioMetadata(track *domain.MusicTrack) (*domain.MusicTrack, error) {
+// ID3v1 tags and Latin-1 ID3v2 frames are decoded as legacy text;
+// other tag formats are always Unicode.
+func extractAudioMetadata(track *domain.MusicTrack, charset domain.Charset) (*domain.MusicTrack, error) {
 	file, err := os.Open(track.FilePath)
 	if err != nil {
 		// If we can't open the file, return basic metadata
@@ -123,22 +190,44 @@ func extractAudioMetadata(track *domain.MusicTrack) (*domain.MusicTrack, error)
 		return track, nil
 	}
 
+	var latin1Frames map[string]bool
+	switch metadata.Format() {
+	case tag.ID3v2_2, tag.ID3v2_3, tag.ID3v2_4:
+		// Frames whose encoding cannot be read are kept as the tag library decoded them
+		latin1Frames, _ = readID3v2Latin1Frames(track.FilePath)
+	}
+	text := tagTextDecoder(metadata.Format(), charset, latin1Frames)
+
 	// Extract metadata fields
-	if title := strings.TrimSpace(metadata.Title()); title != "" {
+	if title := text(metadata.Title(), "TIT2", "TT2"); title != "" {
 		track.Title = title
 	}
 
-	if artist := strings.TrimSpace(metadata.Artist()); artist != "" {
+	if artist := text(metadata.Artist(), "TPE1", "TP1"); artist != "" {
 		track.Artist = artist
 	}
 
-	if album := strings.TrimSpace(metadata.Album()); album != "" {
+	if album := text(metadata.Album(), "TALB", "TAL"); album != "" {
 		track.Album = album
 	}
 
+	track.AlbumArtist = text(metadata.AlbumArtist(), "TPE2", "TP2")
+
+	// Compilation flag and sort names are only available as raw tags
+	applyRawTags(track, metadata.Raw())
+	if metadata.Format() == tag.MP4 {
+		if sortTags, err := readMP4SortTags(track.FilePath); err == nil {
+			applyRawTags(track, sortTags)
+		}
+	}
+	track.ArtistSort = text(track.ArtistSort, "TSOP", "TSP")
+	track.AlbumSort = text(track.AlbumSort, "TSOA", "TSA")
+	track.TitleSort = text(track.TitleSort, "TSOT", "TST")
+
 	// Extended metadata
-	track.Metadata.Composer = strings.TrimSpace(metadata.Composer())
-	track.Metadata.Genre = strings.TrimSpace(metadata.Genre())
+	track.Metadata.Composer = text(metadata.Composer(), "TCOM", "TCM")
+	track.Metadata.Genre = text(metadata.Genre(), "TCON", "TCO")
+	track.Metadata.Comment = text(metadata.Comment(), "COMM", "COM")
 
 	if year := metadata.Year(); year > 0 {
 		track.Metadata.Year = year
@@ -154,15 +243,51 @@ func extractAudioMetadata(track *domain.MusicTrack) (*domain.MusicTrack, error)
 	// Album art
 	if picture := metadata.Picture(); picture != nil {
 		track.Metadata.AlbumArt = picture.Data
+		track.Metadata.HasAlbumArt = len(picture.Data) > 0
 	}
 
-	// Format-specific metadata
-	if format := metadata.Format(); format != tag.UnknownFormat {
-		// Could extract bit rate, sample rate, etc. from the format if needed
-		track.Metadata.SampleRate = 44100 // Default assumption
+	return track, nil
+}
+
+// readEmbeddedArt returns the artwork embedded in the tags of an audio file, or nil if it has none.
+func readEmbeddedArt(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, domain.NewAudioEngineError("read_album_art", filePath, 0, "failed to open file", err)
 	}
+	defer file.Close()
 
-	return track, nil
+	metadata, err := tag.ReadFrom(file)
+	if err != nil || metadata == nil || metadata.Picture() == nil {
+		// Files without readable tags have no artwork
+		return nil, nil
+	}
+	return metadata.Picture().Data, nil
+}
+
+// tagTextDecoder returns a function that trims the text of a tag field and converts it to UTF-8.
+// The function is given the text and the IDs of the ID3v2 frames the field is read from.
+// The tag library returns ID3v1 text as raw bytes and ID3v2 text decoded with the
+// frame's encoding; only frames in latin1Frames (encoding 0) can hold legacy text.
+func tagTextDecoder(format tag.Format, charset domain.Charset, latin1Frames map[string]bool) func(string, ...string) string {
+	switch format {
+	case tag.ID3v1:
+		return func(text string, _ ...string) string { return strings.TrimSpace(decodeLegacyText(text, charset)) }
+	case tag.ID3v2_2, tag.ID3v2_3, tag.ID3v2_4:
+		return func(text string, frames ...string) string {
+			for _, frame := range frames {
+				if latin1Frames[frame] {
+					return strings.TrimSpace(decodeLatin1Text(text, charset))
+				}
+			}
+			return strings.TrimSpace(text)
+		}
+	default:
+		return func(text string, _ ...string) string { return strings.TrimSpace(text) }
+	}
 }
 
 // generateTrackID generates a unique ID for a track
@@ -0,0 +1,293 @@
+// Package bass provides parsing of raw tags that the tag library does not interpret.
+package bass
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// rawCompilationTags lists the raw tag names of the compilation flag:
+// ID3v2.3/2.4 and ID3v2.2 frames (iTunes), Vorbis comments (lowercased by the
+// tag library) and the MP4 atom.
+var rawCompilationTags = []string{"TCMP", "TCP", "compilation", "cpil"}
+
+// rawSortTags maps the raw tag names of sort names to the track field they set.
+var rawSortTags = map[string]func(track *domain.MusicTrack) *string{
+	// ID3v2.3/2.4 and ID3v2.2 frames
+	"TSOP": artistSortField, "TSP": artistSortField,
+	"TSOA": albumSortField, "TSA": albumSortField,
+	"TSOT": titleSortField, "TST": titleSortField,
+	// Vorbis comments
+	"artistsort": artistSortField,
+	"albumsort":  albumSortField,
+	"titlesort":  titleSortField,
+	// MP4 atoms
+	"soar": artistSortField,
+	"soal": albumSortField,
+	"sonm": titleSortField,
+}
+
+func artistSortField(track *domain.MusicTrack) *string { return &track.ArtistSort }
+func albumSortField(track *domain.MusicTrack) *string  { return &track.AlbumSort }
+func titleSortField(track *domain.MusicTrack) *string  { return &track.TitleSort }
+
+// rawRatingTags lists the raw tag names of the rating: ID3v2.3/2.4 and ID3v2.2
+// popularimeter frames, and Vorbis comments.
+var rawRatingTags = []string{"POPM", "POP", "rating", "fmps_rating"}
+
+// applyRawTags sets the compilation flag, sort names and rating from raw tag values.
+// Values that are missing or empty leave the track unchanged.
+func applyRawTags(track *domain.MusicTrack, raw map[string]interface{}) {
+	for _, name := range rawCompilationTags {
+		if value, ok := raw[name]; ok {
+			track.Compilation = isTrueTagValue(value)
+			break
+		}
+	}
+
+	for name, field := range rawSortTags {
+		if value, ok := raw[name].(string); ok {
+			if value = strings.TrimSpace(strings.TrimRight(value, "\x00")); value != "" {
+				*field(track) = value
+			}
+		}
+	}
+
+	if track.Metadata != nil {
+		for _, name := range rawRatingTags {
+			if rating := ratingTagValue(raw[name]); rating > 0 {
+				track.Metadata.Rating = rating
+				break
+			}
+		}
+	}
+}
+
+// ratingTagValue converts a raw rating to stars (1 to 5), or 0 if unrated.
+// Popularimeter frames hold an e-mail address followed by a rating byte
+// (1 to 255); text ratings are stars (1 to 5), percentages (up to 100) or
+// fractions (FMPS, 0.0 to 1.0).
+func ratingTagValue(value interface{}) int {
+	switch v := value.(type) {
+	case []byte:
+		end := bytes.IndexByte(v, 0)
+		if end < 0 || end+1 >= len(v) {
+			return 0
+		}
+		// Windows Media Player writes 1, 64, 128, 196 and 255 for one to five stars
+		switch rating := v[end+1]; {
+		case rating == 0:
+			return 0
+		case rating < 32:
+			return 1
+		case rating < 96:
+			return 2
+		case rating < 160:
+			return 3
+		case rating < 224:
+			return 4
+		default:
+			return 5
+		}
+	case string:
+		var rating float64
+		if _, err := fmt.Sscan(strings.TrimSpace(strings.TrimRight(v, "\x00")), &rating); err != nil || rating <= 0 {
+			return 0
+		}
+		switch {
+		case rating <= 1 && strings.Contains(v, "."):
+			return max(1, int(rating*5+0.5))
+		case rating <= 5:
+			return int(rating + 0.5)
+		case rating <= 100:
+			return max(1, int(rating/20+0.5))
+		}
+	}
+	return 0
+}
+
+// isTrueTagValue interprets a flag stored as a number or text ("1", "true").
+func isTrueTagValue(value interface{}) bool {
+	switch v := value.(type) {
+	case int:
+		return v != 0
+	case bool:
+		return v
+	case string:
+		v = strings.ToLower(strings.TrimSpace(strings.TrimRight(v, "\x00")))
+		return v != "" && v != "0" && v != "false" && v != "no"
+	default:
+		return false
+	}
+}
+
+// mp4SortAtoms lists the MP4 item list atoms holding sort names.
+var mp4SortAtoms = map[string]bool{"soar": true, "soal": true, "sonm": true}
+
+// readMP4SortTags reads the sort name atoms from the item list of an MP4 file
+// (moov/udta/meta/ilst), which the tag library does not read.
+func readMP4SortTags(filePath string) (map[string]interface{}, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	src := io.NewSectionReader(file, 0, stat.Size())
+
+	tags := make(map[string]interface{})
+	var walk func(offset, end int64, inList bool) error
+	walk = func(offset, end int64, inList bool) error {
+		for offset+8 <= end {
+			header := make([]byte, 8)
+			if _, err := src.ReadAt(header, offset); err != nil {
+				return err
+			}
+			size := int64(binary.BigEndian.Uint32(header[:4]))
+			name := string(header[4:8])
+			if size < 8 || offset+size > end {
+				return fmt.Errorf("invalid MP4 atom %q", name)
+			}
+
+			body, bodyEnd := offset+8, offset+size
+			switch {
+			case inList && mp4SortAtoms[name]:
+				if value, ok := readMP4TextData(src, body, bodyEnd); ok {
+					tags[name] = value
+				}
+			case name == "moov" || name == "udta" || name == "ilst":
+				if err := walk(body, bodyEnd, name == "ilst"); err != nil {
+					return err
+				}
+			case name == "meta":
+				// Full box: version and flags precede the children
+				if err := walk(body+4, bodyEnd, false); err != nil {
+					return err
+				}
+			}
+			offset = bodyEnd
+		}
+		return nil
+	}
+
+	if err := walk(0, src.Size(), false); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// readMP4TextData reads the UTF-8 value of the data atom of an item list entry.
+func readMP4TextData(src *io.SectionReader, offset, end int64) (string, bool) {
+	// size (4), "data" (4), version (1), type (3), locale (4), value
+	const dataHeaderSize = 16
+	if end-offset < dataHeaderSize || end-offset > 64*1024 {
+		return "", false
+	}
+
+	data := make([]byte, end-offset)
+	if _, err := src.ReadAt(data, offset); err != nil {
+		return "", false
+	}
+	if !bytes.Equal(data[4:8], []byte("data")) || binary.BigEndian.Uint32(data[8:12])&0xFFFFFF != 1 {
+		return "", false // Type 1 is UTF-8 text
+	}
+	size := int64(binary.BigEndian.Uint32(data[:4]))
+	if size < dataHeaderSize || size > int64(len(data)) {
+		return "", false
+	}
+	return string(data[dataHeaderSize:size]), true
+}
+
+// id3v2CommentFrames lists the comment frames, which hold an encoding byte like text frames.
+var id3v2CommentFrames = map[string]bool{"COMM": true, "COM": true}
+
+// readID3v2Latin1Frames reads the ID3v2 tag at the start of a file and returns
+// the IDs of the text and comment frames with encoding 0 (ISO-8859-1).
+// The tag library does not report the encoding of the frames it reads.
+// Compressed and encrypted frames are skipped.
+func readID3v2Latin1Frames(filePath string) (map[string]bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return nil, err
+	}
+	version, flags := header[3], header[5]
+	if string(header[:3]) != "ID3" || version < 2 || version > 4 {
+		return nil, fmt.Errorf("no ID3v2 tag")
+	}
+	data := make([]byte, syncsafeInt(header[6:10]))
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+	if flags&0x80 != 0 && version < 4 {
+		// Unsynchronisation of the whole tag; ID3v2.4 applies it per frame
+		data = bytes.ReplaceAll(data, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	if flags&0x40 != 0 {
+		// Extended header (compression in ID3v2.2, which is not supported)
+		switch {
+		case version == 2:
+			return nil, fmt.Errorf("compressed ID3v2.2 tag")
+		case len(data) < 4:
+			return nil, fmt.Errorf("invalid ID3v2 extended header")
+		case version == 3:
+			data = data[min(4+int(binary.BigEndian.Uint32(data[:4])), len(data)):]
+		default:
+			data = data[min(syncsafeInt(data[:4]), len(data)):]
+		}
+	}
+
+	idSize, headerSize := 4, 10
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+	frames := make(map[string]bool)
+	for len(data) >= headerSize && data[0] != 0 {
+		id := string(data[:idSize])
+		var size, skip int
+		encrypted := false
+		switch version {
+		case 2:
+			size = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(data[4:8]))
+			encrypted = data[9]&0xC0 != 0 // Compression or encryption
+		default:
+			size = syncsafeInt(data[4:8])
+			encrypted = data[9]&0x0C != 0
+			if data[9]&0x01 != 0 {
+				skip = 4 // Data length indicator
+			}
+		}
+		if size > len(data)-headerSize {
+			return nil, fmt.Errorf("invalid ID3v2 frame %q", id)
+		}
+
+		body := data[headerSize : headerSize+size]
+		if !encrypted && skip < len(body) && (id[0] == 'T' || id3v2CommentFrames[id]) && body[skip] == 0 {
+			frames[id] = true
+		}
+		data = data[headerSize+size:]
+	}
+	return frames, nil
+}
+
+// syncsafeInt decodes a 4-byte ID3v2 size, which stores 7 bits per byte.
+func syncsafeInt(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
@@ -0,0 +1,161 @@
+package bass
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestApplyRawTags(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want domain.MusicTrack
+	}{
+		{
+			name: "ID3v2.4",
+			raw:  map[string]interface{}{"TCMP": "1", "TSOP": "Beatles, The", "TSOA": "White Album", "TSOT": "Back in the USSR"},
+			want: domain.MusicTrack{Compilation: true, ArtistSort: "Beatles, The", AlbumSort: "White Album", TitleSort: "Back in the USSR"},
+		},
+		{
+			name: "ID3v2.2",
+			raw:  map[string]interface{}{"TCP": "1\x00", "TSP": "Doors, The"},
+			want: domain.MusicTrack{Compilation: true, ArtistSort: "Doors, The"},
+		},
+		{
+			name: "Vorbis comments",
+			raw:  map[string]interface{}{"compilation": "0", "albumsort": " Greatest Hits "},
+			want: domain.MusicTrack{AlbumSort: "Greatest Hits"},
+		},
+		{
+			name: "MP4",
+			raw:  map[string]interface{}{"cpil": 1, "sonm": "Song"},
+			want: domain.MusicTrack{Compilation: true, TitleSort: "Song"},
+		},
+		{
+			name: "empty values",
+			raw:  map[string]interface{}{"TSOP": "", "TCMP": ""},
+			want: domain.MusicTrack{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var track domain.MusicTrack
+			applyRawTags(&track, tt.raw)
+			assert.Equal(t, tt.want, track)
+		})
+	}
+}
+
+func TestApplyRawTags_Rating(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want int
+	}{
+		{"POPM five stars", map[string]interface{}{"POPM": []byte("user@example.com\x00\xff\x00\x00\x00\x01")}, 5},
+		{"POPM two stars", map[string]interface{}{"POPM": []byte("\x00\x40")}, 2},
+		{"POPM four stars", map[string]interface{}{"POP": []byte("a\x00\xc4")}, 4},
+		{"POPM unrated", map[string]interface{}{"POPM": []byte("user@example.com\x00\x00")}, 0},
+		{"Vorbis stars", map[string]interface{}{"rating": "3"}, 3},
+		{"Vorbis percent", map[string]interface{}{"rating": "80"}, 4},
+		{"FMPS", map[string]interface{}{"fmps_rating": "0.2"}, 1},
+		{"invalid", map[string]interface{}{"rating": "great"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track := domain.MusicTrack{Metadata: &domain.TrackMetadata{}}
+			applyRawTags(&track, tt.raw)
+			assert.Equal(t, tt.want, track.Metadata.Rating)
+		})
+	}
+}
+
+// testMP4TextItem builds an item list entry with a UTF-8 data atom.
+func testMP4TextItem(name, value string) []byte {
+	header := binary.BigEndian.AppendUint32(nil, 1) // Version 0, type 1 (UTF-8)
+	return testMP4Atom(name, testMP4Atom("data", header, make([]byte, 4), []byte(value)))
+}
+
+func TestReadMP4SortTags(t *testing.T) {
+	ilst := testMP4Atom("ilst",
+		testMP4TextItem("\xa9nam", "The Song"),
+		testMP4TextItem("sonm", "Song, The"),
+		testMP4TextItem("soar", "Artist, The"),
+	)
+	meta := testMP4Atom("meta", make([]byte, 4), testMP4Atom("hdlr", make([]byte, 25)), ilst)
+	data := bytes.Join([][]byte{
+		testMP4Atom("ftyp", []byte("M4A "), make([]byte, 4)),
+		testMP4Atom("moov", testMP4Atom("mvhd", make([]byte, 100)), testMP4Atom("udta", meta)),
+		testMP4Atom("mdat", make([]byte, 100)),
+	}, nil)
+	path := writeContainerTestFile(t, "song.m4a", data)
+
+	tags, err := readMP4SortTags(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"sonm": "Song, The", "soar": "Artist, The"}, tags)
+
+	_, err = readMP4SortTags(writeContainerTestFile(t, "bad.m4a", []byte("\x00\x00\x00\x04ftyp")))
+	assert.Error(t, err)
+}
+
+// testID3v23Frame builds an ID3v2.3 frame with an encoding byte followed by text.
+func testID3v23Frame(id string, encoding byte, text []byte) []byte {
+	frame := []byte(id)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(1+len(text)))
+	frame = append(frame, 0, 0, encoding)
+	return append(frame, text...)
+}
+
+// testUTF16Text encodes text as UTF-16 with a little-endian byte order mark.
+func testUTF16Text(text string) []byte {
+	b := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		b = binary.LittleEndian.AppendUint16(b, unit)
+	}
+	return append(b, 0, 0)
+}
+
+// testID3v23File builds an MP3 file holding only an ID3v2.3 tag with the given frames.
+func testID3v23File(t *testing.T, frames ...[]byte) string {
+	body := append(bytes.Join(frames, nil), make([]byte, 16)...) // Padding
+	size := len(body)
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return writeContainerTestFile(t, "song.mp3", append(append(tag, body...), 0xFF, 0xFB, 0x90, 0x00))
+}
+
+func TestReadID3v2Latin1Frames(t *testing.T) {
+	path := testID3v23File(t,
+		testID3v23Frame("TIT2", 1, testUTF16Text("Title")),
+		testID3v23Frame("TPE1", 0, []byte("Artist")),
+		testID3v23Frame("COMM", 0, []byte("eng\x00Comment")),
+		testID3v23Frame("APIC", 0, []byte("image/png")),
+	)
+
+	frames, err := readID3v2Latin1Frames(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"TPE1": true, "COMM": true}, frames)
+
+	_, err = readID3v2Latin1Frames(writeContainerTestFile(t, "plain.mp3", make([]byte, 64)))
+	assert.Error(t, err)
+}
+
+func TestExtractAudioMetadata_RedecodesOnlyLatin1Frames(t *testing.T) {
+	mojibake := latin1([]byte("Sigur Rós"))
+	path := testID3v23File(t,
+		testID3v23Frame("TIT2", 1, testUTF16Text(mojibake)),
+		testID3v23Frame("TPE1", 0, []byte("Sigur Rós")),
+	)
+
+	track, err := extractAudioMetadata(&domain.MusicTrack{FilePath: path, Metadata: &domain.TrackMetadata{}}, domain.DefaultTagCharset)
+	require.NoError(t, err)
+	assert.Equal(t, mojibake, track.Title, "Unicode frames are kept as written")
+	assert.Equal(t, "Sigur Rós", track.Artist, "Latin-1 frames are re-decoded")
+}
@@ -0,0 +1,140 @@
+// Package bass provides technical metadata extraction for audio files.
+package bass
+
+import (
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// technicalInfo holds the audio properties of a file.
+// Zero values mean the property is unknown.
+type technicalInfo struct {
+	codec      string
+	bitRate    int // kbps
+	sampleRate int // Hz
+	bitDepth   int
+	channels   int
+	duration   time.Duration
+}
+
+// applyTo copies the known properties to the track.
+func (i technicalInfo) applyTo(track *domain.MusicTrack) {
+	if i.codec != "" {
+		track.Metadata.Codec = i.codec
+	}
+	if i.bitRate > 0 {
+		track.Metadata.BitRate = i.bitRate
+	}
+	if i.sampleRate > 0 {
+		track.Metadata.SampleRate = i.sampleRate
+	}
+	if i.bitDepth > 0 {
+		track.Metadata.BitDepth = i.bitDepth
+	}
+	if i.channels > 0 {
+		track.Metadata.Channels = i.channels
+	}
+	if i.duration > 0 {
+		track.Duration = i.duration
+	}
+}
+
+// streamCodecNames maps BASS stream channel types to codec names.
+var streamCodecNames = map[ChannelType]string{
+	ChannelTypeStreamVorbis:   "Vorbis",
+	ChannelTypeStreamMP1:      "MP1",
+	ChannelTypeStreamMP2:      "MP2",
+	ChannelTypeStreamMP3:      "MP3",
+	ChannelTypeStreamAIFF:     "PCM",
+	ChannelTypeStreamWAVPCM:   "PCM",
+	ChannelTypeStreamWAVFloat: "PCM",
+}
+
+// musicCodecNames maps BASS music channel types to tracker format names.
+var musicCodecNames = map[ChannelType]string{
+	ChannelTypeMusicMOD: "MOD",
+	ChannelTypeMusicMTM: "MTM",
+	ChannelTypeMusicS3M: "S3M",
+	ChannelTypeMusicXM:  "XM",
+	ChannelTypeMusicIT:  "IT",
+}
+
+// codecName returns the codec name for a BASS channel type.
+// Returns an empty string for codecs decoded by the operating system (CoreAudio,
+// Media Foundation), whose actual codec BASS does not report.
+func codecName(ctype ChannelType) string {
+	if name, ok := musicCodecNames[ctype&^ChannelTypeMusicMO3]; ok {
+		if ctype&ChannelTypeMusicMO3 != 0 {
+			return "MO3"
+		}
+		return name
+	}
+	if name, ok := streamCodecNames[ctype]; ok {
+		return name
+	}
+	if ctype&ChannelTypeStreamWAV != 0 {
+		return "WAV" // Compressed WAVE codec (e.g., ADPCM)
+	}
+	return ""
+}
+
+// isLosslessChannelType checks if the channel decodes uncompressed PCM,
+// where the original resolution is the source bit depth.
+func isLosslessChannelType(ctype ChannelType) bool {
+	return ctype == ChannelTypeStreamWAVPCM || ctype == ChannelTypeStreamWAVFloat || ctype == ChannelTypeStreamAIFF
+}
+
+// readChannelInfo reads the properties of a loaded BASS channel.
+func readChannelInfo(handle int64) technicalInfo {
+	info := technicalInfo{
+		duration: bassChannelBytes2Seconds(handle, bassChannelGetLength(handle)),
+	}
+
+	channel, err := bassChannelGetInfo(handle)
+	if err != nil {
+		return info
+	}
+	info.codec = codecName(channel.ctype)
+	info.sampleRate = channel.freq
+	info.channels = channel.chans
+
+	if isLosslessChannelType(channel.ctype) {
+		info.bitDepth = channel.origres & 0xFFFF
+		if channel.origres&origResFloat != 0 && info.bitDepth == 0 {
+			info.bitDepth = 32
+		}
+	}
+
+	// BASS reports the average bitrate for VBR files (and 0 for trackers)
+	if bitRate, err := bassChannelGetAttribute(handle, ChannelAttribBITRATE); err == nil && bitRate > 0 {
+		info.bitRate = int(bitRate + 0.5)
+	}
+
+	return info
+}
+
+// readEngineInfo decodes the file's headers with BASS.
+// Prescanning makes the duration exact for VBR MP3 files without a seek table.
+func readEngineInfo(filePath string) (technicalInfo, bool) {
+	handle, err := bassStreamCreateFile(filePath, streamDecodeOnly|streamPreScan)
+	if err != nil {
+		return technicalInfo{}, false
+	}
+	defer bassStreamFree(handle)
+
+	return readChannelInfo(handle), true
+}
+
+// extractTechnicalInfo fills in the codec, bitrate, sample rate, bit depth,
+// channel count and duration of a regular audio file.
+// Container headers are used where they record exact values; other formats are read by BASS.
+func extractTechnicalInfo(track *domain.MusicTrack) {
+	if info, ok := readContainerInfo(track.FilePath); ok {
+		info.applyTo(track)
+		return
+	}
+	if info, ok := readEngineInfo(track.FilePath); ok {
+		info.applyTo(track)
+	}
+}
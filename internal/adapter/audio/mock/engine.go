@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,6 +37,8 @@ type Engine struct {
 	failInitialize bool
 	failLoad       bool
 	failPlay       bool
+	metadataErrors map[string]error  // GetMetadata errors by file path
+	albumArt       map[string][]byte // GetAlbumArt results by file path
 }
 
 // mockTrack represents a loaded track in the mock engine.
@@ -85,6 +88,31 @@ func (m *Engine) SetFailPlay(fail bool) {
 	m.failPlay = fail
 }
 
+// SetMetadataError configures the mock to fail reading metadata of a file (for testing).
+// A nil error removes the failure.
+func (m *Engine) SetMetadataError(filePath string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.metadataErrors == nil {
+		m.metadataErrors = make(map[string]error)
+	}
+	if err == nil {
+		delete(m.metadataErrors, filePath)
+		return
+	}
+	m.metadataErrors[filePath] = err
+}
+
+// SetAlbumArt configures the artwork returned for a file (for testing).
+func (m *Engine) SetAlbumArt(filePath string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.albumArt == nil {
+		m.albumArt = make(map[string][]byte)
+	}
+	m.albumArt[filePath] = data
+}
+
 // Initialize initializes the mock audio engine.
 func (m *Engine) Initialize(device int, frequency int, flags int) error {
 	m.mu.Lock()
@@ -363,12 +391,34 @@ func (m *Engine) GetVolume(handle domain.TrackHandle) (float64, error) {
 	return track.volume, nil
 }
 
+// GetAlbumArt returns the artwork configured with SetAlbumArt, or nil.
+func (m *Engine) GetAlbumArt(track domain.MusicTrack) ([]byte, error) {
+	if track.FilePath == "" {
+		return nil, domain.ErrInvalidFilePath
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.metadataErrors[track.FilePath]; err != nil {
+		return nil, err
+	}
+	return m.albumArt[track.FilePath], nil
+}
+
 // GetMetadata extracts mock metadata from a file path.
 func (m *Engine) GetMetadata(filePath string) (*domain.MusicTrack, error) {
 	if filePath == "" {
 		return nil, domain.ErrInvalidFilePath
 	}
 
+	m.mu.RLock()
+	err := m.metadataErrors[filePath]
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
 	// Extract filename for mock metadata
 	filename := filepath.Base(filePath)
 	ext := filepath.Ext(filename)
@@ -388,8 +438,10 @@ func (m *Engine) GetMetadata(filePath string) (*domain.MusicTrack, error) {
 			Composer:   "Mock Composer",
 			Genre:      "Mock Genre",
 			Year:       2024,
+			Codec:      strings.ToUpper(strings.TrimPrefix(ext, ".")),
 			BitRate:    320,
 			SampleRate: 44100,
+			Channels:   2,
 		},
 	}
 
@@ -0,0 +1,113 @@
+// Package lyrics provides parsing of LRC lyrics files.
+package lyrics
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// lrcTimestamp matches a line timestamp: [mm:ss], [mm:ss.xx] or [mm:ss:xx].
+var lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+// lrcTag matches an ID tag such as [ar:Artist] or [offset:+500].
+var lrcTag = regexp.MustCompile(`^\[([a-zA-Z#]+):([^\]]*)\]\s*$`)
+
+// lrcWordTimestamp matches the word timestamps of enhanced LRC (<mm:ss.xx>).
+var lrcWordTimestamp = regexp.MustCompile(`<\d+:\d{1,2}(?:[.:]\d{1,3})?>`)
+
+// parseLRC parses lyrics in LRC format.
+// Lines with several timestamps are repeated at each time, and the [offset:]
+// tag is applied. Text without any timestamps is returned as unsynced lyrics.
+// Returns nil if the text contains no lyrics.
+func parseLRC(text string) *domain.Lyrics {
+	text = strings.TrimPrefix(text, "\ufeff")
+	rawLines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var offset time.Duration
+	synced := make([]domain.LyricsLine, 0)
+	plain := make([]domain.LyricsLine, 0, len(rawLines))
+
+	for _, raw := range rawLines {
+		line := strings.TrimSpace(raw)
+
+		if tag := lrcTag.FindStringSubmatch(line); tag != nil {
+			if strings.EqualFold(tag[1], "offset") {
+				if ms, err := strconv.Atoi(strings.TrimSpace(tag[2])); err == nil {
+					offset = time.Duration(ms) * time.Millisecond
+				}
+			}
+			continue
+		}
+
+		times := make([]time.Duration, 0, 1)
+		for {
+			match := lrcTimestamp.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			times = append(times, parseLRCTime(match[1], match[2], match[3]))
+			line = line[len(match[0]):]
+		}
+
+		if len(times) == 0 {
+			plain = append(plain, domain.LyricsLine{Text: line})
+			continue
+		}
+
+		text := strings.TrimSpace(lrcWordTimestamp.ReplaceAllString(line, ""))
+		for _, t := range times {
+			synced = append(synced, domain.LyricsLine{Time: t, Text: text})
+		}
+	}
+
+	if len(synced) > 0 {
+		for i := range synced {
+			// A positive offset makes the lyrics appear sooner
+			synced[i].Time = max(synced[i].Time-offset, 0)
+		}
+		slices.SortStableFunc(synced, func(a, b domain.LyricsLine) int {
+			return cmp.Compare(a.Time, b.Time)
+		})
+		return &domain.Lyrics{Lines: synced, Synced: true}
+	}
+
+	plain = trimBlankLines(plain)
+	if len(plain) == 0 {
+		return nil
+	}
+	return &domain.Lyrics{Lines: plain}
+}
+
+// parseLRCTime converts the minutes, seconds and fraction of a timestamp.
+// The fraction is read as decimal digits, so "5" is 500 ms and "05" is 50 ms.
+func parseLRCTime(minutes, seconds, fraction string) time.Duration {
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	t := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	if fraction != "" {
+		f, _ := strconv.Atoi(fraction)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		t += time.Duration(f) * time.Millisecond
+	}
+	return t
+}
+
+// trimBlankLines removes blank lines from the start and end of the lyrics.
+func trimBlankLines(lines []domain.LyricsLine) []domain.LyricsLine {
+	for len(lines) > 0 && lines[0].Text == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1].Text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,51 @@
+package lyrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestParseLRC_Synced(t *testing.T) {
+	text := "\ufeff[ar:Artist]\r\n[ti:Title]\r\n" +
+		"[00:12.5]First line\r\n" +
+		"[00:05.20][01:00.00]Chorus\r\n" +
+		"[00:20:30]<00:20.30>Word <00:21.00>timing\r\n" +
+		"[00:25.123]\r\n" +
+		"Untimed lines are ignored\r\n"
+
+	lyrics := parseLRC(text)
+	require.NotNil(t, lyrics)
+	assert.True(t, lyrics.Synced)
+	assert.Equal(t, []domain.LyricsLine{
+		{Time: 5200 * time.Millisecond, Text: "Chorus"},
+		{Time: 12500 * time.Millisecond, Text: "First line"},
+		{Time: 20300 * time.Millisecond, Text: "Word timing"},
+		{Time: 25123 * time.Millisecond, Text: ""},
+		{Time: time.Minute, Text: "Chorus"},
+	}, lyrics.Lines)
+}
+
+func TestParseLRC_Offset(t *testing.T) {
+	lyrics := parseLRC("[offset:+500]\n[00:00.20]Start\n[00:02.00]Later")
+	require.NotNil(t, lyrics)
+	assert.Equal(t, time.Duration(0), lyrics.Lines[0].Time, "Times are clamped at zero")
+	assert.Equal(t, 1500*time.Millisecond, lyrics.Lines[1].Time)
+
+	lyrics = parseLRC("[offset:-250]\n[00:01.00]Line")
+	require.NotNil(t, lyrics)
+	assert.Equal(t, 1250*time.Millisecond, lyrics.Lines[0].Time)
+}
+
+func TestParseLRC_Plain(t *testing.T) {
+	lyrics := parseLRC("\n\nFirst verse\n\nSecond verse\n\n")
+	require.NotNil(t, lyrics)
+	assert.False(t, lyrics.Synced)
+	assert.Equal(t, []domain.LyricsLine{{Text: "First verse"}, {Text: ""}, {Text: "Second verse"}}, lyrics.Lines)
+
+	assert.Nil(t, parseLRC(""))
+	assert.Nil(t, parseLRC("[ar:Only tags]\n\n"))
+}
@@ -0,0 +1,106 @@
+// Package lyrics reads song lyrics from sidecar .lrc files and embedded tags.
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhowden/tag"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// maxSidecarSize is the largest .lrc file that is read.
+const maxSidecarSize = 1 << 20
+
+// sidecarExtensions lists the extensions of sidecar lyrics files, in order of preference.
+var sidecarExtensions = []string{".lrc", ".LRC"}
+
+// syltFrames lists the raw tag names of ID3v2.3/2.4 and ID3v2.2 synchronised lyrics.
+var syltFrames = []string{"SYLT", "SLT"}
+
+// Reader implements ports.LyricsReader.
+// Lyrics are looked up in this order: a sidecar .lrc file with the same name
+// as the audio file, synchronised lyrics (SYLT), then the lyrics tag (USLT,
+// LYRICS or ©lyr), which is parsed as LRC if it has timestamps.
+//
+// Thread-safe: The reader has no state.
+type Reader struct{}
+
+// NewReader creates a new lyrics reader.
+func NewReader() *Reader {
+	return &Reader{}
+}
+
+// ReadLyrics returns the lyrics of the audio file.
+func (r *Reader) ReadLyrics(filePath string) (*domain.Lyrics, error) {
+	if filePath == "" {
+		return nil, domain.ErrInvalidFilePath
+	}
+
+	if lyrics := readSidecar(filePath); lyrics != nil {
+		lyrics.Source = domain.LyricsSourceSidecar
+		return lyrics, nil
+	}
+
+	if lyrics := readEmbedded(filePath); lyrics != nil {
+		lyrics.Source = domain.LyricsSourceEmbedded
+		return lyrics, nil
+	}
+
+	return nil, domain.ErrLyricsNotFound
+}
+
+// readSidecar parses the .lrc file next to the audio file, if there is one.
+func readSidecar(filePath string) *domain.Lyrics {
+	base := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	for _, ext := range sidecarExtensions {
+		info, err := os.Stat(base + ext)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxSidecarSize {
+			continue
+		}
+		data, err := os.ReadFile(base + ext)
+		if err != nil {
+			continue
+		}
+		if lyrics := parseLRC(string(data)); lyrics != nil {
+			return lyrics
+		}
+	}
+	return nil
+}
+
+// readEmbedded reads the lyrics stored in the tags of the audio file.
+func readEmbedded(filePath string) *domain.Lyrics {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	metadata, err := tag.ReadFrom(file)
+	if err != nil || metadata == nil {
+		return nil
+	}
+
+	raw := metadata.Raw()
+	for _, name := range syltFrames {
+		if data, ok := raw[name].([]byte); ok {
+			if lyrics := parseSYLT(data); lyrics != nil {
+				return lyrics
+			}
+		}
+	}
+
+	text := metadata.Lyrics()
+	if text == "" {
+		// Vorbis comments written by some taggers
+		text, _ = raw["unsyncedlyrics"].(string)
+	}
+	return parseLRC(text)
+}
+
+// Verify that Reader implements the LyricsReader interface
+var _ ports.LyricsReader = (*Reader)(nil)
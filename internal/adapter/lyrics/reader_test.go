@@ -0,0 +1,89 @@
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// syncsafe encodes a size as a 28-bit ID3v2 syncsafe integer.
+func syncsafe(size int) []byte {
+	return []byte{byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+}
+
+// testID3File writes an MP3 file whose ID3v2.4 tag contains the given frames.
+func testID3File(t *testing.T, dir string, frames map[string][]byte) string {
+	var body []byte
+	for name, data := range frames {
+		body = append(body, name...)
+		body = append(body, syncsafe(len(data))...)
+		body = append(body, 0, 0)
+		body = append(body, data...)
+	}
+
+	data := append([]byte("ID3\x04\x00\x00"), syncsafe(len(body))...)
+	data = append(data, body...)
+	data = append(data, make([]byte, 256)...) // Stand-in for the audio
+
+	path := filepath.Join(dir, "song.mp3")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestReader_Sidecar(t *testing.T) {
+	dir := t.TempDir()
+	path := testID3File(t, dir, map[string][]byte{
+		"USLT": []byte("\x03eng\x00Embedded lyrics"),
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "song.lrc"), []byte("[00:01.00]Sidecar line"), 0600))
+
+	lyrics, err := NewReader().ReadLyrics(path)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LyricsSourceSidecar, lyrics.Source)
+	assert.True(t, lyrics.Synced)
+	assert.Equal(t, []domain.LyricsLine{{Time: time.Second, Text: "Sidecar line"}}, lyrics.Lines)
+}
+
+func TestReader_EmbeddedUnsynced(t *testing.T) {
+	path := testID3File(t, t.TempDir(), map[string][]byte{
+		"USLT": []byte("\x03eng\x00First line\nSecond line"),
+	})
+
+	lyrics, err := NewReader().ReadLyrics(path)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LyricsSourceEmbedded, lyrics.Source)
+	assert.False(t, lyrics.Synced)
+	assert.Equal(t, []domain.LyricsLine{{Text: "First line"}, {Text: "Second line"}}, lyrics.Lines)
+}
+
+func TestReader_EmbeddedSyncedPreferred(t *testing.T) {
+	path := testID3File(t, t.TempDir(), map[string][]byte{
+		"USLT": []byte("\x03eng\x00Plain"),
+		"SYLT": testSYLT(encodingUTF8, syltTimestampMilliseconds, []byte{0}, []byte("Synced\x00"), 500),
+	})
+
+	lyrics, err := NewReader().ReadLyrics(path)
+	require.NoError(t, err)
+	assert.True(t, lyrics.Synced)
+	assert.Equal(t, "Synced", lyrics.Lines[0].Text)
+}
+
+func TestReader_NotFound(t *testing.T) {
+	path := testID3File(t, t.TempDir(), map[string][]byte{
+		"TIT2": []byte("\x03Title"),
+	})
+
+	_, err := NewReader().ReadLyrics(path)
+	assert.ErrorIs(t, err, domain.ErrLyricsNotFound)
+
+	_, err = NewReader().ReadLyrics(filepath.Join(t.TempDir(), "missing.mp3"))
+	assert.ErrorIs(t, err, domain.ErrLyricsNotFound)
+
+	_, err = NewReader().ReadLyrics("")
+	assert.ErrorIs(t, err, domain.ErrInvalidFilePath)
+}
@@ -0,0 +1,121 @@
+// Package lyrics provides parsing of ID3v2 synchronised lyrics (SYLT) frames.
+package lyrics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// ID3v2 text encodings.
+const (
+	encodingISO88591 = 0
+	encodingUTF16    = 1 // With byte order mark
+	encodingUTF16BE  = 2
+	encodingUTF8     = 3
+)
+
+// syltTimestampMilliseconds is the SYLT time stamp format for absolute milliseconds.
+// The other format (MPEG frames) cannot be converted without decoding the audio.
+const syltTimestampMilliseconds = 2
+
+// parseSYLT parses the body of a SYLT frame:
+// encoding (1), language (3), time stamp format (1), content type (1),
+// content descriptor (terminated string), then text (terminated) and
+// 32-bit time stamp pairs.
+// Returns nil if the frame is malformed or uses MPEG frame time stamps.
+func parseSYLT(data []byte) *domain.Lyrics {
+	if len(data) < 6 || data[4] != syltTimestampMilliseconds {
+		return nil
+	}
+	encoding := data[0]
+
+	_, rest, ok := readTerminatedText(data[6:], encoding)
+	if !ok {
+		return nil
+	}
+
+	lines := make([]domain.LyricsLine, 0)
+	for len(rest) > 0 {
+		var text string
+		text, rest, ok = readTerminatedText(rest, encoding)
+		if !ok || len(rest) < 4 {
+			break
+		}
+		ms := binary.BigEndian.Uint32(rest[:4])
+		rest = rest[4:]
+
+		// Entries often start with a newline to mark the start of a line
+		lines = append(lines, domain.LyricsLine{
+			Time: time.Duration(ms) * time.Millisecond,
+			Text: strings.TrimSpace(text),
+		})
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return &domain.Lyrics{Lines: lines, Synced: true}
+}
+
+// readTerminatedText reads a string terminated by a null character in the
+// given encoding and returns the rest of the data.
+func readTerminatedText(data []byte, encoding byte) (string, []byte, bool) {
+	switch encoding {
+	case encodingISO88591, encodingUTF8:
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return "", nil, false
+		}
+		if encoding == encodingUTF8 {
+			return string(data[:end]), data[end+1:], true
+		}
+		runes := make([]rune, end)
+		for i, b := range data[:end] {
+			runes[i] = rune(b)
+		}
+		return string(runes), data[end+1:], true
+
+	case encodingUTF16, encodingUTF16BE:
+		end := -1
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return "", nil, false
+		}
+		return decodeUTF16(data[:end], encoding == encodingUTF16BE), data[end+2:], true
+
+	default:
+		return "", nil, false
+	}
+}
+
+// decodeUTF16 decodes UTF-16 text, using the byte order mark if there is one.
+func decodeUTF16(data []byte, bigEndian bool) string {
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFE && data[1] == 0xFF:
+			bigEndian, data = true, data[2:]
+		case data[0] == 0xFF && data[1] == 0xFE:
+			bigEndian, data = false, data[2:]
+		}
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = binary.BigEndian.Uint16(data[2*i:])
+		} else {
+			units[i] = binary.LittleEndian.Uint16(data[2*i:])
+		}
+	}
+	return string(utf16.Decode(units))
+}
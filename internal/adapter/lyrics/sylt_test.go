@@ -0,0 +1,60 @@
+package lyrics
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// testSYLT builds a SYLT frame body from text and millisecond pairs.
+func testSYLT(encoding, format byte, descriptor []byte, entries ...interface{}) []byte {
+	data := append([]byte{encoding}, "eng"...)
+	data = append(data, format, 1)
+	data = append(data, descriptor...)
+	for i := 0; i+1 < len(entries); i += 2 {
+		data = append(data, entries[i].([]byte)...)
+		data = binary.BigEndian.AppendUint32(data, uint32(entries[i+1].(int)))
+	}
+	return data
+}
+
+func TestParseSYLT_UTF8(t *testing.T) {
+	data := testSYLT(encodingUTF8, syltTimestampMilliseconds, []byte("desc\x00"),
+		[]byte("Hello\x00"), 1000,
+		[]byte("\nWörld\x00"), 2500,
+	)
+
+	lyrics := parseSYLT(data)
+	require.NotNil(t, lyrics)
+	assert.True(t, lyrics.Synced)
+	assert.Equal(t, []domain.LyricsLine{
+		{Time: time.Second, Text: "Hello"},
+		{Time: 2500 * time.Millisecond, Text: "Wörld"},
+	}, lyrics.Lines)
+}
+
+func TestParseSYLT_Encodings(t *testing.T) {
+	latin1 := testSYLT(encodingISO88591, syltTimestampMilliseconds, []byte{0}, []byte("Caf\xe9\x00"), 10)
+	lyrics := parseSYLT(latin1)
+	require.NotNil(t, lyrics)
+	assert.Equal(t, "Café", lyrics.Lines[0].Text)
+
+	// UTF-16 with a little-endian byte order mark
+	utf16 := testSYLT(encodingUTF16, syltTimestampMilliseconds, []byte{0xFF, 0xFE, 0, 0},
+		[]byte{0xFF, 0xFE, 'H', 0, 'i', 0, 0, 0}, 20)
+	lyrics = parseSYLT(utf16)
+	require.NotNil(t, lyrics)
+	assert.Equal(t, "Hi", lyrics.Lines[0].Text)
+	assert.Equal(t, 20*time.Millisecond, lyrics.Lines[0].Time)
+}
+
+func TestParseSYLT_Unsupported(t *testing.T) {
+	// MPEG frame time stamps cannot be converted
+	assert.Nil(t, parseSYLT(testSYLT(encodingUTF8, 1, []byte{0}, []byte("Line\x00"), 10)))
+	assert.Nil(t, parseSYLT([]byte{encodingUTF8}))
+	assert.Nil(t, parseSYLT(testSYLT(encodingUTF8, syltTimestampMilliseconds, []byte("no terminator"))))
+}
@@ -0,0 +1,97 @@
+package playlistfile
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// M3U implements ports.PlaylistFileFormat for M3U and M3U8 playlists.
+// Both are read as UTF-8, falling back to Windows-1252 for legacy .m3u files,
+// and written as UTF-8. #EXTINF lines provide the duration and title of the
+// following entry; other comment lines are ignored.
+//
+// Thread-safe: The format has no state.
+type M3U struct{}
+
+// NewM3U creates a new M3U playlist format.
+func NewM3U() *M3U {
+	return &M3U{}
+}
+
+// Extensions returns the file extensions of M3U playlists.
+func (f *M3U) Extensions() []string {
+	return []string{".m3u", ".m3u8"}
+}
+
+// Read parses an M3U playlist file.
+func (f *M3U) Read(filePath string) ([]domain.PlaylistFileEntry, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(filePath)
+	entries := make([]domain.PlaylistFileEntry, 0)
+	var info domain.PlaylistFileEntry // From the last #EXTINF line
+	for i, line := range strings.Split(decodeText(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info = parseExtInf(strings.TrimPrefix(line, "#EXTINF:"))
+		case strings.HasPrefix(line, "#"):
+		default:
+			info.Path = resolveLocation(dir, line)
+			info.Line = i + 1
+			entries = append(entries, info)
+			info = domain.PlaylistFileEntry{}
+		}
+	}
+	return entries, nil
+}
+
+// parseExtInf parses "<seconds> [attributes],<title>". A duration of -1 means unknown.
+func parseExtInf(value string) domain.PlaylistFileEntry {
+	var entry domain.PlaylistFileEntry
+	length, title, _ := strings.Cut(value, ",")
+	entry.Title = strings.TrimSpace(title)
+
+	// Extended players add attributes such as tvg-id="x" after the length
+	if fields := strings.Fields(length); len(fields) > 0 {
+		if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil && seconds > 0 {
+			entry.Duration = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return entry
+}
+
+// Write saves entries as an extended M3U playlist.
+func (f *M3U) Write(filePath string, entries []domain.PlaylistFileEntry, relative bool) error {
+	dir := filepath.Dir(filePath)
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	for _, entry := range entries {
+		seconds := -1
+		if entry.Duration > 0 {
+			seconds = int(math.Round(entry.Duration.Seconds()))
+		}
+		title := strings.ReplaceAll(entry.Title, "\n", " ")
+		fmt.Fprintf(&b, "#EXTINF:%d,%s\n", seconds, title)
+		b.WriteString(writeLocation(dir, entry.Path, relative))
+		b.WriteString("\n")
+	}
+
+	return os.WriteFile(filePath, []byte(b.String()), 0644)
+}
+
+// Verify interface implementation
+var _ ports.PlaylistFileFormat = (*M3U)(nil)
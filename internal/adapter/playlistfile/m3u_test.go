@@ -0,0 +1,94 @@
+package playlistfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestM3U_Read(t *testing.T) {
+	dir := t.TempDir()
+	playlist := filepath.Join(dir, "mix.m3u8")
+	content := "\ufeff#EXTM3U\r\n" +
+		"#EXTINF:215,Pink Floyd - Dogs\r\n" +
+		"Animals/02 Dogs.mp3\r\n" +
+		"\r\n" +
+		"# A comment\r\n" +
+		"#EXTINF:-1 tvg-id=\"radio\",Radio\r\n" +
+		"http://example.com/stream\r\n" +
+		"/music/Other/Song.flac\r\n" +
+		`..\Shared\Track.ogg` + "\r\n"
+	require.NoError(t, os.WriteFile(playlist, []byte(content), 0644))
+
+	entries, err := NewM3U().Read(playlist)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "Animals", "02 Dogs.mp3"), Title: "Pink Floyd - Dogs", Duration: 215 * time.Second, Line: 3},
+		{Path: "http://example.com/stream", Title: "Radio", Line: 7},
+		{Path: filepath.FromSlash("/music/Other/Song.flac"), Line: 8},
+		{Path: filepath.Join(filepath.Dir(dir), "Shared", "Track.ogg"), Line: 9},
+	}, entries)
+}
+
+func TestM3U_Read_LegacyCodepage(t *testing.T) {
+	dir := t.TempDir()
+	playlist := filepath.Join(dir, "old.m3u")
+	require.NoError(t, os.WriteFile(playlist, []byte("#EXTINF:10,Beyonc\xe9\nsong.mp3\n"), 0644))
+
+	entries, err := NewM3U().Read(playlist)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Beyoncé", entries[0].Title)
+}
+
+func TestM3U_Read_FileURL(t *testing.T) {
+	entries, err := readM3U(t, "file:///music/My%20Song.mp3\n")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.FromSlash("/music/My Song.mp3"), entries[0].Path)
+}
+
+func TestM3U_WriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	entries := []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "Album", "01 Intro.mp3"), Title: "Artist - Intro", Duration: 61500 * time.Millisecond},
+		{Path: filepath.Join(dir, "Loose.ogg")},
+	}
+
+	for _, relative := range []bool{false, true} {
+		playlist := filepath.Join(dir, "out.m3u")
+		require.NoError(t, NewM3U().Write(playlist, entries, relative))
+
+		data, err := os.ReadFile(playlist)
+		require.NoError(t, err)
+		if relative {
+			assert.Contains(t, string(data), "\nAlbum/01 Intro.mp3\n")
+		} else {
+			assert.Contains(t, string(data), "\n"+entries[0].Path+"\n")
+		}
+		assert.Contains(t, string(data), "#EXTINF:62,Artist - Intro\n")
+		assert.Contains(t, string(data), "#EXTINF:-1,\n")
+
+		read, err := NewM3U().Read(playlist)
+		require.NoError(t, err)
+		require.Len(t, read, 2)
+		assert.Equal(t, entries[0].Path, read[0].Path)
+		assert.Equal(t, "Artist - Intro", read[0].Title)
+		assert.Equal(t, 62*time.Second, read[0].Duration)
+		assert.Equal(t, entries[1].Path, read[1].Path)
+	}
+}
+
+// readM3U writes an M3U file with the given content and reads it back.
+func readM3U(t *testing.T, content string) ([]domain.PlaylistFileEntry, error) {
+	t.Helper()
+	playlist := filepath.Join(t.TempDir(), "test.m3u")
+	require.NoError(t, os.WriteFile(playlist, []byte(content), 0644))
+	return NewM3U().Read(playlist)
+}
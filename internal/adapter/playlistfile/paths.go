@@ -0,0 +1,120 @@
+// Package playlistfile reads and writes M3U, PLS and XSPF playlist files.
+package playlistfile
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// resolveLocation turns a location written in a playlist file into an absolute path.
+// Relative paths are resolved against dir; file:// URLs are converted to paths.
+// Other URLs (e.g. streams) are returned as written.
+func resolveLocation(dir, location string) string {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return ""
+	}
+
+	if isURL(location) {
+		u, err := url.Parse(location)
+		if err != nil || !strings.EqualFold(u.Scheme, "file") {
+			return location
+		}
+		location = u.Path
+		if len(location) > 2 && location[0] == '/' && location[2] == ':' {
+			location = location[1:] // file:///C:/Music
+		}
+	}
+
+	// Playlists written on Windows use backslashes
+	if filepath.Separator == '/' {
+		location = strings.ReplaceAll(location, `\`, "/")
+	}
+	location = filepath.FromSlash(location)
+
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(dir, location)
+	}
+	return filepath.Clean(location)
+}
+
+// isURL reports whether a location has a URL scheme such as http:// or file://.
+// Windows drive letters ("C:\") are not schemes.
+func isURL(location string) bool {
+	scheme, _, ok := strings.Cut(location, "://")
+	if !ok || len(scheme) < 2 {
+		return false
+	}
+	for i, r := range scheme {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || !strings.ContainsRune("0123456789+-.", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLocation returns how a track path is written to a playlist file in dir.
+// Relative paths use forward slashes so that the playlist works on other systems;
+// paths that cannot be made relative (e.g. on another drive) are kept absolute.
+func writeLocation(dir, path string, relative bool) string {
+	if !relative || isURL(path) {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// resolveURI turns an XSPF location, which is a URI, into an absolute path.
+// Relative URIs are percent-decoded and resolved against dir.
+func resolveURI(dir, location string) string {
+	location = strings.TrimSpace(location)
+	if !isURL(location) {
+		if unescaped, err := url.PathUnescape(location); err == nil {
+			location = unescaped
+		}
+	}
+	return resolveLocation(dir, location)
+}
+
+// writeURI returns how a track path is written to an XSPF playlist in dir:
+// a percent-encoded relative URI, or a file:// URI for absolute paths.
+func writeURI(dir, path string, relative bool) string {
+	if isURL(path) {
+		return path
+	}
+	location := writeLocation(dir, path, relative)
+	if !filepath.IsAbs(location) {
+		if first, _, _ := strings.Cut(location, "/"); strings.Contains(first, ":") {
+			location = "./" + location // Not a scheme
+		}
+		return (&url.URL{Path: location}).EscapedPath()
+	}
+	location = filepath.ToSlash(location)
+	if !strings.HasPrefix(location, "/") {
+		location = "/" + location // C:/Music
+	}
+	return (&url.URL{Scheme: "file", Path: location}).String()
+}
+
+// decodeText returns the content of a playlist file as UTF-8 without a byte order mark.
+// Files that are not valid UTF-8 were written in the system codepage, which is
+// assumed to be Windows-1252 as on most systems that still write such files.
+func decodeText(data []byte) string {
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	if utf8.ValidString(text) {
+		return text
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().String(text)
+	if err != nil {
+		return text
+	}
+	return decoded
+}
@@ -0,0 +1,123 @@
+package playlistfile
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// PLS implements ports.PlaylistFileFormat for PLS playlists.
+// Entries are numbered FileN, TitleN and LengthN keys in a [playlist] section;
+// keys are matched case-insensitively and entries are returned in number order.
+//
+// Thread-safe: The format has no state.
+type PLS struct{}
+
+// NewPLS creates a new PLS playlist format.
+func NewPLS() *PLS {
+	return &PLS{}
+}
+
+// Extensions returns the file extensions of PLS playlists.
+func (f *PLS) Extensions() []string {
+	return []string{".pls"}
+}
+
+// Read parses a PLS playlist file.
+func (f *PLS) Read(filePath string) ([]domain.PlaylistFileEntry, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(filePath)
+	byNumber := make(map[int]*domain.PlaylistFileEntry)
+	entry := func(number int) *domain.PlaylistFileEntry {
+		if byNumber[number] == nil {
+			byNumber[number] = &domain.PlaylistFileEntry{}
+		}
+		return byNumber[number]
+	}
+
+	for i, line := range strings.Split(decodeText(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue // Section headers, comments and blank lines
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(key, "file"):
+			if number, ok := plsNumber(key, "file"); ok {
+				entry(number).Path = resolveLocation(dir, value)
+				entry(number).Line = i + 1
+			}
+		case strings.HasPrefix(key, "title"):
+			if number, ok := plsNumber(key, "title"); ok {
+				entry(number).Title = value
+			}
+		case strings.HasPrefix(key, "length"):
+			if number, ok := plsNumber(key, "length"); ok {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					entry(number).Duration = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(byNumber))
+	for number, e := range byNumber {
+		if e.Path != "" { // Titles or lengths without a file
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+
+	entries := make([]domain.PlaylistFileEntry, len(numbers))
+	for i, number := range numbers {
+		entries[i] = *byNumber[number]
+	}
+	return entries, nil
+}
+
+// plsNumber returns the entry number of a key such as "file3".
+func plsNumber(key, prefix string) (int, bool) {
+	number, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+	return number, err == nil
+}
+
+// Write saves entries as a version 2 PLS playlist.
+func (f *PLS) Write(filePath string, entries []domain.PlaylistFileEntry, relative bool) error {
+	dir := filepath.Dir(filePath)
+
+	var b strings.Builder
+	b.WriteString("[playlist]\n")
+	for i, entry := range entries {
+		number := i + 1
+		seconds := -1
+		if entry.Duration > 0 {
+			seconds = int(math.Round(entry.Duration.Seconds()))
+		}
+		fmt.Fprintf(&b, "File%d=%s\n", number, writeLocation(dir, entry.Path, relative))
+		if entry.Title != "" {
+			fmt.Fprintf(&b, "Title%d=%s\n", number, strings.ReplaceAll(entry.Title, "\n", " "))
+		}
+		fmt.Fprintf(&b, "Length%d=%d\n", number, seconds)
+	}
+	fmt.Fprintf(&b, "NumberOfEntries=%d\n", len(entries))
+	b.WriteString("Version=2\n")
+
+	return os.WriteFile(filePath, []byte(b.String()), 0644)
+}
+
+// Verify interface implementation
+var _ ports.PlaylistFileFormat = (*PLS)(nil)
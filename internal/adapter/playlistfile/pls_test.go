@@ -0,0 +1,69 @@
+package playlistfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestPLS_Read(t *testing.T) {
+	dir := t.TempDir()
+	playlist := filepath.Join(dir, "mix.pls")
+	content := "[playlist]\r\n" +
+		"File2=http://example.com/stream\r\n" +
+		"Title2=Radio\r\n" +
+		"Length2=-1\r\n" +
+		"file1=Animals/02 Dogs.mp3\r\n" +
+		"title1=Pink Floyd - Dogs\r\n" +
+		"length1=1024\r\n" +
+		"File3=file:///music/My%20Song.flac\r\n" +
+		"Title4=No file\r\n" +
+		"NumberOfEntries=3\r\n" +
+		"Version=2\r\n"
+	require.NoError(t, os.WriteFile(playlist, []byte(content), 0644))
+
+	entries, err := NewPLS().Read(playlist)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "Animals", "02 Dogs.mp3"), Title: "Pink Floyd - Dogs", Duration: 1024 * time.Second, Line: 5},
+		{Path: "http://example.com/stream", Title: "Radio", Line: 2},
+		{Path: filepath.FromSlash("/music/My Song.flac"), Line: 8},
+	}, entries)
+}
+
+func TestPLS_WriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	entries := []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "Album", "01 Intro.mp3"), Title: "Artist - Intro", Duration: 61500 * time.Millisecond},
+		{Path: filepath.Join(dir, "Loose.ogg")},
+	}
+
+	for _, relative := range []bool{false, true} {
+		playlist := filepath.Join(dir, "out.pls")
+		require.NoError(t, NewPLS().Write(playlist, entries, relative))
+
+		data, err := os.ReadFile(playlist)
+		require.NoError(t, err)
+		if relative {
+			assert.Contains(t, string(data), "File1=Album/01 Intro.mp3\n")
+		} else {
+			assert.Contains(t, string(data), "File1="+entries[0].Path+"\n")
+		}
+		assert.Contains(t, string(data), "Title1=Artist - Intro\nLength1=62\n")
+		assert.Contains(t, string(data), "Length2=-1\nNumberOfEntries=2\nVersion=2\n")
+
+		read, err := NewPLS().Read(playlist)
+		require.NoError(t, err)
+		require.Len(t, read, 2)
+		assert.Equal(t, entries[0].Path, read[0].Path)
+		assert.Equal(t, "Artist - Intro", read[0].Title)
+		assert.Equal(t, 62*time.Second, read[0].Duration)
+		assert.Equal(t, entries[1].Path, read[1].Path)
+	}
+}
@@ -0,0 +1,138 @@
+package playlistfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// xspfNamespace is the XML namespace of XSPF version 1.
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+// xspfPlaylist is the document written by XSPF.Write.
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+// xspfTrack is a <track> element. Duration is in milliseconds.
+type xspfTrack struct {
+	Location string `xml:"location,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Title    string `xml:"title,omitempty"`
+	Duration string `xml:"duration,omitempty"`
+}
+
+// XSPF implements ports.PlaylistFileFormat for XSPF (XML Shareable Playlist Format) playlists.
+// Locations are URIs: file:// URIs and relative URIs are converted to paths.
+// A track's creator and title are combined into "Creator - Title".
+//
+// Thread-safe: The format has no state.
+type XSPF struct{}
+
+// NewXSPF creates a new XSPF playlist format.
+func NewXSPF() *XSPF {
+	return &XSPF{}
+}
+
+// Extensions returns the file extensions of XSPF playlists.
+func (f *XSPF) Extensions() []string {
+	return []string{".xspf"}
+}
+
+// Read parses an XSPF playlist file.
+func (f *XSPF) Read(filePath string) ([]domain.PlaylistFileEntry, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(filePath)
+	entries := make([]domain.PlaylistFileEntry, 0)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "track" {
+			continue
+		}
+		line, _ := decoder.InputPos()
+		var track xspfTrack
+		if err := decoder.DecodeElement(&track, &start); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(track.Location) == "" {
+			continue // Tracks identified only by metadata cannot be played
+		}
+		entries = append(entries, xspfEntry(dir, track, line))
+	}
+	return entries, nil
+}
+
+// xspfEntry converts a <track> element to a playlist entry.
+func xspfEntry(dir string, track xspfTrack, line int) domain.PlaylistFileEntry {
+	entry := domain.PlaylistFileEntry{
+		Path: resolveURI(dir, track.Location),
+		Line: line,
+	}
+
+	entry.Title = strings.TrimSpace(track.Title)
+	if creator := strings.TrimSpace(track.Creator); creator != "" && entry.Title != "" {
+		entry.Title = creator + " - " + entry.Title
+	}
+
+	if ms, err := strconv.ParseInt(strings.TrimSpace(track.Duration), 10, 64); err == nil && ms > 0 {
+		entry.Duration = time.Duration(ms) * time.Millisecond
+	}
+	return entry
+}
+
+// Write saves entries as an XSPF version 1 playlist.
+func (f *XSPF) Write(filePath string, entries []domain.PlaylistFileEntry, relative bool) error {
+	dir := filepath.Dir(filePath)
+
+	playlist := xspfPlaylist{
+		Version: "1",
+		XMLNS:   xspfNamespace,
+		Tracks:  make([]xspfTrack, len(entries)),
+	}
+	for i, entry := range entries {
+		playlist.Tracks[i] = xspfTrack{
+			Location: writeURI(dir, entry.Path, relative),
+			Title:    entry.Title,
+		}
+		if entry.Duration > 0 {
+			playlist.Tracks[i].Duration = strconv.FormatInt(entry.Duration.Milliseconds(), 10)
+		}
+	}
+
+	data, err := xml.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// Verify interface implementation
+var _ ports.PlaylistFileFormat = (*XSPF)(nil)
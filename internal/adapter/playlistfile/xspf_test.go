@@ -0,0 +1,86 @@
+package playlistfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestXSPF_Read(t *testing.T) {
+	dir := t.TempDir()
+	playlist := filepath.Join(dir, "mix.xspf")
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title>Mix</title>
+  <trackList>
+    <track>
+      <location>Animals/02%20Dogs.mp3</location>
+      <creator>Pink Floyd</creator>
+      <title>Dogs</title>
+      <duration>1024000</duration>
+    </track>
+    <track>
+      <location>file:///music/My%20Song.flac</location>
+      <title>Song</title>
+    </track>
+    <track>
+      <title>Metadata only</title>
+    </track>
+    <track><location>http://example.com/stream</location></track>
+  </trackList>
+</playlist>
+`
+	require.NoError(t, os.WriteFile(playlist, []byte(content), 0644))
+
+	entries, err := NewXSPF().Read(playlist)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "Animals", "02 Dogs.mp3"), Title: "Pink Floyd - Dogs", Duration: 1024 * time.Second, Line: 5},
+		{Path: filepath.FromSlash("/music/My Song.flac"), Title: "Song", Line: 11},
+		{Path: "http://example.com/stream", Line: 18},
+	}, entries)
+}
+
+func TestXSPF_Read_Invalid(t *testing.T) {
+	playlist := filepath.Join(t.TempDir(), "broken.xspf")
+	require.NoError(t, os.WriteFile(playlist, []byte("<playlist><trackList><track>"), 0644))
+
+	_, err := NewXSPF().Read(playlist)
+	assert.Error(t, err)
+}
+
+func TestXSPF_WriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	entries := []domain.PlaylistFileEntry{
+		{Path: filepath.Join(dir, "My Album", "01 Intro & Outro.mp3"), Title: "Artist - Intro", Duration: 61500 * time.Millisecond},
+		{Path: filepath.Join(dir, "Loose.ogg")},
+	}
+
+	for _, relative := range []bool{false, true} {
+		playlist := filepath.Join(dir, "out.xspf")
+		require.NoError(t, NewXSPF().Write(playlist, entries, relative))
+
+		data, err := os.ReadFile(playlist)
+		require.NoError(t, err)
+		if relative {
+			assert.Contains(t, string(data), "<location>My%20Album/01%20Intro%20&amp;%20Outro.mp3</location>")
+		} else {
+			assert.Contains(t, string(data), "<location>file://")
+		}
+		assert.Contains(t, string(data), "<duration>61500</duration>")
+
+		read, err := NewXSPF().Read(playlist)
+		require.NoError(t, err)
+		require.Len(t, read, 2)
+		assert.Equal(t, entries[0].Path, read[0].Path)
+		assert.Equal(t, "Artist - Intro", read[0].Title)
+		assert.Equal(t, 61500*time.Millisecond, read[0].Duration)
+		assert.Equal(t, entries[1].Path, read[1].Path)
+	}
+}
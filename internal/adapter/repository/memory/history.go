@@ -3,6 +3,7 @@ package memory
 
 import (
 	"encoding/json"
+	"strconv"
 	"sync"
 
 	"fyne.io/fyne/v2"
@@ -10,7 +11,16 @@ import (
 	"github.com/tejashwikalptaru/gotune/internal/ports"
 )
 
+// maxListens is the number of plays kept in the listening history log.
+const maxListens = 5000
+
+// listenChunkSize is the number of plays stored under one preferences key.
+const listenChunkSize = 100
+
 // HistoryRepository implements ports.HistoryRepository using Fyne preferences.
+// The listening history log keeps the most recent maxListens plays. It is stored
+// in numbered chunks of listenChunkSize plays, so that appending a play only
+// rewrites the last chunk; the oldest chunk is removed once it is no longer needed.
 //
 // Fyne preferences automatically use OS-specific app data directories:
 // - macOS: ~/Library/Preferences/com.gotune.app.plist
@@ -21,23 +31,35 @@ import (
 type HistoryRepository struct {
 	prefs fyne.Preferences
 	mu    sync.RWMutex
+
+	// Listening history log limits (see maxListens and listenChunkSize)
+	listenLimit     int
+	listenChunkSize int
 }
 
 // NewHistoryRepository creates a new history repository.
 // The preferences parameter should be obtained from fyne.CurrentApp().Preferences().
 func NewHistoryRepository(prefs fyne.Preferences) *HistoryRepository {
 	return &HistoryRepository{
-		prefs: prefs,
+		prefs:           prefs,
+		listenLimit:     maxListens,
+		listenChunkSize: listenChunkSize,
 	}
 }
 
 // SaveQueue persists the current playback queue.
+// Album artwork is not stored; it is read from the files when displayed.
 func (r *HistoryRepository) SaveQueue(tracks []domain.MusicTrack) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	stored := make([]domain.MusicTrack, len(tracks))
+	for i, track := range tracks {
+		stored[i] = track.WithoutAlbumArt()
+	}
+
 	// Serialize tracks to JSON
-	data, err := json.Marshal(tracks)
+	data, err := json.Marshal(stored)
 	if err != nil {
 		return domain.NewServiceError("HistoryRepository", "SaveQueue", "failed to marshal tracks", err)
 	}
@@ -69,6 +91,47 @@ func (r *HistoryRepository) LoadQueue() ([]domain.MusicTrack, error) {
 	return tracks, nil
 }
 
+// SaveUpNext persists the Up Next tracks.
+// Album artwork is not stored; it is read from the files when displayed.
+func (r *HistoryRepository) SaveUpNext(tracks []domain.MusicTrack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]domain.MusicTrack, len(tracks))
+	for i, track := range tracks {
+		stored[i] = track.WithoutAlbumArt()
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return domain.NewServiceError("HistoryRepository", "SaveUpNext", "failed to marshal tracks", err)
+	}
+
+	r.prefs.SetString("history.up_next", string(data))
+	return nil
+}
+
+// LoadUpNext retrieves the last saved Up Next tracks.
+func (r *HistoryRepository) LoadUpNext() ([]domain.MusicTrack, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data := r.prefs.String("history.up_next")
+	if data == "" {
+		return []domain.MusicTrack{}, nil
+	}
+
+	var tracks []domain.MusicTrack
+	if err := json.Unmarshal([]byte(data), &tracks); err != nil {
+		return nil, domain.NewServiceError("HistoryRepository", "LoadUpNext", "failed to unmarshal tracks", err)
+	}
+	if tracks == nil {
+		tracks = []domain.MusicTrack{}
+	}
+
+	return tracks, nil
+}
+
 // SaveCurrentIndex persists the current track index in the queue.
 func (r *HistoryRepository) SaveCurrentIndex(index int) error {
 	r.mu.Lock()
@@ -96,13 +159,194 @@ func (r *HistoryRepository) LoadCurrentIndex() (int, error) {
 	return storedValue - 1, nil
 }
 
+// SaveShuffleState persists the shuffle mode and the original queue order.
+func (r *HistoryRepository) SaveShuffleState(state domain.ShuffleState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return domain.NewServiceError("HistoryRepository", "SaveShuffleState", "failed to marshal shuffle state", err)
+	}
+
+	r.prefs.SetString("history.shuffle", string(data))
+	return nil
+}
+
+// LoadShuffleState retrieves the last saved shuffle state.
+func (r *HistoryRepository) LoadShuffleState() (domain.ShuffleState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data := r.prefs.String("history.shuffle")
+	if data == "" {
+		// No saved state - the queue is not shuffled
+		return domain.ShuffleState{}, nil
+	}
+
+	var state domain.ShuffleState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return domain.ShuffleState{}, domain.NewServiceError("HistoryRepository", "LoadShuffleState", "failed to unmarshal shuffle state", err)
+	}
+
+	return state, nil
+}
+
+// AppendListen adds a play to the end of the listening history log,
+// dropping the oldest plays beyond maxListens.
+func (r *HistoryRepository) AppendListen(entry domain.ListeningEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, count := r.listenChunks()
+	chunk := first + count - 1
+	var entries []domain.ListeningEntry
+	if count > 0 {
+		var err error
+		if entries, err = r.loadListenChunk(chunk); err != nil {
+			return err
+		}
+	}
+	if count == 0 || len(entries) >= r.listenChunkSize {
+		// Start a new chunk
+		chunk++
+		count++
+		entries = nil
+	}
+	entries = append(entries, entry)
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return domain.NewServiceError("HistoryRepository", "AppendListen", "failed to marshal listens", err)
+	}
+	r.prefs.SetString(listenChunkKey(chunk), string(data))
+
+	// Remove the oldest chunk once the others hold maxListens plays
+	if (count-1)*r.listenChunkSize >= r.listenLimit {
+		r.prefs.RemoveValue(listenChunkKey(first))
+		first++
+		count--
+	}
+	r.prefs.SetInt("history.listens.first", first)
+	r.prefs.SetInt("history.listens.chunks", count)
+
+	return nil
+}
+
+// LoadListens retrieves the listening history log, oldest first.
+func (r *HistoryRepository) LoadListens() ([]domain.ListeningEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	first, count := r.listenChunks()
+	entries := []domain.ListeningEntry{}
+	for chunk := first; chunk < first+count; chunk++ {
+		chunkEntries, err := r.loadListenChunk(chunk)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, chunkEntries...)
+	}
+
+	// The oldest chunk may still hold plays beyond maxListens
+	if excess := len(entries) - r.listenLimit; excess > 0 {
+		entries = entries[excess:]
+	}
+
+	return entries, nil
+}
+
+// RelocateListens points the plays of moved files at their new paths.
+// Only the chunks holding such plays are rewritten.
+func (r *HistoryRepository) RelocateListens(paths map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, count := r.listenChunks()
+	for chunk := first; chunk < first+count; chunk++ {
+		entries, err := r.loadListenChunk(chunk)
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for i, entry := range entries {
+			if newPath, ok := paths[entry.FilePath]; ok {
+				entries[i] = entry.Relocated(newPath)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return domain.NewServiceError("HistoryRepository", "RelocateListens", "failed to marshal listens", err)
+		}
+		r.prefs.SetString(listenChunkKey(chunk), string(data))
+	}
+
+	return nil
+}
+
+// ClearListens removes all entries from the listening history log.
+func (r *HistoryRepository) ClearListens() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clearListens()
+	return nil
+}
+
+// listenChunks returns the number of the oldest listening history chunk and the number of chunks.
+// Must be called with lock held.
+func (r *HistoryRepository) listenChunks() (first, count int) {
+	return r.prefs.Int("history.listens.first"), r.prefs.Int("history.listens.chunks")
+}
+
+// listenChunkKey returns the preferences key of a listening history chunk.
+func listenChunkKey(chunk int) string {
+	return "history.listens." + strconv.Itoa(chunk)
+}
+
+// loadListenChunk deserializes a chunk of the listening history log.
+// Must be called with lock held.
+func (r *HistoryRepository) loadListenChunk(chunk int) ([]domain.ListeningEntry, error) {
+	data := r.prefs.String(listenChunkKey(chunk))
+	if data == "" {
+		return nil, nil
+	}
+
+	var entries []domain.ListeningEntry
+	if err := json.Unmarshal([]byte(data), &entries); err != nil {
+		return nil, domain.NewServiceError("HistoryRepository", "loadListenChunk", "failed to unmarshal listens", err)
+	}
+
+	return entries, nil
+}
+
+// clearListens removes all chunks of the listening history log.
+// Must be called with lock held.
+func (r *HistoryRepository) clearListens() {
+	first, count := r.listenChunks()
+	for chunk := first; chunk < first+count; chunk++ {
+		r.prefs.RemoveValue(listenChunkKey(chunk))
+	}
+	r.prefs.RemoveValue("history.listens.first")
+	r.prefs.RemoveValue("history.listens.chunks")
+}
+
 // Clear removes all saved history data.
 func (r *HistoryRepository) Clear() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	r.prefs.RemoveValue("history.queue")
+	r.prefs.RemoveValue("history.up_next")
 	r.prefs.RemoveValue("history.current_index")
+	r.prefs.RemoveValue("history.shuffle")
+	r.clearListens()
 
 	return nil
 }
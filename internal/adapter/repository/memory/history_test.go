@@ -2,6 +2,7 @@ package memory
 
 import (
 	"testing"
+	"time"
 
 	"fyne.io/fyne/v2/test"
 	"github.com/stretchr/testify/assert"
@@ -56,6 +57,52 @@ func TestHistoryRepository_SaveAndLoadQueue(t *testing.T) {
 	assert.Equal(t, "Song 2", loaded[1].Title)
 }
 
+func TestHistoryRepository_SaveQueue_DropsAlbumArt(t *testing.T) {
+	repo := newTestHistoryRepository()
+
+	tracks := []domain.MusicTrack{{
+		ID:       "track1",
+		FilePath: "/music/song1.mp3",
+		Metadata: &domain.TrackMetadata{AlbumArt: []byte("cover image"), CoverPath: "/music/cover.jpg"},
+	}}
+	require.NoError(t, repo.SaveQueue(tracks))
+
+	loaded, err := repo.LoadQueue()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Nil(t, loaded[0].Metadata.AlbumArt)
+	assert.True(t, loaded[0].Metadata.HasAlbumArt)
+	assert.Equal(t, "/music/cover.jpg", loaded[0].Metadata.CoverPath)
+}
+
+func TestHistoryRepository_SaveAndLoadUpNext(t *testing.T) {
+	repo := newTestHistoryRepository()
+
+	upNext, err := repo.LoadUpNext()
+	require.NoError(t, err)
+	assert.Empty(t, upNext)
+
+	tracks := []domain.MusicTrack{
+		{ID: "1", FilePath: "/music/a.mp3", Title: "A"},
+		{ID: "2", FilePath: "/music/b.mp3", Title: "B"},
+	}
+	require.NoError(t, repo.SaveUpNext(tracks))
+
+	upNext, err = repo.LoadUpNext()
+	require.NoError(t, err)
+	require.Len(t, upNext, 2)
+	assert.Equal(t, "/music/b.mp3", upNext[1].FilePath)
+
+	// Kept apart from the queue, but cleared with it
+	queue, err := repo.LoadQueue()
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+	require.NoError(t, repo.Clear())
+	upNext, err = repo.LoadUpNext()
+	require.NoError(t, err)
+	assert.Empty(t, upNext)
+}
+
 func TestHistoryRepository_LoadQueue_Empty(t *testing.T) {
 	repo := newTestHistoryRepository()
 
@@ -161,6 +208,7 @@ func TestHistoryRepository_Clear(t *testing.T) {
 	}
 	repo.SaveQueue(tracks)
 	repo.SaveCurrentIndex(5)
+	repo.SaveShuffleState(domain.ShuffleState{Mode: domain.ShuffleTracks, OriginalOrder: []string{"/music/song1.mp3"}})
 
 	// Clear
 	err := repo.Clear()
@@ -174,6 +222,30 @@ func TestHistoryRepository_Clear(t *testing.T) {
 	index, err := repo.LoadCurrentIndex()
 	require.NoError(t, err)
 	assert.Equal(t, -1, index)
+
+	state, err := repo.LoadShuffleState()
+	require.NoError(t, err)
+	assert.Equal(t, domain.ShuffleOff, state.Mode)
+}
+
+func TestHistoryRepository_SaveAndLoadShuffleState(t *testing.T) {
+	repo := newTestHistoryRepository()
+
+	// Not shuffled by default
+	state, err := repo.LoadShuffleState()
+	require.NoError(t, err)
+	assert.Equal(t, domain.ShuffleOff, state.Mode)
+	assert.Empty(t, state.OriginalOrder)
+
+	saved := domain.ShuffleState{
+		Mode:          domain.ShuffleAlbums,
+		OriginalOrder: []string{"/music/a.mp3", "/music/b.mp3"},
+	}
+	require.NoError(t, repo.SaveShuffleState(saved))
+
+	state, err = repo.LoadShuffleState()
+	require.NoError(t, err)
+	assert.Equal(t, saved, state)
 }
 
 func TestHistoryRepository_SaveLoadCycle(t *testing.T) {
@@ -215,3 +287,95 @@ func TestHistoryRepository_LargQueue(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1000, len(loaded))
 }
+
+func TestHistoryRepository_Listens(t *testing.T) {
+	repo := newTestHistoryRepository()
+
+	listens, err := repo.LoadListens()
+	require.NoError(t, err)
+	assert.Empty(t, listens)
+
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	first := domain.NewListeningEntry(domain.MusicTrack{FilePath: "/music/a.mp3", Title: "A"})
+	first.StartedAt = started
+	first.EndedAt = started.Add(3 * time.Minute)
+	first.Played = 3 * time.Minute
+	first.Completed = true
+	second := domain.NewListeningEntry(domain.MusicTrack{FilePath: "/music/b.mp3", Title: "B"})
+	second.StartedAt = started.Add(3 * time.Minute)
+	second.EndedAt = started.Add(4 * time.Minute)
+	second.Played = time.Minute
+	require.NoError(t, repo.AppendListen(first))
+	require.NoError(t, repo.AppendListen(second))
+
+	listens, err = repo.LoadListens()
+	require.NoError(t, err)
+	require.Len(t, listens, 2)
+	assert.Equal(t, "A", listens[0].Title, "Oldest first")
+	assert.Equal(t, first.TrackID, listens[0].TrackID)
+	assert.True(t, listens[0].Completed)
+	assert.Equal(t, time.Minute, listens[1].Played)
+	assert.True(t, started.Equal(listens[0].StartedAt))
+
+	// The log is kept apart from the queue, but Clear removes it too
+	require.NoError(t, repo.SaveQueue([]domain.MusicTrack{{FilePath: "/music/c.mp3"}}))
+	require.NoError(t, repo.ClearListens())
+	listens, err = repo.LoadListens()
+	require.NoError(t, err)
+	assert.Empty(t, listens)
+	queue, err := repo.LoadQueue()
+	require.NoError(t, err)
+	assert.Len(t, queue, 1)
+
+	require.NoError(t, repo.AppendListen(first))
+	require.NoError(t, repo.Clear())
+	listens, err = repo.LoadListens()
+	require.NoError(t, err)
+	assert.Empty(t, listens)
+}
+
+func TestHistoryRepository_RelocateListens(t *testing.T) {
+	repo := newTestHistoryRepository()
+	repo.listenChunkSize = 2
+
+	for _, path := range []string{"/old/a.mp3", "/music/b.mp3", "/music/c.mp3", "/old/a.mp3"} {
+		require.NoError(t, repo.AppendListen(domain.NewListeningEntry(domain.MusicTrack{FilePath: path})))
+	}
+	require.NoError(t, repo.RelocateListens(map[string]string{"/old/a.mp3": "/new/a.mp3"}))
+
+	listens, err := repo.LoadListens()
+	require.NoError(t, err)
+	require.Len(t, listens, 4)
+	moved := domain.MusicTrack{FilePath: "/new/a.mp3"}
+	for _, i := range []int{0, 3} {
+		assert.Equal(t, moved.FilePath, listens[i].FilePath)
+		assert.Equal(t, moved.StableID(), listens[i].TrackID)
+	}
+	assert.Equal(t, "/music/b.mp3", listens[1].FilePath)
+}
+
+func TestHistoryRepository_ListensBounded(t *testing.T) {
+	repo := newTestHistoryRepository()
+	repo.listenLimit = 5
+	repo.listenChunkSize = 2
+
+	for i := 0; i < 12; i++ {
+		require.NoError(t, repo.AppendListen(domain.ListeningEntry{Played: time.Duration(i)}))
+	}
+
+	listens, err := repo.LoadListens()
+	require.NoError(t, err)
+	require.Len(t, listens, 5)
+	assert.Equal(t, time.Duration(7), listens[0].Played, "The oldest plays are dropped")
+	assert.Equal(t, time.Duration(11), listens[4].Played)
+
+	// Only the chunks still needed are kept
+	assert.Empty(t, repo.prefs.String(listenChunkKey(2)))
+	assert.NotEmpty(t, repo.prefs.String(listenChunkKey(3)))
+
+	require.NoError(t, repo.ClearListens())
+	assert.Empty(t, repo.prefs.String(listenChunkKey(5)))
+	listens, err = repo.LoadListens()
+	require.NoError(t, err)
+	assert.Empty(t, listens)
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// libraryChunkSize is the number of tracks stored under one preferences key.
+const libraryChunkSize = 200
+
+// LibraryRepository implements ports.LibraryRepository using Fyne preferences.
+// The library is stored in numbered chunks of libraryChunkSize tracks
+// ("library.0", "library.1", ...), in the order tracks were first added, so that
+// saving tracks only rewrites the chunks holding them. Album artwork is not stored,
+// as it would make every chunk grow by an image per track; it is read from the
+// files when displayed.
+//
+// Thread-safe: All operations protected by sync.RWMutex.
+type LibraryRepository struct {
+	prefs     fyne.Preferences
+	mu        sync.RWMutex
+	chunkSize int
+}
+
+// NewLibraryRepository creates a new library repository.
+// The preferences parameter should be obtained from fyne.CurrentApp().Preferences().
+func NewLibraryRepository(prefs fyne.Preferences) *LibraryRepository {
+	return &LibraryRepository{
+		prefs:     prefs,
+		chunkSize: libraryChunkSize,
+	}
+}
+
+// SaveTracks adds tracks to the library, replacing entries with the same file path.
+func (r *LibraryRepository) SaveTracks(tracks []domain.MusicTrack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	chunks, err := r.loadChunks()
+	if err != nil {
+		return err
+	}
+
+	// Index existing entries by path so re-scans replace rather than duplicate
+	type position struct{ chunk, index int }
+	positions := make(map[string]position)
+	for c, chunk := range chunks {
+		for i, track := range chunk {
+			positions[track.FilePath] = position{c, i}
+		}
+	}
+
+	changed := make(map[int]bool)
+	for _, track := range tracks {
+		if pos, exists := positions[track.FilePath]; exists {
+			chunks[pos.chunk][pos.index] = track
+			changed[pos.chunk] = true
+			continue
+		}
+		last := len(chunks) - 1
+		if last < 0 || len(chunks[last]) >= r.chunkSize {
+			chunks = append(chunks, nil)
+			last++
+		}
+		positions[track.FilePath] = position{last, len(chunks[last])}
+		chunks[last] = append(chunks[last], track)
+		changed[last] = true
+	}
+
+	return r.saveChunks(chunks, changed)
+}
+
+// ReplaceAll replaces every track in the library with tracks.
+// The new chunks are written over the old ones, and old chunks beyond them are removed afterwards.
+func (r *LibraryRepository) ReplaceAll(tracks []domain.MusicTrack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	oldCount := r.prefs.Int("library.chunks")
+	chunks := make([][]domain.MusicTrack, 0, len(tracks)/r.chunkSize+1)
+	changed := make(map[int]bool)
+	for start := 0; start < len(tracks); start += r.chunkSize {
+		changed[len(chunks)] = true
+		chunks = append(chunks, tracks[start:min(start+r.chunkSize, len(tracks))])
+	}
+
+	if err := r.saveChunks(chunks, changed); err != nil {
+		return err
+	}
+	for chunk := len(chunks); chunk < oldCount; chunk++ {
+		r.prefs.RemoveValue(libraryChunkKey(chunk))
+	}
+	return nil
+}
+
+// LoadAll retrieves every track in the library.
+func (r *LibraryRepository) LoadAll() ([]domain.MusicTrack, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chunks, err := r.loadChunks()
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := []domain.MusicTrack{}
+	for _, chunk := range chunks {
+		tracks = append(tracks, chunk...)
+	}
+	return tracks, nil
+}
+
+// Clear removes all tracks from the library.
+func (r *LibraryRepository) Clear() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.prefs.Int("library.chunks")
+	for chunk := 0; chunk < count; chunk++ {
+		r.prefs.RemoveValue(libraryChunkKey(chunk))
+	}
+	r.prefs.RemoveValue("library.chunks")
+	return nil
+}
+
+// libraryChunkKey returns the preferences key of a library chunk.
+func libraryChunkKey(chunk int) string {
+	return "library." + strconv.Itoa(chunk)
+}
+
+// loadChunks deserializes the stored library chunks.
+// Must be called with lock held.
+func (r *LibraryRepository) loadChunks() ([][]domain.MusicTrack, error) {
+	count := r.prefs.Int("library.chunks")
+	chunks := make([][]domain.MusicTrack, 0, count)
+	for chunk := 0; chunk < count; chunk++ {
+		var tracks []domain.MusicTrack
+		if data := r.prefs.String(libraryChunkKey(chunk)); data != "" {
+			if err := json.Unmarshal([]byte(data), &tracks); err != nil {
+				return nil, domain.NewServiceError("LibraryRepository", "loadChunks", "failed to unmarshal tracks", err)
+			}
+		}
+		chunks = append(chunks, tracks)
+	}
+
+	return chunks, nil
+}
+
+// saveChunks serializes the changed library chunks.
+// Every chunk is marshaled before any is written, so a failure leaves the library as it was.
+// Must be called with lock held.
+func (r *LibraryRepository) saveChunks(chunks [][]domain.MusicTrack, changed map[int]bool) error {
+	data := make(map[int]string, len(changed))
+	for chunk := range changed {
+		stored := make([]domain.MusicTrack, len(chunks[chunk]))
+		for i, track := range chunks[chunk] {
+			stored[i] = track.WithoutAlbumArt()
+		}
+
+		encoded, err := json.Marshal(stored)
+		if err != nil {
+			return domain.NewServiceError("LibraryRepository", "saveChunks", "failed to marshal tracks", err)
+		}
+		data[chunk] = string(encoded)
+	}
+
+	for chunk, encoded := range data {
+		r.prefs.SetString(libraryChunkKey(chunk), encoded)
+	}
+	r.prefs.SetInt("library.chunks", len(chunks))
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.LibraryRepository = (*LibraryRepository)(nil)
@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helper to create a test library repository
+func newTestLibraryRepository() *LibraryRepository {
+	app := test.NewApp()
+	prefs := app.Preferences()
+
+	return NewLibraryRepository(prefs)
+}
+
+func TestLibraryRepository_LoadAll_Empty(t *testing.T) {
+	repo := newTestLibraryRepository()
+
+	tracks, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.NotNil(t, tracks)
+	assert.Empty(t, tracks)
+}
+
+func TestLibraryRepository_SaveAndLoad(t *testing.T) {
+	repo := newTestLibraryRepository()
+
+	tracks := []domain.MusicTrack{
+		{ID: "track1", FilePath: "/music/song1.mp3", Title: "Song 1"},
+		{ID: "track2", FilePath: "/music/song2.flac", Title: "Song 2"},
+	}
+
+	err := repo.SaveTracks(tracks)
+	require.NoError(t, err)
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "Song 1", loaded[0].Title)
+	assert.Equal(t, "/music/song2.flac", loaded[1].FilePath)
+}
+
+func TestLibraryRepository_SaveTracks_ReplacesSamePath(t *testing.T) {
+	repo := newTestLibraryRepository()
+
+	err := repo.SaveTracks([]domain.MusicTrack{
+		{ID: "track1", FilePath: "/music/song1.mp3", Title: "Old Title"},
+		{ID: "track2", FilePath: "/music/song2.mp3", Title: "Song 2"},
+	})
+	require.NoError(t, err)
+
+	// Re-scan the first file with updated metadata and add a new one
+	err = repo.SaveTracks([]domain.MusicTrack{
+		{ID: "track1b", FilePath: "/music/song1.mp3", Title: "New Title"},
+		{ID: "track3", FilePath: "/music/song3.mp3", Title: "Song 3"},
+	})
+	require.NoError(t, err)
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+
+	// Original order is kept; the entry is replaced in place
+	assert.Equal(t, "New Title", loaded[0].Title)
+	assert.Equal(t, "Song 2", loaded[1].Title)
+	assert.Equal(t, "Song 3", loaded[2].Title)
+}
+
+func TestLibraryRepository_SaveTracks_RewritesChangedChunks(t *testing.T) {
+	repo := newTestLibraryRepository()
+	repo.chunkSize = 2
+
+	tracks := make([]domain.MusicTrack, 0)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		tracks = append(tracks, domain.MusicTrack{FilePath: "/music/" + name + ".mp3", Title: name})
+	}
+	require.NoError(t, repo.SaveTracks(tracks))
+	assert.Equal(t, 3, repo.prefs.Int("library.chunks"))
+
+	// Mark the middle chunk to see whether it is rewritten
+	middle := " " + repo.prefs.String(libraryChunkKey(1))
+	repo.prefs.SetString(libraryChunkKey(1), middle)
+
+	require.NoError(t, repo.SaveTracks([]domain.MusicTrack{
+		{FilePath: "/music/a.mp3", Title: "A"},
+		{FilePath: "/music/f.mp3", Title: "f"},
+	}))
+	assert.Equal(t, middle, repo.prefs.String(libraryChunkKey(1)), "Unchanged chunks are not rewritten")
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	titles := make([]string, len(loaded))
+	for i, track := range loaded {
+		titles[i] = track.Title
+	}
+	assert.Equal(t, []string{"A", "b", "c", "d", "e", "f"}, titles)
+
+	require.NoError(t, repo.Clear())
+	assert.Empty(t, repo.prefs.String(libraryChunkKey(0)))
+	loaded, err = repo.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestLibraryRepository_ReplaceAll(t *testing.T) {
+	repo := newTestLibraryRepository()
+	repo.chunkSize = 2
+
+	require.NoError(t, repo.SaveTracks([]domain.MusicTrack{
+		{FilePath: "/music/a.mp3"}, {FilePath: "/music/b.mp3"}, {FilePath: "/music/c.mp3"},
+	}))
+	require.NoError(t, repo.ReplaceAll([]domain.MusicTrack{{FilePath: "/new/a.mp3"}}))
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "/new/a.mp3", loaded[0].FilePath)
+	assert.Empty(t, repo.prefs.String(libraryChunkKey(1)), "Old chunks beyond the new ones are removed")
+}
+
+func TestLibraryRepository_Clear(t *testing.T) {
+	repo := newTestLibraryRepository()
+
+	err := repo.SaveTracks([]domain.MusicTrack{{ID: "track1", FilePath: "/music/song1.mp3"}})
+	require.NoError(t, err)
+
+	err = repo.Clear()
+	require.NoError(t, err)
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestLibraryRepository_SaveTracks_DropsAlbumArt(t *testing.T) {
+	repo := newTestLibraryRepository()
+
+	art := []byte("cover image")
+	track := domain.MusicTrack{
+		ID:       "track1",
+		FilePath: "/music/song1.mp3",
+		Metadata: &domain.TrackMetadata{Genre: "Rock", AlbumArt: art, HasAlbumArt: true},
+	}
+	require.NoError(t, repo.SaveTracks([]domain.MusicTrack{track}))
+
+	// The saved track is not modified
+	assert.Equal(t, art, track.Metadata.AlbumArt)
+
+	loaded, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.NotNil(t, loaded[0].Metadata)
+	assert.Nil(t, loaded[0].Metadata.AlbumArt)
+	assert.True(t, loaded[0].Metadata.HasAlbumArt)
+	assert.Equal(t, "Rock", loaded[0].Metadata.Genre)
+}
@@ -30,12 +30,19 @@ func NewPlaylistRepository(prefs fyne.Preferences, logger *slog.Logger) *Playlis
 }
 
 // Save persists a playlist.
+// Album artwork is not stored; it is read from the files when displayed.
 func (r *PlaylistRepository) Save(playlist *domain.Playlist) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	stored := *playlist
+	stored.Tracks = make([]domain.MusicTrack, len(playlist.Tracks))
+	for i, track := range playlist.Tracks {
+		stored.Tracks[i] = track.WithoutAlbumArt()
+	}
+
 	// Serialize playlist to JSON
-	data, err := json.Marshal(playlist)
+	data, err := json.Marshal(stored)
 	if err != nil {
 		return domain.NewServiceError("PlaylistRepository", "Save", "failed to marshal playlist", err)
 	}
@@ -51,6 +51,28 @@ func TestPlaylistRepository_SaveAndLoad(t *testing.T) {
 	assert.Equal(t, "Song 1", loaded.Tracks[0].Title)
 }
 
+func TestPlaylistRepository_SaveDropsAlbumArt(t *testing.T) {
+	repo := newTestPlaylistRepository()
+
+	playlist := &domain.Playlist{
+		ID:   "playlist1",
+		Name: "Covers",
+		Tracks: []domain.MusicTrack{
+			{ID: "track1", FilePath: "/music/song1.mp3", Metadata: &domain.TrackMetadata{AlbumArt: []byte("cover image")}},
+		},
+	}
+	require.NoError(t, repo.Save(playlist))
+
+	// The saved playlist is not modified
+	assert.Equal(t, []byte("cover image"), playlist.Tracks[0].Metadata.AlbumArt)
+
+	loaded, err := repo.Load("playlist1")
+	require.NoError(t, err)
+	require.Len(t, loaded.Tracks, 1)
+	assert.Nil(t, loaded.Tracks[0].Metadata.AlbumArt)
+	assert.True(t, loaded.Tracks[0].Metadata.HasAlbumArt)
+}
+
 func TestPlaylistRepository_Load_NotFound(t *testing.T) {
 	repo := newTestPlaylistRepository()
 
@@ -325,3 +347,29 @@ func TestPlaylistRepository_SpecialCharactersInName(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Rock & Roll: The 90's \"Best\" of the decade!", loaded.Name)
 }
+
+func TestPlaylistRepository_SmartPlaylist(t *testing.T) {
+	repo := newTestPlaylistRepository()
+
+	minRating := 4.0
+	rules := &domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{
+			{Field: domain.SmartFieldGenre, Value: "Rock"},
+			{Field: domain.SmartFieldRating, Min: &minRating},
+		},
+		SortBy:     domain.SmartSortPlayCount,
+		Descending: true,
+		Limit:      25,
+	}
+	require.NoError(t, repo.Save(&domain.Playlist{ID: "smart", Name: "Top Rock", Smart: rules}))
+	require.NoError(t, repo.Save(&domain.Playlist{ID: "static", Name: "Static"}))
+
+	loaded, err := repo.Load("smart")
+	require.NoError(t, err)
+	require.True(t, loaded.IsSmart())
+	assert.Equal(t, *rules, *loaded.Smart)
+
+	loaded, err = repo.Load("static")
+	require.NoError(t, err)
+	assert.False(t, loaded.IsSmart())
+}
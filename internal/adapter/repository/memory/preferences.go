@@ -44,22 +44,29 @@ func (r *PreferencesRepository) LoadVolume() (float64, error) {
 	return volume, nil
 }
 
-// SaveLoopMode persists the loop mode state.
-func (r *PreferencesRepository) SaveLoopMode(enabled bool) error {
+// SaveRepeatMode persists the repeat mode.
+func (r *PreferencesRepository) SaveRepeatMode(mode domain.RepeatMode) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.prefs.SetBool("preferences.loop", enabled)
+	r.prefs.SetString("preferences.repeat", string(mode))
+	r.prefs.RemoveValue("preferences.loop")
 	return nil
 }
 
-// LoadLoopMode retrieves the saved loop mode state.
-func (r *PreferencesRepository) LoadLoopMode() (bool, error) {
+// LoadRepeatMode retrieves the saved repeat mode.
+// The loop setting of older versions, which repeated the current track, is read as RepeatOne.
+func (r *PreferencesRepository) LoadRepeatMode() (domain.RepeatMode, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	loop := r.prefs.BoolWithFallback("preferences.loop", false)
-	return loop, nil
+	if mode := r.prefs.String("preferences.repeat"); mode != "" {
+		return domain.RepeatMode(mode), nil
+	}
+	if r.prefs.BoolWithFallback("preferences.loop", false) {
+		return domain.RepeatOne, nil
+	}
+	return domain.RepeatOff, nil
 }
 
 // SaveTheme persists the theme preference.
@@ -115,6 +122,74 @@ func (r *PreferencesRepository) LoadScanPaths() ([]string, error) {
 	return paths, nil
 }
 
+// SaveScanOptions persists the exclude patterns and limits of folder scans.
+func (r *PreferencesRepository) SaveScanOptions(options domain.ScanOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return domain.NewServiceError("PreferencesRepository", "SaveScanOptions", "failed to marshal scan options", err)
+	}
+
+	r.prefs.SetString("preferences.scan_options", string(data))
+	return nil
+}
+
+// LoadScanOptions retrieves the saved scan options.
+func (r *PreferencesRepository) LoadScanOptions() (domain.ScanOptions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var options domain.ScanOptions
+	data := r.prefs.String("preferences.scan_options")
+	if data == "" {
+		return options, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &options); err != nil {
+		return domain.ScanOptions{}, domain.NewServiceError("PreferencesRepository", "LoadScanOptions", "failed to unmarshal scan options", err)
+	}
+
+	return options, nil
+}
+
+// SavePlayThreshold persists the fraction of a track that must be heard for it to count as played.
+func (r *PreferencesRepository) SavePlayThreshold(fraction float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prefs.SetFloat("preferences.play_threshold", fraction)
+	return nil
+}
+
+// LoadPlayThreshold retrieves the saved play threshold.
+func (r *PreferencesRepository) LoadPlayThreshold() (float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fraction := r.prefs.FloatWithFallback("preferences.play_threshold", domain.DefaultPlayThreshold)
+	return fraction, nil
+}
+
+// SaveTagCharset persists the fallback charset for legacy tags.
+func (r *PreferencesRepository) SaveTagCharset(charset domain.Charset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prefs.SetString("preferences.tag_charset", string(charset))
+	return nil
+}
+
+// LoadTagCharset retrieves the saved fallback charset for legacy tags.
+func (r *PreferencesRepository) LoadTagCharset() (domain.Charset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	charset := r.prefs.StringWithFallback("preferences.tag_charset", string(domain.DefaultTagCharset))
+	return domain.Charset(charset), nil
+}
+
 // Clear removes all saved preferences.
 func (r *PreferencesRepository) Clear() error {
 	r.mu.Lock()
@@ -122,8 +197,12 @@ func (r *PreferencesRepository) Clear() error {
 
 	r.prefs.RemoveValue("preferences.volume")
 	r.prefs.RemoveValue("preferences.loop")
+	r.prefs.RemoveValue("preferences.repeat")
 	r.prefs.RemoveValue("preferences.theme")
 	r.prefs.RemoveValue("preferences.scan_paths")
+	r.prefs.RemoveValue("preferences.scan_options")
+	r.prefs.RemoveValue("preferences.play_threshold")
+	r.prefs.RemoveValue("preferences.tag_charset")
 
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"fyne.io/fyne/v2/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
 )
 
 // Helper to create a test preferences repository
@@ -58,35 +59,43 @@ func TestPreferencesRepository_SaveVolume_BoundaryValues(t *testing.T) {
 	assert.Equal(t, 1.0, volume)
 }
 
-func TestPreferencesRepository_SaveAndLoadLoopMode(t *testing.T) {
+func TestPreferencesRepository_SaveAndLoadRepeatMode(t *testing.T) {
 	repo := newTestPreferencesRepository()
 
-	// Save loop enabled
-	err := repo.SaveLoopMode(true)
-	require.NoError(t, err)
+	for _, mode := range []domain.RepeatMode{domain.RepeatOne, domain.RepeatAll, domain.RepeatOff} {
+		err := repo.SaveRepeatMode(mode)
+		require.NoError(t, err)
 
-	// Load loop
-	loop, err := repo.LoadLoopMode()
-	require.NoError(t, err)
-	assert.True(t, loop)
+		loaded, err := repo.LoadRepeatMode()
+		require.NoError(t, err)
+		assert.Equal(t, mode, loaded)
+	}
+}
 
-	// Save loop disabled
-	err = repo.SaveLoopMode(false)
-	require.NoError(t, err)
+func TestPreferencesRepository_LoadRepeatMode_Default(t *testing.T) {
+	repo := newTestPreferencesRepository()
 
-	// Load loop
-	loop, err = repo.LoadLoopMode()
+	// Load when nothing saved - should return default (off)
+	mode, err := repo.LoadRepeatMode()
 	require.NoError(t, err)
-	assert.False(t, loop)
+	assert.Equal(t, domain.RepeatOff, mode)
 }
 
-func TestPreferencesRepository_LoadLoopMode_Default(t *testing.T) {
+func TestPreferencesRepository_LoadRepeatMode_LegacyLoop(t *testing.T) {
 	repo := newTestPreferencesRepository()
 
-	// Load when nothing saved - should return default (false)
-	loop, err := repo.LoadLoopMode()
+	// Older versions saved a loop flag that repeated the current track
+	repo.prefs.SetBool("preferences.loop", true)
+
+	mode, err := repo.LoadRepeatMode()
+	require.NoError(t, err)
+	assert.Equal(t, domain.RepeatOne, mode)
+
+	// Saving a repeat mode replaces the legacy flag
+	require.NoError(t, repo.SaveRepeatMode(domain.RepeatOff))
+	mode, err = repo.LoadRepeatMode()
 	require.NoError(t, err)
-	assert.False(t, loop)
+	assert.Equal(t, domain.RepeatOff, mode)
 }
 
 func TestPreferencesRepository_SaveAndLoadTheme(t *testing.T) {
@@ -183,12 +192,52 @@ func TestPreferencesRepository_SaveScanPaths_OverwritesPrevious(t *testing.T) {
 	assert.Equal(t, "/path3", loaded[0])
 }
 
+func TestPreferencesRepository_SaveAndLoadScanOptions(t *testing.T) {
+	repo := newTestPreferencesRepository()
+
+	options := domain.ScanOptions{
+		ExcludePatterns: []string{"*.tmp", "Samples/"},
+		MaxDepth:        3,
+		SkipHidden:      true,
+	}
+	require.NoError(t, repo.SaveScanOptions(options))
+
+	loaded, err := repo.LoadScanOptions()
+	require.NoError(t, err)
+	assert.Equal(t, options, loaded)
+}
+
+func TestPreferencesRepository_LoadScanOptions_Empty(t *testing.T) {
+	repo := newTestPreferencesRepository()
+
+	options, err := repo.LoadScanOptions()
+	require.NoError(t, err)
+	assert.Equal(t, domain.ScanOptions{}, options)
+}
+
+func TestPreferencesRepository_SaveAndLoadTagCharset(t *testing.T) {
+	repo := newTestPreferencesRepository()
+
+	charset, err := repo.LoadTagCharset()
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultTagCharset, charset)
+
+	require.NoError(t, repo.SaveTagCharset(domain.CharsetShiftJIS))
+	charset, err = repo.LoadTagCharset()
+	require.NoError(t, err)
+	assert.Equal(t, domain.CharsetShiftJIS, charset)
+
+	require.NoError(t, repo.Clear())
+	charset, _ = repo.LoadTagCharset()
+	assert.Equal(t, domain.DefaultTagCharset, charset)
+}
+
 func TestPreferencesRepository_Clear(t *testing.T) {
 	repo := newTestPreferencesRepository()
 
 	// Save all preferences
 	repo.SaveVolume(0.5)
-	repo.SaveLoopMode(true)
+	repo.SaveRepeatMode(domain.RepeatAll)
 	repo.SaveTheme("dark")
 	repo.SaveScanPaths([]string{"/music"})
 
@@ -200,8 +249,8 @@ func TestPreferencesRepository_Clear(t *testing.T) {
 	volume, _ := repo.LoadVolume()
 	assert.Equal(t, 1.0, volume) // Default
 
-	loop, _ := repo.LoadLoopMode()
-	assert.False(t, loop) // Default
+	repeat, _ := repo.LoadRepeatMode()
+	assert.Equal(t, domain.RepeatOff, repeat) // Default
 
 	theme, _ := repo.LoadTheme()
 	assert.Equal(t, "system", theme) // Default
@@ -215,7 +264,7 @@ func TestPreferencesRepository_MultiplePreferences(t *testing.T) {
 
 	// Set multiple preferences
 	repo.SaveVolume(0.8)
-	repo.SaveLoopMode(true)
+	repo.SaveRepeatMode(domain.RepeatOne)
 	repo.SaveTheme("light")
 	repo.SaveScanPaths([]string{"/music", "/audio"})
 
@@ -223,8 +272,8 @@ func TestPreferencesRepository_MultiplePreferences(t *testing.T) {
 	volume, _ := repo.LoadVolume()
 	assert.Equal(t, 0.8, volume)
 
-	loop, _ := repo.LoadLoopMode()
-	assert.True(t, loop)
+	repeat, _ := repo.LoadRepeatMode()
+	assert.Equal(t, domain.RepeatOne, repeat)
 
 	theme, _ := repo.LoadTheme()
 	assert.Equal(t, "light", theme)
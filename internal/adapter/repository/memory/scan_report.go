@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"encoding/json"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// ScanReportRepository implements ports.ScanReportRepository using Fyne preferences.
+// The report of the most recent scan is stored as JSON under the "library.scan_report" key.
+//
+// Thread-safe: All operations protected by sync.RWMutex.
+type ScanReportRepository struct {
+	prefs fyne.Preferences
+	mu    sync.RWMutex
+}
+
+// NewScanReportRepository creates a new scan report repository.
+// The preferences parameter should be obtained from fyne.CurrentApp().Preferences().
+func NewScanReportRepository(prefs fyne.Preferences) *ScanReportRepository {
+	return &ScanReportRepository{
+		prefs: prefs,
+	}
+}
+
+// SaveReport persists a scan report, replacing the previous one.
+func (r *ScanReportRepository) SaveReport(report *domain.ScanReport) error {
+	if report == nil {
+		return domain.NewValidationError("report", nil, "cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return domain.NewServiceError("ScanReportRepository", "SaveReport", "failed to marshal report", err)
+	}
+
+	r.prefs.SetString("library.scan_report", string(data))
+	return nil
+}
+
+// LoadReport retrieves the saved scan report, or nil if there is none.
+func (r *ScanReportRepository) LoadReport() (*domain.ScanReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data := r.prefs.String("library.scan_report")
+	if data == "" {
+		return nil, nil
+	}
+
+	var report domain.ScanReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, domain.NewServiceError("ScanReportRepository", "LoadReport", "failed to unmarshal report", err)
+	}
+
+	return &report, nil
+}
+
+// Verify interface implementation
+var _ ports.ScanReportRepository = (*ScanReportRepository)(nil)
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helper to create a test scan report repository
+func newTestScanReportRepository() *ScanReportRepository {
+	app := test.NewApp()
+	return NewScanReportRepository(app.Preferences())
+}
+
+func TestScanReportRepository_LoadReport_Empty(t *testing.T) {
+	repo := newTestScanReportRepository()
+
+	report, err := repo.LoadReport()
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
+
+func TestScanReportRepository_SaveAndLoad(t *testing.T) {
+	repo := newTestScanReportRepository()
+
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	report := &domain.ScanReport{
+		Path:         "/music",
+		StartedAt:    started,
+		CompletedAt:  started.Add(time.Minute),
+		FilesScanned: 3,
+		TracksFound:  2,
+		Failures: []domain.ScanFailure{
+			{Path: "/music/bad.mp3", Reason: domain.ScanFailureCorrupt, Message: "corrupt or invalid file"},
+		},
+	}
+	require.NoError(t, repo.SaveReport(report))
+
+	loaded, err := repo.LoadReport()
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, report.Path, loaded.Path)
+	assert.True(t, report.StartedAt.Equal(loaded.StartedAt))
+	assert.True(t, report.CompletedAt.Equal(loaded.CompletedAt))
+	assert.Equal(t, report.TracksFound, loaded.TracksFound)
+	assert.Equal(t, report.Failures, loaded.Failures)
+}
+
+func TestScanReportRepository_SaveReplacesPrevious(t *testing.T) {
+	repo := newTestScanReportRepository()
+
+	require.NoError(t, repo.SaveReport(&domain.ScanReport{Path: "/first"}))
+	require.NoError(t, repo.SaveReport(&domain.ScanReport{Path: "/second"}))
+
+	loaded, err := repo.LoadReport()
+	require.NoError(t, err)
+	assert.Equal(t, "/second", loaded.Path)
+}
+
+func TestScanReportRepository_SaveNil(t *testing.T) {
+	repo := newTestScanReportRepository()
+
+	assert.Error(t, repo.SaveReport(nil))
+}
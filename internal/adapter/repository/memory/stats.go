@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"encoding/json"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// StatsRepository implements ports.StatsRepository using Fyne preferences.
+// Statistics are stored as a single JSON object keyed by track ID under the "stats.tracks" key.
+//
+// Thread-safe: All operations protected by sync.RWMutex.
+type StatsRepository struct {
+	prefs fyne.Preferences
+	mu    sync.RWMutex
+}
+
+// NewStatsRepository creates a new statistics repository.
+// The preferences parameter should be obtained from fyne.CurrentApp().Preferences().
+func NewStatsRepository(prefs fyne.Preferences) *StatsRepository {
+	return &StatsRepository{
+		prefs: prefs,
+	}
+}
+
+// SaveStats persists track statistics, replacing entries with the same track ID.
+func (r *StatsRepository) SaveStats(stats []domain.TrackStats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	stored, err := r.loadStats()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range stats {
+		if entry.TrackID == "" {
+			return domain.NewValidationError("trackID", entry.FilePath, "cannot be empty")
+		}
+		stored[entry.TrackID] = entry
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return domain.NewServiceError("StatsRepository", "SaveStats", "failed to marshal stats", err)
+	}
+
+	r.prefs.SetString("stats.tracks", string(data))
+	return nil
+}
+
+// LoadStats retrieves the statistics of a track, or nil if it has none.
+func (r *StatsRepository) LoadStats(trackID string) (*domain.TrackStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, err := r.loadStats()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, exists := stored[trackID]
+	if !exists {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// LoadAll retrieves the statistics of every track.
+func (r *StatsRepository) LoadAll() ([]domain.TrackStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, err := r.loadStats()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]domain.TrackStats, 0, len(stored))
+	for _, entry := range stored {
+		stats = append(stats, entry)
+	}
+	return stats, nil
+}
+
+// RemoveStats removes the statistics of the given tracks.
+func (r *StatsRepository) RemoveStats(trackIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.loadStats()
+	if err != nil {
+		return err
+	}
+
+	removed := false
+	for _, trackID := range trackIDs {
+		if _, exists := stored[trackID]; exists {
+			delete(stored, trackID)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return domain.NewServiceError("StatsRepository", "RemoveStats", "failed to marshal stats", err)
+	}
+
+	r.prefs.SetString("stats.tracks", string(data))
+	return nil
+}
+
+// Clear removes all statistics.
+func (r *StatsRepository) Clear() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prefs.RemoveValue("stats.tracks")
+	return nil
+}
+
+// loadStats deserializes the stored statistics.
+// Must be called with lock held.
+func (r *StatsRepository) loadStats() (map[string]domain.TrackStats, error) {
+	data := r.prefs.String("stats.tracks")
+	if data == "" {
+		return make(map[string]domain.TrackStats), nil
+	}
+
+	var stats map[string]domain.TrackStats
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		return nil, domain.NewServiceError("StatsRepository", "loadStats", "failed to unmarshal stats", err)
+	}
+	if stats == nil {
+		stats = make(map[string]domain.TrackStats)
+	}
+
+	return stats, nil
+}
+
+// Verify interface implementation
+var _ ports.StatsRepository = (*StatsRepository)(nil)
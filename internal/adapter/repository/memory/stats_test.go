@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helper to create a test statistics repository
+func newTestStatsRepository() *StatsRepository {
+	app := test.NewApp()
+	return NewStatsRepository(app.Preferences())
+}
+
+func TestStatsRepository_Empty(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	stats, err := repo.LoadStats("missing")
+	require.NoError(t, err)
+	assert.Nil(t, stats)
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestStatsRepository_SaveAndLoad(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	played := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{
+		{TrackID: "a", FilePath: "/music/a.mp3", PlayCount: 2, FirstPlayedAt: played, LastPlayedAt: played},
+		{TrackID: "b", FilePath: "/music/b.mp3", SkipCount: 1},
+	}))
+
+	stats, err := repo.LoadStats("a")
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 2, stats.PlayCount)
+	assert.True(t, played.Equal(stats.LastPlayedAt))
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestStatsRepository_SaveReplacesByTrackID(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{{TrackID: "a", PlayCount: 1}}))
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{{TrackID: "a", PlayCount: 5}}))
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, 5, all[0].PlayCount)
+}
+
+func TestStatsRepository_SaveRequiresTrackID(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	assert.Error(t, repo.SaveStats([]domain.TrackStats{{FilePath: "/music/a.mp3"}}))
+}
+
+func TestStatsRepository_RemoveStats(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{{TrackID: "a"}, {TrackID: "b"}}))
+	require.NoError(t, repo.RemoveStats([]string{"a", "unknown"}))
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "b", all[0].TrackID)
+}
+
+func TestStatsRepository_Clear(t *testing.T) {
+	repo := newTestStatsRepository()
+
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{{TrackID: "a"}}))
+	require.NoError(t, repo.Clear())
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
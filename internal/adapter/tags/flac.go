@@ -0,0 +1,155 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const (
+	// FLAC metadata block types
+	flacBlockStreamInfo    = 0
+	flacBlockPadding       = 1
+	flacBlockVorbisComment = 4
+	flacBlockPicture       = 6
+
+	// flacLastBlockFlag marks the final metadata block
+	flacLastBlockFlag = 0x80
+
+	// flacMaxBlockSize is the largest metadata block body (24-bit length)
+	flacMaxBlockSize = 1<<24 - 1
+
+	// flacPadding is the padding block size written after the metadata
+	flacPadding = 1024
+)
+
+// flacBlock is a raw FLAC metadata block.
+type flacBlock struct {
+	blockType byte
+	data      []byte
+}
+
+// rewriteFLAC rewrites a FLAC file with an updated Vorbis comment block.
+// Other metadata blocks (STREAMINFO, SEEKTABLE, CUESHEET, ...) are kept as-is.
+func rewriteFLAC(src *io.SectionReader, dst io.Writer, edit domain.TagEdit) error {
+	magic := make([]byte, 4)
+	if _, err := src.ReadAt(magic, 0); err != nil || string(magic) != "fLaC" {
+		return errors.New("not a FLAC file (missing 'fLaC' marker)")
+	}
+
+	blocks, audioStart, err := readFLACBlocks(src)
+	if err != nil {
+		return err
+	}
+
+	blocks, err = applyFLACEdit(blocks, edit)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(magic); err != nil {
+		return err
+	}
+	for i, block := range blocks {
+		header := []byte{
+			block.blockType,
+			byte(len(block.data) >> 16), byte(len(block.data) >> 8), byte(len(block.data)),
+		}
+		if i == len(blocks)-1 {
+			header[0] |= flacLastBlockFlag
+		}
+		if _, err := dst.Write(header); err != nil {
+			return err
+		}
+		if _, err := dst.Write(block.data); err != nil {
+			return err
+		}
+	}
+
+	return copyRange(dst, src, audioStart)
+}
+
+// readFLACBlocks reads the metadata blocks and returns the offset of the first audio frame.
+func readFLACBlocks(src *io.SectionReader) ([]flacBlock, int64, error) {
+	blocks := make([]flacBlock, 0)
+	offset := int64(4)
+
+	for {
+		header := make([]byte, 4)
+		if _, err := src.ReadAt(header, offset); err != nil {
+			return nil, 0, fmt.Errorf("failed to read FLAC block header: %w", err)
+		}
+
+		length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		block := flacBlock{blockType: header[0] &^ flacLastBlockFlag, data: make([]byte, length)}
+		if _, err := src.ReadAt(block.data, offset+4); err != nil {
+			return nil, 0, fmt.Errorf("failed to read FLAC block: %w", err)
+		}
+
+		blocks = append(blocks, block)
+		offset += 4 + int64(length)
+
+		if header[0]&flacLastBlockFlag != 0 {
+			break
+		}
+	}
+
+	if len(blocks) == 0 || blocks[0].blockType != flacBlockStreamInfo {
+		return nil, 0, errors.New("FLAC file does not start with a STREAMINFO block")
+	}
+	return blocks, offset, nil
+}
+
+// applyFLACEdit returns the metadata blocks with the edit applied.
+// Existing padding is dropped and a fresh padding block is appended.
+func applyFLACEdit(blocks []flacBlock, edit domain.TagEdit) ([]flacBlock, error) {
+	result := make([]flacBlock, 0, len(blocks)+2)
+	commentIndex := -1
+
+	for _, block := range blocks {
+		switch {
+		case block.blockType == flacBlockPadding:
+			continue
+		case block.blockType == flacBlockPicture && edit.AlbumArt != nil:
+			continue
+		case block.blockType == flacBlockVorbisComment:
+			if commentIndex >= 0 {
+				// Only one comment block is allowed; drop stray duplicates
+				continue
+			}
+			commentIndex = len(result)
+		}
+		result = append(result, block)
+	}
+
+	comment := &vorbisComment{vendor: defaultVendor}
+	if commentIndex >= 0 {
+		parsed, _, err := parseVorbisComment(result[commentIndex].data)
+		if err != nil {
+			return nil, err
+		}
+		comment = parsed
+	} else {
+		// Place a new comment block right after STREAMINFO
+		commentIndex = 1
+		result = append(result[:1], append([]flacBlock{{blockType: flacBlockVorbisComment}}, result[1:]...)...)
+	}
+
+	applyVorbisEdit(comment, edit)
+	result[commentIndex].data = comment.encode()
+
+	if len(edit.AlbumArt) > 0 {
+		picture := flacBlock{blockType: flacBlockPicture, data: encodePictureBlock(edit.AlbumArt)}
+		result = append(result[:commentIndex+1], append([]flacBlock{picture}, result[commentIndex+1:]...)...)
+	}
+
+	for _, block := range result {
+		if len(block.data) > flacMaxBlockSize {
+			return nil, fmt.Errorf("FLAC metadata block of type %d is too large", block.blockType)
+		}
+	}
+
+	return append(result, flacBlock{blockType: flacBlockPadding, data: make([]byte, flacPadding)}), nil
+}
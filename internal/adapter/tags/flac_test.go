@@ -0,0 +1,102 @@
+package tags
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// testFLACAudio stands in for FLAC audio frames following the metadata.
+var testFLACAudio = append([]byte{0xFF, 0xF8, 0x69, 0x08}, bytes.Repeat([]byte("frame"), 100)...)
+
+// buildTestFLAC builds a FLAC file with a STREAMINFO block, the given comments and padding.
+func buildTestFLAC(comments ...string) []byte {
+	var data bytes.Buffer
+	data.WriteString("fLaC")
+
+	writeBlock := func(blockType byte, body []byte, last bool) {
+		if last {
+			blockType |= flacLastBlockFlag
+		}
+		data.Write([]byte{blockType, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))})
+		data.Write(body)
+	}
+
+	writeBlock(flacBlockStreamInfo, bytes.Repeat([]byte{0x42}, 34), false)
+	if len(comments) > 0 {
+		comment := &vorbisComment{vendor: "reference libFLAC 1.4.3", comments: comments}
+		writeBlock(flacBlockVorbisComment, comment.encode(), false)
+	}
+	writeBlock(flacBlockPadding, make([]byte, 64), true)
+
+	data.Write(testFLACAudio)
+	return data.Bytes()
+}
+
+func TestRewriteFLAC_AllFields(t *testing.T) {
+	cover := testCoverJPEG(t)
+	path := writeTestFile(t, "song.flac", buildTestFLAC("TITLE=Old Title", "COMPOSER=Bach"))
+
+	require.NoError(t, NewWriter().WriteTags(path, fullTestEdit(cover)))
+
+	metadata := readTestTags(t, path)
+	assertFullTestEdit(t, metadata, cover)
+	assert.Equal(t, "Bach", metadata.Composer())
+
+	// STREAMINFO stays first and the audio frames are untouched
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, byte(flacBlockStreamInfo), data[4])
+	assert.True(t, bytes.HasSuffix(data, testFLACAudio))
+}
+
+func TestRewriteFLAC_SplitsLegacyTrackTotal(t *testing.T) {
+	path := writeTestFile(t, "song.flac", buildTestFLAC("TRACKNUMBER=3/12", "title=Lower Case Key"))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{TrackNumber: intPtr(5), Title: strPtr("Replaced")}))
+
+	metadata := readTestTags(t, path)
+	trackNumber, trackTotal := metadata.Track()
+	assert.Equal(t, 5, trackNumber)
+	assert.Equal(t, 12, trackTotal)
+	assert.Equal(t, "Replaced", metadata.Title())
+}
+
+func TestRewriteFLAC_AddsCommentBlock(t *testing.T) {
+	path := writeTestFile(t, "song.flac", buildTestFLAC())
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Artist: strPtr("New Artist")}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "New Artist", metadata.Artist())
+	assert.Equal(t, defaultVendor, metadata.Raw()["vendor"])
+}
+
+func TestRewriteFLAC_RemovesPicture(t *testing.T) {
+	path := writeTestFile(t, "song.flac", buildTestFLAC("TITLE=Song"))
+
+	writer := NewWriter()
+	require.NoError(t, writer.WriteTags(path, domain.TagEdit{AlbumArt: testCoverJPEG(t)}))
+	require.NotNil(t, readTestTags(t, path).Picture())
+
+	require.NoError(t, writer.WriteTags(path, domain.TagEdit{AlbumArt: []byte{}}))
+	assert.Nil(t, readTestTags(t, path).Picture())
+	assert.Equal(t, "Song", readTestTags(t, path).Title())
+}
+
+func TestVorbisComment_Set(t *testing.T) {
+	comment := &vorbisComment{comments: []string{"ARTIST=A", "TITLE=T", "artist=B"}}
+
+	comment.set("ARTIST", "C")
+	assert.Equal(t, []string{"ARTIST=C", "TITLE=T"}, comment.comments)
+
+	comment.set("TITLE", "")
+	assert.Equal(t, []string{"ARTIST=C"}, comment.comments)
+
+	comment.set("ALBUM", "X")
+	assert.Equal(t, []string{"ARTIST=C", "ALBUM=X"}, comment.comments)
+}
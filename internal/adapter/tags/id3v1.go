@@ -0,0 +1,151 @@
+package tags
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const (
+	// id3v1Size is the size of the ID3v1 tag at the end of an MP3 file
+	id3v1Size = 128
+
+	// id3v1ExtendedSize is the size of the enhanced ("TAG+") tag that may precede it
+	id3v1ExtendedSize = 227
+
+	// id3v1NoGenre is the genre byte of tags without a standard genre
+	id3v1NoGenre = 0xFF
+)
+
+// ID3v1 field offsets and lengths
+const (
+	id3v1TitleOffset   = 3
+	id3v1ArtistOffset  = 33
+	id3v1AlbumOffset   = 63
+	id3v1YearOffset    = 93
+	id3v1CommentOffset = 97
+	id3v1TrackOffset   = 126 // ID3v1.1: follows a zero byte that ends a shorter comment
+	id3v1GenreOffset   = 127
+
+	id3v1TextLength    = 30
+	id3v1YearLength    = 4
+	id3v1CommentLength = 28 // ID3v1.1, leaving room for the track number
+)
+
+// id3v1Genres lists the genres of the ID3v1 genre byte, with the Winamp extensions.
+var id3v1Genres = [...]string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge",
+	"Hip-Hop", "Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B",
+	"Rap", "Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska",
+	"Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient",
+	"Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance", "Classical",
+	"Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel",
+	"Noise", "AlternRock", "Bass", "Soul", "Punk", "Space", "Meditative",
+	"Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic",
+	"Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk",
+	"Eurodance", "Dream", "Southern Rock", "Comedy", "Cult", "Gangsta",
+	"Top 40", "Christian Rap", "Pop/Funk", "Jungle", "Native American",
+	"Cabaret", "New Wave", "Psychadelic", "Rave", "Showtunes", "Trailer",
+	"Lo-Fi", "Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro",
+	"Musical", "Rock & Roll", "Hard Rock", "Folk", "Folk-Rock",
+	"National Folk", "Swing", "Fast Fusion", "Bebob", "Latin", "Revival",
+	"Celtic", "Bluegrass", "Avantgarde", "Gothic Rock", "Progressive Rock",
+	"Psychedelic Rock", "Symphonic Rock", "Slow Rock", "Big Band",
+	"Chorus", "Easy Listening", "Acoustic", "Humour", "Speech", "Chanson",
+	"Opera", "Chamber Music", "Sonata", "Symphony", "Booty Bass", "Primus",
+	"Porn Groove", "Satire", "Slow Jam", "Club", "Tango", "Samba",
+	"Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle",
+	"Duet", "Punk Rock", "Drum Solo", "Acapella", "Euro-House", "Dance Hall",
+}
+
+// readID3v1 finds the ID3v1 tag at the end of an MP3 file.
+// Returns where the audio ends, before any enhanced tag, and a copy of the
+// tag, or nil if the file has none.
+func readID3v1(src *io.SectionReader) (int64, []byte) {
+	size := src.Size()
+	tag := make([]byte, id3v1Size)
+	if size < id3v1Size {
+		return size, nil
+	}
+	if _, err := src.ReadAt(tag, size-id3v1Size); err != nil || string(tag[:3]) != "TAG" {
+		return size, nil
+	}
+
+	audioEnd := size - id3v1Size
+	if audioEnd >= id3v1ExtendedSize {
+		// The enhanced tag holds longer copies of the fields; it would keep the
+		// old values, so it is dropped
+		marker := make([]byte, 4)
+		if _, err := src.ReadAt(marker, audioEnd-id3v1ExtendedSize); err == nil && string(marker) == "TAG+" {
+			audioEnd -= id3v1ExtendedSize
+		}
+	}
+	return audioEnd, tag
+}
+
+// applyID3v1Edit returns the ID3v1 tag with the edit applied, so that players
+// reading it do not show the old values.
+// Returns nil, to drop the tag, if an edited text cannot be written in Latin-1;
+// the ID3v2 tag then holds the only copy.
+func applyID3v1Edit(tag []byte, edit domain.TagEdit) []byte {
+	tag = bytes.Clone(tag)
+
+	setText := func(offset, length int, value *string) bool {
+		if value == nil {
+			return true
+		}
+		latin1, ok := toLatin1(*value)
+		if !ok {
+			return false
+		}
+		field := tag[offset : offset+length]
+		clear(field)
+		copy(field, latin1)
+		return true
+	}
+
+	// A zero byte before the last comment byte marks an ID3v1.1 track number
+	hasTrack := tag[id3v1TrackOffset-1] == 0 && tag[id3v1TrackOffset] != 0
+	if edit.TrackNumber != nil {
+		hasTrack = *edit.TrackNumber > 0 && *edit.TrackNumber <= 0xFF
+		tag[id3v1TrackOffset-1] = 0
+		tag[id3v1TrackOffset] = 0
+		if hasTrack {
+			tag[id3v1TrackOffset] = byte(*edit.TrackNumber)
+		}
+	}
+	commentLength := id3v1TextLength
+	if hasTrack {
+		commentLength = id3v1CommentLength
+	}
+
+	if !setText(id3v1TitleOffset, id3v1TextLength, edit.Title) ||
+		!setText(id3v1ArtistOffset, id3v1TextLength, edit.Artist) ||
+		!setText(id3v1AlbumOffset, id3v1TextLength, edit.Album) ||
+		!setText(id3v1CommentOffset, commentLength, edit.Comment) {
+		return nil
+	}
+
+	if edit.Year != nil {
+		year := ""
+		if *edit.Year > 0 && *edit.Year <= 9999 {
+			year = strconv.Itoa(*edit.Year)
+		}
+		setText(id3v1YearOffset, id3v1YearLength, &year)
+	}
+
+	if edit.Genre != nil {
+		tag[id3v1GenreOffset] = id3v1NoGenre
+		for i, genre := range id3v1Genres {
+			if strings.EqualFold(genre, strings.TrimSpace(*edit.Genre)) {
+				tag[id3v1GenreOffset] = byte(i)
+				break
+			}
+		}
+	}
+
+	return tag
+}
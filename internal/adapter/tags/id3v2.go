@@ -0,0 +1,460 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const (
+	// id3v2HeaderSize is the size of the ID3v2 tag header (and footer)
+	id3v2HeaderSize = 10
+
+	// id3v2Padding is the padding reserved after the frames of a rewritten tag
+	id3v2Padding = 1024
+
+	// ID3v2 tag header flags
+	id3v2FlagUnsynchronisation = 0x80
+	id3v2FlagExtendedHeader    = 0x40
+	id3v2FlagFooter            = 0x10
+
+	// ID3v2 text encodings
+	id3v2EncodingLatin1 = 0x00
+	id3v2EncodingUTF16  = 0x01
+	id3v2EncodingUTF8   = 0x03
+
+	// id3v2PictureFront is the APIC picture type for the front cover
+	id3v2PictureFront = 0x03
+
+	// id3v2CommentLanguage is the language code written in new COMM frames
+	id3v2CommentLanguage = "eng"
+)
+
+// id3v2Frame is a raw ID3v2.3/2.4 frame. Frames the edit does not touch are
+// written back byte-for-byte.
+type id3v2Frame struct {
+	id    string
+	flags [2]byte
+	data  []byte
+}
+
+// rewriteID3v2 rewrites an MP3 file with an updated ID3v2 tag.
+// Existing ID3v2.3 and 2.4 tags keep their version; files without a tag get an
+// ID3v2.4 tag. ID3v2.2 tags are rejected rather than silently discarded.
+// An ID3v1 tag at the end of the file is updated too (see applyID3v1Edit).
+func rewriteID3v2(src *io.SectionReader, dst io.Writer, edit domain.TagEdit) error {
+	version := byte(4)
+	frames := make([]id3v2Frame, 0)
+	audioStart := int64(0)
+
+	header := make([]byte, id3v2HeaderSize)
+	if n, _ := src.ReadAt(header, 0); n == id3v2HeaderSize && string(header[:3]) == "ID3" {
+		version = header[3]
+		if version != 3 && version != 4 {
+			return fmt.Errorf("ID3v2.%d tags are not supported", version)
+		}
+
+		flags := header[5]
+		size := int64(syncsafeInt(header[6:10]))
+		audioStart = id3v2HeaderSize + size
+		if version == 4 && flags&id3v2FlagFooter != 0 {
+			audioStart += id3v2HeaderSize
+		}
+
+		body := make([]byte, size)
+		if _, err := src.ReadAt(body, id3v2HeaderSize); err != nil {
+			return fmt.Errorf("failed to read ID3v2 tag: %w", err)
+		}
+
+		// ID3v2.3 unsynchronises the whole tag; ID3v2.4 flags each frame instead
+		if version == 3 && flags&id3v2FlagUnsynchronisation != 0 {
+			body = removeUnsynchronisation(body)
+		}
+
+		if flags&id3v2FlagExtendedHeader != 0 {
+			skip, err := id3v2ExtendedHeaderSize(body, version)
+			if err != nil {
+				return err
+			}
+			body = body[skip:]
+		}
+
+		var err error
+		frames, err = parseID3v2Frames(body, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	frames = applyID3v2Edit(frames, version, edit)
+
+	tag, err := encodeID3v2(frames, version)
+	if err != nil {
+		return err
+	}
+	if _, err := dst.Write(tag); err != nil {
+		return err
+	}
+
+	audioEnd, id3v1 := readID3v1(src)
+	if err := copySpan(dst, src, audioStart, audioEnd); err != nil {
+		return err
+	}
+	if id3v1 == nil {
+		return nil
+	}
+	if id3v1 = applyID3v1Edit(id3v1, edit); id3v1 != nil {
+		_, err = dst.Write(id3v1)
+	}
+	return err
+}
+
+// id3v2ExtendedHeaderSize returns the number of bytes taken by the extended header.
+func id3v2ExtendedHeaderSize(body []byte, version byte) (int, error) {
+	if len(body) < 4 {
+		return 0, errors.New("truncated ID3v2 extended header")
+	}
+
+	// ID3v2.3 excludes the size field itself; ID3v2.4 uses a syncsafe size including it
+	size := int(binary.BigEndian.Uint32(body[:4])) + 4
+	if version == 4 {
+		size = int(syncsafeInt(body[:4]))
+	}
+
+	if size > len(body) {
+		return 0, errors.New("ID3v2 extended header overruns the tag")
+	}
+	return size, nil
+}
+
+// parseID3v2Frames splits the tag body into frames, stopping at the padding.
+func parseID3v2Frames(body []byte, version byte) ([]id3v2Frame, error) {
+	frames := make([]id3v2Frame, 0)
+
+	pos := 0
+	for pos+id3v2HeaderSize <= len(body) {
+		id := string(body[pos : pos+4])
+		if !isID3v2FrameID(id) {
+			// Padding (or garbage after the last frame)
+			break
+		}
+
+		var size int
+		if version == 4 {
+			size = int(syncsafeInt(body[pos+4 : pos+8]))
+		} else {
+			size = int(binary.BigEndian.Uint32(body[pos+4 : pos+8]))
+		}
+
+		start := pos + id3v2HeaderSize
+		if size < 0 || start+size > len(body) {
+			return nil, fmt.Errorf("ID3v2 frame %s overruns the tag", id)
+		}
+
+		frame := id3v2Frame{id: id, data: append([]byte(nil), body[start:start+size]...)}
+		copy(frame.flags[:], body[pos+8:pos+10])
+		frames = append(frames, frame)
+
+		pos = start + size
+	}
+
+	return frames, nil
+}
+
+// isID3v2FrameID reports whether id is a valid four-character frame identifier.
+func isID3v2FrameID(id string) bool {
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// applyID3v2Edit returns the frames with the edit applied.
+func applyID3v2Edit(frames []id3v2Frame, version byte, edit domain.TagEdit) []id3v2Frame {
+	setText := func(id string, value *string) {
+		if value != nil {
+			frames = replaceID3v2Frames(frames, isFrame(id), textFrame(id, *value, version), *value == "")
+		}
+	}
+
+	setText("TIT2", edit.Title)
+	setText("TPE1", edit.Artist)
+	setText("TALB", edit.Album)
+	setText("TPE2", edit.AlbumArtist)
+	setText("TCON", edit.Genre)
+
+	if edit.Year != nil {
+		// ID3v2.4 replaced TYER with TDRC; drop whichever does not belong to this version
+		yearID, otherID := "TYER", "TDRC"
+		if version == 4 {
+			yearID, otherID = otherID, yearID
+		}
+		frames = replaceID3v2Frames(frames, isFrame(otherID), id3v2Frame{}, true)
+		frames = replaceID3v2Frames(frames, isFrame(yearID), textFrame(yearID, strconv.Itoa(*edit.Year), version), *edit.Year == 0)
+	}
+
+	setNumber := func(id string, number *int) {
+		if number == nil {
+			return
+		}
+		previous := ""
+		for _, frame := range frames {
+			if frame.id == id {
+				previous = decodeID3v2Text(frame.data)
+				break
+			}
+		}
+		value := numberWithTotal(previous, *number)
+		frames = replaceID3v2Frames(frames, isFrame(id), textFrame(id, value, version), *number == 0)
+	}
+
+	setNumber("TRCK", edit.TrackNumber)
+	setNumber("TPOS", edit.DiscNumber)
+
+	if edit.Comment != nil {
+		// Only the description-less comment is "the" comment; keep iTunes-style
+		// named comments (iTunNORM etc.) intact, but after ours since readers
+		// usually show the first COMM frame
+		frames = replaceID3v2Frames(frames, isPlainComment, id3v2Frame{}, true)
+		if *edit.Comment != "" {
+			frames = insertID3v2Frame(frames, isFrame("COMM"), commentFrame(*edit.Comment, version))
+		}
+	}
+
+	if edit.AlbumArt != nil {
+		frames = replaceID3v2Frames(frames, isFrame("APIC"), pictureFrame(edit.AlbumArt), len(edit.AlbumArt) == 0)
+	}
+
+	return frames
+}
+
+// isFrame returns a matcher for frames with the given identifier.
+func isFrame(id string) func(id3v2Frame) bool {
+	return func(frame id3v2Frame) bool {
+		return frame.id == id
+	}
+}
+
+// isPlainComment matches COMM frames with an empty content description.
+func isPlainComment(frame id3v2Frame) bool {
+	if frame.id != "COMM" || len(frame.data) < 5 {
+		return false
+	}
+
+	desc := frame.data[4:]
+	switch frame.data[0] {
+	case id3v2EncodingUTF16:
+		if len(desc) >= 2 && (desc[0] == 0xFF && desc[1] == 0xFE || desc[0] == 0xFE && desc[1] == 0xFF) {
+			desc = desc[2:]
+		}
+		return len(desc) >= 2 && desc[0] == 0 && desc[1] == 0
+	case 0x02: // UTF-16BE without BOM
+		return len(desc) >= 2 && desc[0] == 0 && desc[1] == 0
+	default:
+		return desc[0] == 0
+	}
+}
+
+// replaceID3v2Frames removes every frame matching the predicate and, unless remove
+// is set, puts the replacement where the first match was (or at the end).
+func replaceID3v2Frames(frames []id3v2Frame, match func(id3v2Frame) bool, replacement id3v2Frame, remove bool) []id3v2Frame {
+	result := make([]id3v2Frame, 0, len(frames)+1)
+	inserted := remove
+
+	for _, frame := range frames {
+		if !match(frame) {
+			result = append(result, frame)
+			continue
+		}
+		if !inserted {
+			result = append(result, replacement)
+			inserted = true
+		}
+	}
+
+	if !inserted {
+		result = append(result, replacement)
+	}
+	return result
+}
+
+// insertID3v2Frame inserts the frame before the first frame matching the predicate,
+// or at the end if none match.
+func insertID3v2Frame(frames []id3v2Frame, match func(id3v2Frame) bool, frame id3v2Frame) []id3v2Frame {
+	for i, existing := range frames {
+		if match(existing) {
+			return append(frames[:i], append([]id3v2Frame{frame}, frames[i:]...)...)
+		}
+	}
+	return append(frames, frame)
+}
+
+// textFrame builds a text information frame.
+func textFrame(id, value string, version byte) id3v2Frame {
+	return id3v2Frame{id: id, data: encodeID3v2Text(value, version, false)}
+}
+
+// commentFrame builds a COMM frame with an empty description.
+func commentFrame(value string, version byte) id3v2Frame {
+	text := encodeID3v2Text(value, version, false)
+
+	data := make([]byte, 0, len(text)+6)
+	data = append(data, text[0])
+	data = append(data, id3v2CommentLanguage...)
+	data = append(data, encodeID3v2Text("", version, true)[1:]...) // Empty, terminated description
+	data = append(data, text[1:]...)
+
+	return id3v2Frame{id: "COMM", data: data}
+}
+
+// pictureFrame builds an APIC frame holding the front cover.
+func pictureFrame(image []byte) id3v2Frame {
+	mimeType := pictureMIMEType(image)
+
+	data := make([]byte, 0, len(image)+len(mimeType)+4)
+	data = append(data, id3v2EncodingLatin1)
+	data = append(data, mimeType...)
+	data = append(data, 0)
+	data = append(data, id3v2PictureFront)
+	data = append(data, 0) // Empty description
+	data = append(data, image...)
+
+	return id3v2Frame{id: "APIC", data: data}
+}
+
+// encodeID3v2Text encodes a string with its leading encoding byte.
+// ID3v2.4 uses UTF-8. ID3v2.3 uses Latin-1 when possible and UTF-16 otherwise.
+// When terminate is set, the string is followed by the encoding's terminator.
+func encodeID3v2Text(value string, version byte, terminate bool) []byte {
+	if version == 4 {
+		data := append([]byte{id3v2EncodingUTF8}, value...)
+		if terminate {
+			data = append(data, 0)
+		}
+		return data
+	}
+
+	if latin1, ok := toLatin1(value); ok {
+		data := append([]byte{id3v2EncodingLatin1}, latin1...)
+		if terminate {
+			data = append(data, 0)
+		}
+		return data
+	}
+
+	units := utf16.Encode([]rune(value))
+	data := make([]byte, 0, 3+len(units)*2+2)
+	data = append(data, id3v2EncodingUTF16, 0xFF, 0xFE)
+	for _, u := range units {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	if terminate {
+		data = append(data, 0, 0)
+	}
+	return data
+}
+
+// decodeID3v2Text decodes the value of a text information frame.
+func decodeID3v2Text(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	text := data[1:]
+	switch data[0] {
+	case id3v2EncodingUTF16, 0x02:
+		order := binary.ByteOrder(binary.BigEndian)
+		if len(text) >= 2 && text[0] == 0xFF && text[1] == 0xFE {
+			order = binary.LittleEndian
+			text = text[2:]
+		} else if len(text) >= 2 && text[0] == 0xFE && text[1] == 0xFF {
+			text = text[2:]
+		}
+		units := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			units = append(units, order.Uint16(text[i:]))
+		}
+		return string(bytes.TrimRight([]byte(string(utf16.Decode(units))), "\x00"))
+	case id3v2EncodingUTF8:
+		return string(bytes.TrimRight(text, "\x00"))
+	default:
+		runes := make([]rune, 0, len(text))
+		for _, b := range bytes.TrimRight(text, "\x00") {
+			runes = append(runes, rune(b))
+		}
+		return string(runes)
+	}
+}
+
+// toLatin1 converts a string to ISO-8859-1, reporting false if it cannot be represented.
+func toLatin1(value string) ([]byte, bool) {
+	latin1 := make([]byte, 0, len(value))
+	for _, r := range value {
+		if r > 0xFF {
+			return nil, false
+		}
+		latin1 = append(latin1, byte(r))
+	}
+	return latin1, true
+}
+
+// encodeID3v2 serializes a complete tag (header, frames and padding).
+func encodeID3v2(frames []id3v2Frame, version byte) ([]byte, error) {
+	var body bytes.Buffer
+	for _, frame := range frames {
+		if len(frame.data) >= 1<<28 {
+			return nil, fmt.Errorf("ID3v2 frame %s is too large", frame.id)
+		}
+
+		body.WriteString(frame.id)
+		if version == 4 {
+			body.Write(syncsafeBytes(uint32(len(frame.data))))
+		} else {
+			_ = binary.Write(&body, binary.BigEndian, uint32(len(frame.data)))
+		}
+		body.Write(frame.flags[:])
+		body.Write(frame.data)
+	}
+	body.Write(make([]byte, id3v2Padding))
+
+	if body.Len() >= 1<<28 {
+		return nil, errors.New("ID3v2 tag is too large")
+	}
+
+	tag := make([]byte, 0, id3v2HeaderSize+body.Len())
+	tag = append(tag, 'I', 'D', '3', version, 0, 0)
+	tag = append(tag, syncsafeBytes(uint32(body.Len()))...)
+	tag = append(tag, body.Bytes()...)
+	return tag, nil
+}
+
+// removeUnsynchronisation reverses ID3v2 unsynchronisation ($FF $00 -> $FF).
+func removeUnsynchronisation(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		result = append(result, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0x00 {
+			i++
+		}
+	}
+	return result
+}
+
+// syncsafeInt decodes a 28-bit syncsafe integer.
+func syncsafeInt(b []byte) uint32 {
+	return uint32(b[0]&0x7F)<<21 | uint32(b[1]&0x7F)<<14 | uint32(b[2]&0x7F)<<7 | uint32(b[3]&0x7F)
+}
+
+// syncsafeBytes encodes a 28-bit syncsafe integer.
+func syncsafeBytes(n uint32) []byte {
+	return []byte{byte(n>>21) & 0x7F, byte(n>>14) & 0x7F, byte(n>>7) & 0x7F, byte(n) & 0x7F}
+}
@@ -0,0 +1,227 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dhowden/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// testMP3Audio stands in for MPEG audio frames following the tag.
+var testMP3Audio = append([]byte{0xFF, 0xFB, 0x90, 0x00}, bytes.Repeat([]byte("audio"), 100)...)
+
+// buildID3v23Tag builds an ID3v2.3 tag from raw frames (id followed by data).
+func buildID3v23Tag(frames ...[2]string) []byte {
+	var body bytes.Buffer
+	for _, frame := range frames {
+		body.WriteString(frame[0])
+		_ = binary.Write(&body, binary.BigEndian, uint32(len(frame[1])))
+		body.Write([]byte{0, 0})
+		body.WriteString(frame[1])
+	}
+	body.Write(make([]byte, 16)) // Padding
+
+	tag := []byte{'I', 'D', '3', 3, 0, 0}
+	tag = append(tag, syncsafeBytes(uint32(body.Len()))...)
+	return append(tag, body.Bytes()...)
+}
+
+func TestRewriteID3v2_NewTag(t *testing.T) {
+	cover := testCoverJPEG(t)
+	path := writeTestFile(t, "song.mp3", testMP3Audio)
+
+	require.NoError(t, NewWriter().WriteTags(path, fullTestEdit(cover)))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, tag.ID3v2_4, metadata.Format())
+	assertFullTestEdit(t, metadata, cover)
+
+	// The audio frames follow the new tag unchanged
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(data, testMP3Audio))
+	assert.Equal(t, id3v2HeaderSize+int(syncsafeInt(data[6:10]))+len(testMP3Audio), len(data))
+}
+
+func TestRewriteID3v2_ExistingTagKeepsOtherFrames(t *testing.T) {
+	original := buildID3v23Tag(
+		[2]string{"TIT2", "\x00Old Title"},
+		[2]string{"TCOM", "\x00Johann Sebastian Bach"},
+		[2]string{"TRCK", "\x003/12"},
+		[2]string{"COMM", "\x00engiTunNORM\x00 0000"},
+		[2]string{"COMM", "\x00eng\x00Old comment"},
+	)
+	path := writeTestFile(t, "song.mp3", append(original, testMP3Audio...))
+
+	edit := domain.TagEdit{
+		Title:       strPtr("Ünïcödé Title"),
+		TrackNumber: intPtr(5),
+		Comment:     strPtr("New comment"),
+	}
+	require.NoError(t, NewWriter().WriteTags(path, edit))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, tag.ID3v2_3, metadata.Format())
+	assert.Equal(t, "Ünïcödé Title", metadata.Title())
+	assert.Equal(t, "Johann Sebastian Bach", metadata.Composer())
+	assert.Equal(t, "New comment", metadata.Comment())
+
+	trackNumber, trackTotal := metadata.Track()
+	assert.Equal(t, 5, trackNumber)
+	assert.Equal(t, 12, trackTotal)
+
+	// The named iTunes comment survives; only the plain comment was replaced
+	raw := metadata.Raw()
+	comments := 0
+	for key, value := range raw {
+		if comm, ok := value.(*tag.Comm); ok && strings.HasPrefix(key, "COMM") {
+			comments++
+			assert.NotEqual(t, "Old comment", comm.Text)
+		}
+	}
+	assert.Equal(t, 2, comments)
+}
+
+func TestRewriteID3v2_UTF16ForNonLatin1(t *testing.T) {
+	original := buildID3v23Tag([2]string{"TIT2", "\x00Old"})
+	path := writeTestFile(t, "song.mp3", append(original, testMP3Audio...))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Artist: strPtr("坂本龍一")}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "坂本龍一", metadata.Artist())
+	assert.Equal(t, "Old", metadata.Title())
+}
+
+func TestRewriteID3v2_ClearFields(t *testing.T) {
+	path := writeTestFile(t, "song.mp3", testMP3Audio)
+	cover := testCoverJPEG(t)
+
+	writer := NewWriter()
+	require.NoError(t, writer.WriteTags(path, fullTestEdit(cover)))
+	require.NoError(t, writer.WriteTags(path, domain.TagEdit{
+		Genre:       strPtr(""),
+		Year:        intPtr(0),
+		TrackNumber: intPtr(0),
+		Comment:     strPtr(""),
+		AlbumArt:    []byte{},
+	}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "New Title", metadata.Title())
+	assert.Empty(t, metadata.Genre())
+	assert.Zero(t, metadata.Year())
+	trackNumber, _ := metadata.Track()
+	assert.Zero(t, trackNumber)
+	assert.Empty(t, metadata.Comment())
+	assert.Nil(t, metadata.Picture())
+}
+
+// buildID3v1Tag builds an ID3v1.1 tag.
+func buildID3v1Tag(title, artist, comment string, track, genre byte) []byte {
+	tag := make([]byte, id3v1Size)
+	copy(tag, "TAG")
+	copy(tag[id3v1TitleOffset:], title)
+	copy(tag[id3v1ArtistOffset:], artist)
+	copy(tag[id3v1AlbumOffset:], "Old Album")
+	copy(tag[id3v1YearOffset:], "1990")
+	copy(tag[id3v1CommentOffset:], comment)
+	tag[id3v1TrackOffset] = track
+	tag[id3v1GenreOffset] = genre
+	return tag
+}
+
+// readTestID3v1 reads the ID3v1 tag at the end of a file.
+func readTestID3v1(t *testing.T, path string) tag.Metadata {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	metadata, err := tag.ReadID3v1Tags(file)
+	require.NoError(t, err)
+	return metadata
+}
+
+func TestRewriteID3v2_UpdatesID3v1(t *testing.T) {
+	extended := append([]byte("TAG+"), bytes.Repeat([]byte("x"), id3v1ExtendedSize-4)...)
+	data := bytes.Join([][]byte{
+		buildID3v23Tag([2]string{"TIT2", "\x00Old Title"}),
+		testMP3Audio,
+		extended,
+		buildID3v1Tag("Old Title", "Old Artist", "Old comment", 3, 8),
+	}, nil)
+	path := writeTestFile(t, "song.mp3", data)
+
+	edit := fullTestEdit(nil)
+	edit.Title = strPtr("A Title Longer Than Thirty Characters")
+	require.NoError(t, NewWriter().WriteTags(path, edit))
+
+	metadata := readTestID3v1(t, path)
+	assert.Equal(t, "A Title Longer Than Thirty Cha", metadata.Title())
+	assert.Equal(t, "New Artist", metadata.Artist())
+	assert.Equal(t, "New Album", metadata.Album())
+	assert.Equal(t, 1959, metadata.Year())
+	assert.Equal(t, "Edited by GoTune", metadata.Comment())
+	trackNumber, _ := metadata.Track()
+	assert.Equal(t, 5, trackNumber)
+	assert.Equal(t, "Jazz", metadata.Genre())
+
+	// The audio is kept and the stale enhanced tag is dropped
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(written[:len(written)-id3v1Size], testMP3Audio))
+	assert.NotContains(t, string(written), "TAG+")
+}
+
+func TestRewriteID3v2_KeepsUneditedID3v1Fields(t *testing.T) {
+	data := append(append([]byte(nil), testMP3Audio...), buildID3v1Tag("Old Title", "Old Artist", "Old comment", 3, 8)...)
+	path := writeTestFile(t, "song.mp3", data)
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("New Title"), Genre: strPtr("Unknown Genre")}))
+
+	metadata := readTestID3v1(t, path)
+	assert.Equal(t, "New Title", metadata.Title())
+	assert.Equal(t, "Old Artist", metadata.Artist())
+	assert.Equal(t, "Old comment", metadata.Comment())
+	trackNumber, _ := metadata.Track()
+	assert.Equal(t, 3, trackNumber)
+	assert.Empty(t, metadata.Genre())
+}
+
+func TestRewriteID3v2_DropsID3v1ForNonLatin1(t *testing.T) {
+	data := append(append([]byte(nil), testMP3Audio...), buildID3v1Tag("Old Title", "Old Artist", "", 0, 8)...)
+	path := writeTestFile(t, "song.mp3", data)
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Artist: strPtr("坂本龍一")}))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(written, testMP3Audio), "The ID3v1 tag should be removed")
+	assert.Equal(t, "坂本龍一", readTestTags(t, path).Artist())
+}
+
+func TestRewriteID3v2_UnsupportedVersion(t *testing.T) {
+	original := []byte{'I', 'D', '3', 2, 0, 0, 0, 0, 0, 0}
+	path := writeTestFile(t, "song.mp3", append(original, testMP3Audio...))
+
+	err := NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title")})
+	assert.ErrorContains(t, err, "ID3v2.2")
+}
+
+func TestRemoveUnsynchronisation(t *testing.T) {
+	assert.Equal(t, []byte{0xFF, 0xE0, 0xFF, 0x00}, removeUnsynchronisation([]byte{0xFF, 0x00, 0xE0, 0xFF, 0x00, 0x00}))
+}
+
+func TestSyncsafe(t *testing.T) {
+	for _, n := range []uint32{0, 127, 128, 1024, 1<<28 - 1} {
+		assert.Equal(t, n, syncsafeInt(syncsafeBytes(n)))
+	}
+}
@@ -0,0 +1,457 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// MP4 "data" atom type indicators
+const (
+	mp4DataImplicit = 0
+	mp4DataUTF8     = 1
+	mp4DataJPEG     = 13
+	mp4DataPNG      = 14
+)
+
+// mp4Containers lists the atoms inside moov whose payload is a list of child atoms.
+var mp4Containers = map[string]bool{
+	"moov": true,
+	"trak": true,
+	"mdia": true,
+	"minf": true,
+	"stbl": true,
+	"udta": true,
+	"meta": true,
+	"ilst": true,
+}
+
+// mp4Atom is a node in the moov atom tree.
+// Containers hold children; all other atoms keep their raw payload.
+type mp4Atom struct {
+	name     string
+	prefix   []byte // Version/flags of full-box containers (meta)
+	data     []byte
+	children []*mp4Atom
+}
+
+// mp4TopLevelAtom locates an atom at the top level of the file.
+type mp4TopLevelAtom struct {
+	name   string
+	offset int64
+	size   int64
+}
+
+// rewriteMP4 rewrites an MP4/M4A file with updated iTunes-style metadata atoms
+// (moov/udta/meta/ilst). If the moov atom changes size, chunk offsets pointing
+// past it are shifted so the audio samples are still found.
+func rewriteMP4(src *io.SectionReader, dst io.Writer, edit domain.TagEdit) error {
+	atoms, err := readMP4TopLevel(src)
+	if err != nil {
+		return err
+	}
+
+	moovIndex := -1
+	fragmented := false
+	for i, atom := range atoms {
+		switch atom.name {
+		case "moov":
+			moovIndex = i
+		case "moof":
+			fragmented = true
+		}
+	}
+	if len(atoms) == 0 || atoms[0].name != "ftyp" {
+		return errors.New("not an MP4 file (missing 'ftyp' atom)")
+	}
+	if moovIndex < 0 {
+		return errors.New("MP4 file has no 'moov' atom")
+	}
+
+	old := atoms[moovIndex]
+	raw := make([]byte, old.size)
+	if _, err := src.ReadAt(raw, old.offset); err != nil {
+		return fmt.Errorf("failed to read 'moov' atom: %w", err)
+	}
+	moov, err := parseMP4Atom(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := applyMP4Edit(moov, edit); err != nil {
+		return err
+	}
+
+	// Shifting chunk offsets does not change atom sizes, so measure first
+	delta := int64(len(encodeMP4Atom(moov))) - old.size
+	if delta != 0 {
+		if fragmented {
+			return errors.New("fragmented MP4 files are not supported")
+		}
+		if err := shiftMP4ChunkOffsets(moov, old.offset+old.size, delta); err != nil {
+			return err
+		}
+	}
+
+	for i, atom := range atoms {
+		if i == moovIndex {
+			if _, err := dst.Write(encodeMP4Atom(moov)); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := io.Copy(dst, io.NewSectionReader(src, atom.offset, atom.size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMP4TopLevel lists the top-level atoms of the file.
+func readMP4TopLevel(src *io.SectionReader) ([]mp4TopLevelAtom, error) {
+	atoms := make([]mp4TopLevelAtom, 0)
+	header := make([]byte, 16)
+
+	for offset := int64(0); offset < src.Size(); {
+		if _, err := src.ReadAt(header[:8], offset); err != nil {
+			return nil, fmt.Errorf("failed to read MP4 atom header: %w", err)
+		}
+
+		size := int64(binary.BigEndian.Uint32(header))
+		switch size {
+		case 0: // Extends to the end of the file
+			size = src.Size() - offset
+		case 1: // 64-bit size follows the name
+			if _, err := src.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, fmt.Errorf("failed to read MP4 atom size: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+		}
+
+		if size < 8 || offset+size > src.Size() {
+			return nil, fmt.Errorf("MP4 atom %q has an invalid size", header[4:8])
+		}
+
+		atoms = append(atoms, mp4TopLevelAtom{name: string(header[4:8]), offset: offset, size: size})
+		offset += size
+	}
+
+	return atoms, nil
+}
+
+// parseMP4Atom parses a complete atom (header included) into a tree.
+func parseMP4Atom(raw []byte) (*mp4Atom, error) {
+	atom := &mp4Atom{name: string(raw[4:8])}
+	payload := raw[8:]
+	if binary.BigEndian.Uint32(raw) == 1 {
+		payload = raw[16:]
+	}
+
+	if !mp4Containers[atom.name] {
+		atom.data = payload
+		return atom, nil
+	}
+
+	// ISO meta is a full box with version/flags; QuickTime meta goes straight to children
+	if atom.name == "meta" && !(len(payload) >= 8 && string(payload[4:8]) == "hdlr") {
+		if len(payload) < 4 {
+			return nil, errors.New("truncated MP4 'meta' atom")
+		}
+		atom.prefix = payload[:4]
+		payload = payload[4:]
+	}
+
+	children, err := parseMP4Children(payload)
+	if err != nil {
+		return nil, fmt.Errorf("in %q: %w", atom.name, err)
+	}
+	atom.children = children
+	return atom, nil
+}
+
+// parseMP4Children parses a sequence of sibling atoms.
+func parseMP4Children(payload []byte) ([]*mp4Atom, error) {
+	children := make([]*mp4Atom, 0)
+
+	for pos := 0; pos < len(payload); {
+		if pos+8 > len(payload) {
+			// Some writers pad containers with a few zero bytes
+			if bytes.Count(payload[pos:], []byte{0}) == len(payload)-pos {
+				break
+			}
+			return nil, errors.New("truncated MP4 atom header")
+		}
+
+		size := int(binary.BigEndian.Uint32(payload[pos:]))
+		switch size {
+		case 0:
+			size = len(payload) - pos
+		case 1:
+			if pos+16 > len(payload) {
+				return nil, errors.New("truncated MP4 atom header")
+			}
+			size64 := binary.BigEndian.Uint64(payload[pos+8:])
+			if size64 > uint64(len(payload)-pos) {
+				return nil, errors.New("MP4 atom overruns its parent")
+			}
+			size = int(size64)
+		}
+
+		if size < 8 || pos+size > len(payload) {
+			return nil, fmt.Errorf("MP4 atom %q overruns its parent", payload[pos+4:pos+8])
+		}
+
+		child, err := parseMP4Atom(payload[pos : pos+size])
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+		pos += size
+	}
+
+	return children, nil
+}
+
+// encodeMP4Atom serializes an atom tree. 64-bit sizes are used only when required.
+func encodeMP4Atom(atom *mp4Atom) []byte {
+	payload := atom.data
+	if atom.children != nil || mp4Containers[atom.name] {
+		var buf bytes.Buffer
+		buf.Write(atom.prefix)
+		for _, child := range atom.children {
+			buf.Write(encodeMP4Atom(child))
+		}
+		payload = buf.Bytes()
+	}
+
+	size := 8 + len(payload)
+	if size > math.MaxUint32 {
+		data := make([]byte, 0, 16+len(payload))
+		data = binary.BigEndian.AppendUint32(data, 1)
+		data = append(data, atom.name...)
+		data = binary.BigEndian.AppendUint64(data, uint64(16+len(payload)))
+		return append(data, payload...)
+	}
+
+	data := make([]byte, 0, size)
+	data = binary.BigEndian.AppendUint32(data, uint32(size))
+	data = append(data, atom.name...)
+	return append(data, payload...)
+}
+
+// child returns the first child with the given name, creating it if requested.
+func (a *mp4Atom) child(name string, create bool) *mp4Atom {
+	for _, child := range a.children {
+		if child.name == name {
+			return child
+		}
+	}
+	if !create {
+		return nil
+	}
+
+	child := &mp4Atom{name: name, children: make([]*mp4Atom, 0)}
+	a.children = append(a.children, child)
+	return child
+}
+
+// metadataList returns moov/udta/meta/ilst, creating the path if missing.
+func metadataList(moov *mp4Atom) *mp4Atom {
+	meta := moov.child("udta", true).child("meta", false)
+	if meta == nil {
+		meta = moov.child("udta", true).child("meta", true)
+		meta.prefix = []byte{0, 0, 0, 0}
+
+		// iTunes metadata handler: pre_defined, "mdir", "appl" + reserved, empty name
+		hdlr := make([]byte, 0, 25)
+		hdlr = append(hdlr, 0, 0, 0, 0, 0, 0, 0, 0)
+		hdlr = append(hdlr, "mdirappl"...)
+		hdlr = append(hdlr, make([]byte, 9)...)
+		meta.children = append(meta.children, &mp4Atom{name: "hdlr", data: hdlr})
+	}
+	return meta.child("ilst", true)
+}
+
+// applyMP4Edit applies the edit to the moov atom's metadata item list.
+func applyMP4Edit(moov *mp4Atom, edit domain.TagEdit) error {
+	ilst := metadataList(moov)
+
+	setText := func(name string, value *string) {
+		if value == nil {
+			return
+		}
+		var item *mp4Atom
+		if *value != "" {
+			item = mp4Item(name, mp4DataUTF8, []byte(*value))
+		}
+		setMP4Item(ilst, name, item)
+	}
+
+	setText("\xa9nam", edit.Title)
+	setText("\xa9ART", edit.Artist)
+	setText("\xa9alb", edit.Album)
+	setText("aART", edit.AlbumArtist)
+	setText("\xa9cmt", edit.Comment)
+
+	if edit.Genre != nil {
+		// A free-text genre replaces the numeric ID3v1-style genre
+		setMP4Item(ilst, "gnre", nil)
+		setText("\xa9gen", edit.Genre)
+	}
+
+	if edit.Year != nil {
+		year := formatOptionalNumber(*edit.Year)
+		setText("\xa9day", &year)
+	}
+
+	setNumber := func(name string, number *int, size int) error {
+		if number == nil {
+			return nil
+		}
+		if *number < 0 || *number > math.MaxUint16 {
+			return fmt.Errorf("%s number %d is out of range", name, *number)
+		}
+		if *number == 0 {
+			setMP4Item(ilst, name, nil)
+			return nil
+		}
+
+		// number/total pair: 2 reserved bytes, number, total (and 2 more reserved for trkn)
+		value := make([]byte, size)
+		if previous := mp4ItemValue(ilst.child(name, false)); len(previous) >= 6 {
+			copy(value[4:6], previous[4:6])
+		}
+		binary.BigEndian.PutUint16(value[2:4], uint16(*number))
+		setMP4Item(ilst, name, mp4Item(name, mp4DataImplicit, value))
+		return nil
+	}
+
+	if err := setNumber("trkn", edit.TrackNumber, 8); err != nil {
+		return err
+	}
+	if err := setNumber("disk", edit.DiscNumber, 6); err != nil {
+		return err
+	}
+
+	if edit.AlbumArt != nil {
+		var item *mp4Atom
+		if len(edit.AlbumArt) > 0 {
+			var dataType uint32
+			switch pictureMIMEType(edit.AlbumArt) {
+			case "image/jpeg":
+				dataType = mp4DataJPEG
+			case "image/png":
+				dataType = mp4DataPNG
+			default:
+				return errors.New("MP4 cover art must be a JPEG or PNG image")
+			}
+			item = mp4Item("covr", dataType, edit.AlbumArt)
+		}
+		setMP4Item(ilst, "covr", item)
+	}
+
+	return nil
+}
+
+// mp4Item builds a metadata item holding a single data atom.
+func mp4Item(name string, dataType uint32, value []byte) *mp4Atom {
+	data := make([]byte, 0, 8+len(value))
+	data = binary.BigEndian.AppendUint32(data, dataType) // Version (0) and type
+	data = binary.BigEndian.AppendUint32(data, 0)        // Locale
+	data = append(data, value...)
+
+	return &mp4Atom{name: name, data: encodeMP4Atom(&mp4Atom{name: "data", data: data})}
+}
+
+// mp4ItemValue returns the value of the first data atom in a metadata item.
+func mp4ItemValue(item *mp4Atom) []byte {
+	if item == nil {
+		return nil
+	}
+	children, err := parseMP4Children(item.data)
+	if err != nil {
+		return nil
+	}
+	for _, child := range children {
+		if child.name == "data" && len(child.data) >= 8 {
+			return child.data[8:]
+		}
+	}
+	return nil
+}
+
+// setMP4Item replaces every item with the given name, in place of the first one.
+// A nil item removes the entries.
+func setMP4Item(ilst *mp4Atom, name string, item *mp4Atom) {
+	result := make([]*mp4Atom, 0, len(ilst.children)+1)
+	inserted := item == nil
+
+	for _, child := range ilst.children {
+		if child.name != name {
+			result = append(result, child)
+			continue
+		}
+		if !inserted {
+			result = append(result, item)
+			inserted = true
+		}
+	}
+
+	if !inserted {
+		result = append(result, item)
+	}
+	ilst.children = result
+}
+
+// shiftMP4ChunkOffsets adds delta to every stco/co64 chunk offset at or past boundary.
+func shiftMP4ChunkOffsets(atom *mp4Atom, boundary, delta int64) error {
+	for _, child := range atom.children {
+		if err := shiftMP4ChunkOffsets(child, boundary, delta); err != nil {
+			return err
+		}
+	}
+
+	if atom.name != "stco" && atom.name != "co64" {
+		return nil
+	}
+	if len(atom.data) < 8 {
+		return fmt.Errorf("truncated MP4 %q atom", atom.name)
+	}
+
+	// Version/flags, entry count, then 32-bit (stco) or 64-bit (co64) offsets
+	count := int(binary.BigEndian.Uint32(atom.data[4:8]))
+	width := 4
+	if atom.name == "co64" {
+		width = 8
+	}
+	if 8+count*width > len(atom.data) {
+		return fmt.Errorf("truncated MP4 %q atom", atom.name)
+	}
+
+	for i := 0; i < count; i++ {
+		entry := atom.data[8+i*width:]
+		if width == 8 {
+			offset := int64(binary.BigEndian.Uint64(entry))
+			if offset >= boundary {
+				binary.BigEndian.PutUint64(entry, uint64(offset+delta))
+			}
+			continue
+		}
+
+		offset := int64(binary.BigEndian.Uint32(entry))
+		if offset >= boundary {
+			shifted := offset + delta
+			if shifted < 0 || shifted > math.MaxUint32 {
+				return fmt.Errorf("chunk offset %d does not fit in 32 bits after rewriting", shifted)
+			}
+			binary.BigEndian.PutUint32(entry, uint32(shifted))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// testMP4Samples stands in for the audio samples stored in mdat.
+var testMP4Samples = []byte("AUDIO-SAMPLES-0123456789")
+
+// mp4Box builds an atom from a name and payload parts.
+func mp4Box(name string, parts ...[]byte) []byte {
+	payload := bytes.Join(parts, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	box = append(box, name...)
+	return append(box, payload...)
+}
+
+// buildTestMP4 builds an M4A file with one chunk offset pointing at the samples in mdat.
+// Items are raw ilst children; with none, the file has no udta atom at all.
+func buildTestMP4(moovFirst bool, items ...[]byte) []byte {
+	ftyp := mp4Box("ftyp", []byte("M4A "), make([]byte, 4), []byte("M4A isom"))
+	mdat := mp4Box("mdat", testMP4Samples)
+
+	buildMoov := func(chunkOffset uint32) []byte {
+		stco := mp4Box("stco", make([]byte, 4), binary.BigEndian.AppendUint32(nil, 1), binary.BigEndian.AppendUint32(nil, chunkOffset))
+		trak := mp4Box("trak", mp4Box("mdia", mp4Box("minf", mp4Box("stbl", stco))))
+		parts := [][]byte{mp4Box("mvhd", make([]byte, 100)), trak}
+		if len(items) > 0 {
+			hdlr := mp4Box("hdlr", make([]byte, 8), []byte("mdirappl"), make([]byte, 9))
+			parts = append(parts, mp4Box("udta", mp4Box("meta", make([]byte, 4), hdlr, mp4Box("ilst", items...))))
+		}
+		return mp4Box("moov", parts...)
+	}
+
+	// The chunk offset depends on where mdat ends up, which depends on the moov size
+	if moovFirst {
+		moov := buildMoov(0)
+		offset := uint32(len(ftyp) + len(moov) + 8)
+		return bytes.Join([][]byte{ftyp, buildMoov(offset), mdat}, nil)
+	}
+	offset := uint32(len(ftyp) + 8)
+	return bytes.Join([][]byte{ftyp, mdat, buildMoov(offset)}, nil)
+}
+
+// testMP4TextItem builds a UTF-8 ilst item.
+func testMP4TextItem(name, value string) []byte {
+	return mp4Box(name, mp4Box("data", binary.BigEndian.AppendUint32(nil, mp4DataUTF8), make([]byte, 4), []byte(value)))
+}
+
+// readTestMP4Samples follows the chunk offset of the rewritten file to the samples.
+func readTestMP4Samples(t *testing.T, path string) []byte {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	src := io.NewSectionReader(file, 0, info.Size())
+	atoms, err := readMP4TopLevel(src)
+	require.NoError(t, err)
+
+	for _, atom := range atoms {
+		if atom.name != "moov" {
+			continue
+		}
+		raw := make([]byte, atom.size)
+		_, err := src.ReadAt(raw, atom.offset)
+		require.NoError(t, err)
+		moov, err := parseMP4Atom(raw)
+		require.NoError(t, err)
+
+		stco := moov.child("trak", false).child("mdia", false).child("minf", false).child("stbl", false).child("stco", false)
+		require.NotNil(t, stco)
+		offset := int64(binary.BigEndian.Uint32(stco.data[8:]))
+
+		samples := make([]byte, len(testMP4Samples))
+		_, err = src.ReadAt(samples, offset)
+		require.NoError(t, err)
+		return samples
+	}
+
+	t.Fatal("no moov atom")
+	return nil
+}
+
+func TestRewriteMP4_AllFields(t *testing.T) {
+	cover := testCoverJPEG(t)
+	trkn := mp4Box("trkn", mp4Box("data", make([]byte, 8), []byte{0, 0, 0, 3, 0, 12, 0, 0}))
+	path := writeTestFile(t, "song.m4a", buildTestMP4(true,
+		testMP4TextItem("\xa9nam", "Old Title"),
+		testMP4TextItem("\xa9wrt", "Bach"),
+		trkn,
+	))
+
+	require.NoError(t, NewWriter().WriteTags(path, fullTestEdit(cover)))
+
+	metadata := readTestTags(t, path)
+	assertFullTestEdit(t, metadata, cover)
+	assert.Equal(t, "Bach", metadata.Composer())
+
+	_, trackTotal := metadata.Track()
+	assert.Equal(t, 12, trackTotal)
+
+	// moov grew in front of mdat, so the chunk offset must have moved with it
+	assert.Equal(t, testMP4Samples, readTestMP4Samples(t, path))
+}
+
+func TestRewriteMP4_CreatesMetadataAtoms(t *testing.T) {
+	path := writeTestFile(t, "song.m4a", buildTestMP4(true))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title"), Genre: strPtr("Rock")}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "Title", metadata.Title())
+	assert.Equal(t, "Rock", metadata.Genre())
+	assert.Equal(t, testMP4Samples, readTestMP4Samples(t, path))
+}
+
+func TestRewriteMP4_MoovAfterMdat(t *testing.T) {
+	path := writeTestFile(t, "song.m4a", buildTestMP4(false, testMP4TextItem("\xa9nam", "Old")))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("A much longer title than before")}))
+
+	assert.Equal(t, "A much longer title than before", readTestTags(t, path).Title())
+	assert.Equal(t, testMP4Samples, readTestMP4Samples(t, path))
+}
+
+func TestRewriteMP4_RemoveItems(t *testing.T) {
+	path := writeTestFile(t, "song.m4a", buildTestMP4(true, testMP4TextItem("\xa9nam", "Title"), testMP4TextItem("\xa9cmt", "Comment")))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Comment: strPtr("")}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "Title", metadata.Title())
+	assert.Empty(t, metadata.Comment())
+	assert.Equal(t, testMP4Samples, readTestMP4Samples(t, path))
+}
+
+func TestRewriteMP4_RejectsUnsupportedCover(t *testing.T) {
+	path := writeTestFile(t, "song.m4a", buildTestMP4(true))
+
+	err := NewWriter().WriteTags(path, domain.TagEdit{AlbumArt: []byte("GIF89a not really")})
+	assert.ErrorContains(t, err, "JPEG or PNG")
+}
@@ -0,0 +1,290 @@
+package tags
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const (
+	// oggHeaderSize is the fixed part of an Ogg page header (before the segment table)
+	oggHeaderSize = 27
+
+	// Ogg page header flags
+	oggFlagContinued = 0x01
+	oggFlagFirstPage = 0x02
+
+	// oggMaxSegments is the largest number of lacing values in one page
+	oggMaxSegments = 255
+)
+
+var (
+	vorbisIdentificationPrefix = []byte("\x01vorbis")
+	vorbisCommentPrefix        = []byte("\x03vorbis")
+	opusHeadPrefix             = []byte("OpusHead")
+	opusTagsPrefix             = []byte("OpusTags")
+)
+
+// oggCRCTable is the lookup table for the Ogg CRC-32 (polynomial 0x04c11db7, unreflected).
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// oggPage is a single Ogg page.
+type oggPage struct {
+	flags    byte
+	granule  uint64
+	serial   uint32
+	sequence uint32
+	segments []byte // Lacing values
+	body     []byte
+}
+
+// readOggPage reads the next page from r.
+func readOggPage(r io.Reader) (*oggPage, error) {
+	header := make([]byte, oggHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "OggS" {
+		return nil, errors.New("expected Ogg page ('OggS')")
+	}
+
+	page := &oggPage{
+		flags:    header[5],
+		granule:  binary.LittleEndian.Uint64(header[6:]),
+		serial:   binary.LittleEndian.Uint32(header[14:]),
+		sequence: binary.LittleEndian.Uint32(header[18:]),
+		segments: make([]byte, header[26]),
+	}
+	if _, err := io.ReadFull(r, page.segments); err != nil {
+		return nil, err
+	}
+
+	size := 0
+	for _, lacing := range page.segments {
+		size += int(lacing)
+	}
+	page.body = make([]byte, size)
+	if _, err := io.ReadFull(r, page.body); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// encode serializes the page, computing its checksum.
+func (p *oggPage) encode() []byte {
+	data := make([]byte, 0, oggHeaderSize+len(p.segments)+len(p.body))
+	data = append(data, 'O', 'g', 'g', 'S', 0, p.flags)
+	data = binary.LittleEndian.AppendUint64(data, p.granule)
+	data = binary.LittleEndian.AppendUint32(data, p.serial)
+	data = binary.LittleEndian.AppendUint32(data, p.sequence)
+	data = binary.LittleEndian.AppendUint32(data, 0) // Checksum placeholder
+	data = append(data, byte(len(p.segments)))
+	data = append(data, p.segments...)
+	data = append(data, p.body...)
+
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	binary.LittleEndian.PutUint32(data[22:], crc)
+
+	return data
+}
+
+// size returns the encoded size of the page in bytes.
+func (p *oggPage) size() int64 {
+	return int64(oggHeaderSize + len(p.segments) + len(p.body))
+}
+
+// rewriteOgg rewrites an Ogg Vorbis or Opus file with an updated comment header.
+// The header packets are repaginated and, if the number of header pages changes,
+// the sequence numbers of the stream's remaining pages are shifted to match.
+func rewriteOgg(src *io.SectionReader, dst io.Writer, edit domain.TagEdit) error {
+	reader := bufio.NewReader(io.NewSectionReader(src, 0, src.Size()))
+
+	first, err := readOggPage(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read first Ogg page: %w", err)
+	}
+	if first.flags&oggFlagFirstPage == 0 {
+		return errors.New("first Ogg page is not a beginning-of-stream page")
+	}
+
+	// The identification header determines how many header packets follow
+	var headerPackets int
+	var commentPrefix []byte
+	switch {
+	case bytes.HasPrefix(first.body, vorbisIdentificationPrefix):
+		headerPackets, commentPrefix = 3, vorbisCommentPrefix // identification, comment, setup
+	case bytes.HasPrefix(first.body, opusHeadPrefix):
+		headerPackets, commentPrefix = 2, opusTagsPrefix // identification, comment
+	default:
+		return errors.New("unsupported Ogg codec (only Vorbis and Opus are supported)")
+	}
+
+	// Reassemble the header packets following the identification page
+	packets := make([][]byte, 0, headerPackets-1)
+	var partial []byte
+	oldHeaderPages := 1
+	audioStart := first.size()
+
+	for len(packets) < headerPackets-1 {
+		page, err := readOggPage(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read Ogg header page: %w", err)
+		}
+		if page.serial != first.serial {
+			return errors.New("multiplexed Ogg streams are not supported")
+		}
+		oldHeaderPages++
+		audioStart += page.size()
+
+		offset := 0
+		for _, lacing := range page.segments {
+			partial = append(partial, page.body[offset:offset+int(lacing)]...)
+			offset += int(lacing)
+			if lacing < 255 {
+				packets = append(packets, partial)
+				partial = nil
+			}
+		}
+	}
+	if len(partial) > 0 || len(packets) != headerPackets-1 {
+		return errors.New("Ogg header packets do not end on a page boundary")
+	}
+
+	comment, err := rewriteOggCommentPacket(packets[0], commentPrefix, edit)
+	if err != nil {
+		return err
+	}
+	packets[0] = comment
+
+	// Write the identification page unchanged, then the repaginated headers
+	if _, err := dst.Write(first.encode()); err != nil {
+		return err
+	}
+	headerPages := paginateOggPackets(packets, first.serial, 1)
+	for _, page := range headerPages {
+		if _, err := dst.Write(page.encode()); err != nil {
+			return err
+		}
+	}
+
+	delta := uint32(1+len(headerPages)) - uint32(oldHeaderPages)
+	if delta == 0 {
+		return copyRange(dst, src, audioStart)
+	}
+
+	// Renumber the remaining pages of this stream; other (chained) streams are untouched
+	rest := bufio.NewReader(io.NewSectionReader(src, audioStart, src.Size()-audioStart))
+	for {
+		page, err := readOggPage(rest)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read Ogg page: %w", err)
+		}
+		if page.serial == first.serial {
+			page.sequence += delta
+		}
+		if _, err := dst.Write(page.encode()); err != nil {
+			return err
+		}
+	}
+}
+
+// rewriteOggCommentPacket applies the edit to a Vorbis or Opus comment packet.
+// Any data following the comments (Vorbis framing bit, Opus padding) is preserved.
+func rewriteOggCommentPacket(packet, prefix []byte, edit domain.TagEdit) ([]byte, error) {
+	if !bytes.HasPrefix(packet, prefix) {
+		return nil, errors.New("second Ogg header packet is not a comment header")
+	}
+
+	comment, consumed, err := parseVorbisComment(packet[len(prefix):])
+	if err != nil {
+		return nil, err
+	}
+	trailer := packet[len(prefix)+consumed:]
+
+	applyVorbisEdit(comment, edit)
+	if edit.AlbumArt != nil {
+		setVorbisPicture(comment, edit.AlbumArt)
+	}
+
+	encoded := comment.encode()
+	result := make([]byte, 0, len(prefix)+len(encoded)+len(trailer))
+	result = append(result, prefix...)
+	result = append(result, encoded...)
+	result = append(result, trailer...)
+	return result, nil
+}
+
+// paginateOggPackets splits packets into pages, each packet starting on a new page.
+// Header pages carry a granule position of zero.
+func paginateOggPackets(packets [][]byte, serial, sequence uint32) []*oggPage {
+	pages := make([]*oggPage, 0, len(packets))
+
+	for _, packet := range packets {
+		// Lacing values: runs of 255 terminated by a value below 255
+		lacing := make([]byte, 0, len(packet)/255+1)
+		for remaining := len(packet); ; remaining -= 255 {
+			if remaining < 255 {
+				lacing = append(lacing, byte(remaining))
+				break
+			}
+			lacing = append(lacing, 255)
+		}
+
+		offset := 0
+		for start := 0; start < len(lacing); start += oggMaxSegments {
+			end := min(start+oggMaxSegments, len(lacing))
+
+			page := &oggPage{
+				serial:   serial,
+				sequence: sequence,
+				segments: lacing[start:end],
+			}
+			if start > 0 {
+				page.flags = oggFlagContinued
+			}
+
+			size := 0
+			for _, l := range page.segments {
+				size += int(l)
+			}
+			page.body = packet[offset : offset+size]
+			offset += size
+
+			// Pages on which no packet ends carry a granule position of -1
+			if lacing[end-1] == 255 {
+				page.granule = ^uint64(0)
+			}
+
+			pages = append(pages, page)
+			sequence++
+		}
+	}
+
+	return pages
+}
@@ -0,0 +1,173 @@
+package tags
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const testOggSerial = 0x1234
+
+// testOggAudioPages returns audio pages (sequence numbers from first) ending the stream.
+func testOggAudioPages(first uint32) []*oggPage {
+	pages := make([]*oggPage, 0, 3)
+	for i := uint32(0); i < 3; i++ {
+		body := bytes.Repeat([]byte{byte('a' + i)}, 300)
+		pages = append(pages, &oggPage{
+			granule:  uint64(1024 * (i + 1)),
+			serial:   testOggSerial,
+			sequence: first + i,
+			segments: []byte{255, 45},
+			body:     body,
+		})
+	}
+	pages[len(pages)-1].flags = 0x04 // End of stream
+	return pages
+}
+
+// buildTestOggVorbis builds an Ogg Vorbis stream whose comment and setup headers share a page.
+func buildTestOggVorbis(comments ...string) []byte {
+	identification := append(append([]byte(nil), vorbisIdentificationPrefix...), make([]byte, 23)...)
+
+	comment := &vorbisComment{vendor: "Xiph.Org libVorbis I 20200704", comments: comments}
+	commentPacket := append(append(append([]byte(nil), vorbisCommentPrefix...), comment.encode()...), 0x01)
+	setupPacket := append([]byte("\x05vorbis"), bytes.Repeat([]byte{0x55}, 40)...)
+
+	var data bytes.Buffer
+	data.Write((&oggPage{flags: oggFlagFirstPage, serial: testOggSerial, segments: []byte{byte(len(identification))}, body: identification}).encode())
+	data.Write((&oggPage{
+		serial:   testOggSerial,
+		sequence: 1,
+		segments: []byte{byte(len(commentPacket)), byte(len(setupPacket))},
+		body:     append(commentPacket, setupPacket...),
+	}).encode())
+	for _, page := range testOggAudioPages(2) {
+		data.Write(page.encode())
+	}
+	return data.Bytes()
+}
+
+// readTestOggPages reads every page of a file, checking the stored checksums.
+func readTestOggPages(t *testing.T, path string) []*oggPage {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	pages := make([]*oggPage, 0)
+	reader := bufio.NewReader(bytes.NewReader(data))
+	offset := 0
+	for {
+		page, err := readOggPage(reader)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		// Re-encoding computes the checksum; it must match what was written
+		encoded := page.encode()
+		require.Equal(t, data[offset:offset+len(encoded)], encoded, "page %d checksum", page.sequence)
+		offset += len(encoded)
+
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+func TestRewriteOgg_Vorbis(t *testing.T) {
+	cover := testCoverJPEG(t)
+	path := writeTestFile(t, "song.ogg", buildTestOggVorbis("TITLE=Old", "COMPOSER=Bach"))
+
+	require.NoError(t, NewWriter().WriteTags(path, fullTestEdit(cover)))
+
+	metadata := readTestTags(t, path)
+	assertFullTestEdit(t, metadata, cover)
+	assert.Equal(t, "Bach", metadata.Composer())
+
+	// Headers now take one page each; audio pages are renumbered but otherwise unchanged
+	pages := readTestOggPages(t, path)
+	require.Len(t, pages, 6)
+	for i, page := range pages {
+		assert.Equal(t, uint32(i), page.sequence)
+	}
+	for i, audio := range testOggAudioPages(3) {
+		assert.Equal(t, audio.body, pages[3+i].body)
+		assert.Equal(t, audio.granule, pages[3+i].granule)
+		assert.Equal(t, audio.flags, pages[3+i].flags)
+	}
+	assert.True(t, bytes.HasPrefix(pages[2].body, []byte("\x05vorbis")))
+}
+
+func TestRewriteOgg_LargeCoverSpansPages(t *testing.T) {
+	// A cover larger than one page (255 * 255 bytes) forces a continued comment packet
+	cover := append(testCoverJPEG(t), make([]byte, 100*1024)...)
+	path := writeTestFile(t, "song.ogg", buildTestOggVorbis("TITLE=Old"))
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{AlbumArt: cover}))
+
+	metadata := readTestTags(t, path)
+	require.NotNil(t, metadata.Picture())
+	assert.Equal(t, cover, metadata.Picture().Data)
+	assert.Equal(t, "Old", metadata.Title())
+
+	pages := readTestOggPages(t, path)
+	require.Greater(t, len(pages), 6)
+	assert.Equal(t, byte(oggFlagContinued), pages[2].flags&oggFlagContinued)
+	for i, page := range pages {
+		assert.Equal(t, uint32(i), page.sequence)
+	}
+}
+
+func TestRewriteOgg_Opus(t *testing.T) {
+	head := append(append([]byte(nil), opusHeadPrefix...), 1, 2, 0x38, 0x01, 0x80, 0xBB, 0, 0, 0, 0, 0)
+	comment := &vorbisComment{vendor: "libopus 1.4", comments: []string{"TITLE=Old"}}
+	// Opus allows binary data after the comments when its first bit is set
+	tags := append(append(append([]byte(nil), opusTagsPrefix...), comment.encode()...), 0x01, 0xAA)
+
+	var data bytes.Buffer
+	data.Write((&oggPage{flags: oggFlagFirstPage, serial: testOggSerial, segments: []byte{byte(len(head))}, body: head}).encode())
+	data.Write((&oggPage{serial: testOggSerial, sequence: 1, segments: []byte{byte(len(tags))}, body: tags}).encode())
+	for _, page := range testOggAudioPages(2) {
+		data.Write(page.encode())
+	}
+	path := writeTestFile(t, "song.opus", data.Bytes())
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("New"), Artist: strPtr("Artist")}))
+
+	metadata := readTestTags(t, path)
+	assert.Equal(t, "New", metadata.Title())
+	assert.Equal(t, "Artist", metadata.Artist())
+
+	pages := readTestOggPages(t, path)
+	require.Len(t, pages, 5)
+	assert.True(t, bytes.HasSuffix(pages[1].body, []byte{0x01, 0xAA}))
+}
+
+func TestRewriteOgg_UnsupportedCodec(t *testing.T) {
+	head := []byte("\x7fFLAC")
+	data := (&oggPage{flags: oggFlagFirstPage, serial: testOggSerial, segments: []byte{byte(len(head))}, body: head}).encode()
+	path := writeTestFile(t, "song.oga", data)
+
+	err := NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title")})
+	assert.ErrorContains(t, err, "unsupported Ogg codec")
+}
+
+func TestPaginateOggPackets(t *testing.T) {
+	packet := make([]byte, 255*255)
+	pages := paginateOggPackets([][]byte{packet, {1, 2, 3}}, testOggSerial, 1)
+
+	// 255 lacing values of 255 fill the first page; the terminating 0 spills over
+	require.Len(t, pages, 3)
+	assert.Equal(t, ^uint64(0), pages[0].granule)
+	assert.Equal(t, []byte{0}, pages[1].segments)
+	assert.Equal(t, byte(oggFlagContinued), pages[1].flags)
+	assert.Equal(t, uint64(0), pages[1].granule)
+	assert.Equal(t, []byte{3}, pages[2].segments)
+	assert.Equal(t, uint32(3), pages[2].sequence)
+}
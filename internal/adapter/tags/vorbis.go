@@ -0,0 +1,197 @@
+package tags
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// defaultVendor is the vendor string used when a file has no comment block yet.
+const defaultVendor = "GoTune"
+
+// vorbisComment is a Vorbis comment block, shared by FLAC, Ogg Vorbis and Opus.
+// Comments are kept in their original order as raw "KEY=value" strings.
+type vorbisComment struct {
+	vendor   string
+	comments []string
+}
+
+// parseVorbisComment decodes a comment block and returns the number of bytes consumed.
+func parseVorbisComment(data []byte) (*vorbisComment, int, error) {
+	pos := 0
+	readString := func() (string, error) {
+		if pos+4 > len(data) {
+			return "", errors.New("truncated Vorbis comment")
+		}
+		length := int(binary.LittleEndian.Uint32(data[pos:]))
+		pos += 4
+		if length < 0 || pos+length > len(data) {
+			return "", errors.New("Vorbis comment overruns its block")
+		}
+		value := string(data[pos : pos+length])
+		pos += length
+		return value, nil
+	}
+
+	vendor, err := readString()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if pos+4 > len(data) {
+		return nil, 0, errors.New("truncated Vorbis comment")
+	}
+	count := int(binary.LittleEndian.Uint32(data[pos:]))
+	pos += 4
+
+	comment := &vorbisComment{vendor: vendor, comments: make([]string, 0)}
+	for i := 0; i < count; i++ {
+		value, err := readString()
+		if err != nil {
+			return nil, 0, err
+		}
+		comment.comments = append(comment.comments, value)
+	}
+
+	return comment, pos, nil
+}
+
+// encode serializes the comment block.
+func (c *vorbisComment) encode() []byte {
+	size := 8 + len(c.vendor)
+	for _, comment := range c.comments {
+		size += 4 + len(comment)
+	}
+
+	data := make([]byte, 0, size)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(c.vendor)))
+	data = append(data, c.vendor...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(c.comments)))
+	for _, comment := range c.comments {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(comment)))
+		data = append(data, comment...)
+	}
+	return data
+}
+
+// get returns the first value for a field name (case-insensitive).
+func (c *vorbisComment) get(key string) (string, bool) {
+	for _, comment := range c.comments {
+		if k, v, ok := strings.Cut(comment, "="); ok && strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// set replaces every value of a field with a single value, in place of the first
+// occurrence. An empty value removes the field.
+func (c *vorbisComment) set(key, value string) {
+	result := make([]string, 0, len(c.comments)+1)
+	inserted := value == ""
+
+	for _, comment := range c.comments {
+		if k, _, _ := strings.Cut(comment, "="); !strings.EqualFold(k, key) {
+			result = append(result, comment)
+			continue
+		}
+		if !inserted {
+			result = append(result, key+"="+value)
+			inserted = true
+		}
+	}
+
+	if !inserted {
+		result = append(result, key+"="+value)
+	}
+	c.comments = result
+}
+
+// applyVorbisEdit applies the text fields of an edit to the comment block.
+// Cover art is handled by the caller since FLAC stores it in its own block.
+func applyVorbisEdit(c *vorbisComment, edit domain.TagEdit) {
+	setText := func(key string, value *string) {
+		if value != nil {
+			c.set(key, *value)
+		}
+	}
+
+	setText("TITLE", edit.Title)
+	setText("ARTIST", edit.Artist)
+	setText("ALBUM", edit.Album)
+	setText("GENRE", edit.Genre)
+	setText("COMMENT", edit.Comment)
+
+	if edit.AlbumArtist != nil {
+		// Some taggers write "ALBUM ARTIST"; keep a single spelling
+		c.set("ALBUM ARTIST", "")
+		c.set("ALBUMARTIST", *edit.AlbumArtist)
+	}
+
+	if edit.Year != nil {
+		c.set("YEAR", "")
+		c.set("DATE", formatOptionalNumber(*edit.Year))
+	}
+
+	setNumber := func(numberKey, totalKey string, number *int) {
+		if number == nil {
+			return
+		}
+		// Split a legacy "3/12" value so the total is not lost
+		if previous, ok := c.get(numberKey); ok {
+			if _, total, found := strings.Cut(previous, "/"); found {
+				if _, hasTotal := c.get(totalKey); !hasTotal && strings.TrimSpace(total) != "" {
+					c.set(totalKey, strings.TrimSpace(total))
+				}
+			}
+		}
+		c.set(numberKey, formatOptionalNumber(*number))
+	}
+
+	setNumber("TRACKNUMBER", "TRACKTOTAL", edit.TrackNumber)
+	setNumber("DISCNUMBER", "DISCTOTAL", edit.DiscNumber)
+}
+
+// setVorbisPicture replaces the embedded cover of an Ogg comment block.
+// Ogg streams embed a base64 FLAC picture block in METADATA_BLOCK_PICTURE.
+func setVorbisPicture(c *vorbisComment, image []byte) {
+	c.set("COVERART", "")
+	c.set("COVERARTMIME", "")
+
+	if len(image) == 0 {
+		c.set("METADATA_BLOCK_PICTURE", "")
+		return
+	}
+	c.set("METADATA_BLOCK_PICTURE", base64.StdEncoding.EncodeToString(encodePictureBlock(image)))
+}
+
+// encodePictureBlock builds a FLAC PICTURE block body holding the front cover.
+func encodePictureBlock(image []byte) []byte {
+	mimeType := pictureMIMEType(image)
+	width, height, depth := pictureSize(image)
+
+	data := make([]byte, 0, 32+len(mimeType)+len(image))
+	data = binary.BigEndian.AppendUint32(data, id3v2PictureFront)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(mimeType)))
+	data = append(data, mimeType...)
+	data = binary.BigEndian.AppendUint32(data, 0) // Empty description
+	data = binary.BigEndian.AppendUint32(data, uint32(width))
+	data = binary.BigEndian.AppendUint32(data, uint32(height))
+	data = binary.BigEndian.AppendUint32(data, uint32(depth))
+	data = binary.BigEndian.AppendUint32(data, 0) // Colors used (non-indexed)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(image)))
+	data = append(data, image...)
+	return data
+}
+
+// formatOptionalNumber formats a number, returning "" (remove) for zero.
+func formatOptionalNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
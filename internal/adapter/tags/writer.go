@@ -0,0 +1,176 @@
+// Package tags writes metadata tags back to audio files.
+// Tags are read with dhowden/tag (see the bass adapter); this package handles the
+// write side for ID3v2 (MP3), Vorbis comments (FLAC, Ogg Vorbis, Opus) and
+// iTunes-style MP4 atoms (M4A).
+package tags
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder for cover art dimensions
+	_ "image/jpeg" // Register JPEG decoder for cover art dimensions
+	_ "image/png"  // Register PNG decoder for cover art dimensions
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// rewriter copies an audio file from src to dst, replacing its tags with the edit applied.
+type rewriter func(src *io.SectionReader, dst io.Writer, edit domain.TagEdit) error
+
+// rewriters maps lower-case file extensions to the rewriter for their tag format.
+var rewriters = map[string]rewriter{
+	".mp3":  rewriteID3v2,
+	".flac": rewriteFLAC,
+	".ogg":  rewriteOgg,
+	".oga":  rewriteOgg,
+	".opus": rewriteOgg,
+	".m4a":  rewriteMP4,
+	".m4b":  rewriteMP4,
+	".mp4":  rewriteMP4,
+}
+
+// Writer implements ports.TagWriter.
+// Files are rewritten into a temporary file next to the original, which then
+// replaces the original, so a failed write never leaves a half-written file.
+//
+// Thread-safe: Writes are serialized by a mutex.
+type Writer struct {
+	mu sync.Mutex
+}
+
+// NewWriter creates a new tag writer.
+func NewWriter() *Writer {
+	return &Writer{}
+}
+
+// SupportsFormat returns true if tags can be written for the file extension.
+func (w *Writer) SupportsFormat(ext string) bool {
+	_, ok := rewriters[strings.ToLower(ext)]
+	return ok
+}
+
+// WriteTags applies the edit to the tags stored in the file.
+func (w *Writer) WriteTags(filePath string, edit domain.TagEdit) error {
+	if filePath == "" {
+		return domain.ErrInvalidFilePath
+	}
+
+	rewrite, ok := rewriters[strings.ToLower(filepath.Ext(filePath))]
+	if !ok {
+		return domain.ErrTagsNotWritable
+	}
+
+	if edit.IsEmpty() {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	src, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return domain.ErrFileNotFound
+		}
+		return domain.NewTagError("write", filePath, "failed to open file", err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return domain.NewTagError("write", filePath, "failed to stat file", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return domain.NewTagError("write", filePath, "failed to create temporary file", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file unless it replaced the original
+	replaced := false
+	defer func() {
+		if !replaced {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	buffered := bufio.NewWriterSize(tmp, 256*1024)
+	if err := rewrite(io.NewSectionReader(src, 0, info.Size()), buffered, edit); err != nil {
+		return domain.NewTagError("write", filePath, "failed to rewrite tags", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return domain.NewTagError("write", filePath, "failed to write temporary file", err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return domain.NewTagError("write", filePath, "failed to set file permissions", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return domain.NewTagError("write", filePath, "failed to close temporary file", err)
+	}
+
+	// Close the source before renaming over it (required on Windows)
+	src.Close()
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return domain.NewTagError("write", filePath, "failed to replace file", err)
+	}
+	replaced = true
+
+	return nil
+}
+
+// copyRange copies the bytes of src from offset to the end into dst.
+func copyRange(dst io.Writer, src *io.SectionReader, offset int64) error {
+	return copySpan(dst, src, offset, src.Size())
+}
+
+// copySpan copies the bytes of src from offset up to end into dst.
+func copySpan(dst io.Writer, src *io.SectionReader, offset, end int64) error {
+	if offset > end {
+		return fmt.Errorf("offset %d is past the end of the data at %d", offset, end)
+	}
+	_, err := io.Copy(dst, io.NewSectionReader(src, offset, end-offset))
+	return err
+}
+
+// pictureMIMEType detects the MIME type of cover art image data.
+func pictureMIMEType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// pictureSize returns the dimensions and bit depth of cover art, or zeros if unknown.
+func pictureSize(data []byte) (width, height, depth int) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, 0
+	}
+
+	depth = 24
+	if format == "png" {
+		depth = 32
+	}
+	return config.Width, config.Height, depth
+}
+
+// numberWithTotal formats a track or disc number, keeping the "/total" suffix of
+// the previous value (e.g. "3/12" edited to 5 becomes "5/12").
+func numberWithTotal(previous string, number int) string {
+	if _, total, ok := strings.Cut(previous, "/"); ok && strings.TrimSpace(total) != "" {
+		return strconv.Itoa(number) + "/" + strings.TrimSpace(total)
+	}
+	return strconv.Itoa(number)
+}
+
+// Verify interface implementation
+var _ ports.TagWriter = (*Writer)(nil)
@@ -0,0 +1,166 @@
+package tags
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhowden/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helpers for building edits
+func strPtr(s string) *string { return &s }
+func intPtr(n int) *int       { return &n }
+
+// testCoverJPEG returns a small JPEG image for cover art tests.
+func testCoverJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 60), G: uint8(y * 80), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// fullTestEdit returns an edit that sets every supported field.
+func fullTestEdit(cover []byte) domain.TagEdit {
+	return domain.TagEdit{
+		Title:       strPtr("New Title"),
+		Artist:      strPtr("New Artist"),
+		Album:       strPtr("New Album"),
+		AlbumArtist: strPtr("Various Artists"),
+		Genre:       strPtr("Jazz"),
+		Year:        intPtr(1959),
+		TrackNumber: intPtr(5),
+		DiscNumber:  intPtr(2),
+		Comment:     strPtr("Edited by GoTune"),
+		AlbumArt:    cover,
+	}
+}
+
+// writeTestFile writes data to a file in a temporary directory.
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0640))
+	return path
+}
+
+// readTestTags reads a file's tags back with dhowden/tag (the reader used by the player).
+func readTestTags(t *testing.T, path string) tag.Metadata {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	metadata, err := tag.ReadFrom(file)
+	require.NoError(t, err)
+	return metadata
+}
+
+// assertFullTestEdit checks that every field of fullTestEdit was written.
+func assertFullTestEdit(t *testing.T, metadata tag.Metadata, cover []byte) {
+	t.Helper()
+
+	assert.Equal(t, "New Title", metadata.Title())
+	assert.Equal(t, "New Artist", metadata.Artist())
+	assert.Equal(t, "New Album", metadata.Album())
+	assert.Equal(t, "Various Artists", metadata.AlbumArtist())
+	assert.Equal(t, "Jazz", metadata.Genre())
+	assert.Equal(t, 1959, metadata.Year())
+	trackNumber, _ := metadata.Track()
+	assert.Equal(t, 5, trackNumber)
+	discNumber, _ := metadata.Disc()
+	assert.Equal(t, 2, discNumber)
+	assert.Equal(t, "Edited by GoTune", metadata.Comment())
+
+	require.NotNil(t, metadata.Picture())
+	assert.Equal(t, "image/jpeg", metadata.Picture().MIMEType)
+	assert.Equal(t, cover, metadata.Picture().Data)
+}
+
+func TestWriter_SupportsFormat(t *testing.T) {
+	writer := NewWriter()
+
+	for _, ext := range []string{".mp3", ".flac", ".ogg", ".opus", ".m4a", ".MP3"} {
+		assert.True(t, writer.SupportsFormat(ext), ext)
+	}
+	for _, ext := range []string{".wav", ".mod", ".aiff", ""} {
+		assert.False(t, writer.SupportsFormat(ext), ext)
+	}
+}
+
+func TestWriter_WriteTags_UnsupportedFormat(t *testing.T) {
+	path := writeTestFile(t, "song.wav", []byte("RIFF"))
+
+	err := NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title")})
+	assert.ErrorIs(t, err, domain.ErrTagsNotWritable)
+}
+
+func TestWriter_WriteTags_FileNotFound(t *testing.T) {
+	err := NewWriter().WriteTags(filepath.Join(t.TempDir(), "missing.mp3"), domain.TagEdit{Title: strPtr("Title")})
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+}
+
+func TestWriter_WriteTags_EmptyEditLeavesFileUntouched(t *testing.T) {
+	original := []byte("not really an mp3")
+	path := writeTestFile(t, "song.mp3", original)
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+}
+
+func TestWriter_WriteTags_InvalidFileKeepsOriginal(t *testing.T) {
+	original := []byte("definitely not a flac file")
+	path := writeTestFile(t, "song.flac", original)
+
+	err := NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title")})
+	require.Error(t, err)
+
+	var tagErr *domain.TagError
+	assert.ErrorAs(t, err, &tagErr)
+
+	// The original is untouched and no temporary file is left behind
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWriter_WriteTags_PreservesPermissions(t *testing.T) {
+	path := writeTestFile(t, "song.mp3", []byte{0xFF, 0xFB, 0x90, 0x00})
+
+	require.NoError(t, NewWriter().WriteTags(path, domain.TagEdit{Title: strPtr("Title")}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
+
+func TestNumberWithTotal(t *testing.T) {
+	assert.Equal(t, "5", numberWithTotal("", 5))
+	assert.Equal(t, "5", numberWithTotal("3", 5))
+	assert.Equal(t, "5/12", numberWithTotal("3/12", 5))
+	assert.Equal(t, "5", numberWithTotal("3/", 5))
+}
@@ -0,0 +1,322 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// Tree node IDs are the folder or track path with a prefix telling them apart.
+const (
+	folderNodePrefix = "folder:"
+	trackNodePrefix  = "track:"
+)
+
+// FolderBrowserWindow shows the library as a folder tree rooted at the scan paths.
+// The tree is built from the library, so expanding folders does not read the disk.
+// A folder or track can be played, played next or added to the queue, including whole subtrees.
+type FolderBrowserWindow struct {
+	window fyneapp.Window
+	tree   *widget.Tree
+	status *widget.Label
+
+	playButton     *widget.Button
+	playNextButton *widget.Button
+	enqueueButton  *widget.Button
+
+	// Data state
+	roots    []*domain.FolderNode
+	folders  map[string]*domain.FolderNode // By node ID
+	tracks   map[string]domain.MusicTrack  // By node ID
+	selected string
+
+	// Dependencies
+	presenter     *Presenter
+	eventBus      ports.EventBus
+	logger        *slog.Logger
+	subscriptions []domain.SubscriptionID
+
+	// Lifecycle
+	onWindowClosed func()
+	isVisible      bool
+}
+
+// NewFolderBrowserWindow creates a new folder browser window.
+func NewFolderBrowserWindow(app fyneapp.App, presenter *Presenter, eventBus ports.EventBus, logger *slog.Logger) *FolderBrowserWindow {
+	w := &FolderBrowserWindow{
+		presenter: presenter,
+		eventBus:  eventBus,
+		logger:    logger,
+		folders:   make(map[string]*domain.FolderNode),
+		tracks:    make(map[string]domain.MusicTrack),
+	}
+
+	w.window = app.NewWindow("Library Folders")
+	w.window.Resize(fyneapp.NewSize(500, 600))
+
+	w.buildUI()
+
+	w.subscriptions = append(w.subscriptions,
+		eventBus.Subscribe(domain.EventLibraryChanged, w.onLibraryChanged),
+		eventBus.Subscribe(domain.EventTrackUpdated, w.onLibraryChanged),
+	)
+
+	w.window.SetOnClosed(func() {
+		w.isVisible = false
+		w.unsubscribeFromEvents()
+		if w.onWindowClosed != nil {
+			w.onWindowClosed()
+		}
+	})
+
+	w.reload()
+
+	return w
+}
+
+// buildUI constructs the folder browser layout.
+func (w *FolderBrowserWindow) buildUI() {
+	w.tree = widget.NewTree(w.childIDs, w.isBranch, w.createNode, w.updateNode)
+	w.tree.OnSelected = func(id widget.TreeNodeID) {
+		w.selected = id
+		w.updateButtons()
+	}
+	w.tree.OnUnselected = func(widget.TreeNodeID) {
+		w.selected = ""
+		w.updateButtons()
+	}
+
+	w.playButton = widget.NewButtonWithIcon("Play", theme.MediaPlayIcon(), func() {
+		w.playSelected(w.presenter.OnPlayTracks)
+	})
+	w.playNextButton = widget.NewButtonWithIcon("Play Next", theme.MediaSkipNextIcon(), func() {
+		w.playSelected(w.presenter.OnPlayNextTracks)
+	})
+	w.enqueueButton = widget.NewButtonWithIcon("Add to Queue", theme.ContentAddIcon(), func() {
+		w.playSelected(w.presenter.OnEnqueueTracks)
+	})
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), w.reload)
+
+	w.status = widget.NewLabel("")
+	w.updateButtons()
+
+	toolbar := container.NewHBox(w.playButton, w.playNextButton, w.enqueueButton, refreshButton)
+	w.window.SetContent(container.NewBorder(toolbar, w.status, nil, nil, w.tree))
+}
+
+// childIDs returns the IDs of the subfolders and tracks of a tree node.
+func (w *FolderBrowserWindow) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	var folders []*domain.FolderNode
+	var tracks []domain.MusicTrack
+	if id == "" {
+		folders = w.roots
+	} else if node, ok := w.folders[id]; ok {
+		folders = node.Folders
+		tracks = node.Tracks
+	}
+
+	ids := make([]widget.TreeNodeID, 0, len(folders)+len(tracks))
+	for _, folder := range folders {
+		ids = append(ids, folderNodePrefix+folder.Path)
+	}
+	for _, track := range tracks {
+		ids = append(ids, trackNodePrefix+track.FilePath)
+	}
+	return ids
+}
+
+// isBranch reports whether a tree node is a folder.
+func (w *FolderBrowserWindow) isBranch(id widget.TreeNodeID) bool {
+	return id == "" || strings.HasPrefix(id, folderNodePrefix)
+}
+
+// createNode creates a tree node with an icon and a label.
+func (w *FolderBrowserWindow) createNode(bool) fyneapp.CanvasObject {
+	return container.NewHBox(widget.NewIcon(theme.FolderIcon()), widget.NewLabel(""))
+}
+
+// updateNode shows a folder with its track count, or a track with its title.
+func (w *FolderBrowserWindow) updateNode(id widget.TreeNodeID, branch bool, obj fyneapp.CanvasObject) {
+	box, ok := obj.(*fyneapp.Container)
+	if !ok || len(box.Objects) != 2 {
+		return
+	}
+	icon, _ := box.Objects[0].(*widget.Icon)
+	label, _ := box.Objects[1].(*widget.Label)
+	if icon == nil || label == nil {
+		return
+	}
+
+	if branch {
+		icon.SetResource(theme.FolderIcon())
+		if node, ok := w.folders[id]; ok {
+			label.SetText(fmt.Sprintf("%s (%d)", node.Name, node.TrackCount()))
+		}
+		return
+	}
+
+	icon.SetResource(theme.FileAudioIcon())
+	track, ok := w.tracks[id]
+	if !ok {
+		return
+	}
+	label.SetText(trackLabel(track))
+}
+
+// trackLabel returns "Artist - Title" for a track, or its title or file name
+// when tags are missing.
+func trackLabel(track domain.MusicTrack) string {
+	text := trackFileName(track)
+	if track.Title != "" && track.Artist != "" {
+		text = track.Artist + " - " + track.Title
+	} else if track.Title != "" {
+		text = track.Title
+	}
+	if track.Missing {
+		text = "(missing) " + text
+	}
+	return text
+}
+
+// trackFileName returns the file name of a track, or its entry name inside an archive.
+func trackFileName(track domain.MusicTrack) string {
+	if _, entryName, ok := domain.SplitArchivePath(track.FilePath); ok {
+		return entryName
+	}
+	path := strings.ReplaceAll(track.FilePath, "\\", "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// selectedTracks returns the tracks of the selected folder (including subfolders) or the selected track.
+func (w *FolderBrowserWindow) selectedTracks() []domain.MusicTrack {
+	if node, ok := w.folders[w.selected]; ok {
+		return node.AllTracks()
+	}
+	if track, ok := w.tracks[w.selected]; ok {
+		return []domain.MusicTrack{track}
+	}
+	return nil
+}
+
+// playSelected passes the tracks of the selected folder or track to queue,
+// which plays, plays next or enqueues them.
+func (w *FolderBrowserWindow) playSelected(queue func([]domain.MusicTrack) error) {
+	tracks := w.selectedTracks()
+	if len(tracks) == 0 {
+		return
+	}
+
+	if err := queue(tracks); err != nil {
+		w.logger.Error("failed to queue folder tracks", slog.Any("error", err), slog.Int("tracks", len(tracks)))
+		dialog.ShowError(err, w.window)
+	}
+}
+
+// updateButtons enables the actions when a folder or track with tracks is selected.
+func (w *FolderBrowserWindow) updateButtons() {
+	count := len(w.selectedTracks())
+	if count == 0 {
+		w.playButton.Disable()
+		w.playNextButton.Disable()
+		w.enqueueButton.Disable()
+	} else {
+		w.playButton.Enable()
+		w.playNextButton.Enable()
+		w.enqueueButton.Enable()
+	}
+
+	if w.status == nil {
+		return
+	}
+	switch {
+	case len(w.roots) == 0:
+		w.status.SetText("Open a folder to add it to the library.")
+	case count == 1:
+		w.status.SetText("1 track selected")
+	case count > 1:
+		w.status.SetText(fmt.Sprintf("%d tracks selected", count))
+	default:
+		w.status.SetText("")
+	}
+}
+
+// reload rebuilds the tree from the library.
+func (w *FolderBrowserWindow) reload() {
+	roots, err := w.presenter.GetFolderTree()
+	if err != nil {
+		w.logger.Error("failed to load folder tree", slog.Any("error", err))
+		dialog.ShowError(err, w.window)
+		return
+	}
+
+	w.roots = roots
+	w.folders = make(map[string]*domain.FolderNode)
+	w.tracks = make(map[string]domain.MusicTrack)
+	var index func(node *domain.FolderNode)
+	index = func(node *domain.FolderNode) {
+		w.folders[folderNodePrefix+node.Path] = node
+		for _, track := range node.Tracks {
+			w.tracks[trackNodePrefix+track.FilePath] = track
+		}
+		for _, folder := range node.Folders {
+			index(folder)
+		}
+	}
+	for _, root := range roots {
+		index(root)
+	}
+
+	if _, ok := w.folders[w.selected]; !ok {
+		if _, ok := w.tracks[w.selected]; !ok {
+			w.selected = ""
+			w.tree.UnselectAll()
+		}
+	}
+	w.tree.Refresh()
+	w.updateButtons()
+}
+
+// onLibraryChanged rebuilds the tree when the library changes.
+func (w *FolderBrowserWindow) onLibraryChanged(domain.Event) {
+	fyneapp.Do(w.reload)
+}
+
+// unsubscribeFromEvents unsubscribes from all events.
+func (w *FolderBrowserWindow) unsubscribeFromEvents() {
+	for _, sub := range w.subscriptions {
+		w.eventBus.Unsubscribe(sub)
+	}
+	w.subscriptions = nil
+}
+
+// Show displays the folder browser window.
+func (w *FolderBrowserWindow) Show() {
+	w.isVisible = true
+	w.window.Show()
+}
+
+// Close closes the folder browser window.
+func (w *FolderBrowserWindow) Close() {
+	w.isVisible = false
+	w.unsubscribeFromEvents()
+	w.window.Close()
+}
+
+// IsVisible returns whether the window is currently visible.
+func (w *FolderBrowserWindow) IsVisible() bool {
+	return w.isVisible
+}
+
+// SetOnWindowClosed sets a callback to be invoked when the window is closed.
+func (w *FolderBrowserWindow) SetOnWindowClosed(callback func()) {
+	w.onWindowClosed = callback
+}
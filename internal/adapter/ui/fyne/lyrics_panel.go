@@ -0,0 +1,104 @@
+package fyne
+
+import (
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// LyricsPanel shows the lyrics of the current track.
+// For synced lyrics, the active line is highlighted and kept in view.
+// It must only be used from the Fyne UI goroutine.
+type LyricsPanel struct {
+	widget.BaseWidget
+
+	list   *widget.List
+	lines  []domain.LyricsLine
+	synced bool
+	active int
+}
+
+// NewLyricsPanel creates an empty lyrics panel.
+func NewLyricsPanel() *LyricsPanel {
+	p := &LyricsPanel{active: -1}
+
+	p.list = widget.NewList(
+		func() int {
+			return len(p.lines)
+		},
+		func() fyneapp.CanvasObject {
+			label := widget.NewLabel("")
+			label.Wrapping = fyneapp.TextWrapWord
+			label.Alignment = fyneapp.TextAlignCenter
+			return label
+		},
+		func(id widget.ListItemID, item fyneapp.CanvasObject) {
+			p.updateLine(id, item.(*widget.Label))
+		},
+	)
+	// Lines are not selectable; tapping would only highlight the wrong line
+	p.list.OnSelected = func(id widget.ListItemID) {
+		p.list.Unselect(id)
+	}
+
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// updateLine renders one lyrics line, highlighting the active one.
+func (p *LyricsPanel) updateLine(id widget.ListItemID, label *widget.Label) {
+	if id < 0 || id >= len(p.lines) {
+		return
+	}
+
+	isActive := p.synced && id == p.active
+	label.TextStyle = fyneapp.TextStyle{Bold: isActive}
+	switch {
+	case isActive:
+		label.Importance = widget.HighImportance
+	case p.synced && id < p.active:
+		label.Importance = widget.LowImportance // Already sung
+	default:
+		label.Importance = widget.MediumImportance
+	}
+	label.SetText(p.lines[id].Text)
+}
+
+// SetLyrics replaces the displayed lyrics. Nil lyrics clear the panel.
+func (p *LyricsPanel) SetLyrics(lyrics *domain.Lyrics) {
+	p.lines = nil
+	p.synced = false
+	if lyrics != nil {
+		p.lines = lyrics.Lines
+		p.synced = lyrics.Synced
+	}
+	p.active = -1
+
+	p.list.Refresh()
+	p.list.ScrollToTop()
+}
+
+// SetActiveLine highlights the line at index (-1 for none) and scrolls it into view.
+func (p *LyricsPanel) SetActiveLine(index int) {
+	if !p.synced || index == p.active || index >= len(p.lines) {
+		return
+	}
+	p.active = index
+
+	p.list.Refresh()
+	if index >= 0 {
+		p.list.ScrollTo(index)
+	} else {
+		p.list.ScrollToTop()
+	}
+}
+
+// HasLyrics returns true if the panel has lyrics to show.
+func (p *LyricsPanel) HasLyrics() bool {
+	return len(p.lines) > 0
+}
+
+// CreateRenderer implements fyne.Widget.
+func (p *LyricsPanel) CreateRenderer() fyneapp.WidgetRenderer {
+	return widget.NewSimpleRenderer(p.list)
+}
@@ -19,6 +19,7 @@ import (
 	xdialog "fyne.io/x/fyne/dialog"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/ui/credits"
 	customwidgets "github.com/tejashwikalptaru/gotune/internal/adapter/ui/fyne/widgets"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
 	"github.com/tejashwikalptaru/gotune/res"
 )
 
@@ -40,7 +41,7 @@ type MainWindow struct {
 	stopButton     *widget.Button
 	nextButton     *widget.Button
 	muteButton     *widget.Button
-	loopButton     *widget.Button
+	repeatButton   *widget.Button
 	songInfo       *widget.Label
 	currentTime    *widget.Label
 	endTime        *widget.Label
@@ -48,6 +49,10 @@ type MainWindow struct {
 	volumeSlider   *widget.Slider
 	albumArt       *canvas.Image
 
+	// Edit menu items (labelled with the queue edit they undo or redo)
+	undoItem *fyneapp.MenuItem
+	redoItem *fyneapp.MenuItem
+
 	// State
 	isDarkTheme      bool
 	rotator          *customwidgets.Rotator
@@ -63,9 +68,20 @@ type MainWindow struct {
 	visualizerEnabled     bool
 	visualizerMu          sync.Mutex
 
+	// Lyrics panel (shown next to the album art when the track has lyrics)
+	lyricsPanel  *LyricsPanel
+	lyricsHidden bool // Set when the user hides the panel
+	displaySplit *container.Split
+
 	// Playlist window (optional)
 	playlistWindow *PlaylistWindow
 
+	// Folder browser window (optional)
+	folderBrowser *FolderBrowserWindow
+
+	// Recently Played window (optional)
+	recentlyPlayed *RecentlyPlayedWindow
+
 	// Lifecycle management
 	closeOnce sync.Once
 	scrollWg  sync.WaitGroup // WaitGroup to wait for scroll goroutine to exit
@@ -159,7 +175,7 @@ func (w *MainWindow) buildUI() {
 	w.stopButton = widget.NewButtonWithIcon("", theme.MediaStopIcon(), nil)
 	w.nextButton = widget.NewButtonWithIcon("", theme.MediaSkipNextIcon(), nil)
 	w.muteButton = widget.NewButtonWithIcon("", theme.VolumeUpIcon(), nil)
-	w.loopButton = widget.NewButtonWithIcon("", theme.MediaReplayIcon(), nil)
+	w.repeatButton = widget.NewButtonWithIcon("", theme.MediaReplayIcon(), nil)
 
 	// Song info label
 	w.songInfo = widget.NewLabel("")
@@ -178,7 +194,7 @@ func (w *MainWindow) buildUI() {
 	// Button container
 	buttonsHBox := container.NewHBox(
 		w.prevButton, w.playButton, w.stopButton,
-		w.nextButton, w.muteButton, w.loopButton,
+		w.nextButton, w.muteButton, w.repeatButton,
 	)
 	buttonsHolder := container.NewBorder(nil, nil, buttonsHBox, volumeHolder, w.songInfo)
 
@@ -188,9 +204,15 @@ func (w *MainWindow) buildUI() {
 	w.endTime = widget.NewLabel("00:00")
 	sliderHolder := container.NewBorder(nil, nil, w.currentTime, w.endTime, w.progressSlider)
 
+	// Lyrics panel (hidden until a track with lyrics is loaded)
+	w.lyricsPanel = NewLyricsPanel()
+	w.lyricsPanel.Hide()
+	w.displaySplit = container.NewHSplit(w.tappableStack, w.lyricsPanel)
+	w.displaySplit.SetOffset(0.5)
+
 	// Main layout
 	controls := container.NewVBox(buttonsHolder, sliderHolder)
-	splitContainer := container.NewBorder(nil, controls, nil, nil, w.tappableStack)
+	splitContainer := container.NewBorder(nil, controls, nil, nil, w.displaySplit)
 	w.window.SetContent(container.NewPadded(splitContainer))
 
 	// Menu
@@ -224,8 +246,8 @@ func (w *MainWindow) wirePresenterHandlers() {
 		w.presenter.OnMuteClicked()
 	}
 
-	w.loopButton.OnTapped = func() {
-		w.presenter.OnLoopClicked()
+	w.repeatButton.OnTapped = func() {
+		w.presenter.OnRepeatClicked()
 	}
 
 	// Volume slider
@@ -263,13 +285,87 @@ func (w *MainWindow) createMenu() []*fyneapp.Menu {
 		}
 	})
 
+	browseFolders := fyneapp.NewMenuItem("Browse Folders", func() {
+		if w.presenter != nil {
+			w.showFolderBrowser()
+		}
+	})
+
+	recentlyPlayed := fyneapp.NewMenuItem("Recently Played", func() {
+		if w.presenter != nil {
+			w.showRecentlyPlayed()
+		}
+	})
+
+	importPlaylist := fyneapp.NewMenuItem("Import Playlist...", func() {
+		if w.presenter != nil {
+			NewPlaylistFileDialog(w.window, w.presenter, w.logger).ShowImport()
+		}
+	})
+
+	exportPlaylist := fyneapp.NewMenuItem("Export Playlist...", func() {
+		if w.presenter != nil {
+			NewPlaylistFileDialog(w.window, w.presenter, w.logger).ShowExport()
+		}
+	})
+
+	checkMissing := fyneapp.NewMenuItem("Check for Missing Files", func() {
+		if w.presenter != nil {
+			ShowMissingFilesReport(w.window, w.presenter)
+		}
+	})
+
+	relocateFiles := fyneapp.NewMenuItem("Relocate Files...", func() {
+		if w.presenter != nil {
+			NewRelocateDialog(w.window, w.presenter, w.logger).Show()
+		}
+	})
+
+	scanReport := fyneapp.NewMenuItem("Scan Report...", func() {
+		if w.presenter != nil {
+			NewScanReportDialog(w.window, w.presenter, w.logger).Show()
+		}
+	})
+
+	scanSettings := fyneapp.NewMenuItem("Scan Settings...", func() {
+		if w.presenter != nil {
+			NewScanSettingsDialog(w.window, w.presenter, w.logger).Show()
+		}
+	})
+
+	statsSettings := fyneapp.NewMenuItem("Play Count Settings...", func() {
+		if w.presenter != nil {
+			NewStatsSettingsDialog(w.window, w.presenter, w.logger).Show()
+		}
+	})
+
 	exitMenu := fyneapp.NewMenuItem("Exit", func() {
 		w.window.Close()
 	})
 
-	fileMenuItems := fyneapp.NewMenu("File", openFile, openFolder, separator, viewPlaylist, separator, exitMenu)
+	fileMenuItems := fyneapp.NewMenu("File", openFile, openFolder, separator, viewPlaylist, browseFolders, recentlyPlayed, separator,
+		importPlaylist, exportPlaylist, separator, checkMissing, relocateFiles, scanReport, scanSettings, statsSettings, separator, exitMenu)
 	menus = append(menus, fileMenuItems)
 
+	w.undoItem = fyneapp.NewMenuItem("Undo", func() {
+		if w.presenter != nil {
+			w.presenter.OnUndoClicked()
+		}
+	})
+	w.undoItem.Shortcut = &fyneapp.ShortcutUndo{}
+	w.undoItem.Disabled = true
+
+	w.redoItem = fyneapp.NewMenuItem("Redo", func() {
+		if w.presenter != nil {
+			w.presenter.OnRedoClicked()
+		}
+	})
+	w.redoItem.Shortcut = &fyneapp.ShortcutRedo{}
+	w.redoItem.Disabled = true
+
+	editMenu := fyneapp.NewMenu("Edit", w.undoItem, w.redoItem)
+	menus = append(menus, editMenu)
+
 	creditsItem := fyneapp.NewMenuItem("Credits", func() {
 		w.showCreditsDialog()
 	})
@@ -419,8 +515,10 @@ func (w *MainWindow) ShowAndRun() {
 // It's safe to call multiple times (idempotent).
 func (w *MainWindow) Close() {
 	w.closeOnce.Do(func() {
-		// Close the playlist window if open
+		// Close the playlist, folder browser and Recently Played windows if open
 		w.ClosePlaylistWindow()
+		w.closeFolderBrowser()
+		w.closeRecentlyPlayed()
 
 		// Signal the scroll goroutine to stop
 		close(w.stopScroll)
@@ -464,8 +562,30 @@ func (w *MainWindow) SetMuteState(muted bool) {
 	})
 }
 
-// SetLoopState updates the loop button state.
-func (w *MainWindow) SetLoopState(enabled bool) {
+// SetUndoActions updates the undo and redo menu items, e.g. "Undo Remove Track".
+func (w *MainWindow) SetUndoActions(undoAction, redoAction string) {
+	fyneapp.Do(func() {
+		w.undoItem.Label = undoLabel("Undo", undoAction)
+		w.undoItem.Disabled = undoAction == ""
+		w.redoItem.Label = undoLabel("Redo", redoAction)
+		w.redoItem.Disabled = redoAction == ""
+		if menu := w.window.MainMenu(); menu != nil {
+			menu.Refresh()
+		}
+	})
+}
+
+// undoLabel names an undo or redo command after the queue edit, e.g. "Undo Remove Track".
+func undoLabel(command, action string) string {
+	if action == "" {
+		return command
+	}
+	return command + " " + action
+}
+
+// SetRepeatMode updates the repeat button state.
+// Repeat one shows the repeat icon with a "1" next to it.
+func (w *MainWindow) SetRepeatMode(mode domain.RepeatMode) {
 	fyneapp.Do(func() {
 		var icon *fyneapp.StaticResource
 		if w.isDarkTheme {
@@ -474,12 +594,18 @@ func (w *MainWindow) SetLoopState(enabled bool) {
 			icon = res.ResourceRepeatDarkPng
 		}
 
-		if enabled {
-			w.loopButton.SetIcon(icon)
-		} else {
-			w.loopButton.SetIcon(theme.MediaReplayIcon())
+		switch mode {
+		case domain.RepeatAll:
+			w.repeatButton.SetIcon(icon)
+			w.repeatButton.SetText("")
+		case domain.RepeatOne:
+			w.repeatButton.SetIcon(icon)
+			w.repeatButton.SetText("1")
+		default:
+			w.repeatButton.SetIcon(theme.MediaReplayIcon())
+			w.repeatButton.SetText("")
 		}
-		w.loopButton.Refresh()
+		w.repeatButton.Refresh()
 	})
 }
 
@@ -607,6 +733,66 @@ func (w *MainWindow) ClosePlaylistWindow() {
 	})
 }
 
+// showFolderBrowser displays the folder browser window.
+func (w *MainWindow) showFolderBrowser() {
+	fyneapp.Do(func() {
+		if w.folderBrowser == nil {
+			w.folderBrowser = NewFolderBrowserWindow(
+				w.app,
+				w.presenter,
+				w.presenter.EventBus,
+				w.logger,
+			)
+			// Set callback to clear reference when a window is closed
+			w.folderBrowser.SetOnWindowClosed(func() {
+				fyneapp.Do(func() {
+					w.folderBrowser = nil
+				})
+			})
+		}
+		w.folderBrowser.Show()
+	})
+}
+
+// closeFolderBrowser closes the folder browser window if it's open.
+func (w *MainWindow) closeFolderBrowser() {
+	fyneapp.Do(func() {
+		if w.folderBrowser != nil {
+			w.folderBrowser.Close()
+		}
+	})
+}
+
+// showRecentlyPlayed displays the Recently Played window.
+func (w *MainWindow) showRecentlyPlayed() {
+	fyneapp.Do(func() {
+		if w.recentlyPlayed == nil {
+			w.recentlyPlayed = NewRecentlyPlayedWindow(
+				w.app,
+				w.presenter,
+				w.presenter.EventBus,
+				w.logger,
+			)
+			// Set callback to clear reference when a window is closed
+			w.recentlyPlayed.SetOnWindowClosed(func() {
+				fyneapp.Do(func() {
+					w.recentlyPlayed = nil
+				})
+			})
+		}
+		w.recentlyPlayed.Show()
+	})
+}
+
+// closeRecentlyPlayed closes the Recently Played window if it's open.
+func (w *MainWindow) closeRecentlyPlayed() {
+	fyneapp.Do(func() {
+		if w.recentlyPlayed != nil {
+			w.recentlyPlayed.Close()
+		}
+	})
+}
+
 // IsPlaylistWindowOpen returns whether the playlist window is currently open.
 func (w *MainWindow) IsPlaylistWindowOpen() bool {
 	return w.playlistWindow != nil && w.playlistWindow.IsVisible()
@@ -657,7 +843,18 @@ func (w *MainWindow) showDisplayModeMenu(pos fyneapp.Position) {
 	visualizerSubmenu := fyneapp.NewMenuItem("Visualizer", nil)
 	visualizerSubmenu.ChildMenu = fyneapp.NewMenu("", visualizerItems...)
 
-	menu := fyneapp.NewMenu("", albumArtItem, visualizerSubmenu)
+	// Lyrics toggle (only offered when the track has lyrics)
+	lyricsLabel := "Lyrics"
+	if !w.lyricsHidden {
+		lyricsLabel = "\u2713 " + lyricsLabel
+	}
+	lyricsItem := fyneapp.NewMenuItem(lyricsLabel, func() {
+		w.lyricsHidden = !w.lyricsHidden
+		w.updateLyricsVisibility()
+	})
+	lyricsItem.Disabled = !w.lyricsPanel.HasLyrics()
+
+	menu := fyneapp.NewMenu("", albumArtItem, visualizerSubmenu, fyneapp.NewMenuItemSeparator(), lyricsItem)
 	popup := widget.NewPopUpMenu(menu, w.window.Canvas())
 	popup.ShowAtPosition(pos)
 }
@@ -700,6 +897,32 @@ func (w *MainWindow) switchVisualizer(visType customwidgets.VisualizerType) {
 	})
 }
 
+// SetLyrics shows the lyrics of the current track, or hides the panel if lyrics is nil.
+func (w *MainWindow) SetLyrics(lyrics *domain.Lyrics) {
+	fyneapp.Do(func() {
+		w.lyricsPanel.SetLyrics(lyrics)
+		w.updateLyricsVisibility()
+	})
+}
+
+// SetActiveLyricsLine highlights the synced lyrics line being sung.
+func (w *MainWindow) SetActiveLyricsLine(index int) {
+	fyneapp.Do(func() {
+		w.lyricsPanel.SetActiveLine(index)
+	})
+}
+
+// updateLyricsVisibility shows the lyrics panel if there are lyrics and the user has not hidden it.
+// Must be called on the UI goroutine.
+func (w *MainWindow) updateLyricsVisibility() {
+	if w.lyricsPanel.HasLyrics() && !w.lyricsHidden {
+		w.lyricsPanel.Show()
+	} else {
+		w.lyricsPanel.Hide()
+	}
+	w.displaySplit.Refresh()
+}
+
 // UpdateVisualizer updates the visualizer with new FFT data.
 func (w *MainWindow) UpdateVisualizer(data []float32) {
 	fyneapp.Do(func() {
@@ -0,0 +1,199 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// maxListedUnresolvedEntries limits how many unresolved entries are listed after an import.
+const maxListedUnresolvedEntries = 10
+
+// Import destinations offered by the import dialog.
+const (
+	importToQueue    = "Add to queue"
+	importAsPlaylist = "Save as new playlist"
+)
+
+// exportQueueLabel is the export source that stands for the queue.
+const exportQueueLabel = "Queue"
+
+// defaultExportExtension is the preselected export format.
+const defaultExportExtension = ".m3u8"
+
+// PlaylistFileDialog imports playlist files (M3U, PLS, XSPF) into the queue or a new
+// playlist, and exports the queue or a saved playlist to a playlist file.
+type PlaylistFileDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	logger    *slog.Logger
+}
+
+// NewPlaylistFileDialog creates a new playlist file dialog.
+func NewPlaylistFileDialog(window fyneapp.Window, presenter *Presenter, logger *slog.Logger) *PlaylistFileDialog {
+	return &PlaylistFileDialog{
+		window:    window,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+// ShowImport asks for a playlist file and where to import it.
+func (d *PlaylistFileDialog) ShowImport() {
+	openDialog := dialog.NewFileOpen(func(reader fyneapp.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, d.window)
+			return
+		}
+		if reader == nil {
+			return // User cancelled
+		}
+		filePath := reader.URI().Path()
+		_ = reader.Close()
+		d.showImportOptions(filePath)
+	}, d.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter(d.presenter.SupportedPlaylistExtensions()))
+	openDialog.Show()
+}
+
+// showImportOptions asks whether to add the file to the queue or save it as a playlist.
+func (d *PlaylistFileDialog) showImportOptions(filePath string) {
+	name := widget.NewEntry()
+	name.SetText(strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)))
+	name.Disable()
+
+	destination := widget.NewRadioGroup([]string{importToQueue, importAsPlaylist}, func(selected string) {
+		if selected == importAsPlaylist {
+			name.Enable()
+		} else {
+			name.Disable()
+		}
+	})
+	destination.Required = true
+	destination.SetSelected(importToQueue)
+
+	form := widget.NewForm(
+		widget.NewFormItem("File", widget.NewLabel(filepath.Base(filePath))),
+		widget.NewFormItem("Import", destination),
+		widget.NewFormItem("Playlist name", name),
+	)
+
+	importDialog := dialog.NewCustomConfirm("Import Playlist", "Import", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		result, err := d.presenter.OnImportPlaylist(filePath, destination.Selected == importToQueue, name.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to import playlist: %w", err), d.window)
+			return
+		}
+		dialog.ShowInformation("Import Playlist", formatPlaylistImport(result), d.window)
+	}, d.window)
+	importDialog.Resize(fyneapp.NewSize(480, 0))
+	importDialog.Show()
+}
+
+// ShowExport asks what to export and how paths are written, then for the file to write.
+func (d *PlaylistFileDialog) ShowExport() {
+	playlists, err := d.presenter.GetSavedPlaylists()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load playlists: %w", err), d.window)
+		return
+	}
+
+	options := []string{exportQueueLabel}
+	ids := map[string]string{exportQueueLabel: ""}
+	for _, playlist := range playlists {
+		label := playlist.Name
+		if _, taken := ids[label]; taken {
+			label = fmt.Sprintf("%s (%s)", playlist.Name, playlist.ID)
+		}
+		options = append(options, label)
+		ids[label] = playlist.ID
+	}
+
+	source := widget.NewSelect(options, nil)
+	source.SetSelected(exportQueueLabel)
+	extensions := d.presenter.SupportedPlaylistExtensions()
+	format := widget.NewSelect(extensions, nil)
+	format.SetSelected(defaultExportExtension)
+	if format.Selected == "" && len(extensions) > 0 {
+		format.SetSelected(extensions[0])
+	}
+	relative := widget.NewCheck("Write paths relative to the playlist file", nil)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Export", source),
+		widget.NewFormItem("Format", format),
+		widget.NewFormItem("", relative),
+	)
+
+	exportDialog := dialog.NewCustomConfirm("Export Playlist", "Export...", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		fileName := source.Selected
+		if fileName == exportQueueLabel {
+			fileName = "queue"
+		}
+		d.export(ids[source.Selected], fileName+format.Selected, relative.Checked)
+	}, d.window)
+	exportDialog.Resize(fyneapp.NewSize(480, 0))
+	exportDialog.Show()
+}
+
+// export asks for the file and writes the queue (empty playlistID) or a saved playlist to it.
+// The format follows the extension of the chosen file.
+func (d *PlaylistFileDialog) export(playlistID, fileName string, relative bool) {
+	saveDialog := dialog.NewFileSave(func(writer fyneapp.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, d.window)
+			return
+		}
+		if writer == nil {
+			return // User cancelled
+		}
+		// The playlist format writes the file itself
+		filePath := writer.URI().Path()
+		_ = writer.Close()
+
+		if err := d.presenter.OnExportPlaylist(playlistID, filePath, relative); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to export playlist: %w", err), d.window)
+			return
+		}
+		d.logger.Info("playlist exported", slog.String("path", filePath))
+	}, d.window)
+	saveDialog.SetFileName(fileName)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter(d.presenter.SupportedPlaylistExtensions()))
+	saveDialog.Show()
+}
+
+// formatPlaylistImport describes an import, listing the first few entries that could not be resolved.
+func formatPlaylistImport(result *domain.PlaylistImport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d track(s) imported", len(result.Tracks))
+	if result.PlaylistID != "" {
+		fmt.Fprintf(&b, " into playlist %q", result.Name)
+	}
+	b.WriteString(".")
+
+	if len(result.Unresolved) == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n\n%d playlist entries could not be imported:\n", len(result.Unresolved))
+	for i, unresolved := range result.Unresolved {
+		if i == maxListedUnresolvedEntries {
+			fmt.Fprintf(&b, "\n...and %d more", len(result.Unresolved)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\nLine %d: %s (%s)", unresolved.Entry.Line, unresolved.Entry.Path, unresolved.Reason)
+	}
+	return b.String()
+}
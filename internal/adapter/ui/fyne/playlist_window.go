@@ -1,13 +1,18 @@
 package fyne
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
 	fyneapp "fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/tejashwikalptaru/gotune/internal/adapter/ui/fyne/widgets"
@@ -16,18 +21,35 @@ import (
 )
 
 // PlaylistWindow manages the playlist view window.
-// It displays the current playback queue with search functionality
-// and responds to playlist events for live updates.
+// It displays the current playback queue with search functionality, the
+// Up Next tracks played before the rest of the queue, and responds to
+// playlist events for live updates.
 type PlaylistWindow struct {
 	window      fyneapp.Window
 	app         fyneapp.App
 	list        *widget.List
 	searchEntry *widget.Entry
+	searchError *widget.Label
+	shuffle     *widget.Select
+	undoButton  *widget.Button
+	redoButton  *widget.Button
+	sortHeaders []*widget.Button
+
+	// Up Next section (hidden while Up Next is empty)
+	upNextSection *fyneapp.Container
+	upNextLabel   *widget.Label
+	upNextList    *widget.List
 
 	// Data state
-	data           []domain.MusicTrack // Filtered view (shown in the list)
-	mainCollection []domain.MusicTrack // Full queue
-	currentIndex   int                 // Selected track index
+	data            []domain.MusicTrack   // Filtered view (shown in the list)
+	mainCollection  []domain.MusicTrack   // Full queue
+	currentIndex    int                   // Selected track index
+	searchPredicate domain.TrackPredicate // Compiled search query (nil when not searching)
+	upNext          []domain.MusicTrack   // Up Next tracks (not filtered by search)
+	selection       map[string]bool       // IDs of the selected tracks
+	selectionAnchor int                   // Filtered index a Shift+click range starts from
+	sortField       domain.QueueSortField // Field of the last sort (empty if not sorted yet)
+	sortDirection   domain.SortDirection  // Direction of the last sort
 
 	// Dependencies
 	presenter     *Presenter
@@ -39,14 +61,37 @@ type PlaylistWindow struct {
 	isVisible      bool
 }
 
+// shuffleLabels are the shuffle modes as shown in the shuffle selector.
+var shuffleLabels = map[domain.ShuffleMode]string{
+	domain.ShuffleOff:    "Off",
+	domain.ShuffleTracks: "Tracks",
+	domain.ShuffleAlbums: "Albums",
+}
+
+// sortColumns are the column headers above the playlist; clicking one sorts the queue by its field.
+var sortColumns = []struct {
+	label string
+	field domain.QueueSortField
+}{
+	{"Title", domain.QueueSortTitle},
+	{"Artist", domain.QueueSortArtist},
+	{"Album", domain.QueueSortAlbum},
+	{"Year", domain.QueueSortYear},
+	{"Track", domain.QueueSortTrack},
+	{"Genre", domain.QueueSortGenre},
+	{"Length", domain.QueueSortDuration},
+}
+
 // NewPlaylistWindow creates a new playlist window.
 // It initializes the UI, subscribes to events, and loads the current queue.
 func NewPlaylistWindow(app fyneapp.App, presenter *Presenter, eventBus ports.EventBus) *PlaylistWindow {
 	w := &PlaylistWindow{
-		app:          app,
-		presenter:    presenter,
-		eventBus:     eventBus,
-		currentIndex: -1,
+		app:             app,
+		presenter:       presenter,
+		eventBus:        eventBus,
+		currentIndex:    -1,
+		selection:       make(map[string]bool),
+		selectionAnchor: -1,
 	}
 
 	// Create the window
@@ -55,6 +100,7 @@ func NewPlaylistWindow(app fyneapp.App, presenter *Presenter, eventBus ports.Eve
 
 	// Build UI
 	w.buildUI()
+	w.addShortcuts()
 
 	// Subscribe to events
 	w.subscribeToEvents()
@@ -78,11 +124,29 @@ func NewPlaylistWindow(app fyneapp.App, presenter *Presenter, eventBus ports.Eve
 func (w *PlaylistWindow) buildUI() {
 	// Create the search entry
 	w.searchEntry = widget.NewEntry()
-	w.searchEntry.SetPlaceHolder("Search...")
+	w.searchEntry.SetPlaceHolder(`Search... (e.g. artist:"pink floyd" year:>=1975 -live)`)
 	w.searchEntry.OnChanged = func(query string) {
 		w.searchCollection(query)
 	}
 
+	// Create the search error label (shown only for invalid queries)
+	w.searchError = widget.NewLabel("")
+	w.searchError.Importance = widget.DangerImportance
+	w.searchError.Wrapping = fyneapp.TextWrapWord
+	w.searchError.Hide()
+
+	// Create the shuffle selector
+	w.shuffle = widget.NewSelect(
+		[]string{shuffleLabels[domain.ShuffleOff], shuffleLabels[domain.ShuffleTracks], shuffleLabels[domain.ShuffleAlbums]},
+		w.onShuffleSelected,
+	)
+
+	// Create the undo and redo buttons (enabled by the queue history)
+	w.undoButton = widget.NewButtonWithIcon("", theme.ContentUndoIcon(), w.undo)
+	w.redoButton = widget.NewButtonWithIcon("", theme.ContentRedoIcon(), w.redo)
+	w.undoButton.Disable()
+	w.redoButton.Disable()
+
 	// Create the list widget
 	w.list = widget.NewList(
 		func() int {
@@ -96,13 +160,61 @@ func (w *PlaylistWindow) buildUI() {
 		},
 	)
 
+	// Create the Up Next section
+	w.upNextList = widget.NewList(
+		func() int {
+			return len(w.upNext)
+		},
+		func() fyneapp.CanvasObject {
+			label := widgets.NewDoubleTapLabel(nil)
+			label.SetSecondaryTapped(w.onUpNextSecondaryTapped)
+			return label
+		},
+		func(i widget.ListItemID, obj fyneapp.CanvasObject) {
+			label, ok := obj.(*widgets.DoubleTapLabel)
+			if !ok || i < 0 || i >= len(w.upNext) {
+				return
+			}
+			label.SetIndex(i)
+			label.SetText(trackDisplayText(w.upNext[i]))
+		},
+	)
+	w.upNextLabel = widget.NewLabel("")
+	w.upNextLabel.TextStyle = fyneapp.TextStyle{Bold: true}
+	clearUpNext := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
+		if w.presenter != nil {
+			w.presenter.OnClearUpNext()
+		}
+	})
+	w.upNextSection = container.NewBorder(
+		container.NewBorder(nil, nil, nil, clearUpNext, w.upNextLabel), nil, nil, nil, w.upNextList,
+	)
+	w.upNextSection.Hide()
+
+	// Create the column headers that sort the queue
+	headers := container.NewGridWithColumns(len(sortColumns))
+	for _, column := range sortColumns {
+		field := column.field
+		header := widget.NewButton(column.label, func() {
+			w.onSortHeaderTapped(field)
+		})
+		header.Importance = widget.LowImportance
+		header.IconPlacement = widget.ButtonIconTrailingText
+		w.sortHeaders = append(w.sortHeaders, header)
+		headers.Add(header)
+	}
+
 	// Create layout
+	shuffleBar := container.NewHBox(w.undoButton, w.redoButton, widget.NewLabel("Shuffle:"), w.shuffle)
+	searchBar := container.NewVBox(container.NewBorder(nil, nil, nil, shuffleBar, w.searchEntry), w.searchError, headers)
+	lists := container.NewVSplit(w.upNextSection, w.list)
+	lists.Offset = 0.3
 	content := container.NewBorder(
-		w.searchEntry, // Top
-		nil,           // Bottom
-		nil,           // Left
-		nil,           // Right
-		w.list,        // Center
+		searchBar, // Top
+		nil,       // Bottom
+		nil,       // Left
+		nil,       // Right
+		lists,     // Center
 	)
 
 	w.window.SetContent(content)
@@ -126,19 +238,80 @@ func (w *PlaylistWindow) updateCell(i widget.ListItemID, obj fyneapp.CanvasObjec
 		return
 	}
 
-	track := w.data[i]
 	label.SetIndex(i)
 
-	// Ensure secondary tap callback is set (handles cell reuse)
+	// Ensure the tap callbacks are set (handles cell reuse)
 	label.SetSecondaryTapped(w.onCellSecondaryTapped)
+	label.SetPressed(w.onCellPressed)
+
+	// Selected tracks are highlighted
+	label.Importance = widget.MediumImportance
+	if w.selection[w.data[i].ID] {
+		label.Importance = widget.HighImportance
+	}
+	label.SetText(trackDisplayText(w.data[i]))
+}
 
+// trackDisplayText formats a track for the queue and Up Next lists: its title
+// (or file path), followed by its technical details.
+func trackDisplayText(track domain.MusicTrack) string {
 	// Display the track title or filename if the title is empty
 	displayText := track.Title
 	if displayText == "" {
 		displayText = track.FilePath
 	}
+	if track.Missing {
+		displayText = "(missing) " + displayText
+	}
 
-	label.SetText(displayText)
+	// Followed by the codec, bitrate and other technical details
+	if details := formatTrackDetails(track); details != "" {
+		displayText += "  —  " + details
+	}
+	return displayText
+}
+
+// formatTrackDetails formats the technical metadata of a track for display,
+// e.g. "FLAC · 24-bit · 96 kHz · stereo · 1411 kbps · 6:53".
+// Unknown properties are left out.
+func formatTrackDetails(track domain.MusicTrack) string {
+	metadata := metadataOf(track)
+	parts := make([]string, 0, 6)
+
+	if metadata.Codec != "" {
+		parts = append(parts, metadata.Codec)
+	}
+	if metadata.BitDepth > 0 {
+		parts = append(parts, fmt.Sprintf("%d-bit", metadata.BitDepth))
+	}
+	if metadata.SampleRate > 0 {
+		parts = append(parts, strconv.FormatFloat(float64(metadata.SampleRate)/1000, 'f', -1, 64)+" kHz")
+	}
+	switch {
+	case metadata.Channels == 1:
+		parts = append(parts, "mono")
+	case metadata.Channels == 2:
+		parts = append(parts, "stereo")
+	case metadata.Channels > 2:
+		parts = append(parts, fmt.Sprintf("%d ch", metadata.Channels))
+	}
+	if metadata.BitRate > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", metadata.BitRate))
+	}
+	if track.Duration > 0 {
+		parts = append(parts, formatTrackDuration(track.Duration))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// formatTrackDuration formats a duration as m:ss, or h:mm:ss for long tracks.
+func formatTrackDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
 }
 
 // onCellDoubleTapped handles double-tap events on list cells.
@@ -164,22 +337,280 @@ func (w *PlaylistWindow) onCellDoubleTapped(index int) {
 	}
 }
 
+// onCellPressed updates the selection when a list cell is clicked:
+// a click selects the track, Ctrl/Cmd+click toggles it and Shift+click selects
+// the range from the previous click (added to the selection with Ctrl/Cmd).
+func (w *PlaylistWindow) onCellPressed(index int, modifier fyneapp.KeyModifier) {
+	if index < 0 || index >= len(w.data) {
+		return
+	}
+
+	extend := modifier&fyneapp.KeyModifierShortcutDefault != 0
+	switch {
+	case modifier&fyneapp.KeyModifierShift != 0 && w.selectionAnchor >= 0 && w.selectionAnchor < len(w.data):
+		if !extend {
+			clear(w.selection)
+		}
+		for i := min(w.selectionAnchor, index); i <= max(w.selectionAnchor, index); i++ {
+			w.selection[w.data[i].ID] = true
+		}
+	case extend:
+		id := w.data[index].ID
+		if w.selection[id] {
+			delete(w.selection, id)
+		} else {
+			w.selection[id] = true
+		}
+		w.selectionAnchor = index
+	default:
+		w.selectOnly(index)
+	}
+	w.list.Refresh()
+}
+
+// selectOnly selects the track at the given filtered index and nothing else.
+func (w *PlaylistWindow) selectOnly(index int) {
+	clear(w.selection)
+	w.selection[w.data[index].ID] = true
+	w.selectionAnchor = index
+}
+
+// selectAll selects all tracks shown in the list.
+func (w *PlaylistWindow) selectAll() {
+	for _, track := range w.data {
+		w.selection[track.ID] = true
+	}
+	w.list.Refresh()
+}
+
+// clearSelection deselects all tracks.
+func (w *PlaylistWindow) clearSelection() {
+	clear(w.selection)
+	w.selectionAnchor = -1
+	w.list.Refresh()
+}
+
+// selectedIndexes returns the queue indexes of the selected tracks, in queue order.
+func (w *PlaylistWindow) selectedIndexes() []int {
+	indexes := make([]int, 0, len(w.selection))
+	for i, track := range w.mainCollection {
+		if w.selection[track.ID] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// selectedTracks returns the selected tracks, in queue order.
+func (w *PlaylistWindow) selectedTracks() []domain.MusicTrack {
+	tracks := make([]domain.MusicTrack, 0, len(w.selection))
+	for _, track := range w.mainCollection {
+		if w.selection[track.ID] {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
 // onCellSecondaryTapped handles right-click (secondary tap) events on list cells.
+// Clicking a selected track acts on the whole selection.
 func (w *PlaylistWindow) onCellSecondaryTapped(index int, pos fyneapp.Position) {
 	if index < 0 || index >= len(w.data) {
 		return
 	}
 
-	// Create context menu with "Remove from playlist" item
+	if !w.selection[w.data[index].ID] {
+		w.selectOnly(index)
+		w.list.Refresh()
+	}
+	if indexes := w.selectedIndexes(); len(indexes) > 1 {
+		w.showSelectionMenu(indexes, pos)
+		return
+	}
+
+	// Create context menu with "Remove from playlist" and tag editing items
 	removeItem := fyneapp.NewMenuItem("Remove from playlist", func() {
 		w.removeTrackAtIndex(index)
 	})
 
-	menu := fyneapp.NewMenu("", removeItem)
+	editItem := fyneapp.NewMenuItem("Edit Tags...", func() {
+		w.editTags([]domain.MusicTrack{w.data[index]})
+	})
+	editItem.Disabled = w.presenter == nil || !w.presenter.CanEditTags(w.data[index])
+
+	guessItem := fyneapp.NewMenuItem("Guess Tags from Path...", func() {
+		w.guessTags([]domain.MusicTrack{w.data[index]})
+	})
+	guessItem.Disabled = w.presenter == nil
+
+	playNextItem := fyneapp.NewMenuItem("Play Next", func() {
+		w.playNext([]domain.MusicTrack{w.data[index]})
+	})
+	playNextItem.Disabled = w.presenter == nil
+
+	items := []*fyneapp.MenuItem{playNextItem, removeItem, w.copyToPlaylistItem(w.selectedIndexes()), editItem, guessItem}
+	items = append(items, w.undoMenuItems()...)
+	items = append(items, w.collectionMenuItems()...)
+
+	menu := fyneapp.NewMenu("", items...)
+	popup := widget.NewPopUpMenu(menu, w.window.Canvas())
+	popup.ShowAtPosition(pos)
+}
+
+// showSelectionMenu shows the context menu acting on several selected tracks.
+func (w *PlaylistWindow) showSelectionMenu(indexes []int, pos fyneapp.Position) {
+	tracks := w.selectedTracks()
+
+	playNextItem := fyneapp.NewMenuItem("Play Next", func() {
+		w.playNext(tracks)
+	})
+	removeItem := fyneapp.NewMenuItem(fmt.Sprintf("Remove %d Tracks", len(indexes)), func() {
+		w.removeSelection()
+	})
+	moveTopItem := fyneapp.NewMenuItem("Move to Top", func() {
+		w.moveSelection(indexes, 0)
+	})
+	moveBottomItem := fyneapp.NewMenuItem("Move to Bottom", func() {
+		w.moveSelection(indexes, len(w.mainCollection)-len(indexes))
+	})
+	editItem := fyneapp.NewMenuItem(fmt.Sprintf("Edit Tags of %d Tracks...", len(tracks)), func() {
+		w.editTags(tracks)
+	})
+	guessItem := fyneapp.NewMenuItem(fmt.Sprintf("Guess Tags of %d Tracks...", len(tracks)), func() {
+		w.guessTags(tracks)
+	})
+	items := []*fyneapp.MenuItem{
+		playNextItem, removeItem, moveTopItem, moveBottomItem, w.copyToPlaylistItem(indexes), editItem, guessItem,
+	}
+	items = append(items, w.undoMenuItems()...)
+
+	menu := fyneapp.NewMenu("", items...)
 	popup := widget.NewPopUpMenu(menu, w.window.Canvas())
 	popup.ShowAtPosition(pos)
 }
 
+// copyToPlaylistItem returns an "Add to Playlist" item with a submenu of the
+// saved playlists the queue tracks at indexes can be copied to.
+func (w *PlaylistWindow) copyToPlaylistItem(indexes []int) *fyneapp.MenuItem {
+	item := fyneapp.NewMenuItem("Add to Playlist", nil)
+	item.Disabled = true
+	if w.presenter == nil {
+		return item
+	}
+
+	playlists, err := w.presenter.GetSavedPlaylists()
+	if err != nil {
+		w.presenter.logger.Warn("failed to load playlists", slog.Any("error", err))
+		return item
+	}
+	children := make([]*fyneapp.MenuItem, 0, len(playlists))
+	for _, playlist := range playlists {
+		if playlist.IsSmart() {
+			continue // Smart playlist tracks follow their rules
+		}
+		id := playlist.ID
+		children = append(children, fyneapp.NewMenuItem(playlist.Name, func() {
+			if err := w.presenter.OnCopyTracksToPlaylist(indexes, id); err != nil {
+				dialog.ShowError(err, w.window)
+			}
+		}))
+	}
+	if len(children) > 0 {
+		item.ChildMenu = fyneapp.NewMenu("", children...)
+		item.Disabled = false
+	}
+	return item
+}
+
+// undoMenuItems returns the context menu items that undo and redo queue edits.
+func (w *PlaylistWindow) undoMenuItems() []*fyneapp.MenuItem {
+	if w.presenter == nil {
+		return nil
+	}
+	undoAction, redoAction := w.presenter.GetUndoActions()
+	undoItem := fyneapp.NewMenuItem(undoLabel("Undo", undoAction), w.undo)
+	undoItem.Disabled = undoAction == ""
+	redoItem := fyneapp.NewMenuItem(undoLabel("Redo", redoAction), w.redo)
+	redoItem.Disabled = redoAction == ""
+	return []*fyneapp.MenuItem{fyneapp.NewMenuItemSeparator(), undoItem, redoItem}
+}
+
+// collectionMenuItems returns the context menu items acting on all listed tracks.
+func (w *PlaylistWindow) collectionMenuItems() []*fyneapp.MenuItem {
+	var items []*fyneapp.MenuItem
+
+	// While searching, the visible results can be edited together
+	if w.searchPredicate != nil && len(w.data) > 1 {
+		items = append(items, fyneapp.NewMenuItem(fmt.Sprintf("Edit Tags of %d Results...", len(w.data)), func() {
+			w.editTags(w.data)
+		}))
+	}
+	if len(w.data) > 1 {
+		items = append(items, fyneapp.NewMenuItem(fmt.Sprintf("Guess Tags of %d Tracks...", len(w.data)), func() {
+			w.guessTags(w.data)
+		}))
+	}
+	return items
+}
+
+// removeSelection removes the selected tracks from the playlist as one change.
+func (w *PlaylistWindow) removeSelection() {
+	indexes := w.selectedIndexes()
+	if w.presenter == nil || len(indexes) == 0 {
+		return
+	}
+	if err := w.presenter.OnPlaylistTracksRemoved(indexes); err == nil {
+		w.clearSelection()
+	}
+}
+
+// moveSelection moves the selected tracks to a block starting at toIndex.
+func (w *PlaylistWindow) moveSelection(indexes []int, toIndex int) {
+	if w.presenter == nil {
+		return
+	}
+	if err := w.presenter.OnPlaylistTracksMoved(indexes, toIndex); err != nil {
+		dialog.ShowError(err, w.window)
+	}
+}
+
+// onUpNextSecondaryTapped shows the context menu of an Up Next track.
+func (w *PlaylistWindow) onUpNextSecondaryTapped(index int, pos fyneapp.Position) {
+	if w.presenter == nil || index < 0 || index >= len(w.upNext) {
+		return
+	}
+
+	removeItem := fyneapp.NewMenuItem("Remove from Up Next", func() {
+		_ = w.presenter.OnUpNextTrackRemoved(index) // Logged by the presenter
+	})
+	clearItem := fyneapp.NewMenuItem("Clear Up Next", w.presenter.OnClearUpNext)
+
+	popup := widget.NewPopUpMenu(fyneapp.NewMenu("", removeItem, clearItem), w.window.Canvas())
+	popup.ShowAtPosition(pos)
+}
+
+// playNext adds tracks to Up Next.
+func (w *PlaylistWindow) playNext(tracks []domain.MusicTrack) {
+	if w.presenter == nil {
+		return
+	}
+	if err := w.presenter.OnPlayNextTracks(tracks); err != nil {
+		dialog.ShowError(err, w.window)
+	}
+}
+
+// showUpNext shows the Up Next tracks, hiding the section while there are none.
+func (w *PlaylistWindow) showUpNext(upNext []domain.MusicTrack) {
+	w.upNext = upNext
+	w.upNextLabel.SetText(fmt.Sprintf("Up Next (%d)", len(upNext)))
+	w.upNextList.Refresh()
+	if len(upNext) == 0 {
+		w.upNextSection.Hide()
+	} else {
+		w.upNextSection.Show()
+	}
+}
+
 // removeTrackAtIndex removes the track at the given filtered index from the playlist.
 func (w *PlaylistWindow) removeTrackAtIndex(filteredIndex int) {
 	// Map filtered index to actual index in the main collection
@@ -199,6 +630,37 @@ func (w *PlaylistWindow) removeTrackAtIndex(filteredIndex int) {
 	}
 }
 
+// editTags opens the tag editor for the given tracks.
+// Tracks whose tags cannot be written are left out.
+func (w *PlaylistWindow) editTags(tracks []domain.MusicTrack) {
+	if w.presenter == nil {
+		return
+	}
+
+	editable := make([]domain.MusicTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if w.presenter.CanEditTags(track) {
+			editable = append(editable, track)
+		}
+	}
+	if len(editable) == 0 {
+		return
+	}
+
+	editor := NewTagEditor(w.window, editable, func(edit domain.TagEdit) error {
+		return w.presenter.OnEditTags(editable, edit)
+	}, w.presenter.logger)
+	editor.Show()
+}
+
+// guessTags opens the tag guess dialog for the given tracks.
+func (w *PlaylistWindow) guessTags(tracks []domain.MusicTrack) {
+	if w.presenter == nil || len(tracks) == 0 {
+		return
+	}
+	NewTagGuessDialog(w.window, w.presenter, tracks, w.presenter.logger).Show()
+}
+
 // findActualIndex finds the actual index in the mainCollection for a given filtered data index.
 func (w *PlaylistWindow) findActualIndex(filteredIndex int) int {
 	if filteredIndex < 0 || filteredIndex >= len(w.data) {
@@ -226,7 +688,7 @@ func (w *PlaylistWindow) findFilteredIndex(mainIndex int) int {
 	}
 
 	// If no filter is active, indices are the same
-	if w.searchEntry.Text == "" {
+	if w.searchPredicate == nil {
 		return mainIndex
 	}
 
@@ -250,6 +712,8 @@ func (w *PlaylistWindow) subscribeToEvents() {
 	w.subscriptions = append(w.subscriptions,
 		w.eventBus.Subscribe(domain.EventPlaylistUpdated, w.onPlaylistUpdated),
 		w.eventBus.Subscribe(domain.EventTrackAdded, w.onTrackAdded),
+		w.eventBus.Subscribe(domain.EventShuffleChanged, w.onShuffleChanged),
+		w.eventBus.Subscribe(domain.EventQueueHistory, w.onQueueHistory),
 	)
 }
 
@@ -271,14 +735,10 @@ func (w *PlaylistWindow) onPlaylistUpdated(event domain.Event) {
 	fyneapp.Do(func() {
 		w.mainCollection = playlistEvent.Playlist
 		w.currentIndex = playlistEvent.Index
+		w.showUpNext(playlistEvent.UpNext)
 
 		// Re-apply search filter if active
-		query := w.searchEntry.Text
-		if query == "" {
-			w.data = w.mainCollection
-		} else {
-			w.searchCollection(query)
-		}
+		w.applySearch()
 
 		w.updateWindowTitle()
 		w.list.Refresh()
@@ -315,10 +775,9 @@ func (w *PlaylistWindow) onTrackAdded(event domain.Event) {
 		w.mainCollection = append(w.mainCollection, trackEvent.Track)
 
 		// If no search filter, add to visible data
-		query := w.searchEntry.Text
-		if query == "" {
+		if w.searchPredicate == nil {
 			w.data = w.mainCollection
-		} else if w.matchesSearch(trackEvent.Track, query) {
+		} else if w.searchPredicate(trackEvent.Track) {
 			// Check if track matches current search
 			w.data = append(w.data, trackEvent.Track)
 		}
@@ -328,50 +787,190 @@ func (w *PlaylistWindow) onTrackAdded(event domain.Event) {
 	})
 }
 
-// searchCollection filters the playlist based on the search query.
-func (w *PlaylistWindow) searchCollection(query string) {
-	query = strings.ToLower(strings.TrimSpace(query))
+// onShuffleChanged shows the new shuffle mode in the selector.
+func (w *PlaylistWindow) onShuffleChanged(event domain.Event) {
+	shuffleEvent, ok := event.(domain.ShuffleChangedEvent)
+	if !ok {
+		return
+	}
 
-	if query == "" {
-		// No search, show all tracks
-		w.data = w.mainCollection
-		w.updateWindowTitle()
-		w.list.Refresh()
+	fyneapp.Do(func() {
+		w.showShuffleMode(shuffleEvent.Mode)
+
+		// A shuffled queue is no longer sorted
+		if shuffleEvent.Mode != domain.ShuffleOff {
+			w.sortField = ""
+			w.showSortHeaders()
+		}
+	})
+}
+
+// onQueueHistory enables the undo and redo buttons when there is an edit to undo or redo.
+func (w *PlaylistWindow) onQueueHistory(event domain.Event) {
+	historyEvent, ok := event.(domain.QueueHistoryEvent)
+	if !ok {
 		return
 	}
 
-	// Filter tracks
-	filtered := make([]domain.MusicTrack, 0)
-	for _, track := range w.mainCollection {
-		if w.matchesSearch(track, query) {
-			filtered = append(filtered, track)
+	fyneapp.Do(func() {
+		w.showUndoActions(historyEvent.UndoAction, historyEvent.RedoAction)
+	})
+}
+
+// showUndoActions enables the undo and redo buttons for the given queue edits.
+func (w *PlaylistWindow) showUndoActions(undoAction, redoAction string) {
+	if undoAction == "" {
+		w.undoButton.Disable()
+	} else {
+		w.undoButton.Enable()
+	}
+	if redoAction == "" {
+		w.redoButton.Disable()
+	} else {
+		w.redoButton.Enable()
+	}
+}
+
+// addShortcuts adds the undo (Ctrl/Cmd+Z), redo (Ctrl/Cmd+Y, Ctrl/Cmd+Shift+Z)
+// and select all (Ctrl/Cmd+A) shortcuts, and Delete to remove the selected tracks.
+// A focused search entry handles them itself to edit the query.
+func (w *PlaylistWindow) addShortcuts() {
+	canvas := w.window.Canvas()
+	canvas.AddShortcut(&fyneapp.ShortcutSelectAll{}, func(fyneapp.Shortcut) { w.selectAll() })
+	canvas.SetOnTypedKey(func(event *fyneapp.KeyEvent) {
+		switch event.Name {
+		case fyneapp.KeyDelete:
+			w.removeSelection()
+		case fyneapp.KeyEscape:
+			w.clearSelection()
 		}
+	})
+	canvas.AddShortcut(&fyneapp.ShortcutUndo{}, func(fyneapp.Shortcut) { w.undo() })
+	canvas.AddShortcut(&fyneapp.ShortcutRedo{}, func(fyneapp.Shortcut) { w.redo() })
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyneapp.KeyZ,
+		Modifier: fyneapp.KeyModifierShortcutDefault | fyneapp.KeyModifierShift,
+	}, func(fyneapp.Shortcut) { w.redo() })
+}
+
+// undo reverts the most recent queue edit.
+func (w *PlaylistWindow) undo() {
+	if w.presenter != nil {
+		w.presenter.OnUndoClicked()
 	}
+}
 
-	w.data = filtered
-	w.updateWindowTitle()
-	w.list.Refresh()
+// redo makes the most recently undone queue edit again.
+func (w *PlaylistWindow) redo() {
+	if w.presenter != nil {
+		w.presenter.OnRedoClicked()
+	}
+}
+
+// onSortHeaderTapped sorts the queue by the field of a column header.
+// Clicking the header of the last sort again reverses its direction.
+func (w *PlaylistWindow) onSortHeaderTapped(field domain.QueueSortField) {
+	if w.presenter == nil {
+		return
+	}
+
+	direction := domain.SortAscending
+	if field == w.sortField {
+		direction = w.sortDirection.Reverse()
+	}
+	if err := w.presenter.OnSortQueue(field, direction); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+
+	w.sortField, w.sortDirection = field, direction
+	w.showSortHeaders()
 }
 
-// matchesSearch checks if a track matches the search query.
-func (w *PlaylistWindow) matchesSearch(track domain.MusicTrack, query string) bool {
-	query = strings.ToLower(query)
+// showSortHeaders marks the column header of the last sort with an arrow for its direction.
+func (w *PlaylistWindow) showSortHeaders() {
+	for i, header := range w.sortHeaders {
+		switch {
+		case sortColumns[i].field != w.sortField:
+			header.SetIcon(nil)
+		case w.sortDirection == domain.SortDescending:
+			header.SetIcon(theme.MenuDropDownIcon())
+		default:
+			header.SetIcon(theme.MenuDropUpIcon())
+		}
+	}
+}
 
-	// Search across multiple fields
-	if strings.Contains(strings.ToLower(track.FilePath), query) {
-		return true
+// onShuffleSelected shuffles or unshuffles the queue when a mode is picked.
+func (w *PlaylistWindow) onShuffleSelected(label string) {
+	if w.presenter == nil {
+		return
 	}
-	if strings.Contains(strings.ToLower(track.Title), query) {
-		return true
+
+	for mode, modeLabel := range shuffleLabels {
+		if modeLabel != label {
+			continue
+		}
+		if err := w.presenter.OnShuffleModeChanged(mode); err != nil {
+			dialog.ShowError(err, w.window)
+		}
+		return
 	}
-	if strings.Contains(strings.ToLower(track.Artist), query) {
-		return true
+}
+
+// showShuffleMode selects a shuffle mode without shuffling the queue again.
+func (w *PlaylistWindow) showShuffleMode(mode domain.ShuffleMode) {
+	onChanged := w.shuffle.OnChanged
+	w.shuffle.OnChanged = nil
+	w.shuffle.SetSelected(shuffleLabels[mode])
+	w.shuffle.OnChanged = onChanged
+}
+
+// searchCollection filters the playlist based on the search query.
+// See Presenter.CompileSearchQuery for the query syntax. An invalid query leaves the
+// previous filter in place and shows the syntax error below the search entry.
+func (w *PlaylistWindow) searchCollection(query string) {
+	if strings.TrimSpace(query) == "" {
+		// No search, show all tracks
+		w.searchPredicate = nil
+		w.searchError.Hide()
+	} else {
+		predicate, err := w.presenter.CompileSearchQuery(query)
+		if err != nil {
+			w.showSearchError(err)
+			return
+		}
+		w.searchPredicate = predicate
+		w.searchError.Hide()
 	}
-	if strings.Contains(strings.ToLower(track.Album), query) {
-		return true
+
+	// The selection may include tracks the new search hides
+	clear(w.selection)
+	w.selectionAnchor = -1
+
+	w.applySearch()
+	w.updateWindowTitle()
+	w.list.Refresh()
+}
+
+// applySearch rebuilds the visible data from the main collection using the current search.
+func (w *PlaylistWindow) applySearch() {
+	if w.searchPredicate == nil {
+		w.data = w.mainCollection
+		return
 	}
+	w.data = w.presenter.FilterTracks(w.mainCollection, w.searchPredicate)
+}
 
-	return false
+// showSearchError displays a query syntax error below the search entry.
+func (w *PlaylistWindow) showSearchError(err error) {
+	var syntaxErr *domain.QuerySyntaxError
+	if errors.As(err, &syntaxErr) {
+		w.searchError.SetText(fmt.Sprintf("Column %d: %s", syntaxErr.Position, syntaxErr.Message))
+	} else {
+		w.searchError.SetText(err.Error())
+	}
+	w.searchError.Show()
 }
 
 // loadInitialData loads the current queue from the playlist service.
@@ -383,6 +982,9 @@ func (w *PlaylistWindow) loadInitialData() {
 	w.mainCollection = w.presenter.playlistService.GetQueue()
 	w.currentIndex = w.presenter.playlistService.GetCurrentIndex()
 	w.data = w.mainCollection
+	w.showShuffleMode(w.presenter.GetShuffleMode())
+	w.showUndoActions(w.presenter.GetUndoActions())
+	w.showUpNext(w.presenter.GetUpNext())
 
 	w.updateWindowTitle()
 	w.list.Refresh()
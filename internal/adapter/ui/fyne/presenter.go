@@ -4,6 +4,7 @@ package fyne
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"sync"
 	"time"
@@ -13,13 +14,17 @@ import (
 	"github.com/tejashwikalptaru/gotune/internal/service"
 )
 
+// albumArtThumbnailSize is the maximum width and height of displayed album art.
+// It is larger than the album art area so the artwork stays sharp on HiDPI screens.
+const albumArtThumbnailSize = 512
+
 // UIView defines the interface for UI updates.
 // The actual UI implementation (MainWindow) must implement this interface.
 type UIView interface {
 	// Playback state updates
 	SetPlayState(playing bool)
 	SetMuteState(muted bool)
-	SetLoopState(enabled bool)
+	SetRepeatMode(mode domain.RepeatMode)
 	SetVolume(volume float64)
 
 	// Track information updates
@@ -34,6 +39,7 @@ type UIView interface {
 
 	// Playlist updates
 	UpdatePlaylistSelection(index int)
+	SetUndoActions(undoAction, redoAction string)
 
 	// Playlist window management
 	ShowPlaylistWindow()
@@ -47,6 +53,10 @@ type UIView interface {
 	SetVisualizerType(visType string)
 	GetVisualizerType() string
 
+	// Lyrics updates
+	SetLyrics(lyrics *domain.Lyrics)
+	SetActiveLyricsLine(index int)
+
 	// Notifications
 	ShowNotification(title, message string)
 }
@@ -70,10 +80,19 @@ type Presenter struct {
 	playlistService   *service.PlaylistService
 	libraryService    *service.LibraryService
 	preferenceService *service.PreferenceService
+	tagService        *service.TagService
+	relocationService *service.RelocationService
+	lyricsService     *service.LyricsService
+	playlistFiles     *service.PlaylistFileService
+	listeningHistory  *service.ListeningHistoryService
+	statsService      *service.StatsService
 
 	// Event bus for subscriptions (exported for PlaylistWindow access)
 	EventBus ports.EventBus
 
+	// Thumbnail cache for album artwork
+	thumbnails ports.ThumbnailCache
+
 	// UI view
 	view UIView
 
@@ -102,7 +121,14 @@ func NewPresenter(
 	playlistService *service.PlaylistService,
 	libraryService *service.LibraryService,
 	preferenceService *service.PreferenceService,
+	tagService *service.TagService,
+	relocationService *service.RelocationService,
+	lyricsService *service.LyricsService,
+	playlistFiles *service.PlaylistFileService,
+	listeningHistory *service.ListeningHistoryService,
+	statsService *service.StatsService,
 	eventBus ports.EventBus,
+	thumbnails ports.ThumbnailCache,
 	view UIView,
 ) *Presenter {
 	p := &Presenter{
@@ -111,7 +137,14 @@ func NewPresenter(
 		playlistService:   playlistService,
 		libraryService:    libraryService,
 		preferenceService: preferenceService,
+		tagService:        tagService,
+		relocationService: relocationService,
+		lyricsService:     lyricsService,
+		playlistFiles:     playlistFiles,
+		listeningHistory:  listeningHistory,
+		statsService:      statsService,
 		EventBus:          eventBus,
+		thumbnails:        thumbnails,
 		view:              view,
 		stopProgressChan:  make(chan bool, 1),
 	}
@@ -137,14 +170,20 @@ func (p *Presenter) subscribeToEvents() {
 		domain.EventTrackPaused:    p.onTrackPaused,
 		domain.EventTrackStopped:   p.onTrackStopped,
 		domain.EventTrackCompleted: p.onTrackCompleted,
+		domain.EventTrackUpdated:   p.onTrackUpdated,
 
 		// Volume events
-		domain.EventVolumeChanged: p.onVolumeChanged,
-		domain.EventMuteToggled:   p.onMuteToggled,
-		domain.EventLoopToggled:   p.onLoopToggled,
+		domain.EventVolumeChanged:     p.onVolumeChanged,
+		domain.EventMuteToggled:       p.onMuteToggled,
+		domain.EventRepeatModeChanged: p.onRepeatModeChanged,
 
 		// Playlist events
 		domain.EventPlaylistUpdated: p.onPlaylistUpdated,
+		domain.EventQueueHistory:    p.onQueueHistory,
+
+		// Lyrics events
+		domain.EventLyricsLoaded: p.onLyricsLoaded,
+		domain.EventLyricsLine:   p.onLyricsLine,
 
 		// Scan events
 		domain.EventScanStarted:   p.onScanStarted,
@@ -160,14 +199,15 @@ func (p *Presenter) subscribeToEvents() {
 
 // syncInitialState synchronizes the UI with the current application state.
 // This is called during presenter initialization to ensure the UI reflects
-// the current state of services (volume, loop mode, loaded track, etc.).
+// the current state of services (volume, repeat mode, loaded track, etc.).
 func (p *Presenter) syncInitialState() {
 	state := p.playbackService.GetState()
 
 	// Update UI with current values
 	p.view.SetVolume(state.Volume * 100.0) // Convert from 0.0-1.0 to 0-100
-	p.view.SetLoopState(state.IsLooping)
+	p.view.SetRepeatMode(state.RepeatMode)
 	p.view.SetMuteState(state.IsMuted)
+	p.view.SetUndoActions(p.playlistService.GetUndoActions())
 
 	// Restore visualizer preferences
 	visualizerType := p.preferenceService.GetVisualizerType()
@@ -191,12 +231,11 @@ func (p *Presenter) syncInitialState() {
 		}
 
 		// Update album art if available
-		if state.CurrentTrack.Metadata != nil &&
-			len(state.CurrentTrack.Metadata.AlbumArt) > 0 {
-			p.view.SetAlbumArt(state.CurrentTrack.Metadata.AlbumArt)
-		} else {
-			p.view.ClearAlbumArt()
-		}
+		p.showAlbumArt(*state.CurrentTrack)
+
+		// Show the lyrics that were loaded with the track
+		p.view.SetLyrics(p.lyricsService.GetLyrics())
+		p.view.SetActiveLyricsLine(p.lyricsService.GetActiveLine())
 	}
 
 	// Update play state
@@ -231,11 +270,63 @@ func (p *Presenter) onTrackLoaded(event domain.Event) {
 	}
 
 	// Set album art (check the Metadata field)
-	if e.Track.Metadata != nil && len(e.Track.Metadata.AlbumArt) > 0 {
-		p.view.SetAlbumArt(e.Track.Metadata.AlbumArt)
-	} else {
+	p.showAlbumArt(e.Track)
+}
+
+// showAlbumArt displays a thumbnail of the track's artwork, or the default artwork.
+// Artwork that is not held in the track's metadata is read from its file.
+func (p *Presenter) showAlbumArt(track domain.MusicTrack) {
+	art, err := p.libraryService.GetAlbumArt(track)
+	if err != nil {
+		p.logger.Debug("failed to read album art", slog.Any("error", err), slog.String("path", track.FilePath))
+	}
+	if len(art) == 0 {
 		p.view.ClearAlbumArt()
+		return
 	}
+
+	thumbnail, err := p.thumbnails.Thumbnail(art, albumArtThumbnailSize)
+	if err != nil {
+		// Let the view try the original image
+		p.logger.Debug("failed to create album art thumbnail", slog.Any("error", err))
+		thumbnail = art
+	}
+	p.view.SetAlbumArt(thumbnail)
+}
+
+func (p *Presenter) onTrackUpdated(event domain.Event) {
+	e, ok := event.(domain.TrackUpdatedEvent)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	if p.currentTrack == nil || p.currentTrack.FilePath != e.Track.FilePath {
+		p.mu.Unlock()
+		return
+	}
+	p.currentTrack = &e.Track
+	p.mu.Unlock()
+
+	// Refresh the now-playing display with the edited tags
+	p.view.SetTrackInfo(e.Track.Title, e.Track.Artist, e.Track.Album)
+	p.showAlbumArt(e.Track)
+}
+
+func (p *Presenter) onLyricsLoaded(event domain.Event) {
+	e, ok := event.(domain.LyricsLoadedEvent)
+	if !ok {
+		return
+	}
+	p.view.SetLyrics(e.Lyrics)
+}
+
+func (p *Presenter) onLyricsLine(event domain.Event) {
+	e, ok := event.(domain.LyricsLineEvent)
+	if !ok {
+		return
+	}
+	p.view.SetActiveLyricsLine(e.Index)
 }
 
 func (p *Presenter) onTrackStarted(event domain.Event) {
@@ -292,13 +383,13 @@ func (p *Presenter) onMuteToggled(event domain.Event) {
 	p.view.SetMuteState(e.Muted)
 }
 
-func (p *Presenter) onLoopToggled(event domain.Event) {
-	e, ok := event.(domain.LoopToggledEvent)
+func (p *Presenter) onRepeatModeChanged(event domain.Event) {
+	e, ok := event.(domain.RepeatModeChangedEvent)
 	if !ok {
 		return
 	}
 
-	p.view.SetLoopState(e.Enabled)
+	p.view.SetRepeatMode(e.Mode)
 }
 
 func (p *Presenter) onPlaylistUpdated(event domain.Event) {
@@ -311,6 +402,15 @@ func (p *Presenter) onPlaylistUpdated(event domain.Event) {
 	p.view.UpdatePlaylistSelection(e.Index)
 }
 
+func (p *Presenter) onQueueHistory(event domain.Event) {
+	e, ok := event.(domain.QueueHistoryEvent)
+	if !ok {
+		return
+	}
+
+	p.view.SetUndoActions(e.UndoAction, e.RedoAction)
+}
+
 func (p *Presenter) onScanStarted(event domain.Event) {
 	e, ok := event.(domain.ScanStartedEvent)
 	if !ok {
@@ -332,6 +432,9 @@ func (p *Presenter) onScanCompleted(event domain.Event) {
 	}
 
 	message := fmt.Sprintf("Found %d tracks", len(e.TracksFound))
+	if len(e.Failures) > 0 {
+		message += fmt.Sprintf(", %d file(s) could not be read (see File > Scan Report)", len(e.Failures))
+	}
 	p.view.ShowNotification("Scan Complete", message)
 }
 
@@ -452,12 +555,36 @@ func (p *Presenter) OnMuteClicked() {
 	p.playbackService.Mute(!state.IsMuted)
 }
 
-// OnLoopClicked handles the loop button click.
-func (p *Presenter) OnLoopClicked() {
-	state := p.playbackService.GetState()
-	newLoopState := !state.IsLooping
-	p.playbackService.SetLoop(newLoopState)
-	p.preferenceService.SetLoopMode(newLoopState)
+// OnUndoClicked reverts the most recent queue edit.
+func (p *Presenter) OnUndoClicked() {
+	if err := p.playlistService.Undo(); err != nil {
+		p.logger.Debug("nothing undone", slog.Any("error", err))
+	}
+}
+
+// OnRedoClicked makes the most recently undone queue edit again.
+func (p *Presenter) OnRedoClicked() {
+	if err := p.playlistService.Redo(); err != nil {
+		p.logger.Debug("nothing redone", slog.Any("error", err))
+	}
+}
+
+// GetUndoActions returns the queue edits that would be undone and redone (empty if none).
+func (p *Presenter) GetUndoActions() (string, string) {
+	return p.playlistService.GetUndoActions()
+}
+
+// OnRepeatClicked handles the repeat button click, cycling through
+// repeat off, repeat all and repeat one.
+func (p *Presenter) OnRepeatClicked() {
+	mode := p.playbackService.GetRepeatMode().Next()
+	if err := p.playbackService.SetRepeatMode(mode); err != nil {
+		p.logger.Error("failed to set repeat mode", slog.Any("error", err))
+		return
+	}
+	if err := p.preferenceService.SetRepeatMode(mode); err != nil {
+		p.logger.Warn("failed to save repeat mode", slog.Any("error", err))
+	}
 }
 
 // OnSeekRequested handles seek requests from the progress slider.
@@ -500,6 +627,9 @@ func (p *Presenter) OnFolderOpened(folderPath string) error {
 		return err
 	}
 
+	// Keep albums together, with compilations filed under their album artist
+	service.SortTracksByAlbum(tracks)
+
 	// Add all tracks to the playlist (don't play first automatically)
 	err = p.playlistService.AddTracks(tracks, false)
 	if err != nil {
@@ -509,9 +639,90 @@ func (p *Presenter) OnFolderOpened(folderPath string) error {
 	// Save the last folder
 	p.preferenceService.SetLastFolder(folderPath)
 
+	// Remember the folder so moved files can be searched for in it
+	if err := p.preferenceService.AddScanPath(folderPath); err != nil {
+		p.logger.Warn("failed to save scan path", slog.Any("error", err), slog.String("path", folderPath))
+	}
+
+	return nil
+}
+
+// GetFolderTree returns the library arranged in folder trees under the scan paths.
+func (p *Presenter) GetFolderTree() ([]*domain.FolderNode, error) {
+	return p.libraryService.GetFolderTree(p.preferenceService.GetScanPaths())
+}
+
+// OnEnqueueTracks adds library tracks to the end of the queue.
+func (p *Presenter) OnEnqueueTracks(tracks []domain.MusicTrack) error {
+	return p.playlistService.AddTracks(tracks, false)
+}
+
+// OnSortQueue sorts the queue by a track field.
+func (p *Presenter) OnSortQueue(field domain.QueueSortField, direction domain.SortDirection) error {
+	return p.playlistService.SortBy(field, direction)
+}
+
+// OnPlayNextTracks adds library tracks to Up Next, to be played after the current track.
+func (p *Presenter) OnPlayNextTracks(tracks []domain.MusicTrack) error {
+	return p.playlistService.AddToUpNext(tracks)
+}
+
+// OnUpNextTrackRemoved removes a track from Up Next by index.
+func (p *Presenter) OnUpNextTrackRemoved(index int) error {
+	if err := p.playlistService.RemoveFromUpNext(index); err != nil {
+		p.logger.Error("failed to remove up next track", slog.Any("error", err), slog.Int("index", index))
+		return err
+	}
 	return nil
 }
 
+// OnClearUpNext removes all tracks from Up Next.
+func (p *Presenter) OnClearUpNext() {
+	p.playlistService.ClearUpNext()
+}
+
+// GetUpNext returns the tracks played before the rest of the queue.
+func (p *Presenter) GetUpNext() []domain.MusicTrack {
+	return p.playlistService.GetUpNext()
+}
+
+// GetShuffleMode returns how the queue is shuffled.
+func (p *Presenter) GetShuffleMode() domain.ShuffleMode {
+	return p.playlistService.GetShuffleMode()
+}
+
+// OnShuffleModeChanged shuffles the queue in the given mode, or restores its
+// original order for ShuffleOff.
+func (p *Presenter) OnShuffleModeChanged(mode domain.ShuffleMode) error {
+	if mode == domain.ShuffleOff {
+		return p.playlistService.Unshuffle()
+	}
+	return p.playlistService.Shuffle(mode)
+}
+
+// OnPlayTracks adds library tracks to the queue and plays the first of them.
+// Tracks already in the queue are played from their current position.
+func (p *Presenter) OnPlayTracks(tracks []domain.MusicTrack) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	if err := p.playlistService.AddTracks(tracks, false); err != nil {
+		return err
+	}
+	_, err := p.playlistService.PlayTrackByPath(tracks[0].FilePath)
+	return err
+}
+
+// GetListeningSessions returns the listening history grouped into sessions, most recent first.
+func (p *Presenter) GetListeningSessions(limit int) ([]domain.ListeningSession, error) {
+	return p.listeningHistory.Sessions(limit)
+}
+
+// OnClearListeningHistory removes all plays from the listening history.
+func (p *Presenter) OnClearListeningHistory() error {
+	return p.listeningHistory.ClearHistory()
+}
+
 // OnTrackSelected handles track selection from a playlist.
 func (p *Presenter) OnTrackSelected(trackPath string) error {
 	// PlayTrackByPath returns (index, error)
@@ -538,11 +749,208 @@ func (p *Presenter) OnPlaylistTrackRemoved(index int) error {
 	return nil
 }
 
+// OnPlaylistTracksRemoved removes several tracks from the playlist by index as one change.
+func (p *Presenter) OnPlaylistTracksRemoved(indexes []int) error {
+	if err := p.playlistService.RemoveTracks(indexes); err != nil {
+		p.logger.Error("failed to remove tracks", slog.Any("error", err), slog.Int("count", len(indexes)))
+		return err
+	}
+	return nil
+}
+
+// OnPlaylistTracksMoved moves several tracks of the playlist, by index, to a block starting at toIndex.
+func (p *Presenter) OnPlaylistTracksMoved(indexes []int, toIndex int) error {
+	return p.playlistService.MoveTracks(indexes, toIndex)
+}
+
+// OnCopyTracksToPlaylist adds playlist tracks, by index, to a saved playlist.
+func (p *Presenter) OnCopyTracksToPlaylist(indexes []int, playlistID string) error {
+	return p.playlistService.CopyTracksToPlaylist(indexes, playlistID)
+}
+
 // GetQueue returns the current queue.
 func (p *Presenter) GetQueue() []domain.MusicTrack {
 	return p.playlistService.GetQueue()
 }
 
+// CompileSearchQuery compiles a search query into a track predicate.
+// See service.CompileQuery for the query syntax. Returns a *domain.QuerySyntaxError
+// if the query is invalid.
+func (p *Presenter) CompileSearchQuery(query string) (domain.TrackPredicate, error) {
+	return service.CompileQuery(query)
+}
+
+// FilterTracks returns the tracks matching the predicate, in order.
+func (p *Presenter) FilterTracks(tracks []domain.MusicTrack, predicate domain.TrackPredicate) []domain.MusicTrack {
+	return service.FilterTracks(tracks, predicate)
+}
+
+// CanEditTags returns true if the tags of the track can be edited.
+func (p *Presenter) CanEditTags(track domain.MusicTrack) bool {
+	return p.tagService.CanEdit(track)
+}
+
+// OnEditTags handles tag edits from the tag editor for one or more tracks.
+func (p *Presenter) OnEditTags(tracks []domain.MusicTrack, edit domain.TagEdit) error {
+	if _, err := p.tagService.EditTags(tracks, edit); err != nil {
+		p.logger.Error("failed to edit tags", slog.Any("error", err), slog.Int("tracks", len(tracks)))
+		return err
+	}
+	return nil
+}
+
+// GuessTags infers tags of the tracks from their paths for review.
+func (p *Presenter) GuessTags(tracks []domain.MusicTrack, patterns []string, onlyMissing bool) ([]domain.TagGuess, error) {
+	return p.tagService.GuessTags(tracks, patterns, onlyMissing)
+}
+
+// OnApplyTagGuesses applies tag guesses reviewed in the tag guess dialog.
+func (p *Presenter) OnApplyTagGuesses(guesses []domain.TagGuess) error {
+	if _, err := p.tagService.ApplyTagGuesses(guesses); err != nil {
+		p.logger.Error("failed to apply tag guesses", slog.Any("error", err), slog.Int("tracks", len(guesses)))
+		return err
+	}
+	return nil
+}
+
+// GetScanOptions returns the exclude patterns, limits and tag patterns used by scans.
+func (p *Presenter) GetScanOptions() domain.ScanOptions {
+	return p.preferenceService.GetScanOptions()
+}
+
+// OnScanOptionsChanged saves new scan options and applies them to later folder scans.
+func (p *Presenter) OnScanOptionsChanged(options domain.ScanOptions) error {
+	if err := p.preferenceService.SetScanOptions(options); err != nil {
+		p.logger.Error("failed to save scan options", slog.Any("error", err))
+		return err
+	}
+	p.libraryService.SetScanOptions(p.preferenceService.GetScanOptions())
+	return nil
+}
+
+// GetTagCharset returns the fallback charset for legacy tags.
+func (p *Presenter) GetTagCharset() domain.Charset {
+	return p.preferenceService.GetTagCharset()
+}
+
+// OnTagCharsetChanged saves the fallback charset for legacy tags and applies it to later scans.
+func (p *Presenter) OnTagCharsetChanged(charset domain.Charset) error {
+	if err := p.preferenceService.SetTagCharset(charset); err != nil {
+		p.logger.Error("failed to save tag charset", slog.Any("error", err))
+		return err
+	}
+	p.libraryService.SetTagCharset(charset)
+	return nil
+}
+
+// GetPlayThreshold returns the fraction of a track that must be heard for it to count as played.
+func (p *Presenter) GetPlayThreshold() float64 {
+	return p.preferenceService.GetPlayThreshold()
+}
+
+// OnPlayThresholdChanged saves the fraction of a track that must be heard for it to count
+// as played and applies it to the statistics, including the track playing now.
+func (p *Presenter) OnPlayThresholdChanged(fraction float64) error {
+	if err := p.preferenceService.SetPlayThreshold(fraction); err != nil {
+		p.logger.Error("failed to save play threshold", slog.Any("error", err))
+		return err
+	}
+	return p.statsService.SetPlayThreshold(fraction)
+}
+
+// GetScanReport returns the report of the most recent scan, or nil if there is none.
+func (p *Presenter) GetScanReport() (*domain.ScanReport, error) {
+	report, err := p.libraryService.GetLastScanReport()
+	if err != nil {
+		p.logger.Error("failed to load scan report", slog.Any("error", err))
+	}
+	return report, err
+}
+
+// OnExportScanReport writes the scan report as JSON or plain text.
+func (p *Presenter) OnExportScanReport(w io.Writer, report *domain.ScanReport, asJSON bool) error {
+	var err error
+	if asJSON {
+		err = service.ExportScanReportJSON(w, report)
+	} else {
+		err = service.ExportScanReportText(w, report)
+	}
+	if err != nil {
+		p.logger.Error("failed to export scan report", slog.Any("error", err))
+	}
+	return err
+}
+
+// OnCheckMissingFiles checks the queue, library and saved playlists for missing files.
+func (p *Presenter) OnCheckMissingFiles() ([]domain.MusicTrack, error) {
+	missing, err := p.relocationService.CheckMissing()
+	if err != nil {
+		p.logger.Error("failed to check for missing files", slog.Any("error", err))
+	}
+	return missing, err
+}
+
+// OnRelocatePrefix remaps tracks under oldPrefix to newPrefix.
+func (p *Presenter) OnRelocatePrefix(oldPrefix, newPrefix string) (int, error) {
+	relocated, err := p.relocationService.RelocatePrefix(oldPrefix, newPrefix)
+	if err != nil {
+		p.logger.Error("failed to relocate files", slog.Any("error", err))
+	}
+	return relocated, err
+}
+
+// OnRelocateByFilename searches the scanned folders for missing files.
+func (p *Presenter) OnRelocateByFilename() (int, error) {
+	relocated, err := p.relocationService.RelocateByFilename()
+	if err != nil {
+		p.logger.Error("failed to relocate files", slog.Any("error", err))
+	}
+	return relocated, err
+}
+
+// SupportedPlaylistExtensions returns the extensions of playlist files that can be imported and exported.
+func (p *Presenter) SupportedPlaylistExtensions() []string {
+	return p.playlistFiles.SupportedExtensions()
+}
+
+// GetSavedPlaylists returns the saved playlists that can be exported.
+func (p *Presenter) GetSavedPlaylists() ([]*domain.Playlist, error) {
+	playlists, err := p.playlistService.GetPlaylists()
+	if err != nil {
+		p.logger.Error("failed to load playlists", slog.Any("error", err))
+	}
+	return playlists, err
+}
+
+// OnImportPlaylist imports a playlist file into the queue, or as a new playlist named name.
+func (p *Presenter) OnImportPlaylist(filePath string, toQueue bool, name string) (*domain.PlaylistImport, error) {
+	var result *domain.PlaylistImport
+	var err error
+	if toQueue {
+		result, err = p.playlistFiles.ImportToQueue(filePath)
+	} else {
+		result, err = p.playlistFiles.ImportAsPlaylist(filePath, name)
+	}
+	if err != nil {
+		p.logger.Error("failed to import playlist", slog.Any("error", err))
+	}
+	return result, err
+}
+
+// OnExportPlaylist writes the queue (empty playlistID) or a saved playlist to a playlist file.
+func (p *Presenter) OnExportPlaylist(playlistID, filePath string, relative bool) error {
+	var err error
+	if playlistID == "" {
+		err = p.playlistFiles.ExportQueue(filePath, relative)
+	} else {
+		err = p.playlistFiles.ExportPlaylist(playlistID, filePath, relative)
+	}
+	if err != nil {
+		p.logger.Error("failed to export playlist", slog.Any("error", err))
+	}
+	return err
+}
+
 // OnVisualizerModeChanged handles visualizer mode changes from the UI.
 func (p *Presenter) OnVisualizerModeChanged(enabled bool) {
 	if enabled {
@@ -0,0 +1,342 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// recentSessionsLimit is the number of listening sessions shown in the Recently Played window.
+const recentSessionsLimit = 100
+
+// Tree node IDs are the start time of a session or play with a prefix telling them apart,
+// so they stay the same as plays are logged.
+const (
+	sessionNodePrefix = "session:"
+	listenNodePrefix  = "listen:"
+)
+
+// RecentlyPlayedWindow shows the listening history as sessions of plays, most recent first.
+// A session or a single play can be played again, played next or added to the queue.
+type RecentlyPlayedWindow struct {
+	window fyneapp.Window
+	tree   *widget.Tree
+	status *widget.Label
+
+	playButton     *widget.Button
+	playNextButton *widget.Button
+	enqueueButton  *widget.Button
+	clearButton    *widget.Button
+
+	// Data state
+	sessions []domain.ListeningSession
+	byID     map[string]domain.ListeningSession // Sessions by node ID
+	entries  map[string]domain.ListeningEntry   // Plays by node ID
+	selected string
+
+	// Dependencies
+	presenter     *Presenter
+	eventBus      ports.EventBus
+	logger        *slog.Logger
+	subscriptions []domain.SubscriptionID
+
+	// Lifecycle
+	onWindowClosed func()
+	isVisible      bool
+}
+
+// NewRecentlyPlayedWindow creates a new Recently Played window.
+func NewRecentlyPlayedWindow(app fyneapp.App, presenter *Presenter, eventBus ports.EventBus, logger *slog.Logger) *RecentlyPlayedWindow {
+	w := &RecentlyPlayedWindow{
+		presenter: presenter,
+		eventBus:  eventBus,
+		logger:    logger,
+		byID:      make(map[string]domain.ListeningSession),
+		entries:   make(map[string]domain.ListeningEntry),
+	}
+
+	w.window = app.NewWindow("Recently Played")
+	w.window.Resize(fyneapp.NewSize(500, 600))
+
+	w.buildUI()
+
+	w.subscriptions = append(w.subscriptions,
+		eventBus.Subscribe(domain.EventListenLogged, w.onListenLogged),
+	)
+
+	w.window.SetOnClosed(func() {
+		w.isVisible = false
+		w.unsubscribeFromEvents()
+		if w.onWindowClosed != nil {
+			w.onWindowClosed()
+		}
+	})
+
+	w.reload()
+
+	return w
+}
+
+// buildUI constructs the Recently Played layout.
+func (w *RecentlyPlayedWindow) buildUI() {
+	w.tree = widget.NewTree(w.childIDs, w.isBranch, w.createNode, w.updateNode)
+	w.tree.OnSelected = func(id widget.TreeNodeID) {
+		w.selected = id
+		w.updateButtons()
+	}
+	w.tree.OnUnselected = func(widget.TreeNodeID) {
+		w.selected = ""
+		w.updateButtons()
+	}
+
+	w.playButton = widget.NewButtonWithIcon("Play", theme.MediaPlayIcon(), func() {
+		w.playSelected(w.presenter.OnPlayTracks)
+	})
+	w.playNextButton = widget.NewButtonWithIcon("Play Next", theme.MediaSkipNextIcon(), func() {
+		w.playSelected(w.presenter.OnPlayNextTracks)
+	})
+	w.enqueueButton = widget.NewButtonWithIcon("Add to Queue", theme.ContentAddIcon(), func() {
+		w.playSelected(w.presenter.OnEnqueueTracks)
+	})
+	w.clearButton = widget.NewButtonWithIcon("Clear History", theme.DeleteIcon(), w.clearHistory)
+
+	w.status = widget.NewLabel("")
+	w.updateButtons()
+
+	toolbar := container.NewHBox(w.playButton, w.playNextButton, w.enqueueButton, w.clearButton)
+	w.window.SetContent(container.NewBorder(toolbar, w.status, nil, nil, w.tree))
+}
+
+// childIDs returns the IDs of the sessions, or of the plays of a session.
+func (w *RecentlyPlayedWindow) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	if id == "" {
+		ids := make([]widget.TreeNodeID, len(w.sessions))
+		for i, session := range w.sessions {
+			ids[i] = sessionNodeID(session)
+		}
+		return ids
+	}
+
+	session, ok := w.byID[id]
+	if !ok {
+		return nil
+	}
+	ids := make([]widget.TreeNodeID, len(session.Entries))
+	for i, entry := range session.Entries {
+		ids[i] = listenNodeID(entry)
+	}
+	return ids
+}
+
+// sessionNodeID returns the tree node ID of a session.
+func sessionNodeID(session domain.ListeningSession) widget.TreeNodeID {
+	return sessionNodePrefix + strconv.FormatInt(session.StartedAt.UnixNano(), 10)
+}
+
+// listenNodeID returns the tree node ID of a play.
+func listenNodeID(entry domain.ListeningEntry) widget.TreeNodeID {
+	return listenNodePrefix + strconv.FormatInt(entry.StartedAt.UnixNano(), 10)
+}
+
+// isBranch reports whether a tree node is a session.
+func (w *RecentlyPlayedWindow) isBranch(id widget.TreeNodeID) bool {
+	return id == "" || strings.HasPrefix(id, sessionNodePrefix)
+}
+
+// createNode creates a tree node with an icon and a label.
+func (w *RecentlyPlayedWindow) createNode(bool) fyneapp.CanvasObject {
+	return container.NewHBox(widget.NewIcon(theme.HistoryIcon()), widget.NewLabel(""))
+}
+
+// updateNode shows a session with its time and track count, or a play with its time and track.
+func (w *RecentlyPlayedWindow) updateNode(id widget.TreeNodeID, branch bool, obj fyneapp.CanvasObject) {
+	box, ok := obj.(*fyneapp.Container)
+	if !ok || len(box.Objects) != 2 {
+		return
+	}
+	icon, _ := box.Objects[0].(*widget.Icon)
+	label, _ := box.Objects[1].(*widget.Label)
+	if icon == nil || label == nil {
+		return
+	}
+
+	if branch {
+		icon.SetResource(theme.HistoryIcon())
+		if session, ok := w.byID[id]; ok {
+			label.SetText(formatListeningSession(session))
+		}
+		return
+	}
+
+	icon.SetResource(theme.FileAudioIcon())
+	if entry, ok := w.entries[id]; ok {
+		label.SetText(formatListeningEntry(entry))
+	}
+}
+
+// formatListeningSession formats a session for display, e.g. "Mon 2 Jan 2006 15:04 – 16:10 · 12 plays".
+func formatListeningSession(session domain.ListeningSession) string {
+	text := session.StartedAt.Local().Format("Mon 2 Jan 2006 15:04") + " – " + session.EndedAt.Local().Format("15:04")
+	if len(session.Entries) == 1 {
+		return text + " · 1 play"
+	}
+	return fmt.Sprintf("%s · %d plays", text, len(session.Entries))
+}
+
+// formatListeningEntry formats a play for display, e.g. "15:04  Artist - Title (1:20, skipped)".
+// Plays that completed only show when they started.
+func formatListeningEntry(entry domain.ListeningEntry) string {
+	text := entry.StartedAt.Local().Format("15:04") + "  " + trackLabel(entry.Track())
+	if !entry.Completed {
+		text += fmt.Sprintf(" (%s, skipped)", formatTrackDuration(entry.Played))
+	}
+	return text
+}
+
+// selectedTracks returns the tracks of the selected session, in play order, or of the selected play.
+func (w *RecentlyPlayedWindow) selectedTracks() []domain.MusicTrack {
+	if session, ok := w.byID[w.selected]; ok {
+		return session.Tracks()
+	}
+	if entry, ok := w.entries[w.selected]; ok {
+		return []domain.MusicTrack{entry.Track()}
+	}
+	return nil
+}
+
+// playSelected passes the tracks of the selected session or play to queue,
+// which plays, plays next or enqueues them.
+func (w *RecentlyPlayedWindow) playSelected(queue func([]domain.MusicTrack) error) {
+	tracks := w.selectedTracks()
+	if len(tracks) == 0 {
+		return
+	}
+
+	if err := queue(tracks); err != nil {
+		w.logger.Error("failed to queue played tracks", slog.Any("error", err), slog.Int("tracks", len(tracks)))
+		dialog.ShowError(err, w.window)
+	}
+}
+
+// clearHistory removes all plays from the listening history after confirmation.
+func (w *RecentlyPlayedWindow) clearHistory() {
+	dialog.ShowConfirm("Clear History", "Remove all plays from the listening history?", func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := w.presenter.OnClearListeningHistory(); err != nil {
+			w.logger.Error("failed to clear listening history", slog.Any("error", err))
+			dialog.ShowError(err, w.window)
+			return
+		}
+		w.reload()
+	}, w.window)
+}
+
+// updateButtons enables the actions when a session or play is selected.
+func (w *RecentlyPlayedWindow) updateButtons() {
+	count := len(w.selectedTracks())
+	if count == 0 {
+		w.playButton.Disable()
+		w.playNextButton.Disable()
+		w.enqueueButton.Disable()
+	} else {
+		w.playButton.Enable()
+		w.playNextButton.Enable()
+		w.enqueueButton.Enable()
+	}
+	if len(w.sessions) == 0 {
+		w.clearButton.Disable()
+	} else {
+		w.clearButton.Enable()
+	}
+
+	if w.status == nil {
+		return
+	}
+	switch {
+	case len(w.sessions) == 0:
+		w.status.SetText("Tracks you play will show up here.")
+	case count == 1:
+		w.status.SetText("1 track selected")
+	case count > 1:
+		w.status.SetText(fmt.Sprintf("%d tracks selected", count))
+	default:
+		w.status.SetText("")
+	}
+}
+
+// reload rebuilds the tree from the listening history.
+func (w *RecentlyPlayedWindow) reload() {
+	sessions, err := w.presenter.GetListeningSessions(recentSessionsLimit)
+	if err != nil {
+		w.logger.Error("failed to load listening history", slog.Any("error", err))
+		dialog.ShowError(err, w.window)
+		return
+	}
+
+	w.sessions = sessions
+	w.byID = make(map[string]domain.ListeningSession, len(sessions))
+	w.entries = make(map[string]domain.ListeningEntry)
+	for _, session := range sessions {
+		w.byID[sessionNodeID(session)] = session
+		for _, entry := range session.Entries {
+			w.entries[listenNodeID(entry)] = entry
+		}
+	}
+
+	if _, ok := w.byID[w.selected]; !ok {
+		if _, ok := w.entries[w.selected]; !ok {
+			w.selected = ""
+			w.tree.UnselectAll()
+		}
+	}
+	w.tree.Refresh()
+	w.updateButtons()
+}
+
+// onListenLogged rebuilds the tree when a play is logged.
+func (w *RecentlyPlayedWindow) onListenLogged(domain.Event) {
+	fyneapp.Do(w.reload)
+}
+
+// unsubscribeFromEvents unsubscribes from all events.
+func (w *RecentlyPlayedWindow) unsubscribeFromEvents() {
+	for _, sub := range w.subscriptions {
+		w.eventBus.Unsubscribe(sub)
+	}
+	w.subscriptions = nil
+}
+
+// Show displays the Recently Played window.
+func (w *RecentlyPlayedWindow) Show() {
+	w.isVisible = true
+	w.window.Show()
+}
+
+// Close closes the Recently Played window.
+func (w *RecentlyPlayedWindow) Close() {
+	w.isVisible = false
+	w.unsubscribeFromEvents()
+	w.window.Close()
+}
+
+// IsVisible returns whether the window is currently visible.
+func (w *RecentlyPlayedWindow) IsVisible() bool {
+	return w.isVisible
+}
+
+// SetOnWindowClosed sets a callback to be invoked when the window is closed.
+func (w *RecentlyPlayedWindow) SetOnWindowClosed(callback func()) {
+	w.onWindowClosed = callback
+}
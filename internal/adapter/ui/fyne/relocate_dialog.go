@@ -0,0 +1,110 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// maxListedMissingFiles limits how many missing files are listed in the report.
+const maxListedMissingFiles = 10
+
+// RelocateDialog lets the user point moved files at their new location,
+// either by replacing a path prefix or by searching the scanned folders.
+type RelocateDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	logger    *slog.Logger
+}
+
+// NewRelocateDialog creates a new relocate dialog.
+func NewRelocateDialog(window fyneapp.Window, presenter *Presenter, logger *slog.Logger) *RelocateDialog {
+	return &RelocateDialog{
+		window:    window,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+// Show displays the relocate dialog.
+func (d *RelocateDialog) Show() {
+	oldPrefix := widget.NewEntry()
+	oldPrefix.SetPlaceHolder("/Volumes/OldDrive/Music")
+	newPrefix := widget.NewEntry()
+	newPrefix.SetPlaceHolder("/Volumes/NewDrive/Music")
+	search := widget.NewCheck("Search scanned folders by file name", nil)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Old path", oldPrefix),
+		widget.NewFormItem("New path", newPrefix),
+		widget.NewFormItem("", search),
+	)
+
+	relocateDialog := dialog.NewCustomConfirm("Relocate Files", "Relocate", "Cancel", form, func(relocate bool) {
+		if !relocate {
+			return
+		}
+		d.relocate(strings.TrimSpace(oldPrefix.Text), strings.TrimSpace(newPrefix.Text), search.Checked)
+	}, d.window)
+	relocateDialog.Resize(fyneapp.NewSize(480, 0))
+	relocateDialog.Show()
+}
+
+// relocate runs the prefix remapping (if both paths are set) and then the file name search.
+func (d *RelocateDialog) relocate(oldPrefix, newPrefix string, search bool) {
+	total := 0
+	if oldPrefix != "" || newPrefix != "" {
+		relocated, err := d.presenter.OnRelocatePrefix(oldPrefix, newPrefix)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to relocate files: %w", err), d.window)
+			return
+		}
+		total += relocated
+	}
+
+	if search {
+		relocated, err := d.presenter.OnRelocateByFilename()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to search for files: %w", err), d.window)
+			return
+		}
+		total += relocated
+	}
+
+	d.logger.Info("relocated files", slog.Int("count", total))
+	dialog.ShowInformation("Relocate Files", fmt.Sprintf("%d file(s) relocated.", total), d.window)
+}
+
+// ShowMissingFilesReport checks for missing files and shows the result.
+func ShowMissingFilesReport(window fyneapp.Window, presenter *Presenter) {
+	missing, err := presenter.OnCheckMissingFiles()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to check for missing files: %w", err), window)
+		return
+	}
+	dialog.ShowInformation("Missing Files", formatMissingFiles(missing), window)
+}
+
+// formatMissingFiles describes the missing files, listing the first few paths.
+func formatMissingFiles(missing []domain.MusicTrack) string {
+	if len(missing) == 0 {
+		return "All files were found."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d file(s) could not be found:\n", len(missing))
+	for i, track := range missing {
+		if i == maxListedMissingFiles {
+			fmt.Fprintf(&b, "\n...and %d more", len(missing)-i)
+			break
+		}
+		b.WriteString("\n" + track.FilePath)
+	}
+	b.WriteString("\n\nUse File > Relocate Files... to find them.")
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// scanFailureReasonLabels are the descriptions of scan failure reasons shown to the user.
+var scanFailureReasonLabels = map[domain.ScanFailureReason]string{
+	domain.ScanFailureUnsupported: "Unsupported format or codec",
+	domain.ScanFailurePermission:  "Permission denied",
+	domain.ScanFailureCorrupt:     "Corrupt or invalid header",
+	domain.ScanFailureNotFound:    "File not found",
+	domain.ScanFailureUnknown:     "Unreadable",
+}
+
+// ScanReportDialog shows the files the most recent scan could not read,
+// and exports the report as text or JSON.
+type ScanReportDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	logger    *slog.Logger
+}
+
+// NewScanReportDialog creates a new scan report dialog.
+func NewScanReportDialog(window fyneapp.Window, presenter *Presenter, logger *slog.Logger) *ScanReportDialog {
+	return &ScanReportDialog{
+		window:    window,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+// Show displays the scan report dialog.
+func (d *ScanReportDialog) Show() {
+	report, err := d.presenter.GetScanReport()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load scan report: %w", err), d.window)
+		return
+	}
+	if report == nil {
+		dialog.ShowInformation("Scan Report", "No folder or files have been scanned yet.", d.window)
+		return
+	}
+
+	summary := widget.NewLabel(formatScanSummary(report))
+	summary.Wrapping = fyneapp.TextWrapWord
+
+	failures := widget.NewList(
+		func() int {
+			return len(report.Failures)
+		},
+		func() fyneapp.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyneapp.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyneapp.CanvasObject) {
+			failure := report.Failures[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%s: %s", scanFailureReasonLabels[failure.Reason], failure.Path))
+		},
+	)
+	failures.OnSelected = func(id widget.ListItemID) {
+		failures.Unselect(id)
+		failure := report.Failures[id]
+		dialog.ShowInformation(scanFailureReasonLabels[failure.Reason], failure.Path+"\n\n"+failure.Message, d.window)
+	}
+
+	export := widget.NewButton("Export...", func() {
+		d.export(report)
+	})
+
+	content := container.NewBorder(summary, container.NewHBox(export), nil, nil, failures)
+	reportDialog := dialog.NewCustom("Scan Report", "Close", content, d.window)
+	reportDialog.Resize(fyneapp.NewSize(640, 420))
+	reportDialog.Show()
+}
+
+// export saves the report to a file chosen by the user.
+// Files named *.json get JSON; anything else gets plain text.
+func (d *ScanReportDialog) export(report *domain.ScanReport) {
+	saveDialog := dialog.NewFileSave(func(writer fyneapp.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, d.window)
+			return
+		}
+		if writer == nil {
+			return // User cancelled
+		}
+		defer writer.Close()
+
+		asJSON := strings.EqualFold(writer.URI().Extension(), ".json")
+		if err := d.presenter.OnExportScanReport(writer, report, asJSON); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to export scan report: %w", err), d.window)
+			return
+		}
+		d.logger.Info("scan report exported", slog.String("path", writer.URI().Path()))
+	}, d.window)
+	saveDialog.SetFileName("scan-report.txt")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".json"}))
+	saveDialog.Show()
+}
+
+// formatScanSummary describes the scan and how many files could not be read.
+func formatScanSummary(report *domain.ScanReport) string {
+	scanned := report.Path
+	if scanned == "" {
+		scanned = "selected files"
+	}
+
+	summary := fmt.Sprintf("Scan of %s on %s: %d file(s) scanned, %d track(s) found.",
+		scanned, report.CompletedAt.Format("2006-01-02 15:04"), report.FilesScanned, report.TracksFound)
+	if len(report.Failures) == 0 {
+		return summary + "\nAll files were read."
+	}
+	return summary + fmt.Sprintf("\n%d file(s) or folder(s) could not be read:", len(report.Failures))
+}
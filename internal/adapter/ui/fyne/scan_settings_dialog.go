@@ -0,0 +1,138 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// charsetLabels are the names of legacy tag charsets shown to the user.
+var charsetLabels = map[domain.Charset]string{
+	domain.CharsetWindows1252: "Western (Windows-1252)",
+	domain.CharsetWindows1250: "Central European (Windows-1250)",
+	domain.CharsetWindows1251: "Cyrillic (Windows-1251)",
+	domain.CharsetKOI8R:       "Cyrillic (KOI8-R)",
+	domain.CharsetShiftJIS:    "Japanese (Shift-JIS)",
+	domain.CharsetGBK:         "Simplified Chinese (GBK)",
+	domain.CharsetBig5:        "Traditional Chinese (Big5)",
+	domain.CharsetEUCKR:       "Korean (EUC-KR)",
+}
+
+// ScanSettingsDialog edits the exclude patterns, limits, tag patterns and
+// legacy tag charset of scans.
+type ScanSettingsDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	logger    *slog.Logger
+}
+
+// NewScanSettingsDialog creates a new scan settings dialog.
+func NewScanSettingsDialog(window fyneapp.Window, presenter *Presenter, logger *slog.Logger) *ScanSettingsDialog {
+	return &ScanSettingsDialog{
+		window:    window,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+// Show displays the scan settings dialog.
+func (d *ScanSettingsDialog) Show() {
+	options := d.presenter.GetScanOptions()
+
+	patterns := widget.NewMultiLineEntry()
+	patterns.SetPlaceHolder("*.tmp\nSamples/\n/Incoming")
+	patterns.SetText(strings.Join(options.ExcludePatterns, "\n"))
+	patterns.SetMinRowsVisible(5)
+
+	maxDepth := widget.NewEntry()
+	maxDepth.SetPlaceHolder("No limit")
+	if options.MaxDepth > 0 {
+		maxDepth.SetText(strconv.Itoa(options.MaxDepth))
+	}
+	maxDepth.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		if depth, err := strconv.Atoi(strings.TrimSpace(text)); err != nil || depth < 0 {
+			return fmt.Errorf("enter a number of folder levels")
+		}
+		return nil
+	}
+
+	skipHidden := widget.NewCheck("Skip hidden folders", nil)
+	skipHidden.SetChecked(options.SkipHidden)
+
+	hint := widget.NewLabel("One pattern per line, as in .gitignore. Folders can add their own patterns in a .gotuneignore file.")
+	hint.Wrapping = fyneapp.TextWrapWord
+
+	tagPatterns := widget.NewMultiLineEntry()
+	tagPatterns.SetPlaceHolder("%artist%/%album%/%track% - %title%")
+	tagPatterns.SetText(strings.Join(options.TagPatterns, "\n"))
+	tagPatterns.SetMinRowsVisible(3)
+
+	tagHint := widget.NewLabel("Tags missing from scanned files are taken from the first matching pattern.")
+	tagHint.Wrapping = fyneapp.TextWrapWord
+
+	charsets := domain.Charsets()
+	charsetNames := make([]string, len(charsets))
+	for i, charset := range charsets {
+		charsetNames[i] = charsetLabels[charset]
+	}
+	charsetSelect := widget.NewSelect(charsetNames, nil)
+	charsetSelect.SetSelected(charsetLabels[d.presenter.GetTagCharset()])
+
+	charsetHint := widget.NewLabel("Used for old tags and module names that are not Unicode when their charset cannot be detected. Rescan to apply.")
+	charsetHint.Wrapping = fyneapp.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("Exclude", patterns),
+		widget.NewFormItem("", hint),
+		widget.NewFormItem("Max depth", maxDepth),
+		widget.NewFormItem("", skipHidden),
+		widget.NewFormItem("Tag patterns", tagPatterns),
+		widget.NewFormItem("", tagHint),
+		widget.NewFormItem("Legacy charset", charsetSelect),
+		widget.NewFormItem("", charsetHint),
+	)
+
+	settingsDialog := dialog.NewCustomConfirm("Scan Settings", "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+
+		depth := 0
+		if text := strings.TrimSpace(maxDepth.Text); text != "" {
+			var err error
+			if depth, err = strconv.Atoi(text); err != nil {
+				dialog.ShowError(fmt.Errorf("invalid max depth %q", text), d.window)
+				return
+			}
+		}
+		newOptions := domain.ScanOptions{
+			ExcludePatterns: strings.Split(patterns.Text, "\n"),
+			MaxDepth:        depth,
+			SkipHidden:      skipHidden.Checked,
+			TagPatterns:     strings.Split(tagPatterns.Text, "\n"),
+		}
+		if err := d.presenter.OnScanOptionsChanged(newOptions); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save scan settings: %w", err), d.window)
+			return
+		}
+		if index := charsetSelect.SelectedIndex(); index >= 0 {
+			if err := d.presenter.OnTagCharsetChanged(charsets[index]); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to save tag charset: %w", err), d.window)
+				return
+			}
+		}
+		d.logger.Info("scan settings saved",
+			slog.Int("patterns", len(d.presenter.GetScanOptions().ExcludePatterns)))
+	}, d.window)
+	settingsDialog.Resize(fyneapp.NewSize(480, 0))
+	settingsDialog.Show()
+}
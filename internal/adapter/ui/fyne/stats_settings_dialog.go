@@ -0,0 +1,66 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// StatsSettingsDialog edits how much of a track must be heard for it to count as played.
+type StatsSettingsDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	logger    *slog.Logger
+}
+
+// NewStatsSettingsDialog creates a new play count settings dialog.
+func NewStatsSettingsDialog(window fyneapp.Window, presenter *Presenter, logger *slog.Logger) *StatsSettingsDialog {
+	return &StatsSettingsDialog{
+		window:    window,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+// Show displays the play count settings dialog.
+func (d *StatsSettingsDialog) Show() {
+	percent := widget.NewLabel("")
+	threshold := widget.NewSlider(5, 100)
+	threshold.Step = 5
+	threshold.OnChanged = func(value float64) {
+		percent.SetText(fmt.Sprintf("%.0f%%", value))
+	}
+	threshold.SetValue(math.Round(d.presenter.GetPlayThreshold() * 100))
+
+	hint := widget.NewLabel(fmt.Sprintf(
+		"A track counts as played once this much of it was heard (at most %.0f minutes), or when it plays to the end. "+
+			"Stopping or skipping it earlier counts as a skip.",
+		domain.MaxPlayThresholdTime.Minutes()))
+	hint.Wrapping = fyneapp.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("Counts as played", container.NewBorder(nil, nil, nil, percent, threshold)),
+		widget.NewFormItem("", hint),
+	)
+
+	settingsDialog := dialog.NewCustomConfirm("Play Count Settings", "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+
+		fraction := threshold.Value / 100
+		if err := d.presenter.OnPlayThresholdChanged(fraction); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save play threshold: %w", err), d.window)
+			return
+		}
+		d.logger.Info("play threshold saved", slog.Float64("fraction", fraction))
+	}, d.window)
+	settingsDialog.Resize(fyneapp.NewSize(420, 0))
+	settingsDialog.Show()
+}
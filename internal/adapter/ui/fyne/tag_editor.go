@@ -0,0 +1,275 @@
+package fyne
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// mixedValuesPlaceholder is shown for fields that differ between the edited tracks.
+const mixedValuesPlaceholder = "(multiple values)"
+
+// tagField is one editable text field of the tag editor.
+type tagField struct {
+	entry   *widget.Entry
+	initial string // Common value of all tracks ("" when mixed)
+	mixed   bool   // Tracks have different values for this field
+}
+
+// changed returns the new value if the user changed the field.
+// Mixed fields are only changed when the user enters a value.
+func (f *tagField) changed() (string, bool) {
+	value := strings.TrimSpace(f.entry.Text)
+	if f.mixed {
+		return value, value != ""
+	}
+	return value, value != f.initial
+}
+
+// TagEditor is a dialog for editing the tags of one or more tracks.
+// Only fields the user changes are included in the resulting edit,
+// so editing several tracks keeps their individual values elsewhere.
+type TagEditor struct {
+	window fyneapp.Window
+	tracks []domain.MusicTrack
+	onSave func(domain.TagEdit) error
+	logger *slog.Logger
+
+	title       *tagField
+	artist      *tagField
+	album       *tagField
+	albumArtist *tagField
+	genre       *tagField
+	year        *tagField
+	trackNumber *tagField
+	discNumber  *tagField
+	comment     *tagField
+
+	coverLabel *widget.Label
+	albumArt   []byte // New cover (nil when unchanged, empty to remove)
+}
+
+// NewTagEditor creates a tag editor for the given tracks.
+// onSave is called with the edit when the user confirms the dialog.
+func NewTagEditor(window fyneapp.Window, tracks []domain.MusicTrack, onSave func(domain.TagEdit) error, logger *slog.Logger) *TagEditor {
+	e := &TagEditor{
+		window: window,
+		tracks: tracks,
+		onSave: onSave,
+		logger: logger,
+	}
+
+	e.title = e.newTextField(func(t domain.MusicTrack) string { return t.Title })
+	e.artist = e.newTextField(func(t domain.MusicTrack) string { return t.Artist })
+	e.album = e.newTextField(func(t domain.MusicTrack) string { return t.Album })
+	e.albumArtist = e.newTextField(func(t domain.MusicTrack) string { return t.AlbumArtist })
+	e.genre = e.newTextField(func(t domain.MusicTrack) string { return metadataOf(t).Genre })
+	e.year = e.newNumberField(func(t domain.MusicTrack) int { return metadataOf(t).Year })
+	e.trackNumber = e.newNumberField(func(t domain.MusicTrack) int { return metadataOf(t).TrackNumber })
+	e.discNumber = e.newNumberField(func(t domain.MusicTrack) int { return metadataOf(t).DiscNumber })
+	e.comment = e.newTextField(func(t domain.MusicTrack) string { return metadataOf(t).Comment })
+
+	e.coverLabel = widget.NewLabel(e.describeCover())
+
+	return e
+}
+
+// metadataOf returns the track metadata, or empty metadata if the track has none.
+func metadataOf(track domain.MusicTrack) domain.TrackMetadata {
+	if track.Metadata == nil {
+		return domain.TrackMetadata{}
+	}
+	return *track.Metadata
+}
+
+// newTextField creates a field showing the common value of all tracks.
+func (e *TagEditor) newTextField(value func(domain.MusicTrack) string) *tagField {
+	field := &tagField{entry: widget.NewEntry()}
+	for i, track := range e.tracks {
+		if i == 0 {
+			field.initial = value(track)
+		} else if value(track) != field.initial {
+			field.mixed = true
+			field.initial = ""
+			break
+		}
+	}
+
+	field.entry.SetText(field.initial)
+	if field.mixed {
+		field.entry.SetPlaceHolder(mixedValuesPlaceholder)
+	}
+	return field
+}
+
+// newNumberField creates a field for a numeric tag, where zero is shown as empty.
+func (e *TagEditor) newNumberField(value func(domain.MusicTrack) int) *tagField {
+	field := e.newTextField(func(t domain.MusicTrack) string {
+		if n := value(t); n > 0 {
+			return strconv.Itoa(n)
+		}
+		return ""
+	})
+	field.entry.Validator = func(text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(text); err != nil || n < 0 {
+			return fmt.Errorf("not a number")
+		}
+		return nil
+	}
+	return field
+}
+
+// describeCover returns the cover status shown next to the cover buttons.
+func (e *TagEditor) describeCover() string {
+	switch {
+	case e.albumArt != nil && len(e.albumArt) == 0:
+		return "Cover will be removed"
+	case e.albumArt != nil:
+		return fmt.Sprintf("New cover selected (%d KB)", len(e.albumArt)/1024)
+	}
+
+	// Folder covers are not part of the tags, so only embedded artwork is counted
+	withArt := 0
+	for _, track := range e.tracks {
+		if metadataOf(track).HasAlbumArt || len(metadataOf(track).AlbumArt) > 0 {
+			withArt++
+		}
+	}
+	switch withArt {
+	case 0:
+		return "No cover"
+	case len(e.tracks):
+		return "Embedded cover"
+	default:
+		return mixedValuesPlaceholder
+	}
+}
+
+// chooseCover opens a file dialog to pick a JPEG or PNG cover image.
+func (e *TagEditor) chooseCover() {
+	fileDialog := dialog.NewFileOpen(func(reader fyneapp.URIReadCloser, err error) {
+		if err != nil {
+			e.logger.Error("cover dialog error", slog.Any("error", err))
+			return
+		}
+		if reader == nil {
+			return // User cancelled
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to read image: %w", err), e.window)
+			return
+		}
+		e.albumArt = data
+		e.coverLabel.SetText(e.describeCover())
+	}, e.window)
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".jpg", ".jpeg", ".png"}))
+	fileDialog.Show()
+}
+
+// removeCover marks the cover for removal.
+func (e *TagEditor) removeCover() {
+	e.albumArt = []byte{}
+	e.coverLabel.SetText(e.describeCover())
+}
+
+// buildEdit collects the changed fields into a tag edit.
+func (e *TagEditor) buildEdit() domain.TagEdit {
+	edit := domain.TagEdit{AlbumArt: e.albumArt}
+
+	textFields := []struct {
+		field  *tagField
+		target **string
+	}{
+		{e.title, &edit.Title},
+		{e.artist, &edit.Artist},
+		{e.album, &edit.Album},
+		{e.albumArtist, &edit.AlbumArtist},
+		{e.genre, &edit.Genre},
+		{e.comment, &edit.Comment},
+	}
+	for _, f := range textFields {
+		if value, ok := f.field.changed(); ok {
+			*f.target = &value
+		}
+	}
+
+	numberFields := []struct {
+		field  *tagField
+		target **int
+	}{
+		{e.year, &edit.Year},
+		{e.trackNumber, &edit.TrackNumber},
+		{e.discNumber, &edit.DiscNumber},
+	}
+	for _, f := range numberFields {
+		if value, ok := f.field.changed(); ok {
+			// Empty clears the number; invalid input is rejected by the validator
+			n, _ := strconv.Atoi(value)
+			*f.target = &n
+		}
+	}
+
+	return edit
+}
+
+// Show displays the tag editor dialog.
+func (e *TagEditor) Show() {
+	form := widget.NewForm(
+		widget.NewFormItem("Title", e.title.entry),
+		widget.NewFormItem("Artist", e.artist.entry),
+		widget.NewFormItem("Album", e.album.entry),
+		widget.NewFormItem("Album Artist", e.albumArtist.entry),
+		widget.NewFormItem("Genre", e.genre.entry),
+		widget.NewFormItem("Year", e.year.entry),
+		widget.NewFormItem("Track", e.trackNumber.entry),
+		widget.NewFormItem("Disc", e.discNumber.entry),
+		widget.NewFormItem("Comment", e.comment.entry),
+		widget.NewFormItem("Cover", container.NewHBox(
+			e.coverLabel,
+			widget.NewButton("Choose Image...", e.chooseCover),
+			widget.NewButton("Remove", e.removeCover),
+		)),
+	)
+
+	title := "Edit Tags"
+	if len(e.tracks) > 1 {
+		title = fmt.Sprintf("Edit Tags (%d tracks)", len(e.tracks))
+	}
+
+	editDialog := dialog.NewCustomConfirm(title, "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+		if err := form.Validate(); err != nil {
+			dialog.ShowError(err, e.window)
+			return
+		}
+
+		edit := e.buildEdit()
+		if edit.IsEmpty() {
+			return
+		}
+		if err := e.onSave(edit); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save tags: %w", err), e.window)
+		}
+	}, e.window)
+	editDialog.Resize(fyneapp.NewSize(480, 0))
+	editDialog.Show()
+}
@@ -0,0 +1,134 @@
+package fyne
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// TagGuessDialog infers tags of tracks from their paths and shows the
+// guesses for review before they are applied.
+type TagGuessDialog struct {
+	window    fyneapp.Window
+	presenter *Presenter
+	tracks    []domain.MusicTrack
+	logger    *slog.Logger
+
+	guesses []domain.TagGuess
+}
+
+// NewTagGuessDialog creates a tag guess dialog for the given tracks.
+func NewTagGuessDialog(window fyneapp.Window, presenter *Presenter, tracks []domain.MusicTrack, logger *slog.Logger) *TagGuessDialog {
+	return &TagGuessDialog{
+		window:    window,
+		presenter: presenter,
+		tracks:    tracks,
+		logger:    logger,
+	}
+}
+
+// Show displays the tag guess dialog.
+func (d *TagGuessDialog) Show() {
+	patterns := widget.NewMultiLineEntry()
+	patterns.SetPlaceHolder("%artist%/%album%/%track% - %title%")
+	patterns.SetText(strings.Join(d.presenter.GetScanOptions().TagPatterns, "\n"))
+	patterns.SetMinRowsVisible(3)
+
+	onlyMissing := widget.NewCheck("Only fill in missing tags", nil)
+	onlyMissing.SetChecked(true)
+
+	summary := widget.NewLabel("")
+	summary.Wrapping = fyneapp.TextWrapWord
+
+	preview := widget.NewList(
+		func() int {
+			return len(d.guesses)
+		},
+		func() fyneapp.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyneapp.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyneapp.CanvasObject) {
+			guess := d.guesses[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%s: %s", filepath.Base(guess.Track.FilePath), describeTagEdit(guess.Edit)))
+		},
+	)
+
+	update := func() {
+		guesses, err := d.presenter.GuessTags(d.tracks, strings.Split(patterns.Text, "\n"), onlyMissing.Checked)
+		if err != nil {
+			d.guesses = nil
+			summary.SetText(err.Error())
+		} else {
+			d.guesses = guesses
+			summary.SetText(fmt.Sprintf("%d of %d track(s) will be changed.", len(guesses), len(d.tracks)))
+		}
+		preview.Refresh()
+	}
+	onlyMissing.OnChanged = func(bool) { update() }
+
+	hint := widget.NewLabel("One pattern per line; the first matching pattern is used. Placeholders: %artist%, %albumartist%, " +
+		"%album%, %title%, %genre%, %year%, %track%, %disc% and %ignore%.")
+	hint.Wrapping = fyneapp.TextWrapWord
+
+	top := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Patterns", patterns),
+			widget.NewFormItem("", hint),
+			widget.NewFormItem("", onlyMissing),
+		),
+		container.NewHBox(widget.NewButton("Preview", update)),
+		summary,
+	)
+
+	guessDialog := dialog.NewCustomConfirm("Guess Tags", "Apply", "Cancel", container.NewBorder(top, nil, nil, nil, preview), func(apply bool) {
+		if !apply || len(d.guesses) == 0 {
+			return
+		}
+		if err := d.presenter.OnApplyTagGuesses(d.guesses); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to apply some tags: %w", err), d.window)
+			return
+		}
+		d.logger.Info("guessed tags applied", slog.Int("tracks", len(d.guesses)))
+	}, d.window)
+	guessDialog.Resize(fyneapp.NewSize(640, 520))
+	guessDialog.Show()
+
+	if strings.TrimSpace(patterns.Text) != "" {
+		update()
+	}
+}
+
+// describeTagEdit lists the fields an edit sets, e.g. "Title: Song, Track: 7".
+func describeTagEdit(edit domain.TagEdit) string {
+	parts := make([]string, 0)
+	addText := func(name string, value *string) {
+		if value != nil {
+			parts = append(parts, name+": "+*value)
+		}
+	}
+	addNumber := func(name string, value *int) {
+		if value != nil {
+			parts = append(parts, name+": "+strconv.Itoa(*value))
+		}
+	}
+
+	addText("Title", edit.Title)
+	addText("Artist", edit.Artist)
+	addText("Album", edit.Album)
+	addText("Album Artist", edit.AlbumArtist)
+	addText("Genre", edit.Genre)
+	addNumber("Year", edit.Year)
+	addNumber("Track", edit.TrackNumber)
+	addNumber("Disc", edit.DiscNumber)
+	return strings.Join(parts, ", ")
+}
@@ -2,11 +2,15 @@ package widgets
 
 import (
 	fyneapp "fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 )
 
-// Ensure DoubleTapLabel implements SecondaryTappable interface
-var _ fyneapp.SecondaryTappable = (*DoubleTapLabel)(nil)
+// Ensure DoubleTapLabel implements SecondaryTappable and Mouseable interfaces
+var (
+	_ fyneapp.SecondaryTappable = (*DoubleTapLabel)(nil)
+	_ desktop.Mouseable         = (*DoubleTapLabel)(nil)
+)
 
 // DoubleTapLabel is a custom label widget that responds to double-tap gestures.
 // It extends the standard Fyne Label widget to support double-tap interaction,
@@ -15,6 +19,7 @@ type DoubleTapLabel struct {
 	widget.Label
 	doubleTapped    func(index int)
 	secondaryTapped func(index int, pos fyneapp.Position)
+	pressed         func(index int, modifier fyneapp.KeyModifier)
 	index           int
 }
 
@@ -59,3 +64,21 @@ func (l *DoubleTapLabel) TappedSecondary(pe *fyneapp.PointEvent) {
 		l.secondaryTapped(l.index, pe.AbsolutePosition)
 	}
 }
+
+// SetPressed sets the callback function for primary mouse button presses.
+// It receives the modifier keys held down, e.g. to extend a selection with Shift.
+func (l *DoubleTapLabel) SetPressed(callback func(index int, modifier fyneapp.KeyModifier)) {
+	l.pressed = callback
+}
+
+// MouseDown implements the desktop.Mouseable interface.
+// It is called when a mouse button is pressed over the label.
+func (l *DoubleTapLabel) MouseDown(me *desktop.MouseEvent) {
+	if l.pressed != nil && me.Button == desktop.MouseButtonPrimary {
+		l.pressed(l.index, me.Modifier)
+	}
+}
+
+// MouseUp implements the desktop.Mouseable interface.
+func (l *DoubleTapLabel) MouseUp(_ *desktop.MouseEvent) {
+}
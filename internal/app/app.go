@@ -5,13 +5,19 @@ package app
 import (
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 
 	"fyne.io/fyne/v2"
 	fyneapp "fyne.io/fyne/v2/app"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/artwork"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/audio/bass"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/audio/mock"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/lyrics"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/playlistfile"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/repository/memory"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/tags"
 	fyneui "github.com/tejashwikalptaru/gotune/internal/adapter/ui/fyne"
 	"github.com/tejashwikalptaru/gotune/internal/logger"
 	"github.com/tejashwikalptaru/gotune/internal/ports"
@@ -33,17 +39,28 @@ type Application struct {
 	// Infrastructure
 	eventBus    ports.EventBus
 	audioEngine ports.AudioEngine
+	thumbnails  ports.ThumbnailCache
 
 	// Repositories
 	historyRepo     ports.HistoryRepository
 	playlistRepo    ports.PlaylistRepository
 	preferencesRepo ports.PreferencesRepository
+	libraryRepo     ports.LibraryRepository
+	scanReportRepo  ports.ScanReportRepository
+	statsRepo       ports.StatsRepository
 
 	// Services
 	playbackService   *service.PlaybackService
 	playlistService   *service.PlaylistService
 	libraryService    *service.LibraryService
 	preferenceService *service.PreferenceService
+	tagService        *service.TagService
+	relocationService *service.RelocationService
+	lyricsService     *service.LyricsService
+	statsService      *service.StatsService
+	listeningHistory  *service.ListeningHistoryService
+	smartPlaylists    *service.SmartPlaylistService
+	playlistFiles     *service.PlaylistFileService
 
 	// UI (Phase 8)
 	presenter  *fyneui.Presenter
@@ -67,6 +84,10 @@ type Config struct {
 	// UseMockAudio determines whether to use a mock audio engine (for testing)
 	UseMockAudio bool
 
+	// CacheDir is the directory for cached data such as album art thumbnails
+	// (empty for the user's cache directory)
+	CacheDir string
+
 	// LogLevel controls logging verbosity
 	LogLevel slog.Level
 
@@ -87,6 +108,18 @@ func DefaultConfig() Config {
 	}
 }
 
+// resolveCacheDir returns the configured cache directory,
+// falling back to the user's cache directory (or the temp directory if unavailable).
+func resolveCacheDir(config Config) string {
+	if config.CacheDir != "" {
+		return config.CacheDir
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, config.AppID)
+	}
+	return filepath.Join(os.TempDir(), config.AppID)
+}
+
 // NewApplication creates a new application with all dependencies wired.
 // This is the main dependency injection function.
 func NewApplication(config Config) (*Application, error) {
@@ -133,11 +166,17 @@ func NewApplication(config Config) (*Application, error) {
 		app.audioEngine = engine
 	}
 
+	// Step 3.5: Create the album art thumbnail cache
+	app.thumbnails = artwork.NewThumbnailCache(filepath.Join(resolveCacheDir(config), "thumbnails"))
+
 	// Step 4: Create repositories
 	prefs := app.fyneApp.Preferences()
 	app.historyRepo = memory.NewHistoryRepository(prefs)
 	app.playlistRepo = memory.NewPlaylistRepository(prefs, app.logger.With(slog.String("repo", "playlist")))
 	app.preferencesRepo = memory.NewPreferencesRepository(prefs)
+	app.libraryRepo = memory.NewLibraryRepository(prefs)
+	app.scanReportRepo = memory.NewScanReportRepository(prefs)
+	app.statsRepo = memory.NewStatsRepository(prefs)
 
 	// Step 5: Create services (with dependency injection)
 	app.playbackService = service.NewPlaybackService(
@@ -157,6 +196,8 @@ func NewApplication(config Config) (*Application, error) {
 	app.libraryService = service.NewLibraryService(
 		app.logger.With(slog.String("service", "library")),
 		app.audioEngine,
+		app.libraryRepo,
+		app.scanReportRepo,
 		app.eventBus,
 	)
 
@@ -166,6 +207,65 @@ func NewApplication(config Config) (*Application, error) {
 		app.eventBus,
 	)
 
+	// Folder scans use the saved exclude patterns, limits and tag charset
+	app.libraryService.SetScanOptions(app.preferenceService.GetScanOptions())
+	app.libraryService.SetTagCharset(app.preferenceService.GetTagCharset())
+
+	app.tagService = service.NewTagService(
+		app.logger.With(slog.String("service", "tag")),
+		tags.NewWriter(),
+		app.libraryRepo,
+		app.eventBus,
+	)
+
+	app.relocationService = service.NewRelocationService(
+		app.logger.With(slog.String("service", "relocation")),
+		app.playlistService,
+		app.preferenceService,
+		app.libraryRepo,
+		app.playlistRepo,
+		app.statsRepo,
+		app.historyRepo,
+	)
+
+	app.lyricsService = service.NewLyricsService(
+		app.logger.With(slog.String("service", "lyrics")),
+		lyrics.NewReader(),
+		app.eventBus,
+	)
+
+	app.statsService = service.NewStatsService(
+		app.logger.With(slog.String("service", "stats")),
+		app.statsRepo,
+		app.eventBus,
+	)
+	if err := app.statsService.SetPlayThreshold(app.preferenceService.GetPlayThreshold()); err != nil {
+		app.logger.Warn("invalid play threshold", slog.Any("error", err))
+	}
+
+	app.listeningHistory = service.NewListeningHistoryService(
+		app.logger.With(slog.String("service", "listening_history")),
+		app.historyRepo,
+		app.eventBus,
+	)
+
+	app.smartPlaylists = service.NewSmartPlaylistService(
+		app.logger.With(slog.String("service", "smart_playlist")),
+		app.libraryRepo,
+		app.statsRepo,
+		app.playlistRepo,
+		app.eventBus,
+	)
+
+	app.playlistFiles = service.NewPlaylistFileService(
+		app.logger.With(slog.String("service", "playlist_file")),
+		app.libraryService,
+		app.playlistService,
+		playlistfile.NewM3U(),
+		playlistfile.NewPLS(),
+		playlistfile.NewXSPF(),
+	)
+
 	// Step 6: Load saved state
 	if err := app.loadSavedState(); err != nil {
 		// Non-fatal - just log and continue
@@ -182,7 +282,14 @@ func NewApplication(config Config) (*Application, error) {
 		app.playlistService,
 		app.libraryService,
 		app.preferenceService,
+		app.tagService,
+		app.relocationService,
+		app.lyricsService,
+		app.playlistFiles,
+		app.listeningHistory,
+		app.statsService,
 		app.eventBus,
+		app.thumbnails,
 		app.mainWindow,
 	)
 
@@ -216,9 +323,15 @@ func (a *Application) loadSavedState() error {
 		}
 	}
 
-	// Load saved loop mode
-	loop := a.preferenceService.GetLoopMode()
-	a.playbackService.SetLoop(loop)
+	// Load saved repeat mode
+	if err := a.playbackService.SetRepeatMode(a.preferenceService.GetRepeatMode()); err != nil {
+		a.logger.Warn("failed to set repeat mode", slog.Any("error", err))
+	}
+
+	// Re-evaluate smart playlists, since rules on last played dates change over time
+	if err := a.smartPlaylists.Refresh(); err != nil {
+		a.logger.Warn("failed to refresh smart playlists", slog.Any("error", err))
+	}
 
 	return nil
 }
@@ -252,6 +365,42 @@ func (a *Application) Shutdown() {
 	}
 
 	// Shutdown services (in reverse order of creation)
+	if a.smartPlaylists != nil {
+		if err := a.smartPlaylists.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown smart playlist service", slog.Any("error", err))
+		}
+	}
+
+	if a.listeningHistory != nil {
+		if err := a.listeningHistory.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown listening history service", slog.Any("error", err))
+		}
+	}
+
+	if a.statsService != nil {
+		if err := a.statsService.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown stats service", slog.Any("error", err))
+		}
+	}
+
+	if a.lyricsService != nil {
+		if err := a.lyricsService.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown lyrics service", slog.Any("error", err))
+		}
+	}
+
+	if a.relocationService != nil {
+		if err := a.relocationService.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown relocation service", slog.Any("error", err))
+		}
+	}
+
+	if a.tagService != nil {
+		if err := a.tagService.Shutdown(); err != nil {
+			a.logger.Warn("failed to shutdown tag service", slog.Any("error", err))
+		}
+	}
+
 	if a.preferenceService != nil {
 		if err := a.preferenceService.Shutdown(); err != nil {
 			a.logger.Warn("failed to shutdown preference service", slog.Any("error", err))
@@ -45,6 +45,12 @@ var (
 	// ErrUnsupportedFormat is returned when an audio file format is not supported.
 	ErrUnsupportedFormat = errors.New("unsupported audio format")
 
+	// ErrUnsupportedCodec is returned when the codec of an audio file cannot be decoded.
+	ErrUnsupportedCodec = errors.New("unsupported codec")
+
+	// ErrCorruptFile is returned when an audio file's header is invalid or its data is damaged.
+	ErrCorruptFile = errors.New("corrupt or invalid file")
+
 	// ErrFileNotFound is returned when a file does not exist.
 	ErrFileNotFound = errors.New("file not found")
 
@@ -68,6 +74,21 @@ var (
 
 	// ErrFFTDataUnavailable is returned when FFT data cannot be retrieved from the audio channel.
 	ErrFFTDataUnavailable = errors.New("FFT data unavailable")
+
+	// ErrTagsNotWritable is returned when tags cannot be written for a file format.
+	ErrTagsNotWritable = errors.New("tags cannot be written for this format")
+
+	// ErrLyricsNotFound is returned when a track has no lyrics.
+	ErrLyricsNotFound = errors.New("lyrics not found")
+
+	// ErrUnsupportedPlaylistFormat is returned for playlist files of an unknown format.
+	ErrUnsupportedPlaylistFormat = errors.New("unsupported playlist format")
+
+	// ErrNothingToUndo is returned when there is no queue edit to undo.
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	// ErrNothingToRedo is returned when there is no undone queue edit to redo.
+	ErrNothingToRedo = errors.New("nothing to redo")
 )
 
 // AudioEngineError represents an error from the audio engine.
@@ -104,6 +125,37 @@ func NewAudioEngineError(op, path string, code int, message string, err error) *
 	}
 }
 
+// TagError represents a failure to read or rewrite the tags of an audio file.
+type TagError struct {
+	Op      string // Operation that failed (e.g., "parse", "write")
+	Path    string // File path
+	Message string // Error message
+	Err     error  // Underlying error (if any)
+}
+
+// Error implements the error interface.
+func (e *TagError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("tag %s failed for '%s': %s: %v", e.Op, e.Path, e.Message, e.Err)
+	}
+	return fmt.Sprintf("tag %s failed for '%s': %s", e.Op, e.Path, e.Message)
+}
+
+// Unwrap returns the underlying error.
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// NewTagError creates a new TagError.
+func NewTagError(op, path, message string, err error) *TagError {
+	return &TagError{
+		Op:      op,
+		Path:    path,
+		Message: message,
+		Err:     err,
+	}
+}
+
 // ValidationError represents a validation error.
 type ValidationError struct {
 	Field   string      // Field that failed validation
@@ -125,6 +177,28 @@ func NewValidationError(field string, value interface{}, message string) *Valida
 	}
 }
 
+// QuerySyntaxError represents a malformed search query.
+// Position is the 1-based column in Query where the problem was detected.
+type QuerySyntaxError struct {
+	Query    string // The query text that failed to parse
+	Position int    // 1-based column of the offending token
+	Message  string // Error message
+}
+
+// Error implements the error interface.
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("invalid search query at column %d: %s", e.Position, e.Message)
+}
+
+// NewQuerySyntaxError creates a new QuerySyntaxError.
+func NewQuerySyntaxError(query string, position int, message string) *QuerySyntaxError {
+	return &QuerySyntaxError{
+		Query:    query,
+		Position: position,
+		Message:  message,
+	}
+}
+
 // ServiceError represents an error from a service layer operation.
 type ServiceError struct {
 	Service string // Service name (e.g., "PlaybackService", "PlaylistService")
@@ -36,18 +36,43 @@ const (
 	EventMuteToggled   EventType = "mute.toggled"
 
 	// Playback mode events
-	EventLoopToggled EventType = "loop.toggled"
+	EventRepeatModeChanged EventType = "repeat.changed"
+	EventShuffleChanged    EventType = "shuffle.changed"
 
 	// Queue/Playlist events
 	EventPlaylistUpdated EventType = "playlist.updated"
 	EventQueueChanged    EventType = "queue.changed"
 	EventTrackAdded      EventType = "track.added"
+	EventTrackUpdated    EventType = "track.updated"
+	EventQueueHistory    EventType = "queue.history"
+
+	// Named playlist events
+	EventPlaylistCreated       EventType = "playlist.created"
+	EventPlaylistRenamed       EventType = "playlist.renamed"
+	EventPlaylistDuplicated    EventType = "playlist.duplicated"
+	EventPlaylistDeleted       EventType = "playlist.deleted"
+	EventQueueSavedAsPlaylist  EventType = "playlist.queue_saved"
+	EventPlaylistLoaded        EventType = "playlist.loaded"
+	EventPlaylistTracksAdded   EventType = "playlist.tracks_added"
+	EventPlaylistTracksRemoved EventType = "playlist.tracks_removed"
+
+	// Library events
+	EventLibraryChanged       EventType = "library.changed"
+	EventSmartPlaylistUpdated EventType = "smart_playlist.updated"
 
 	// Library scanning events
 	EventScanStarted   EventType = "scan.started"
 	EventScanProgress  EventType = "scan.progress"
 	EventScanCompleted EventType = "scan.completed"
 	EventScanCancelled EventType = "scan.cancelled"
+
+	// Lyrics events
+	EventLyricsLoaded EventType = "lyrics.loaded"
+	EventLyricsLine   EventType = "lyrics.line"
+
+	// Statistics events
+	EventTrackStatsUpdated EventType = "stats.updated"
+	EventListenLogged      EventType = "history.listen_logged"
 )
 
 // EventHandler is a function that handles events.
@@ -234,22 +259,63 @@ func NewMuteToggledEvent(muted bool) MuteToggledEvent {
 	}
 }
 
-// LoopToggledEvent is published when loop mode is toggled.
-type LoopToggledEvent struct {
+// RepeatModeChangedEvent is published when the repeat mode changes.
+type RepeatModeChangedEvent struct {
+	baseEvent
+	Mode RepeatMode
+}
+
+// Type returns the event type.
+func (e RepeatModeChangedEvent) Type() EventType {
+	return EventRepeatModeChanged
+}
+
+// NewRepeatModeChangedEvent creates a new RepeatModeChangedEvent.
+func NewRepeatModeChangedEvent(mode RepeatMode) RepeatModeChangedEvent {
+	return RepeatModeChangedEvent{
+		baseEvent: newBaseEvent(),
+		Mode:      mode,
+	}
+}
+
+// ShuffleChangedEvent is published when the queue is shuffled or unshuffled.
+type ShuffleChangedEvent struct {
 	baseEvent
-	Enabled bool
+	Mode ShuffleMode
 }
 
 // Type returns the event type.
-func (e LoopToggledEvent) Type() EventType {
-	return EventLoopToggled
+func (e ShuffleChangedEvent) Type() EventType {
+	return EventShuffleChanged
 }
 
-// NewLoopToggledEvent creates a new LoopToggledEvent.
-func NewLoopToggledEvent(enabled bool) LoopToggledEvent {
-	return LoopToggledEvent{
+// NewShuffleChangedEvent creates a new ShuffleChangedEvent.
+func NewShuffleChangedEvent(mode ShuffleMode) ShuffleChangedEvent {
+	return ShuffleChangedEvent{
 		baseEvent: newBaseEvent(),
-		Enabled:   enabled,
+		Mode:      mode,
+	}
+}
+
+// QueueHistoryEvent is published when queue edits can be undone or redone.
+// The actions describe the edits, e.g. "Remove Track"; empty means there is none.
+type QueueHistoryEvent struct {
+	baseEvent
+	UndoAction string
+	RedoAction string
+}
+
+// Type returns the event type.
+func (e QueueHistoryEvent) Type() EventType {
+	return EventQueueHistory
+}
+
+// NewQueueHistoryEvent creates a new QueueHistoryEvent.
+func NewQueueHistoryEvent(undoAction, redoAction string) QueueHistoryEvent {
+	return QueueHistoryEvent{
+		baseEvent:  newBaseEvent(),
+		UndoAction: undoAction,
+		RedoAction: redoAction,
 	}
 }
 
@@ -257,7 +323,8 @@ func NewLoopToggledEvent(enabled bool) LoopToggledEvent {
 type PlaylistUpdatedEvent struct {
 	baseEvent
 	Playlist []MusicTrack
-	Index    int // Current track index
+	UpNext   []MusicTrack // Tracks played before the rest of the playlist
+	Index    int          // Current track index
 }
 
 // Type returns the event type.
@@ -266,10 +333,11 @@ func (e PlaylistUpdatedEvent) Type() EventType {
 }
 
 // NewPlaylistUpdatedEvent creates a new PlaylistUpdatedEvent.
-func NewPlaylistUpdatedEvent(playlist []MusicTrack, index int) PlaylistUpdatedEvent {
+func NewPlaylistUpdatedEvent(playlist, upNext []MusicTrack, index int) PlaylistUpdatedEvent {
 	return PlaylistUpdatedEvent{
 		baseEvent: newBaseEvent(),
 		Playlist:  playlist,
+		UpNext:    upNext,
 		Index:     index,
 	}
 }
@@ -314,6 +382,26 @@ func NewTrackAddedEvent(track MusicTrack, index int) TrackAddedEvent {
 	}
 }
 
+// TrackUpdatedEvent is published when a track's tags have been edited.
+// Track holds the updated track; it is identified by its FilePath.
+type TrackUpdatedEvent struct {
+	baseEvent
+	Track MusicTrack
+}
+
+// Type returns the event type.
+func (e TrackUpdatedEvent) Type() EventType {
+	return EventTrackUpdated
+}
+
+// NewTrackUpdatedEvent creates a new TrackUpdatedEvent.
+func NewTrackUpdatedEvent(track MusicTrack) TrackUpdatedEvent {
+	return TrackUpdatedEvent{
+		baseEvent: newBaseEvent(),
+		Track:     track,
+	}
+}
+
 // ScanStartedEvent is published when a library scan starts.
 type ScanStartedEvent struct {
 	baseEvent
@@ -356,6 +444,7 @@ func NewScanProgressEvent(progress ScanProgress) ScanProgressEvent {
 type ScanCompletedEvent struct {
 	baseEvent
 	TracksFound []MusicTrack
+	Failures    []ScanFailure // Files that could not be read
 }
 
 // Type returns the event type.
@@ -364,10 +453,11 @@ func (e ScanCompletedEvent) Type() EventType {
 }
 
 // NewScanCompletedEvent creates a new ScanCompletedEvent.
-func NewScanCompletedEvent(tracks []MusicTrack) ScanCompletedEvent {
+func NewScanCompletedEvent(tracks []MusicTrack, failures []ScanFailure) ScanCompletedEvent {
 	return ScanCompletedEvent{
 		baseEvent:   newBaseEvent(),
 		TracksFound: tracks,
+		Failures:    failures,
 	}
 }
 
@@ -432,3 +522,288 @@ func NewAutoNextEvent(track MusicTrack, index int) AutoNextEvent {
 		CurrentIndex: index,
 	}
 }
+
+// LyricsLoadedEvent is published when the lyrics of the loaded track have been looked up.
+// Lyrics is nil if the track has no lyrics.
+type LyricsLoadedEvent struct {
+	baseEvent
+	Track  MusicTrack
+	Lyrics *Lyrics
+}
+
+// Type returns the event type.
+func (e LyricsLoadedEvent) Type() EventType {
+	return EventLyricsLoaded
+}
+
+// NewLyricsLoadedEvent creates a new LyricsLoadedEvent.
+func NewLyricsLoadedEvent(track MusicTrack, lyrics *Lyrics) LyricsLoadedEvent {
+	return LyricsLoadedEvent{
+		baseEvent: newBaseEvent(),
+		Track:     track,
+		Lyrics:    lyrics,
+	}
+}
+
+// LyricsLineEvent is published when playback crosses the timestamp of a synced lyrics line.
+// Index is -1 before the first line (e.g. after seeking back to the start).
+type LyricsLineEvent struct {
+	baseEvent
+	Index int
+	Line  LyricsLine
+}
+
+// Type returns the event type.
+func (e LyricsLineEvent) Type() EventType {
+	return EventLyricsLine
+}
+
+// NewLyricsLineEvent creates a new LyricsLineEvent.
+func NewLyricsLineEvent(index int, line LyricsLine) LyricsLineEvent {
+	return LyricsLineEvent{
+		baseEvent: newBaseEvent(),
+		Index:     index,
+		Line:      line,
+	}
+}
+
+// TrackStatsUpdatedEvent is published when a track is counted as played or skipped.
+type TrackStatsUpdatedEvent struct {
+	baseEvent
+	Stats TrackStats
+}
+
+// Type returns the event type.
+func (e TrackStatsUpdatedEvent) Type() EventType {
+	return EventTrackStatsUpdated
+}
+
+// NewTrackStatsUpdatedEvent creates a new TrackStatsUpdatedEvent.
+func NewTrackStatsUpdatedEvent(stats TrackStats) TrackStatsUpdatedEvent {
+	return TrackStatsUpdatedEvent{
+		baseEvent: newBaseEvent(),
+		Stats:     stats,
+	}
+}
+
+// ListenLoggedEvent is published when a play is added to the listening history log.
+type ListenLoggedEvent struct {
+	baseEvent
+	Entry ListeningEntry
+}
+
+// Type returns the event type.
+func (e ListenLoggedEvent) Type() EventType {
+	return EventListenLogged
+}
+
+// NewListenLoggedEvent creates a new ListenLoggedEvent.
+func NewListenLoggedEvent(entry ListeningEntry) ListenLoggedEvent {
+	return ListenLoggedEvent{
+		baseEvent: newBaseEvent(),
+		Entry:     entry,
+	}
+}
+
+// LibraryChangedEvent is published when tracks are added to the library or replaced in it.
+type LibraryChangedEvent struct {
+	baseEvent
+	Tracks []MusicTrack // The tracks that were added or replaced
+}
+
+// Type returns the event type.
+func (e LibraryChangedEvent) Type() EventType {
+	return EventLibraryChanged
+}
+
+// NewLibraryChangedEvent creates a new LibraryChangedEvent.
+func NewLibraryChangedEvent(tracks []MusicTrack) LibraryChangedEvent {
+	return LibraryChangedEvent{
+		baseEvent: newBaseEvent(),
+		Tracks:    tracks,
+	}
+}
+
+// SmartPlaylistUpdatedEvent is published when a smart playlist is created,
+// changed, or re-evaluated with a different result.
+type SmartPlaylistUpdatedEvent struct {
+	baseEvent
+	Playlist *Playlist
+}
+
+// Type returns the event type.
+func (e SmartPlaylistUpdatedEvent) Type() EventType {
+	return EventSmartPlaylistUpdated
+}
+
+// NewSmartPlaylistUpdatedEvent creates a new SmartPlaylistUpdatedEvent.
+func NewSmartPlaylistUpdatedEvent(playlist *Playlist) SmartPlaylistUpdatedEvent {
+	return SmartPlaylistUpdatedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+	}
+}
+
+// PlaylistCreatedEvent is published when a named playlist is created.
+type PlaylistCreatedEvent struct {
+	baseEvent
+	Playlist *Playlist
+}
+
+// Type returns the event type.
+func (e PlaylistCreatedEvent) Type() EventType {
+	return EventPlaylistCreated
+}
+
+// NewPlaylistCreatedEvent creates a new PlaylistCreatedEvent.
+func NewPlaylistCreatedEvent(playlist *Playlist) PlaylistCreatedEvent {
+	return PlaylistCreatedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+	}
+}
+
+// PlaylistRenamedEvent is published when a named playlist is renamed.
+type PlaylistRenamedEvent struct {
+	baseEvent
+	Playlist *Playlist
+	OldName  string
+}
+
+// Type returns the event type.
+func (e PlaylistRenamedEvent) Type() EventType {
+	return EventPlaylistRenamed
+}
+
+// NewPlaylistRenamedEvent creates a new PlaylistRenamedEvent.
+func NewPlaylistRenamedEvent(playlist *Playlist, oldName string) PlaylistRenamedEvent {
+	return PlaylistRenamedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+		OldName:   oldName,
+	}
+}
+
+// PlaylistDuplicatedEvent is published when a named playlist is copied.
+type PlaylistDuplicatedEvent struct {
+	baseEvent
+	Playlist *Playlist // The copy
+	SourceID string
+}
+
+// Type returns the event type.
+func (e PlaylistDuplicatedEvent) Type() EventType {
+	return EventPlaylistDuplicated
+}
+
+// NewPlaylistDuplicatedEvent creates a new PlaylistDuplicatedEvent.
+func NewPlaylistDuplicatedEvent(playlist *Playlist, sourceID string) PlaylistDuplicatedEvent {
+	return PlaylistDuplicatedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+		SourceID:  sourceID,
+	}
+}
+
+// PlaylistDeletedEvent is published when a named playlist is deleted.
+type PlaylistDeletedEvent struct {
+	baseEvent
+	PlaylistID string
+	Name       string
+}
+
+// Type returns the event type.
+func (e PlaylistDeletedEvent) Type() EventType {
+	return EventPlaylistDeleted
+}
+
+// NewPlaylistDeletedEvent creates a new PlaylistDeletedEvent.
+func NewPlaylistDeletedEvent(id, name string) PlaylistDeletedEvent {
+	return PlaylistDeletedEvent{
+		baseEvent:  newBaseEvent(),
+		PlaylistID: id,
+		Name:       name,
+	}
+}
+
+// QueueSavedAsPlaylistEvent is published when the queue is saved as a named playlist.
+type QueueSavedAsPlaylistEvent struct {
+	baseEvent
+	Playlist *Playlist
+}
+
+// Type returns the event type.
+func (e QueueSavedAsPlaylistEvent) Type() EventType {
+	return EventQueueSavedAsPlaylist
+}
+
+// NewQueueSavedAsPlaylistEvent creates a new QueueSavedAsPlaylistEvent.
+func NewQueueSavedAsPlaylistEvent(playlist *Playlist) QueueSavedAsPlaylistEvent {
+	return QueueSavedAsPlaylistEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+	}
+}
+
+// PlaylistLoadedEvent is published when a named playlist is loaded into the queue.
+type PlaylistLoadedEvent struct {
+	baseEvent
+	Playlist *Playlist
+	Replace  bool // true if the playlist replaced the queue, false if it was appended
+}
+
+// Type returns the event type.
+func (e PlaylistLoadedEvent) Type() EventType {
+	return EventPlaylistLoaded
+}
+
+// NewPlaylistLoadedEvent creates a new PlaylistLoadedEvent.
+func NewPlaylistLoadedEvent(playlist *Playlist, replace bool) PlaylistLoadedEvent {
+	return PlaylistLoadedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+		Replace:   replace,
+	}
+}
+
+// PlaylistTracksAddedEvent is published when tracks are added to a named playlist.
+type PlaylistTracksAddedEvent struct {
+	baseEvent
+	Playlist *Playlist
+	Tracks   []MusicTrack // The tracks that were added
+}
+
+// Type returns the event type.
+func (e PlaylistTracksAddedEvent) Type() EventType {
+	return EventPlaylistTracksAdded
+}
+
+// NewPlaylistTracksAddedEvent creates a new PlaylistTracksAddedEvent.
+func NewPlaylistTracksAddedEvent(playlist *Playlist, tracks []MusicTrack) PlaylistTracksAddedEvent {
+	return PlaylistTracksAddedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+		Tracks:    tracks,
+	}
+}
+
+// PlaylistTracksRemovedEvent is published when tracks are removed from a named playlist.
+type PlaylistTracksRemovedEvent struct {
+	baseEvent
+	Playlist *Playlist
+	Tracks   []MusicTrack // The tracks that were removed
+}
+
+// Type returns the event type.
+func (e PlaylistTracksRemovedEvent) Type() EventType {
+	return EventPlaylistTracksRemoved
+}
+
+// NewPlaylistTracksRemovedEvent creates a new PlaylistTracksRemovedEvent.
+func NewPlaylistTracksRemovedEvent(playlist *Playlist, tracks []MusicTrack) PlaylistTracksRemovedEvent {
+	return PlaylistTracksRemovedEvent{
+		baseEvent: newBaseEvent(),
+		Playlist:  playlist,
+		Tracks:    tracks,
+	}
+}
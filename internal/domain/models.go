@@ -3,6 +3,11 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -12,7 +17,8 @@ type MusicTrack struct {
 	// ID is a unique identifier for the track (UUID)
 	ID string
 
-	// FilePath is the absolute path to the audio file on the filesystem
+	// FilePath is the absolute path to the audio file on the filesystem,
+	// or a virtual path to an entry inside a ZIP archive (see SplitArchivePath)
 	FilePath string
 
 	// Title is the song title (from metadata or filename)
@@ -24,6 +30,18 @@ type MusicTrack struct {
 	// Album is the album name
 	Album string
 
+	// AlbumArtist is the artist credited for the whole album (may differ from Artist)
+	AlbumArtist string
+
+	// Compilation indicates that the album is a compilation of various artists
+	Compilation bool
+
+	// ArtistSort, AlbumSort and TitleSort are the names used for sorting
+	// (e.g. "Beatles, The"), empty if the file has no sort tags
+	ArtistSort string
+	AlbumSort  string
+	TitleSort  string
+
 	// Duration is the total length of the track
 	Duration time.Duration
 
@@ -33,10 +51,126 @@ type MusicTrack struct {
 	// IsMOD indicates if this is a tracker module file (MOD, XM, IT, S3M)
 	IsMOD bool
 
+	// FileSize is the size of the file in bytes when it was scanned (0 if unknown).
+	// It is used to recognize the file after it was moved.
+	FileSize int64
+
+	// Missing indicates that the file was not found at FilePath during the last check
+	Missing bool
+
 	// Metadata contains additional track information
 	Metadata *TrackMetadata
 }
 
+// VariousArtists is the album artist of compilations that do not name one.
+const VariousArtists = "Various Artists"
+
+// EffectiveAlbumArtist returns the artist that the track's album is filed under:
+// the album artist, VariousArtists for compilations without one, or the track artist.
+func (t MusicTrack) EffectiveAlbumArtist() string {
+	switch {
+	case t.AlbumArtist != "":
+		return t.AlbumArtist
+	case t.Compilation:
+		return VariousArtists
+	default:
+		return t.Artist
+	}
+}
+
+// AlbumKey identifies the album of the track for grouping.
+// Tracks of a compilation share a key even though their artists differ.
+// Returns an empty string for tracks without an album.
+func (t MusicTrack) AlbumKey() string {
+	if t.Album == "" {
+		return ""
+	}
+	return strings.ToLower(t.EffectiveAlbumArtist()) + "\x00" + strings.ToLower(t.Album)
+}
+
+// SortArtist returns the key used to sort by artist.
+func (t MusicTrack) SortArtist() string {
+	return sortKey(t.ArtistSort, t.Artist)
+}
+
+// SortAlbumArtist returns the key used to sort by album artist.
+// The artist sort tag is used when the album artist is the track artist.
+func (t MusicTrack) SortAlbumArtist() string {
+	albumArtist := t.EffectiveAlbumArtist()
+	if albumArtist == t.Artist {
+		return t.SortArtist()
+	}
+	return sortKey("", albumArtist)
+}
+
+// SortAlbum returns the key used to sort by album.
+func (t MusicTrack) SortAlbum() string {
+	return sortKey(t.AlbumSort, t.Album)
+}
+
+// SortTitle returns the key used to sort by title.
+func (t MusicTrack) SortTitle() string {
+	return sortKey(t.TitleSort, t.Title)
+}
+
+// sortKey returns the case-folded sort tag, or the value if there is no sort tag.
+func sortKey(sortTag, value string) string {
+	if sortTag != "" {
+		return strings.ToLower(sortTag)
+	}
+	return strings.ToLower(value)
+}
+
+// StableID identifies the track across scans and restarts.
+// Unlike ID, which is generated on every scan, it is derived from the file path.
+func (t MusicTrack) StableID() string {
+	sum := sha256.Sum256([]byte(filepath.Clean(t.FilePath)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// WithoutAlbumArt returns a copy of the track without the artwork bytes, for storing.
+// HasAlbumArt is kept, so it is still known whether the file has artwork.
+// The original track and its metadata are not modified.
+func (t MusicTrack) WithoutAlbumArt() MusicTrack {
+	if t.Metadata == nil || t.Metadata.AlbumArt == nil {
+		return t
+	}
+	metadata := *t.Metadata
+	metadata.HasAlbumArt = true
+	metadata.AlbumArt = nil
+	t.Metadata = &metadata
+	return t
+}
+
+// archivePathMarker marks where the archive ends in a virtual path
+// such as "/music/pack.zip!/song.xm".
+const archivePathMarker = ".zip!/"
+
+// ArchiveEntryPath returns the virtual path of an entry inside a ZIP archive.
+func ArchiveEntryPath(archivePath, entryName string) string {
+	return archivePath + "!/" + entryName
+}
+
+// SplitArchivePath splits a virtual path into the path of the ZIP archive and
+// the name of the entry inside it. Returns false if the path is not inside an archive.
+func SplitArchivePath(path string) (archivePath, entryName string, ok bool) {
+	index := strings.Index(strings.ToLower(path), archivePathMarker)
+	if index < 0 {
+		return "", "", false
+	}
+	end := index + len(".zip")
+	return path[:end], path[end+len("!/"):], true
+}
+
+// PhysicalPath returns the path of the file on the filesystem that holds the track:
+// the archive for virtual paths, otherwise the path itself.
+func PhysicalPath(path string) string {
+	if archivePath, _, ok := SplitArchivePath(path); ok {
+		return archivePath
+	}
+	return path
+}
+
 // TrackMetadata contains extended metadata for an audio track.
 type TrackMetadata struct {
 	// Composer is the song composer
@@ -48,25 +182,137 @@ type TrackMetadata struct {
 	// Year is the release year
 	Year int
 
-	// AlbumArt is the embedded album artwork as raw bytes
+	// AlbumArt is the embedded album artwork as raw bytes.
+	// It is only held in memory: stored tracks drop it (see MusicTrack.WithoutAlbumArt)
+	// and the artwork is read from the file again when it is displayed.
 	AlbumArt []byte
 
-	// BitRate is the audio bit rate in kbps
+	// HasAlbumArt reports whether the file has embedded artwork.
+	// Unlike AlbumArt, it is kept when tracks are stored.
+	HasAlbumArt bool
+
+	// CoverPath is the path of a cover image in the track's folder (cover.jpg, folder.png, ...),
+	// shown when the file has no embedded artwork. Empty if there is none.
+	CoverPath string
+
+	// Codec is the audio codec name (e.g., "MP3", "FLAC", "AAC")
+	Codec string
+
+	// BitRate is the audio bit rate in kbps (the average for VBR files)
 	BitRate int
 
 	// SampleRate is the audio sample rate in Hz
 	SampleRate int
 
+	// BitDepth is the bits per sample of lossless sources (0 if not applicable)
+	BitDepth int
+
+	// Channels is the number of audio channels
+	Channels int
+
 	// TrackNumber is the track number on the album
 	TrackNumber int
 
 	// DiscNumber is the disc number for multi-disc albums
 	DiscNumber int
 
+	// Rating is the star rating from 1 to 5 (0 if unrated)
+	Rating int
+
 	// Comment contains any additional metadata comments
 	Comment string
 }
 
+// TagEdit describes changes to the tags of one or more tracks.
+// Nil fields are left unchanged, so the same edit can be applied to many tracks
+// (e.g. setting the album on a whole folder without touching the titles).
+// An empty string or zero number clears the field.
+type TagEdit struct {
+	Title       *string
+	Artist      *string
+	Album       *string
+	AlbumArtist *string
+	Genre       *string
+	Year        *int
+	TrackNumber *int
+	DiscNumber  *int
+	Comment     *string
+
+	// AlbumArt replaces the front cover when non-nil. An empty slice removes all artwork.
+	AlbumArt []byte
+}
+
+// IsEmpty returns true if the edit does not change anything.
+func (e TagEdit) IsEmpty() bool {
+	return e.Title == nil && e.Artist == nil && e.Album == nil && e.AlbumArtist == nil &&
+		e.Genre == nil && e.Year == nil && e.TrackNumber == nil && e.DiscNumber == nil &&
+		e.Comment == nil && e.AlbumArt == nil
+}
+
+// Apply returns a copy of the track with the edit applied.
+// The original track and its metadata are not modified.
+func (e TagEdit) Apply(track MusicTrack) MusicTrack {
+	metadata := TrackMetadata{}
+	if track.Metadata != nil {
+		metadata = *track.Metadata
+	}
+
+	if e.Title != nil {
+		track.Title = *e.Title
+	}
+	if e.Artist != nil {
+		track.Artist = *e.Artist
+	}
+	if e.Album != nil {
+		track.Album = *e.Album
+	}
+	if e.AlbumArtist != nil {
+		track.AlbumArtist = *e.AlbumArtist
+	}
+	if e.Genre != nil {
+		metadata.Genre = *e.Genre
+	}
+	if e.Year != nil {
+		metadata.Year = *e.Year
+	}
+	if e.TrackNumber != nil {
+		metadata.TrackNumber = *e.TrackNumber
+	}
+	if e.DiscNumber != nil {
+		metadata.DiscNumber = *e.DiscNumber
+	}
+	if e.Comment != nil {
+		metadata.Comment = *e.Comment
+	}
+	if e.AlbumArt != nil {
+		metadata.AlbumArt = e.AlbumArt
+		if len(e.AlbumArt) == 0 {
+			metadata.AlbumArt = nil
+		}
+		metadata.HasAlbumArt = len(e.AlbumArt) > 0
+	}
+
+	track.Metadata = &metadata
+	return track
+}
+
+// TagGuess is the tags inferred for a track from its path, shown for review before they are applied.
+type TagGuess struct {
+	// Track is the track as it is now
+	Track MusicTrack
+
+	// Edit contains only the fields that the guess changes
+	Edit TagEdit
+
+	// Pattern is the tag pattern that matched the path
+	Pattern string
+}
+
+// TrackPredicate reports whether a track satisfies a condition.
+// Predicates are produced by the search query compiler and can be applied to
+// the playback queue as well as the library.
+type TrackPredicate func(track MusicTrack) bool
+
 // Playlist represents a collection of music tracks.
 type Playlist struct {
 	// ID is a unique identifier for the playlist (UUID)
@@ -83,6 +329,224 @@ type Playlist struct {
 
 	// UpdatedAt is when the playlist was last modified
 	UpdatedAt time.Time
+
+	// Smart holds the rules of a smart playlist (nil for static playlists).
+	// The Tracks of a smart playlist are the result of the last evaluation.
+	Smart *SmartPlaylistRules
+}
+
+// IsSmart returns true if the playlist is a smart playlist.
+func (p *Playlist) IsSmart() bool {
+	return p.Smart != nil
+}
+
+// PlaylistFileEntry is a track listed in a playlist file such as M3U.
+type PlaylistFileEntry struct {
+	// Path is the absolute path of the track, or the location as written
+	// in the file if it is not a local path (e.g. a URL)
+	Path string
+
+	// Title is the display title stored in the playlist file (empty if none)
+	Title string
+
+	// Duration is the length stored in the playlist file (0 if unknown)
+	Duration time.Duration
+
+	// Line is the line number of the entry in the file (0 for entries being written)
+	Line int
+}
+
+// UnresolvedEntry is a playlist file entry that does not lead to a playable track.
+type UnresolvedEntry struct {
+	// Entry is the entry as read from the playlist file
+	Entry PlaylistFileEntry
+
+	// Reason explains why the entry could not be resolved (e.g. "file not found")
+	Reason string
+}
+
+// PlaylistImport is the result of importing a playlist file.
+type PlaylistImport struct {
+	// Name is the name of the imported playlist
+	Name string
+
+	// PlaylistID is the ID of the saved playlist, empty if the tracks were added to the queue
+	PlaylistID string
+
+	// Tracks are the resolved tracks, in playlist order
+	Tracks []MusicTrack
+
+	// Unresolved lists the entries that were skipped
+	Unresolved []UnresolvedEntry
+}
+
+// SmartRuleField is a track property tested by a smart playlist rule.
+type SmartRuleField string
+
+const (
+	// SmartFieldGenre matches the genre (Value, case-insensitive)
+	SmartFieldGenre SmartRuleField = "genre"
+
+	// SmartFieldYear matches the release year (Min, Max)
+	SmartFieldYear SmartRuleField = "year"
+
+	// SmartFieldRating matches the star rating, 0 for unrated tracks (Min, Max)
+	SmartFieldRating SmartRuleField = "rating"
+
+	// SmartFieldPlayCount matches how many times the track was played (Min, Max)
+	SmartFieldPlayCount SmartRuleField = "play_count"
+
+	// SmartFieldLastPlayed matches the days since the track was last played (Min, Max).
+	// Tracks never played only match rules without a Max.
+	SmartFieldLastPlayed SmartRuleField = "last_played"
+
+	// SmartFieldFormat matches the file format, with or without the leading dot (Value)
+	SmartFieldFormat SmartRuleField = "format"
+
+	// SmartFieldPath matches tracks inside a folder (Value)
+	SmartFieldPath SmartRuleField = "path"
+)
+
+// SmartRule is a condition on one track property.
+type SmartRule struct {
+	Field SmartRuleField
+
+	// Value is the text matched by genre, format and path rules
+	Value string
+
+	// Min and Max bound numeric fields, inclusive (nil for no bound)
+	Min *float64
+	Max *float64
+
+	// Negate selects the tracks that do not match the rule
+	Negate bool
+}
+
+// SmartSortField is the order of the tracks of a smart playlist.
+type SmartSortField string
+
+const (
+	SmartSortTitle      SmartSortField = "title"
+	SmartSortArtist     SmartSortField = "artist"
+	SmartSortAlbum      SmartSortField = "album"
+	SmartSortYear       SmartSortField = "year"
+	SmartSortRating     SmartSortField = "rating"
+	SmartSortPlayCount  SmartSortField = "play_count"
+	SmartSortLastPlayed SmartSortField = "last_played"
+	SmartSortAdded      SmartSortField = "added"
+	SmartSortRandom     SmartSortField = "random"
+)
+
+// SmartPlaylistRules define which library tracks a smart playlist holds.
+type SmartPlaylistRules struct {
+	// Rules are the conditions on tracks. A playlist without rules holds the whole library.
+	Rules []SmartRule
+
+	// MatchAny selects tracks matching any rule instead of all of them
+	MatchAny bool
+
+	// SortBy orders the tracks before the limit is applied (library order if empty)
+	SortBy SmartSortField
+
+	// Descending reverses the sort order
+	Descending bool
+
+	// Limit is the largest number of tracks in the playlist (0 for no limit)
+	Limit int
+}
+
+// Album is a group of library tracks that share an album title and album artist.
+type Album struct {
+	// Title is the album name (empty for tracks without an album)
+	Title string
+
+	// Artist is the album artist (see MusicTrack.EffectiveAlbumArtist)
+	Artist string
+
+	// Compilation indicates that the album is a compilation of various artists
+	Compilation bool
+
+	// Tracks are the album tracks in disc and track order
+	Tracks []MusicTrack
+}
+
+// FolderNode is a folder of the library folder tree, with the library tracks
+// directly inside it. ZIP archives appear as folders holding their entries.
+type FolderNode struct {
+	// Path is the absolute path of the folder
+	Path string
+
+	// Name is the folder name (the full path for scan roots)
+	Name string
+
+	// Folders are the subfolders that hold tracks, sorted by name
+	Folders []*FolderNode
+
+	// Tracks are the tracks in the folder, sorted by file name
+	Tracks []MusicTrack
+}
+
+// AllTracks returns the tracks of the folder and its subfolders in tree order:
+// each subfolder in turn, then the folder's own tracks.
+func (n *FolderNode) AllTracks() []MusicTrack {
+	tracks := make([]MusicTrack, 0, n.TrackCount())
+	for _, folder := range n.Folders {
+		tracks = append(tracks, folder.AllTracks()...)
+	}
+	return append(tracks, n.Tracks...)
+}
+
+// TrackCount returns the number of tracks in the folder and its subfolders.
+func (n *FolderNode) TrackCount() int {
+	count := len(n.Tracks)
+	for _, folder := range n.Folders {
+		count += folder.TrackCount()
+	}
+	return count
+}
+
+// LyricsSource identifies where lyrics were loaded from.
+type LyricsSource string
+
+const (
+	// LyricsSourceSidecar is a .lrc file next to the audio file
+	LyricsSourceSidecar LyricsSource = "sidecar"
+
+	// LyricsSourceEmbedded is a lyrics tag in the audio file (USLT, SYLT, LYRICS, ©lyr)
+	LyricsSourceEmbedded LyricsSource = "embedded"
+)
+
+// LyricsLine is a single line of lyrics.
+type LyricsLine struct {
+	// Time is when the line starts (zero for unsynced lyrics)
+	Time time.Duration
+
+	// Text is the line text (may be empty for instrumental breaks)
+	Text string
+}
+
+// Lyrics holds the lyrics of a track.
+type Lyrics struct {
+	// Lines are the lyrics lines, in time order for synced lyrics
+	Lines []LyricsLine
+
+	// Synced indicates that the lines have timestamps
+	Synced bool
+
+	// Source is where the lyrics were loaded from
+	Source LyricsSource
+}
+
+// LineAt returns the index of the line being sung at position,
+// or -1 if the lyrics are not synced or position is before the first line.
+func (l *Lyrics) LineAt(position time.Duration) int {
+	if l == nil || !l.Synced {
+		return -1
+	}
+	// The first line that starts after position, minus one
+	return sort.Search(len(l.Lines), func(i int) bool {
+		return l.Lines[i].Time > position
+	}) - 1
 }
 
 // PlaybackState represents the current state of the music player.
@@ -112,8 +576,8 @@ type PlaybackState struct {
 	// IsMuted indicates if audio is muted
 	IsMuted bool
 
-	// IsLooping indicates if the current track should loop
-	IsLooping bool
+	// RepeatMode is what plays when the current track finishes
+	RepeatMode RepeatMode
 }
 
 // PlaybackStatus represents the current playback state.
@@ -149,13 +613,121 @@ func (s PlaybackStatus) String() string {
 	}
 }
 
+// RepeatMode is what plays when a track finishes.
+type RepeatMode string
+
+const (
+	// RepeatOff plays the queue once and stops at its end
+	RepeatOff RepeatMode = "off"
+
+	// RepeatOne restarts the current track when it finishes
+	RepeatOne RepeatMode = "one"
+
+	// RepeatAll plays the queue again from the start when it ends
+	RepeatAll RepeatMode = "all"
+)
+
+// IsValid returns true if the repeat mode is known.
+func (m RepeatMode) IsValid() bool {
+	return m == RepeatOff || m == RepeatOne || m == RepeatAll
+}
+
+// Next returns the mode that follows in the cycle off, all, one.
+func (m RepeatMode) Next() RepeatMode {
+	switch m {
+	case RepeatOff:
+		return RepeatAll
+	case RepeatAll:
+		return RepeatOne
+	default:
+		return RepeatOff
+	}
+}
+
+// ShuffleMode is how the playback queue is shuffled.
+type ShuffleMode string
+
+const (
+	// ShuffleOff keeps the queue in its original order
+	ShuffleOff ShuffleMode = ""
+
+	// ShuffleTracks shuffles individual tracks
+	ShuffleTracks ShuffleMode = "tracks"
+
+	// ShuffleAlbums shuffles whole albums, keeping the track order within each album
+	ShuffleAlbums ShuffleMode = "albums"
+)
+
+// IsValid returns true if the shuffle mode is known.
+func (m ShuffleMode) IsValid() bool {
+	return m == ShuffleOff || m == ShuffleTracks || m == ShuffleAlbums
+}
+
+// ShuffleState is the shuffle mode of the queue and the order it had before shuffling,
+// so that the shuffle can be undone.
+type ShuffleState struct {
+	// Mode is the current shuffle mode
+	Mode ShuffleMode
+
+	// OriginalOrder holds the file paths of the queued tracks before shuffling
+	OriginalOrder []string
+}
+
+// QueueSortField is a track property the queue can be sorted by.
+type QueueSortField string
+
+const (
+	QueueSortTitle       QueueSortField = "title"
+	QueueSortArtist      QueueSortField = "artist"
+	QueueSortAlbumArtist QueueSortField = "album_artist"
+	QueueSortAlbum       QueueSortField = "album"
+	QueueSortYear        QueueSortField = "year"
+	QueueSortDisc        QueueSortField = "disc"
+	QueueSortTrack       QueueSortField = "track"
+	QueueSortGenre       QueueSortField = "genre"
+	QueueSortDuration    QueueSortField = "duration"
+	QueueSortRating      QueueSortField = "rating"
+	QueueSortFormat      QueueSortField = "format"
+	QueueSortPath        QueueSortField = "path"
+)
+
+// SortDirection is whether a sort puts smaller or larger values first.
+type SortDirection string
+
+const (
+	// SortAscending puts smaller values first (A to Z, oldest first)
+	SortAscending SortDirection = "asc"
+
+	// SortDescending puts larger values first (Z to A, newest first)
+	SortDescending SortDirection = "desc"
+)
+
+// IsValid returns true if the sort direction is known.
+func (d SortDirection) IsValid() bool {
+	return d == SortAscending || d == SortDescending
+}
+
+// Reverse returns the opposite direction.
+func (d SortDirection) Reverse() SortDirection {
+	if d == SortDescending {
+		return SortAscending
+	}
+	return SortDescending
+}
+
+// QueueSortKey is one key of a multi-key queue sort.
+type QueueSortKey struct {
+	Field     QueueSortField
+	Direction SortDirection
+}
+
 // Preferences contain user preferences and settings.
 type Preferences struct {
 	// Volume is the saved volume level (0.0 to 1.0)
 	Volume float64
 
-	// LoopEnabled indicates if loop mode is enabled by default
-	LoopEnabled bool
+	// RepeatMode is the saved repeat mode
+	RepeatMode RepeatMode
 
 	// Theme is the UI theme (dark, light, system)
 	Theme string
@@ -163,6 +735,9 @@ type Preferences struct {
 	// ScanPaths are directories to scan for music
 	ScanPaths []string
 
+	// ScanOptions controls which files folder scans visit
+	ScanOptions ScanOptions
+
 	// LastQueueIndex is the last played track index
 	LastQueueIndex int
 
@@ -179,6 +754,66 @@ const (
 	InvalidTrackHandle TrackHandle = 0
 )
 
+// ScanOptions controls which files and folders a folder scan visits
+// and how missing tags of the scanned tracks are filled in.
+// Symbolic links are followed, and each folder is visited at most once.
+type ScanOptions struct {
+	// ExcludePatterns are .gitignore-style patterns of files and folders to skip,
+	// matched relative to the scanned folder. Folders can add their own
+	// patterns in a .gotuneignore file.
+	ExcludePatterns []string
+
+	// MaxDepth limits how many folder levels below the scanned folder are searched (0 for no limit)
+	MaxDepth int
+
+	// SkipHidden skips folders whose names start with a dot
+	SkipHidden bool
+
+	// TagPatterns describe how tags appear in file paths (e.g. "%artist%/%album%/%track% - %title%").
+	// Tags a scanned file does not have are inferred from the first matching pattern.
+	TagPatterns []string
+}
+
+// ScanFailureReason classifies why a file could not be read during a scan.
+type ScanFailureReason string
+
+const (
+	// ScanFailureUnsupported means the file format or codec is not supported.
+	ScanFailureUnsupported ScanFailureReason = "unsupported"
+
+	// ScanFailurePermission means the file or folder could not be opened.
+	ScanFailurePermission ScanFailureReason = "permission_denied"
+
+	// ScanFailureCorrupt means the file's header is invalid or its data is damaged.
+	ScanFailureCorrupt ScanFailureReason = "corrupt"
+
+	// ScanFailureNotFound means the file disappeared during the scan.
+	ScanFailureNotFound ScanFailureReason = "not_found"
+
+	// ScanFailureUnknown is used for any other error.
+	ScanFailureUnknown ScanFailureReason = "unknown"
+)
+
+// ScanFailure is a file or folder that could not be read during a scan.
+type ScanFailure struct {
+	Path    string
+	Reason  ScanFailureReason
+	Message string // Error message for the failure
+}
+
+// ScanReport summarizes the most recent scan and the files it could not read.
+type ScanReport struct {
+	// Path is the scanned folder, or empty for a scan of individual files
+	Path string
+
+	StartedAt   time.Time
+	CompletedAt time.Time
+
+	FilesScanned int
+	TracksFound  int
+	Failures     []ScanFailure
+}
+
 // ScanProgress represents the progress of a music library scan operation.
 type ScanProgress struct {
 	// CurrentFile is the file currently being scanned
@@ -206,3 +841,168 @@ func (p ScanProgress) Percentage() float64 {
 	}
 	return float64(p.FilesScanned) / float64(p.TotalFiles) * 100.0
 }
+
+// DefaultPlayThreshold is the fraction of a track that must be heard for it to count as played.
+const DefaultPlayThreshold = 0.5
+
+// MaxPlayThresholdTime is how long a track must be heard to count as played,
+// whatever its length, so long tracks and mixes are not counted as skips.
+const MaxPlayThresholdTime = 4 * time.Minute
+
+// TrackStats holds the listening statistics of a track.
+type TrackStats struct {
+	// TrackID is the stable ID of the track (see MusicTrack.StableID)
+	TrackID string
+
+	// FilePath is the path of the track when the stats were last updated
+	FilePath string
+
+	// PlayCount is how many times the track was heard past the play threshold
+	PlayCount int
+
+	// SkipCount is how many times the track was stopped or skipped before the threshold
+	SkipCount int
+
+	// AddedAt is when the track was first found in the library or played
+	AddedAt time.Time
+
+	// FirstPlayedAt and LastPlayedAt are zero if the track was never played
+	FirstPlayedAt time.Time
+	LastPlayedAt  time.Time
+}
+
+// ListeningSessionGap is the longest pause between two plays of the same listening session.
+const ListeningSessionGap = 30 * time.Minute
+
+// ListeningEntry is a play of a track in the listening history log.
+// Only what identifies and describes the track is kept, so that the log stays small.
+type ListeningEntry struct {
+	// TrackID is the stable ID of the track (see MusicTrack.StableID)
+	TrackID string
+
+	// FilePath, Title, Artist, Album and Duration are those of the track when it was played
+	FilePath string
+	Title    string
+	Artist   string
+	Album    string
+	Duration time.Duration
+
+	// StartedAt and EndedAt are when playback of the track started and ended
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// Played is how long the track was heard, not counting seeks and pauses
+	Played time.Duration
+
+	// Completed indicates that the track played to the end
+	Completed bool
+}
+
+// NewListeningEntry creates the log entry of a play of the track.
+// The play times are left for the caller to set.
+func NewListeningEntry(track MusicTrack) ListeningEntry {
+	return ListeningEntry{
+		TrackID:  track.StableID(),
+		FilePath: track.FilePath,
+		Title:    track.Title,
+		Artist:   track.Artist,
+		Album:    track.Album,
+		Duration: track.Duration,
+	}
+}
+
+// Track returns the played track, for adding it to the queue again.
+// It only has the tags kept in the log.
+func (e ListeningEntry) Track() MusicTrack {
+	return MusicTrack{
+		ID:         e.TrackID,
+		FilePath:   e.FilePath,
+		Title:      e.Title,
+		Artist:     e.Artist,
+		Album:      e.Album,
+		Duration:   e.Duration,
+		FileFormat: filepath.Ext(e.FilePath),
+	}
+}
+
+// Relocated returns the entry pointing at the new path of a moved file.
+func (e ListeningEntry) Relocated(newPath string) ListeningEntry {
+	e.FilePath = newPath
+	e.TrackID = MusicTrack{FilePath: newPath}.StableID()
+	return e
+}
+
+// ListeningSession is a run of plays with no pause longer than ListeningSessionGap.
+type ListeningSession struct {
+	// StartedAt and EndedAt are when the first play started and the last one ended
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// Entries are the plays of the session, oldest first
+	Entries []ListeningEntry
+}
+
+// Tracks returns the tracks played in the session in play order, each only once.
+func (s ListeningSession) Tracks() []MusicTrack {
+	seen := make(map[string]bool, len(s.Entries))
+	tracks := make([]MusicTrack, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		if seen[entry.FilePath] {
+			continue
+		}
+		seen[entry.FilePath] = true
+		tracks = append(tracks, entry.Track())
+	}
+	return tracks
+}
+
+// Charset is a legacy text encoding used to decode tags that are not stored as Unicode,
+// such as ID3v1 tags, ID3v2 frames marked as Latin-1 and tracker module names.
+type Charset string
+
+const (
+	// CharsetWindows1252 is Western European (a superset of Latin-1)
+	CharsetWindows1252 Charset = "windows-1252"
+
+	// CharsetWindows1250 is Central European
+	CharsetWindows1250 Charset = "windows-1250"
+
+	// CharsetWindows1251 is Cyrillic
+	CharsetWindows1251 Charset = "windows-1251"
+
+	// CharsetKOI8R is Cyrillic as used on older Unix systems
+	CharsetKOI8R Charset = "koi8-r"
+
+	// CharsetShiftJIS is Japanese
+	CharsetShiftJIS Charset = "shift_jis"
+
+	// CharsetGBK is Simplified Chinese
+	CharsetGBK Charset = "gbk"
+
+	// CharsetBig5 is Traditional Chinese
+	CharsetBig5 Charset = "big5"
+
+	// CharsetEUCKR is Korean
+	CharsetEUCKR Charset = "euc-kr"
+)
+
+// DefaultTagCharset is the fallback charset for legacy tags when detection is inconclusive.
+const DefaultTagCharset = CharsetWindows1252
+
+// Charsets returns the supported legacy charsets.
+func Charsets() []Charset {
+	return []Charset{
+		CharsetWindows1252, CharsetWindows1250, CharsetWindows1251, CharsetKOI8R,
+		CharsetShiftJIS, CharsetGBK, CharsetBig5, CharsetEUCKR,
+	}
+}
+
+// IsValid returns true if the charset is supported.
+func (c Charset) IsValid() bool {
+	for _, charset := range Charsets() {
+		if c == charset {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+// Package ports define interfaces for album artwork processing.
+package ports
+
+// ThumbnailCache produces resized copies of album artwork.
+// Thumbnails are cached by the content of the source image, so tracks that
+// share a cover (e.g. a whole album) share one cached thumbnail.
+//
+// Thread-safety: Implementations must be thread-safe.
+type ThumbnailCache interface {
+	// Thumbnail returns the image scaled to fit within size x size pixels,
+	// encoded as JPEG (or PNG for images with transparency).
+	// Images that already fit are returned unchanged.
+	//
+	// Returns an error if the image cannot be decoded.
+	Thumbnail(imageData []byte, size int) ([]byte, error)
+
+	// Clear removes all cached thumbnails.
+	Clear() error
+}
@@ -117,6 +117,12 @@ type AudioEngine interface {
 	// Returns a MusicTrack with populated metadata, or an error if extraction fails.
 	GetMetadata(filePath string) (*domain.MusicTrack, error)
 
+	// GetAlbumArt reads the artwork of a track from its file, for tracks whose
+	// metadata does not hold the artwork bytes (see domain.TrackMetadata.AlbumArt).
+	//
+	// Returns nil if the track has no artwork, or an error if the file cannot be read.
+	GetAlbumArt(track domain.MusicTrack) ([]byte, error)
+
 	// Visualization methods
 
 	// GetFFTData retrieves FFT frequency data for visualization.
@@ -127,6 +133,13 @@ type AudioEngine interface {
 	GetFFTData(handle domain.TrackHandle) ([]float32, error)
 }
 
+// TagCharsetDecoder is implemented by audio engines whose metadata extraction
+// decodes legacy (non-Unicode) tags. This is optional and not all implementations need to support it.
+type TagCharsetDecoder interface {
+	// SetTagCharset sets the charset used for legacy tags when detection is inconclusive.
+	SetTagCharset(charset domain.Charset)
+}
+
 // AudioEngineFactory is a function that creates an AudioEngine instance.
 // This allows for dependency injection of different engine implementations.
 type AudioEngineFactory func(config *AudioEngineConfig) (AudioEngine, error)
@@ -0,0 +1,18 @@
+// Package ports define interfaces for reading song lyrics.
+package ports
+
+import (
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// LyricsReader reads the lyrics stored with an audio file.
+//
+// Thread-safety: Implementations must be thread-safe.
+type LyricsReader interface {
+	// ReadLyrics returns the lyrics of the audio file.
+	// A sidecar .lrc file takes precedence over lyrics embedded in the tags.
+	// Synced lyrics are returned with Synced set and lines in time order.
+	//
+	// Returns domain.ErrLyricsNotFound if the file has no lyrics.
+	ReadLyrics(filePath string) (*domain.Lyrics, error)
+}
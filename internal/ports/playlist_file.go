@@ -0,0 +1,27 @@
+// Package ports define interfaces for reading and writing playlist files.
+package ports
+
+import (
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// PlaylistFileFormat reads and writes one playlist file format, such as M3U.
+//
+// Thread-safety: Implementations must be thread-safe.
+type PlaylistFileFormat interface {
+	// Extensions returns the lowercase file extensions of the format, e.g. ".m3u".
+	Extensions() []string
+
+	// Read parses a playlist file.
+	// Relative entry paths are resolved against the folder of the playlist file;
+	// locations that are not local paths (e.g. URLs) are returned as written.
+	//
+	// Returns the entries in file order, or an error if the file cannot be read.
+	Read(filePath string) ([]domain.PlaylistFileEntry, error)
+
+	// Write saves entries to a playlist file, replacing it if it exists.
+	// relative: write paths relative to the folder of the playlist file where possible
+	//
+	// Returns an error if writing fails.
+	Write(filePath string, entries []domain.PlaylistFileEntry, relative bool) error
+}
@@ -40,7 +40,8 @@ type PlaylistRepository interface {
 	Exists(id string) bool
 }
 
-// HistoryRepository handles the persistence of playback history (queue and position).
+// HistoryRepository handles the persistence of playback history: the queue and
+// position, and the log of played tracks.
 // This replaces the hardcoded file paths in the original implementation.
 //
 // Thread-safety: Implementations must be thread-safe.
@@ -57,6 +58,17 @@ type HistoryRepository interface {
 	// Returns the queue or an error if loading fails.
 	LoadQueue() ([]domain.MusicTrack, error)
 
+	// SaveUpNext persists the Up Next tracks, which play before the rest of the queue.
+	//
+	// Returns an error if saving fails.
+	SaveUpNext(tracks []domain.MusicTrack) error
+
+	// LoadUpNext retrieves the last saved Up Next tracks.
+	// If none were saved, returns an empty slice (not an error).
+	//
+	// Returns the tracks or an error if loading fails.
+	LoadUpNext() ([]domain.MusicTrack, error)
+
 	// SaveCurrentIndex persists the current track index in the queue.
 	//
 	// Returns an error if saving fails.
@@ -68,12 +80,128 @@ type HistoryRepository interface {
 	// Returns the index or an error if loading fails.
 	LoadCurrentIndex() (int, error)
 
+	// SaveShuffleState persists the shuffle mode and the original queue order.
+	//
+	// Returns an error if saving fails.
+	SaveShuffleState(state domain.ShuffleState) error
+
+	// LoadShuffleState retrieves the last saved shuffle state.
+	// If no state was saved, returns a state with ShuffleOff (not an error).
+	//
+	// Returns the state or an error if loading fails.
+	LoadShuffleState() (domain.ShuffleState, error)
+
+	// AppendListen adds a play to the end of the listening history log.
+	// Implementations may drop the oldest entries to bound the log size.
+	//
+	// Returns an error if saving fails.
+	AppendListen(entry domain.ListeningEntry) error
+
+	// LoadListens retrieves the listening history log, oldest first.
+	// If nothing was played, returns an empty slice (not an error).
+	//
+	// Returns the entries or an error if loading fails.
+	LoadListens() ([]domain.ListeningEntry, error)
+
+	// RelocateListens points the entries of the listening history log at the new
+	// paths of moved files. paths maps old file paths to new ones; the TrackID of
+	// a relocated entry becomes the stable ID of its new path.
+	//
+	// Returns an error if loading or saving fails.
+	RelocateListens(paths map[string]string) error
+
+	// ClearListens removes all entries from the listening history log.
+	//
+	// Returns an error if clearing fails.
+	ClearListens() error
+
 	// Clear removes all saved history data.
 	//
 	// Returns an error if clearing fails.
 	Clear() error
 }
 
+// LibraryRepository handles the persistence of the music library.
+// The library is the set of tracks discovered by folder and file scans,
+// keyed by file path.
+//
+// Thread-safety: Implementations must be thread-safe.
+type LibraryRepository interface {
+	// SaveTracks adds tracks to the library.
+	// A track whose FilePath is already in the library replaces the stored entry.
+	//
+	// Returns an error if saving fails.
+	SaveTracks(tracks []domain.MusicTrack) error
+
+	// ReplaceAll replaces every track in the library with tracks, in one step.
+	// If saving fails, the library is left as it was.
+	//
+	// Returns an error if saving fails.
+	ReplaceAll(tracks []domain.MusicTrack) error
+
+	// LoadAll retrieves every track in the library.
+	// If the library is empty, returns an empty slice (not an error).
+	//
+	// Returns the tracks or an error if loading fails.
+	LoadAll() ([]domain.MusicTrack, error)
+
+	// Clear removes all tracks from the library.
+	//
+	// Returns an error if clearing fails.
+	Clear() error
+}
+
+// ScanReportRepository handles the persistence of the report of the most recent scan.
+//
+// Thread-safety: Implementations must be thread-safe.
+type ScanReportRepository interface {
+	// SaveReport persists a scan report, replacing the previous one.
+	//
+	// Returns an error if saving fails.
+	SaveReport(report *domain.ScanReport) error
+
+	// LoadReport retrieves the saved scan report.
+	// If no report was saved, returns (nil, nil).
+	//
+	// Returns the report or an error if loading fails.
+	LoadReport() (*domain.ScanReport, error)
+}
+
+// StatsRepository handles the persistence of listening statistics,
+// keyed by the stable track ID (see domain.MusicTrack.StableID).
+//
+// Thread-safety: Implementations must be thread-safe.
+type StatsRepository interface {
+	// SaveStats persists track statistics.
+	// Stats with a TrackID that is already stored replace the stored entry.
+	//
+	// Returns an error if saving fails.
+	SaveStats(stats []domain.TrackStats) error
+
+	// LoadStats retrieves the statistics of a track.
+	// If the track has no statistics, returns (nil, nil).
+	//
+	// Returns the stats or an error if loading fails.
+	LoadStats(trackID string) (*domain.TrackStats, error)
+
+	// LoadAll retrieves the statistics of every track.
+	// If there are none, returns an empty slice (not an error).
+	//
+	// Returns the stats or an error if loading fails.
+	LoadAll() ([]domain.TrackStats, error)
+
+	// RemoveStats removes the statistics of the given tracks.
+	// Track IDs without statistics are ignored.
+	//
+	// Returns an error if saving fails.
+	RemoveStats(trackIDs []string) error
+
+	// Clear removes all statistics.
+	//
+	// Returns an error if clearing fails.
+	Clear() error
+}
+
 // PreferencesRepository handles the persistence of user preferences.
 // This abstracts the Fyne preferences storage.
 //
@@ -92,18 +220,18 @@ type PreferencesRepository interface {
 	// Returns the volume or an error if loading fails.
 	LoadVolume() (float64, error)
 
-	// Loop mode preferences
+	// Repeat mode preferences
 
-	// SaveLoopMode persists in the loop mode state.
+	// SaveRepeatMode persists the repeat mode.
 	//
 	// Returns an error if saving fails.
-	SaveLoopMode(enabled bool) error
+	SaveRepeatMode(mode domain.RepeatMode) error
 
-	// LoadLoopMode retrieves the saved loop mode state.
-	// If no loop mode was saved, returns false as default.
+	// LoadRepeatMode retrieves the saved repeat mode.
+	// If no repeat mode was saved, returns domain.RepeatOff as default.
 	//
-	// Returns the loop mode, or an error if loading fails.
-	LoadLoopMode() (bool, error)
+	// Returns the repeat mode, or an error if loading fails.
+	LoadRepeatMode() (domain.RepeatMode, error)
 
 	// Theme preferences
 
@@ -131,6 +259,44 @@ type PreferencesRepository interface {
 	// Returns the paths or an error if loading fails.
 	LoadScanPaths() ([]string, error)
 
+	// SaveScanOptions persists the exclude patterns and limits of folder scans.
+	//
+	// Returns an error if saving fails.
+	SaveScanOptions(options domain.ScanOptions) error
+
+	// LoadScanOptions retrieves the saved scan options.
+	// If no options were saved, returns the zero value (not an error).
+	//
+	// Returns the options or an error if loading fails.
+	LoadScanOptions() (domain.ScanOptions, error)
+
+	// Statistics preferences
+
+	// SavePlayThreshold persists the fraction of a track that must be heard
+	// for it to count as played.
+	//
+	// Returns an error if saving fails.
+	SavePlayThreshold(fraction float64) error
+
+	// LoadPlayThreshold retrieves the saved play threshold.
+	// If no threshold was saved, returns domain.DefaultPlayThreshold as default.
+	//
+	// Returns the threshold or an error if loading fails.
+	LoadPlayThreshold() (float64, error)
+
+	// Metadata preferences
+
+	// SaveTagCharset persists the fallback charset for legacy tags.
+	//
+	// Returns an error if saving fails.
+	SaveTagCharset(charset domain.Charset) error
+
+	// LoadTagCharset retrieves the saved fallback charset for legacy tags.
+	// If no charset was saved, returns domain.DefaultTagCharset as default.
+	//
+	// Returns the charset or an error if loading fails.
+	LoadTagCharset() (domain.Charset, error)
+
 	// Utility methods
 
 	// Clear removes all saved preferences.
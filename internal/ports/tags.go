@@ -0,0 +1,24 @@
+// Package ports define interfaces for writing audio file tags.
+package ports
+
+import (
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// TagWriter writes metadata tags back to audio files.
+// Reading tags is handled by AudioEngine.GetMetadata; this is the write side.
+//
+// Thread-safety: Implementations must be thread-safe.
+type TagWriter interface {
+	// SupportsFormat returns true if tags can be written for the file extension.
+	// The extension includes the leading dot (e.g., ".mp3").
+	SupportsFormat(ext string) bool
+
+	// WriteTags applies the edit to the tags stored in the file.
+	// Fields left nil in the edit, and any tags the edit does not cover,
+	// are preserved. The file is replaced atomically.
+	//
+	// Returns domain.ErrTagsNotWritable for unsupported formats,
+	// or a *domain.TagError if the file cannot be parsed or written.
+	WriteTags(filePath string, edit domain.TagEdit) error
+}
@@ -58,9 +58,9 @@ type UI interface {
 	// muted: true if audio is muted, false otherwise
 	SetMuteState(muted bool)
 
-	// SetLoopState updates the loop button state.
-	// enabled: true if loop mode is enabled, false otherwise
-	SetLoopState(enabled bool)
+	// SetRepeatMode updates the repeat button state.
+	// mode: whether nothing, the current track or the whole queue repeats
+	SetRepeatMode(mode domain.RepeatMode)
 
 	// Playlist update methods
 
@@ -73,6 +73,11 @@ type UI interface {
 	// index: The index of the track to highlight
 	UpdatePlaylistSelection(index int)
 
+	// SetUndoActions updates the undo and redo menu items.
+	// undoAction, redoAction: the queue edits that would be undone and redone,
+	// e.g. "Remove Track" (empty if there is none)
+	SetUndoActions(undoAction, redoAction string)
+
 	// UpdatePlaylistWindow refreshes the playlist window with new data.
 	// This is called when tracks are added or the queue changes.
 	UpdatePlaylistWindow(tracks []domain.MusicTrack)
@@ -0,0 +1,60 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// compareAlbumOrder orders tracks as they appear in a library sorted by album:
+// album artist, album, disc, track number, title, then path.
+// Sort tags are used where the files have them.
+func compareAlbumOrder(a, b domain.MusicTrack) int {
+	aMeta, bMeta := metadataOf(a), metadataOf(b)
+	return cmp.Or(
+		cmp.Compare(a.SortAlbumArtist(), b.SortAlbumArtist()),
+		cmp.Compare(a.SortAlbum(), b.SortAlbum()),
+		cmp.Compare(aMeta.DiscNumber, bMeta.DiscNumber),
+		cmp.Compare(aMeta.TrackNumber, bMeta.TrackNumber),
+		cmp.Compare(a.SortTitle(), b.SortTitle()),
+		cmp.Compare(a.FilePath, b.FilePath),
+	)
+}
+
+// SortTracksByAlbum sorts tracks in album order, keeping the tracks of each album
+// (including compilations by various artists) together.
+func SortTracksByAlbum(tracks []domain.MusicTrack) {
+	slices.SortStableFunc(tracks, compareAlbumOrder)
+}
+
+// GroupByAlbum groups tracks by album and album artist.
+// Albums are returned in album order, and tracks without an album are grouped
+// by artist under an empty title.
+func GroupByAlbum(tracks []domain.MusicTrack) []domain.Album {
+	sorted := slices.Clone(tracks)
+	SortTracksByAlbum(sorted)
+
+	albums := make([]domain.Album, 0)
+	index := make(map[string]int)
+	for _, track := range sorted {
+		key := track.AlbumKey()
+		if key == "" {
+			key = "\x00" + track.SortAlbumArtist()
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(albums)
+			index[key] = i
+			albums = append(albums, domain.Album{
+				Title:  track.Album,
+				Artist: track.EffectiveAlbumArtist(),
+			})
+		}
+		albums[i].Compilation = albums[i].Compilation || track.Compilation
+		albums[i].Tracks = append(albums[i].Tracks, track)
+	}
+	return albums
+}
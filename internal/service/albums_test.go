@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// albumTestTrack creates a track with album position metadata.
+func albumTestTrack(title, artist, album string, disc, track int) domain.MusicTrack {
+	return domain.MusicTrack{
+		FilePath: "/music/" + title + ".mp3",
+		Title:    title,
+		Artist:   artist,
+		Album:    album,
+		Metadata: &domain.TrackMetadata{DiscNumber: disc, TrackNumber: track},
+	}
+}
+
+func TestSortTracksByAlbum(t *testing.T) {
+	beatles := albumTestTrack("Help!", "The Beatles", "Help!", 1, 1)
+	beatles.ArtistSort = "Beatles, The"
+	compilation1 := albumTestTrack("Hit B", "Zed", "Hits", 1, 2)
+	compilation1.Compilation = true
+	compilation2 := albumTestTrack("Hit A", "Abba", "Hits", 1, 1)
+	compilation2.Compilation = true
+	abba2 := albumTestTrack("Second", "ABBA", "Arrival", 2, 1)
+	abba1 := albumTestTrack("First", "ABBA", "Arrival", 1, 5)
+
+	tracks := []domain.MusicTrack{compilation1, beatles, abba2, compilation2, abba1}
+	SortTracksByAlbum(tracks)
+
+	titles := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		titles = append(titles, track.Title)
+	}
+	// "Beatles, The" sorts under B, and the compilation under "Various Artists"
+	assert.Equal(t, []string{"First", "Second", "Help!", "Hit A", "Hit B"}, titles)
+}
+
+func TestGroupByAlbum(t *testing.T) {
+	hit1 := albumTestTrack("Hit 1", "Band A", "Now 1", 1, 1)
+	hit1.Compilation = true
+	hit2 := albumTestTrack("Hit 2", "Band B", "Now 1", 1, 2)
+	hit2.Compilation = true
+	duet := albumTestTrack("Duet", "Singer feat. Guest", "Songs", 1, 2)
+	duet.AlbumArtist = "Singer"
+	solo := albumTestTrack("Solo", "Singer", "Songs", 1, 1)
+	loose := albumTestTrack("Demo", "Singer", "", 0, 0)
+
+	albums := GroupByAlbum([]domain.MusicTrack{hit2, duet, loose, hit1, solo})
+	require.Len(t, albums, 3)
+
+	assert.Equal(t, "", albums[0].Title, "Tracks without an album come first for their artist")
+	assert.Equal(t, "Singer", albums[0].Artist)
+	assert.Len(t, albums[0].Tracks, 1)
+
+	assert.Equal(t, "Songs", albums[1].Title)
+	assert.Equal(t, "Singer", albums[1].Artist)
+	require.Len(t, albums[1].Tracks, 2)
+	assert.Equal(t, "Solo", albums[1].Tracks[0].Title)
+	assert.False(t, albums[1].Compilation)
+
+	assert.Equal(t, "Now 1", albums[2].Title)
+	assert.Equal(t, domain.VariousArtists, albums[2].Artist)
+	assert.True(t, albums[2].Compilation)
+	require.Len(t, albums[2].Tracks, 2)
+	assert.Equal(t, "Hit 1", albums[2].Tracks[0].Title)
+}
@@ -0,0 +1,116 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// BuildFolderTree arranges library tracks into folder trees, one per root.
+// Roots inside another root are merged into it, and tracks outside every
+// root are left out. Only folders that hold tracks (directly or below) are
+// included, but every root is returned even if it is empty.
+func BuildFolderTree(roots []string, tracks []domain.MusicTrack) []*domain.FolderNode {
+	cleaned := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if root != "" {
+			cleaned = append(cleaned, filepath.Clean(root))
+		}
+	}
+	// Shorter paths first, so nested roots find their parent root already kept
+	sort.Slice(cleaned, func(i, j int) bool { return len(cleaned[i]) < len(cleaned[j]) })
+
+	nodes := make(map[string]*domain.FolderNode)
+	result := make([]*domain.FolderNode, 0, len(cleaned))
+	for _, root := range cleaned {
+		if findRoot(result, root) != nil {
+			continue
+		}
+		node := &domain.FolderNode{Path: root, Name: root}
+		nodes[root] = node
+		result = append(result, node)
+	}
+
+	for _, track := range tracks {
+		parent := trackFolder(track.FilePath)
+		if findRoot(result, parent) == nil {
+			continue
+		}
+		node := folderNode(nodes, parent)
+		node.Tracks = append(node.Tracks, track)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Path) < strings.ToLower(result[j].Path)
+	})
+	for _, root := range result {
+		sortFolderNode(root)
+	}
+	return result
+}
+
+// trackFolder returns the folder that holds a track in the tree.
+// Entries of a ZIP archive are held by the archive.
+func trackFolder(path string) string {
+	if archivePath, _, ok := domain.SplitArchivePath(path); ok {
+		return filepath.Clean(archivePath)
+	}
+	return filepath.Dir(filepath.Clean(path))
+}
+
+// findRoot returns the root that contains path, or nil if there is none.
+func findRoot(roots []*domain.FolderNode, path string) *domain.FolderNode {
+	for _, root := range roots {
+		if isWithinFolder(path, root.Path) {
+			return root
+		}
+	}
+	return nil
+}
+
+// isWithinFolder reports whether path is folder or inside it.
+func isWithinFolder(path, folder string) bool {
+	if path == folder {
+		return true
+	}
+	if !strings.HasSuffix(folder, string(filepath.Separator)) {
+		folder += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, folder)
+}
+
+// folderNode returns the node of a folder inside a root, creating it and its parents as needed.
+func folderNode(nodes map[string]*domain.FolderNode, path string) *domain.FolderNode {
+	if node, ok := nodes[path]; ok {
+		return node
+	}
+	parent := folderNode(nodes, filepath.Dir(path))
+	node := &domain.FolderNode{Path: path, Name: filepath.Base(path)}
+	parent.Folders = append(parent.Folders, node)
+	nodes[path] = node
+	return node
+}
+
+// sortFolderNode sorts the subfolders and tracks of a folder tree by name.
+func sortFolderNode(node *domain.FolderNode) {
+	sort.SliceStable(node.Folders, func(i, j int) bool {
+		return strings.ToLower(node.Folders[i].Name) < strings.ToLower(node.Folders[j].Name)
+	})
+	sort.SliceStable(node.Tracks, func(i, j int) bool {
+		return strings.ToLower(trackFileName(node.Tracks[i])) < strings.ToLower(trackFileName(node.Tracks[j]))
+	})
+	for _, folder := range node.Folders {
+		sortFolderNode(folder)
+	}
+}
+
+// trackFileName returns the file name of a track, or its entry name inside an archive.
+func trackFileName(track domain.MusicTrack) string {
+	if _, entryName, ok := domain.SplitArchivePath(track.FilePath); ok {
+		return entryName
+	}
+	return filepath.Base(track.FilePath)
+}
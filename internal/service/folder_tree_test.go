@@ -0,0 +1,118 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// folderTestTracks creates tracks for the given slash-separated paths.
+func folderTestTracks(paths ...string) []domain.MusicTrack {
+	tracks := make([]domain.MusicTrack, len(paths))
+	for i, path := range paths {
+		tracks[i] = domain.MusicTrack{FilePath: filepath.FromSlash(path)}
+	}
+	return tracks
+}
+
+// folderNames returns the names of the subfolders of a node.
+func folderNames(node *domain.FolderNode) []string {
+	names := make([]string, len(node.Folders))
+	for i, folder := range node.Folders {
+		names[i] = folder.Name
+	}
+	return names
+}
+
+func TestBuildFolderTree(t *testing.T) {
+	tracks := folderTestTracks(
+		"/music/Rock/b.mp3",
+		"/music/Rock/A.mp3",
+		"/music/Rock/Live/x.mp3",
+		"/music/jazz/Kind of Blue/so what.flac",
+		"/music/root.mp3",
+		"/elsewhere/c.mp3",
+	)
+
+	roots := BuildFolderTree([]string{filepath.FromSlash("/music/")}, tracks)
+	require.Len(t, roots, 1)
+	root := roots[0]
+	assert.Equal(t, filepath.FromSlash("/music"), root.Path)
+	assert.Equal(t, 5, root.TrackCount(), "Tracks outside the roots are left out")
+
+	// Folders and tracks are sorted by name, ignoring case
+	assert.Equal(t, []string{"jazz", "Rock"}, folderNames(root))
+	rock := root.Folders[1]
+	assert.Equal(t, filepath.FromSlash("/music/Rock"), rock.Path)
+	assert.Equal(t, []string{"Live"}, folderNames(rock))
+	assert.Equal(t, trackPaths(folderTestTracks("/music/Rock/A.mp3", "/music/Rock/b.mp3")), trackPaths(rock.Tracks))
+
+	// Intermediate folders without tracks are created
+	jazz := root.Folders[0]
+	assert.Empty(t, jazz.Tracks)
+	assert.Equal(t, []string{"Kind of Blue"}, folderNames(jazz))
+}
+
+func TestBuildFolderTree_NestedAndEmptyRoots(t *testing.T) {
+	tracks := folderTestTracks("/music/rock/a.mp3")
+
+	roots := BuildFolderTree([]string{
+		filepath.FromSlash("/music/rock"),
+		filepath.FromSlash("/music"),
+		filepath.FromSlash("/podcasts"),
+		"",
+	}, tracks)
+
+	require.Len(t, roots, 2, "Nested roots are merged into their parent")
+	assert.Equal(t, filepath.FromSlash("/music"), roots[0].Path)
+	assert.Equal(t, 1, roots[0].TrackCount())
+	assert.Equal(t, filepath.FromSlash("/podcasts"), roots[1].Path)
+	assert.Equal(t, 0, roots[1].TrackCount())
+}
+
+func TestBuildFolderTree_Archives(t *testing.T) {
+	archive := filepath.FromSlash("/music/mods/pack.zip")
+	tracks := []domain.MusicTrack{
+		{FilePath: domain.ArchiveEntryPath(archive, "tune2.xm")},
+		{FilePath: domain.ArchiveEntryPath(archive, "tune1.mod")},
+	}
+
+	roots := BuildFolderTree([]string{filepath.FromSlash("/music")}, tracks)
+	require.Len(t, roots, 1)
+	mods := roots[0].Folders[0]
+	require.Len(t, mods.Folders, 1)
+
+	pack := mods.Folders[0]
+	assert.Equal(t, "pack.zip", pack.Name)
+	assert.Equal(t, []string{tracks[1].FilePath, tracks[0].FilePath}, trackPaths(pack.Tracks))
+}
+
+func TestFolderNode_AllTracks(t *testing.T) {
+	tracks := folderTestTracks("/music/a/1.mp3", "/music/b/2.mp3", "/music/3.mp3", "/music/a/deep/4.mp3")
+
+	roots := BuildFolderTree([]string{filepath.FromSlash("/music")}, tracks)
+	require.Len(t, roots, 1)
+
+	// Subfolders first, in tree order, then the folder's own tracks
+	want := trackPaths(folderTestTracks("/music/a/deep/4.mp3", "/music/a/1.mp3", "/music/b/2.mp3", "/music/3.mp3"))
+	assert.Equal(t, want, trackPaths(roots[0].AllTracks()))
+	assert.Equal(t, trackPaths(folderTestTracks("/music/a/deep/4.mp3", "/music/a/1.mp3")), trackPaths(roots[0].Folders[0].AllTracks()))
+}
+
+func TestLibraryService_GetFolderTree(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	root := createWalkerTestTree(t, "album/a.mp3", "b.mp3")
+	_, err := service.ScanFolder(root)
+	require.NoError(t, err)
+
+	tree, err := service.GetFolderTree([]string{root})
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Equal(t, 2, tree[0].TrackCount())
+	assert.Equal(t, []string{"album"}, folderNames(tree[0]))
+}
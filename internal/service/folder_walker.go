@@ -0,0 +1,281 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// ignoreFileName is the per-folder exclude file. It holds one .gitignore-style
+// pattern per line, matched relative to the folder it is in.
+const ignoreFileName = ".gotuneignore"
+
+// maxIgnoreFileSize is the largest exclude file that is read.
+const maxIgnoreFileSize = 64 * 1024
+
+// ignoreRule is a compiled .gitignore-style pattern.
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool // "!pattern" re-includes paths excluded by earlier rules
+	dirOnly bool // "pattern/" only matches folders
+}
+
+// ignoreRules are the rules defined for a folder, matched relative to base.
+type ignoreRules struct {
+	base  string
+	rules []ignoreRule
+}
+
+// compileIgnorePattern compiles one line of .gitignore-style patterns.
+// Returns false for blank lines and comments.
+//
+// As in .gitignore, a pattern containing a slash (other than a trailing one)
+// is anchored to the base folder; otherwise it matches a name at any depth.
+// "*" and "?" do not match slashes, "**" matches any number of folders, and
+// "[...]" matches a character class.
+func compileIgnorePattern(line string) (ignoreRule, bool, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false, nil
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) && len(line) > 1 && (line[1] == '!' || line[1] == '#') {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false, nil
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case '*':
+			if i+1 < len(line) && line[i+1] == '*' {
+				if i+2 < len(line) && line[i+2] == '/' {
+					re.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					re.WriteString(".*")
+					i++
+				}
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				re.WriteString(regexp.QuoteMeta(line[i : i+1]))
+			}
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+
+	pattern, err := regexp.Compile(re.String())
+	if err != nil {
+		return ignoreRule{}, false, err
+	}
+	rule.pattern = pattern
+	return rule, true, nil
+}
+
+// parseIgnorePatterns compiles patterns for the base folder, skipping invalid ones.
+func parseIgnorePatterns(base string, lines []string) ignoreRules {
+	rules := ignoreRules{base: base}
+	for _, line := range lines {
+		if rule, ok, err := compileIgnorePattern(line); err == nil && ok {
+			rules.rules = append(rules.rules, rule)
+		}
+	}
+	return rules
+}
+
+// readIgnoreFile reads the .gotuneignore file of a folder.
+// Returns false if the folder has none.
+func readIgnoreFile(dir string) (ignoreRules, bool) {
+	path := filepath.Join(dir, ignoreFileName)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxIgnoreFileSize {
+		return ignoreRules{}, false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ignoreRules{}, false
+	}
+	defer file.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return parseIgnorePatterns(dir, lines), true
+}
+
+// isExcluded reports whether path is excluded by the rules.
+// Rules are applied in order, so later rules (and deeper folders) win.
+func isExcluded(path string, isDir bool, sets []ignoreRules) bool {
+	excluded := false
+	for _, set := range sets {
+		rel, err := filepath.Rel(set.base, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		for _, rule := range set.rules {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if rule.pattern.MatchString(rel) {
+				excluded = !rule.negate
+			}
+		}
+	}
+	return excluded
+}
+
+// folderWalker walks a folder tree for a scan, applying the scan options.
+// Unlike filepath.Walk it follows symbolic links to folders, and visits each
+// real folder at most once, so link cycles (common on NAS shares) cannot loop.
+type folderWalker struct {
+	ctx     context.Context
+	logger  *slog.Logger
+	options domain.ScanOptions
+	visited map[string]bool // Real paths of visited folders
+	visit   func(path string)
+	failed  func(path string, err error)
+}
+
+// walkFolder calls visit for every file under root that is not excluded.
+// Folders that cannot be read are skipped and passed to failed, which may be nil.
+// Returns context.Canceled if ctx is cancelled.
+func walkFolder(
+	ctx context.Context,
+	logger *slog.Logger,
+	root string,
+	options domain.ScanOptions,
+	visit func(path string),
+	failed func(path string, err error),
+) error {
+	if failed == nil {
+		failed = func(string, error) {}
+	}
+	walker := &folderWalker{
+		ctx:     ctx,
+		logger:  logger,
+		options: options,
+		visited: make(map[string]bool),
+		visit:   visit,
+		failed:  failed,
+	}
+	return walker.walkDir(root, 0, []ignoreRules{parseIgnorePatterns(root, options.ExcludePatterns)})
+}
+
+// walkDir walks one folder at the given depth below the root.
+func (w *folderWalker) walkDir(dir string, depth int, rules []ignoreRules) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	realPath, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		w.failed(dir, err)
+		return nil
+	}
+	if w.visited[realPath] {
+		w.logger.Debug("skipping folder visited before",
+			slog.String("path", dir),
+			slog.String("target", realPath))
+		return nil
+	}
+	w.visited[realPath] = true
+
+	if local, ok := readIgnoreFile(dir); ok {
+		// Copy, so sibling folders do not see this folder's rules
+		rules = append(rules[:len(rules):len(rules)], local)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.failed(dir, err)
+		return nil
+	}
+
+	for _, entry := range entries {
+		if err := w.ctx.Err(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		mode := entry.Type()
+		if mode&fs.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue // Broken link
+			}
+			mode = info.Mode().Type()
+		}
+
+		if mode.IsDir() {
+			if w.options.SkipHidden && strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			if w.options.MaxDepth > 0 && depth >= w.options.MaxDepth {
+				continue
+			}
+			if isExcluded(path, true, rules) {
+				continue
+			}
+			if err := w.walkDir(path, depth+1, rules); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if mode.IsRegular() && !isExcluded(path, false, rules) {
+			w.visit(path)
+		}
+	}
+
+	return nil
+}
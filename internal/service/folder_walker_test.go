@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// createWalkerTestTree creates the given files (empty) and folders (ending in "/") under a new directory.
+func createWalkerTestTree(t *testing.T, paths ...string) string {
+	root := t.TempDir()
+	for _, path := range paths {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if path[len(path)-1] == '/' {
+			require.NoError(t, os.MkdirAll(full, 0o755))
+			continue
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, nil, 0o644))
+	}
+	return root
+}
+
+// walkTestFolder returns the files visited under root, relative to root with forward slashes.
+func walkTestFolder(t *testing.T, root string, options domain.ScanOptions) []string {
+	files := make([]string, 0)
+	err := walkFolder(context.Background(), libTestLogger(), root, options, func(path string) {
+		rel, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+		files = append(files, filepath.ToSlash(rel))
+	}, nil)
+	require.NoError(t, err)
+	return files
+}
+
+func TestCompileIgnorePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.tmp", "a.tmp", false, true},
+		{"*.tmp", "deep/dir/a.tmp", false, true},
+		{"*.tmp", "a.tmp.mp3", false, false},
+		{"Samples/", "Samples", true, true},
+		{"Samples/", "Samples", false, false},
+		{"Samples/", "x/Samples", true, true},
+		{"/top.mp3", "top.mp3", false, true},
+		{"/top.mp3", "sub/top.mp3", false, false},
+		{"live/*.mp3", "live/a.mp3", false, true},
+		{"live/*.mp3", "x/live/a.mp3", false, false},
+		{"live/*.mp3", "live/sub/a.mp3", false, false},
+		{"**/demo", "a/b/demo", true, true},
+		{"**/demo", "demo", true, true},
+		{"a/**/b.xm", "a/b.xm", false, true},
+		{"a/**/b.xm", "a/x/y/b.xm", false, true},
+		{"track?.mod", "track1.mod", false, true},
+		{"track?.mod", "track10.mod", false, false},
+		{"[ab]*.it", "b-side.it", false, true},
+		{"[!ab]*.it", "b-side.it", false, false},
+		{`\#hash.mp3`, "#hash.mp3", false, true},
+		{"song (1).mp3", "song (1).mp3", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			rules := parseIgnorePatterns("/music", []string{tt.pattern})
+			require.Len(t, rules.rules, 1)
+			got := isExcluded(filepath.Join("/music", filepath.FromSlash(tt.path)), tt.isDir, []ignoreRules{rules})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompileIgnorePattern_SkipsBlankAndComments(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		_, ok, err := compileIgnorePattern(line)
+		assert.NoError(t, err)
+		assert.False(t, ok, line)
+	}
+
+	_, _, err := compileIgnorePattern("[z-a]")
+	assert.Error(t, err)
+}
+
+func TestIsExcluded_Negation(t *testing.T) {
+	rules := parseIgnorePatterns("/music", []string{"*.wav", "!keep.wav"})
+
+	assert.True(t, isExcluded("/music/drop.wav", false, []ignoreRules{rules}))
+	assert.False(t, isExcluded("/music/keep.wav", false, []ignoreRules{rules}))
+}
+
+func TestWalkFolder_ExcludePatterns(t *testing.T) {
+	root := createWalkerTestTree(t,
+		"a.mp3", "b.tmp", "Samples/kick.wav", "album/c.mp3", "album/Samples/snare.wav")
+
+	files := walkTestFolder(t, root, domain.ScanOptions{ExcludePatterns: []string{"*.tmp", "Samples/"}})
+	assert.Equal(t, []string{"a.mp3", "album/c.mp3"}, files)
+}
+
+func TestWalkFolder_IgnoreFile(t *testing.T) {
+	root := createWalkerTestTree(t,
+		"a.mp3", "album/b.mp3", "album/demo.mp3", "album/disc2/demo.mp3", "other/demo.mp3")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "album", ignoreFileName),
+		[]byte("# demos\ndemo.mp3\n"), 0o644))
+
+	files := walkTestFolder(t, root, domain.ScanOptions{})
+
+	// The .gotuneignore applies to its folder and below, not to siblings
+	assert.Equal(t, []string{"a.mp3", "album/.gotuneignore", "album/b.mp3", "other/demo.mp3"}, files)
+}
+
+func TestWalkFolder_IgnoreFileOverridesGlobal(t *testing.T) {
+	root := createWalkerTestTree(t, "a.wav", "keep/b.wav")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "keep", ignoreFileName), []byte("!*.wav\n"), 0o644))
+
+	files := walkTestFolder(t, root, domain.ScanOptions{ExcludePatterns: []string{"*.wav", ignoreFileName}})
+	assert.Equal(t, []string{"keep/b.wav"}, files)
+}
+
+func TestWalkFolder_MaxDepth(t *testing.T) {
+	root := createWalkerTestTree(t, "a.mp3", "one/b.mp3", "one/two/c.mp3")
+
+	assert.Equal(t, []string{"a.mp3", "one/b.mp3"}, walkTestFolder(t, root, domain.ScanOptions{MaxDepth: 1}))
+	assert.Equal(t, []string{"a.mp3", "one/b.mp3", "one/two/c.mp3"}, walkTestFolder(t, root, domain.ScanOptions{}))
+}
+
+func TestWalkFolder_SkipHidden(t *testing.T) {
+	root := createWalkerTestTree(t, "a.mp3", ".cache/b.mp3", ".hidden.mp3")
+
+	// Only hidden folders are skipped
+	assert.Equal(t, []string{".hidden.mp3", "a.mp3"}, walkTestFolder(t, root, domain.ScanOptions{SkipHidden: true}))
+	assert.Equal(t, []string{".cache/b.mp3", ".hidden.mp3", "a.mp3"}, walkTestFolder(t, root, domain.ScanOptions{}))
+}
+
+func TestWalkFolder_SymlinkCycle(t *testing.T) {
+	root := createWalkerTestTree(t, "a.mp3", "sub/b.mp3", "linked/c.mp3")
+	if err := os.Symlink(root, filepath.Join(root, "sub", "loop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(root, "linked"), filepath.Join(root, "sub", "alias")))
+
+	// The loop back to the root is not followed, and the linked folder is visited once
+	files := walkTestFolder(t, root, domain.ScanOptions{})
+	assert.Equal(t, []string{"a.mp3", "linked/c.mp3", "sub/b.mp3"}, files)
+}
+
+func TestWalkFolder_FollowsSymlinkedFolders(t *testing.T) {
+	outside := createWalkerTestTree(t, "x.mp3")
+	root := createWalkerTestTree(t, "a.mp3")
+	if err := os.Symlink(outside, filepath.Join(root, "nas")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "broken")))
+
+	files := walkTestFolder(t, root, domain.ScanOptions{})
+	assert.Equal(t, []string{"a.mp3", "nas/x.mp3"}, files)
+}
+
+func TestWalkFolder_Cancelled(t *testing.T) {
+	root := createWalkerTestTree(t, "a.mp3")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := walkFolder(ctx, libTestLogger(), root, domain.ScanOptions{}, func(string) {}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLibraryService_ScanFolder_ScanOptions(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	root := createWalkerTestTree(t, "a.mp3", "b.flac", "deep/er/c.mp3")
+	service.SetScanOptions(domain.ScanOptions{ExcludePatterns: []string{"*.flac"}, MaxDepth: 1})
+
+	tracks, err := service.ScanFolder(root)
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, filepath.Join(root, "a.mp3"), tracks[0].FilePath)
+}
@@ -2,30 +2,40 @@
 package service
 
 import (
+	"archive/zip"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/tejashwikalptaru/gotune/internal/domain"
 	"github.com/tejashwikalptaru/gotune/internal/ports"
 )
 
+// archiveEntryExts lists the formats that are scanned inside ZIP archives.
+// Tracker modules are small and usually distributed in archives (e.g. Modland packs).
+var archiveEntryExts = []string{".mod", ".xm", ".it", ".s3m", ".mtm", ".umx", ".mo3"}
+
 // LibraryService handles music library operations including scanning and metadata extraction.
 // All operations are thread-safe via sync.RWMutex.
 type LibraryService struct {
 	// Dependencies (injected)
-	logger *slog.Logger
-	engine ports.AudioEngine
-	bus    ports.EventBus
+	logger     *slog.Logger
+	engine     ports.AudioEngine
+	repository ports.LibraryRepository
+	reports    ports.ScanReportRepository
+	bus        ports.EventBus
 
 	// State
 	scanning      bool
 	cancelScan    context.CancelFunc
 	scanContext   context.Context
+	scanOptions   domain.ScanOptions
 	supportedExts []string
 
 	// Concurrency control
@@ -36,14 +46,18 @@ type LibraryService struct {
 func NewLibraryService(
 	logger *slog.Logger,
 	engine ports.AudioEngine,
+	repository ports.LibraryRepository,
+	reports ports.ScanReportRepository,
 	bus ports.EventBus,
 ) *LibraryService {
 	logger.Debug("library service initialized")
 
 	return &LibraryService{
-		logger: logger,
-		engine: engine,
-		bus:    bus,
+		logger:     logger,
+		engine:     engine,
+		repository: repository,
+		reports:    reports,
+		bus:        bus,
 		supportedExts: []string{
 			// Common formats
 			".mp3", ".mp2", ".mp1",
@@ -68,6 +82,7 @@ func NewLibraryService(
 
 // ScanFolder scans a folder recursively for audio files and extracts metadata.
 // Returns a list of tracks found. Publishes progress events during scanning.
+// Files that cannot be read are listed in the completed event and the scan report.
 func (s *LibraryService) ScanFolder(folderPath string) ([]domain.MusicTrack, error) {
 	s.mu.Lock()
 	if s.scanning {
@@ -92,10 +107,11 @@ func (s *LibraryService) ScanFolder(folderPath string) ([]domain.MusicTrack, err
 	}()
 
 	// Publish scan started event
+	startedAt := time.Now()
 	s.bus.Publish(domain.NewScanStartedEvent(folderPath))
 
 	// Collect all audio files
-	files, err := s.collectAudioFiles(ctx, folderPath)
+	files, failures, err := s.collectAudioFiles(ctx, folderPath)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			s.bus.Publish(domain.NewScanCancelledEvent("user cancelled"))
@@ -107,6 +123,7 @@ func (s *LibraryService) ScanFolder(folderPath string) ([]domain.MusicTrack, err
 	// Extract metadata for each file
 	tracks := make([]domain.MusicTrack, 0, len(files))
 	total := len(files)
+	patterns := s.tagPatterns()
 
 	for i, filePath := range files {
 		// Check for cancellation
@@ -117,14 +134,11 @@ func (s *LibraryService) ScanFolder(folderPath string) ([]domain.MusicTrack, err
 		default:
 		}
 
-		// Extract metadata
-		track, err := s.engine.GetMetadata(filePath)
+		// Extract metadata; files that can't be read are reported but don't stop the scan
+		track, err := s.readMetadata(filePath, patterns)
 		if err != nil {
-			// Skip files that can't be read but continue scanning
-			continue
-		}
-
-		if track != nil {
+			failures = append(failures, s.scanFailure(filePath, err))
+		} else if track != nil {
 			tracks = append(tracks, *track)
 		}
 
@@ -138,8 +152,19 @@ func (s *LibraryService) ScanFolder(folderPath string) ([]domain.MusicTrack, err
 		s.bus.Publish(domain.NewScanProgressEvent(progress))
 	}
 
+	// Record the scanned tracks in the library
+	s.addToLibrary(tracks)
+	s.saveReport(&domain.ScanReport{
+		Path:         folderPath,
+		StartedAt:    startedAt,
+		CompletedAt:  time.Now(),
+		FilesScanned: total,
+		TracksFound:  len(tracks),
+		Failures:     failures,
+	})
+
 	// Publish scan completed event
-	s.bus.Publish(domain.NewScanCompletedEvent(tracks))
+	s.bus.Publish(domain.NewScanCompletedEvent(tracks, failures))
 
 	return tracks, nil
 }
@@ -168,10 +193,28 @@ func (s *LibraryService) ScanFiles(filePaths []string) ([]domain.MusicTrack, err
 		s.mu.Unlock()
 	}()
 
-	tracks := make([]domain.MusicTrack, 0, len(filePaths))
-	total := len(filePaths)
+	startedAt := time.Now()
+	failures := make([]domain.ScanFailure, 0)
+
+	// Replace archives with the tracks inside them
+	files := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if !isArchive(filePath) {
+			files = append(files, filePath)
+			continue
+		}
+		entries, err := s.collectArchiveEntries(filePath)
+		if err != nil {
+			failures = append(failures, s.scanFailure(filePath, err))
+		}
+		files = append(files, entries...)
+	}
+
+	tracks := make([]domain.MusicTrack, 0, len(files))
+	total := len(files)
+	patterns := s.tagPatterns()
 
-	for i, filePath := range filePaths {
+	for i, filePath := range files {
 		// Check for cancellation
 		select {
 		case <-ctx.Done():
@@ -179,19 +222,12 @@ func (s *LibraryService) ScanFiles(filePaths []string) ([]domain.MusicTrack, err
 		default:
 		}
 
-		// Skip unsupported formats
+		// Skip unsupported formats and files that can't be read
 		if !s.IsFormatSupported(filePath) {
-			continue
-		}
-
-		// Extract metadata
-		track, err := s.engine.GetMetadata(filePath)
-		if err != nil {
-			// Skip files that can't be read
-			continue
-		}
-
-		if track != nil {
+			failures = append(failures, s.scanFailure(filePath, domain.ErrUnsupportedFormat))
+		} else if track, err := s.readMetadata(filePath, patterns); err != nil {
+			failures = append(failures, s.scanFailure(filePath, err))
+		} else if track != nil {
 			tracks = append(tracks, *track)
 		}
 
@@ -205,9 +241,112 @@ func (s *LibraryService) ScanFiles(filePaths []string) ([]domain.MusicTrack, err
 		s.bus.Publish(domain.NewScanProgressEvent(progress))
 	}
 
+	// Record the scanned tracks in the library
+	s.addToLibrary(tracks)
+	s.saveReport(&domain.ScanReport{
+		StartedAt:    startedAt,
+		CompletedAt:  time.Now(),
+		FilesScanned: total,
+		TracksFound:  len(tracks),
+		Failures:     failures,
+	})
+
 	return tracks, nil
 }
 
+// addToLibrary saves scanned tracks to the library repository and publishes LibraryChangedEvent.
+// Failures are logged and do not fail the scan.
+func (s *LibraryService) addToLibrary(tracks []domain.MusicTrack) {
+	if len(tracks) == 0 {
+		return
+	}
+	if err := s.repository.SaveTracks(tracks); err != nil {
+		s.logger.Warn("failed to save tracks to library", slog.Any("error", err))
+		return
+	}
+	s.bus.Publish(domain.NewLibraryChangedEvent(tracks))
+}
+
+// scanFailure logs and returns the failure to read a file or folder during a scan.
+func (s *LibraryService) scanFailure(path string, err error) domain.ScanFailure {
+	failure := newScanFailure(path, err)
+	s.logger.Debug("failed to read file during scan",
+		slog.String("path", path),
+		slog.String("reason", string(failure.Reason)),
+		slog.Any("error", err))
+	return failure
+}
+
+// saveReport saves the report of a completed scan.
+// Failures are logged and do not fail the scan.
+func (s *LibraryService) saveReport(report *domain.ScanReport) {
+	if err := s.reports.SaveReport(report); err != nil {
+		s.logger.Warn("failed to save scan report", slog.Any("error", err))
+	}
+}
+
+// GetAlbumArt returns the artwork of a track: the bytes held in its metadata,
+// or else the artwork read from its file. Returns nil if the track has no artwork.
+func (s *LibraryService) GetAlbumArt(track domain.MusicTrack) ([]byte, error) {
+	if art := metadataOf(track).AlbumArt; len(art) > 0 {
+		return art, nil
+	}
+
+	art, err := s.engine.GetAlbumArt(track)
+	if err != nil {
+		return nil, domain.NewServiceError("LibraryService", "GetAlbumArt", "failed to read album art", err)
+	}
+	return art, nil
+}
+
+// GetLastScanReport returns the report of the most recent completed scan, or nil if there is none.
+func (s *LibraryService) GetLastScanReport() (*domain.ScanReport, error) {
+	return s.reports.LoadReport()
+}
+
+// GetLibrary returns every track in the library.
+func (s *LibraryService) GetLibrary() ([]domain.MusicTrack, error) {
+	return s.repository.LoadAll()
+}
+
+// GetAlbums returns the library grouped by album, in album order.
+// Compilations are grouped under their album artist or domain.VariousArtists.
+func (s *LibraryService) GetAlbums() ([]domain.Album, error) {
+	tracks, err := s.repository.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	return GroupByAlbum(tracks), nil
+}
+
+// GetFolderTree returns the library arranged in folder trees under the given roots
+// (usually the scan paths). See BuildFolderTree. The tree is built from the
+// library repository, so the disk is not read.
+func (s *LibraryService) GetFolderTree(roots []string) ([]*domain.FolderNode, error) {
+	tracks, err := s.repository.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	return BuildFolderTree(roots, tracks), nil
+}
+
+// Search returns the library tracks matching a structured search query.
+// See CompileQuery for the query syntax. Returns a *domain.QuerySyntaxError
+// if the query is malformed.
+func (s *LibraryService) Search(query string) ([]domain.MusicTrack, error) {
+	predicate, err := CompileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.repository.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return FilterTracks(tracks, predicate), nil
+}
+
 // CancelScan cancels the currently running scan operation.
 func (s *LibraryService) CancelScan() error {
 	s.mu.Lock()
@@ -242,6 +381,23 @@ func (s *LibraryService) IsFormatSupported(filePath string) bool {
 	return false
 }
 
+// SetScanOptions sets the exclude patterns, limits and tag patterns used by scans.
+// Scans already in progress are not affected.
+func (s *LibraryService) SetScanOptions(options domain.ScanOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scanOptions = options
+}
+
+// SetTagCharset sets the fallback charset for legacy tags read by later scans.
+// It has no effect if the audio engine does not decode legacy tags.
+func (s *LibraryService) SetTagCharset(charset domain.Charset) {
+	if decoder, ok := s.engine.(ports.TagCharsetDecoder); ok {
+		decoder.SetTagCharset(charset)
+	}
+}
+
 // GetSupportedFormats returns the list of supported file extensions.
 func (s *LibraryService) GetSupportedFormats() []string {
 	s.mu.RLock()
@@ -253,41 +409,69 @@ func (s *LibraryService) GetSupportedFormats() []string {
 	return formats
 }
 
-// collectAudioFiles recursively collects all audio files in a directory.
-func (s *LibraryService) collectAudioFiles(ctx context.Context, folderPath string) ([]string, error) {
-	files := make([]string, 0)
-
-	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
-		// Check for cancellation
-		select {
-		case <-ctx.Done():
-			return context.Canceled
-		default:
-		}
-
-		if err != nil {
-			// Skip files/folders we can't access
-			return nil
-		}
+// collectAudioFiles recursively collects all audio files in a directory,
+// skipping the files and folders excluded by the scan options.
+// Folders and archives that cannot be read are returned as failures.
+func (s *LibraryService) collectAudioFiles(ctx context.Context, folderPath string) ([]string, []domain.ScanFailure, error) {
+	s.mu.RLock()
+	options := s.scanOptions
+	s.mu.RUnlock()
 
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
+	files := make([]string, 0)
+	failures := make([]domain.ScanFailure, 0)
 
+	err := walkFolder(ctx, s.logger, folderPath, options, func(path string) {
 		// Check if supported a format
 		if s.IsFormatSupported(path) {
 			files = append(files, path)
+		} else if isArchive(path) {
+			entries, err := s.collectArchiveEntries(path)
+			if err != nil {
+				failures = append(failures, s.scanFailure(path, err))
+			}
+			files = append(files, entries...)
 		}
-
-		return nil
+	}, func(path string, err error) {
+		failures = append(failures, s.scanFailure(path, err))
 	})
 
 	if errors.Is(err, context.Canceled) {
-		return files, context.Canceled
+		return files, failures, context.Canceled
+	}
+
+	return files, failures, err
+}
+
+// isArchive returns true if the file is a ZIP archive.
+func isArchive(filePath string) bool {
+	return strings.EqualFold(filepath.Ext(filePath), ".zip")
+}
+
+// collectArchiveEntries returns the virtual paths of the tracker modules in a ZIP archive.
+func (s *LibraryService) collectArchiveEntries(archivePath string) ([]string, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		if errors.Is(err, zip.ErrFormat) {
+			err = fmt.Errorf("%w: %w", domain.ErrCorruptFile, err)
+		}
+		return nil, err
 	}
+	defer reader.Close()
 
-	return files, err
+	entries := make([]string, 0)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(file.Name))
+		for _, supported := range archiveEntryExts {
+			if ext == supported {
+				entries = append(entries, domain.ArchiveEntryPath(archivePath, file.Name))
+				break
+			}
+		}
+	}
+	return entries, nil
 }
 
 // ExtractMetadata extracts metadata for a single file.
@@ -296,12 +480,43 @@ func (s *LibraryService) ExtractMetadata(filePath string) (*domain.MusicTrack, e
 		return nil, domain.ErrUnsupportedFormat
 	}
 
-	// Check if the file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	// Check if the file (or the archive holding it) exists
+	if _, err := os.Stat(domain.PhysicalPath(filePath)); os.IsNotExist(err) {
 		return nil, domain.ErrFileNotFound
 	}
 
-	return s.engine.GetMetadata(filePath)
+	return s.readMetadata(filePath, s.tagPatterns())
+}
+
+// readMetadata extracts the metadata of a file and fills in the tags it is
+// missing from the first tag pattern matching its path.
+func (s *LibraryService) readMetadata(filePath string, patterns []*TagPattern) (*domain.MusicTrack, error) {
+	track, err := s.engine.GetMetadata(filePath)
+	if err != nil || track == nil || len(patterns) == 0 {
+		return track, err
+	}
+
+	if edit, ok := GuessTags(patterns, filePath); ok {
+		if edit = missingTags(*track, edit); !edit.IsEmpty() {
+			*track = edit.Apply(*track)
+		}
+	}
+	return track, nil
+}
+
+// tagPatterns compiles the tag patterns of the scan options.
+// Patterns are validated when they are saved, so invalid ones are only logged.
+func (s *LibraryService) tagPatterns() []*TagPattern {
+	s.mu.RLock()
+	sources := s.scanOptions.TagPatterns
+	s.mu.RUnlock()
+
+	patterns, err := CompileTagPatterns(sources)
+	if err != nil {
+		s.logger.Warn("ignoring invalid tag patterns", slog.Any("error", err))
+		return nil
+	}
+	return patterns
 }
 
 // Shutdown cleans up resources.
@@ -325,6 +540,13 @@ var _ interface {
 	IsScanning() bool
 	IsFormatSupported(string) bool
 	GetSupportedFormats() []string
+	SetScanOptions(domain.ScanOptions)
+	SetTagCharset(domain.Charset)
 	ExtractMetadata(string) (*domain.MusicTrack, error)
+	GetLibrary() ([]domain.MusicTrack, error)
+	GetAlbums() ([]domain.Album, error)
+	GetFolderTree([]string) ([]*domain.FolderNode, error)
+	GetLastScanReport() (*domain.ScanReport, error)
+	Search(string) ([]domain.MusicTrack, error)
 	Shutdown() error
 } = (*LibraryService)(nil)
@@ -1,10 +1,13 @@
 package service
 
 import (
+	"archive/zip"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,13 +23,90 @@ func libTestLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
 
+// Mock library repository for testing
+type mockLibraryRepository struct {
+	mu      sync.RWMutex
+	tracks  []domain.MusicTrack
+	saveErr error // Returned by saves when set
+}
+
+func newMockLibraryRepository() *mockLibraryRepository {
+	return &mockLibraryRepository{}
+}
+
+func (m *mockLibraryRepository) SaveTracks(tracks []domain.MusicTrack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	for _, track := range tracks {
+		replaced := false
+		for i := range m.tracks {
+			if m.tracks[i].FilePath == track.FilePath {
+				m.tracks[i] = track
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			m.tracks = append(m.tracks, track)
+		}
+	}
+	return nil
+}
+
+func (m *mockLibraryRepository) ReplaceAll(tracks []domain.MusicTrack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.tracks = slices.Clone(tracks)
+	return nil
+}
+
+func (m *mockLibraryRepository) LoadAll() ([]domain.MusicTrack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]domain.MusicTrack, len(m.tracks))
+	copy(result, m.tracks)
+	return result, nil
+}
+
+func (m *mockLibraryRepository) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracks = nil
+	return nil
+}
+
+// Mock scan report repository for testing
+type mockScanReportRepository struct {
+	mu     sync.RWMutex
+	report *domain.ScanReport
+}
+
+func (m *mockScanReportRepository) SaveReport(report *domain.ScanReport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.report = report
+	return nil
+}
+
+func (m *mockScanReportRepository) LoadReport() (*domain.ScanReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.report, nil
+}
+
 // Helper to create a test library service
 func newTestLibraryService() (*LibraryService, *eventbus.SyncEventBus) {
 	engine := mock.NewEngine()
 	engine.Initialize(-1, 44100, 0)
 
 	bus := eventbus.NewSyncEventBus()
-	service := NewLibraryService(libTestLogger(), engine, bus)
+	service := NewLibraryService(libTestLogger(), engine, newMockLibraryRepository(), &mockScanReportRepository{}, bus)
 
 	return service, bus
 }
@@ -186,6 +266,84 @@ func TestLibraryService_ScanFiles(t *testing.T) {
 	assert.Greater(t, progressCount, 0)
 }
 
+func TestLibraryService_ScanFiles_AddsToLibrary(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	tmpDir := createTestMusicFolder(t)
+	defer cleanupTestFolder(tmpDir)
+
+	files := []string{
+		filepath.Join(tmpDir, "song1.mp3"),
+		filepath.Join(tmpDir, "song2.flac"),
+	}
+
+	_, err := service.ScanFiles(files)
+	require.NoError(t, err)
+
+	// Scanning the same files again should not duplicate them
+	_, err = service.ScanFiles(files)
+	require.NoError(t, err)
+
+	library, err := service.GetLibrary()
+	require.NoError(t, err)
+	assert.Len(t, library, 2)
+}
+
+func TestLibraryService_ScanFiles_TagPatterns(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	tmpDir := t.TempDir()
+	albumDir := filepath.Join(tmpDir, "Album")
+	require.NoError(t, os.MkdirAll(albumDir, 0755))
+	file := filepath.Join(albumDir, "07 - Song.mp3")
+	require.NoError(t, os.WriteFile(file, []byte("fake audio"), 0644))
+
+	service.SetScanOptions(domain.ScanOptions{TagPatterns: []string{"%album%/%track% - %title%"}})
+
+	tracks, err := service.ScanFiles([]string{file})
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+
+	// The mock engine uses the file name as title and tags artist and album
+	assert.Equal(t, "Song", tracks[0].Title)
+	assert.Equal(t, "Mock Album", tracks[0].Album)
+	assert.Equal(t, 7, tracks[0].Metadata.TrackNumber)
+}
+
+func TestLibraryService_Search(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	tmpDir := createTestMusicFolder(t)
+	defer cleanupTestFolder(tmpDir)
+
+	_, err := service.ScanFolder(tmpDir)
+	require.NoError(t, err)
+
+	tracks, err := service.Search("format:flac")
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, filepath.Join(tmpDir, "song2.flac"), tracks[0].FilePath)
+
+	tracks, err = service.Search("-format:flac")
+	require.NoError(t, err)
+	assert.Len(t, tracks, 3)
+}
+
+func TestLibraryService_Search_InvalidQuery(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	_, err := service.Search("bpm:120")
+	require.Error(t, err)
+
+	var syntaxErr *domain.QuerySyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	assert.Equal(t, 1, syntaxErr.Position)
+}
+
 func TestLibraryService_ScanFolder(t *testing.T) {
 	service, bus := newTestLibraryService()
 	defer service.Shutdown()
@@ -233,6 +391,150 @@ func TestLibraryService_ScanFolder_NonExistentFolder(t *testing.T) {
 	assert.Equal(t, 0, len(tracks))
 }
 
+// createTestArchive creates a ZIP archive with empty entries of the given names.
+func createTestArchive(t *testing.T, path string, names ...string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	writer := zip.NewWriter(f)
+	for _, name := range names {
+		_, err := writer.Create(name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+}
+
+func TestLibraryService_ScanFolder_Archive(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "pack.zip")
+	createTestArchive(t, archivePath, "song.xm", "readme.txt", "extra/tune.MOD", "other.mp3")
+
+	tracks, err := service.ScanFolder(tmpDir)
+	require.NoError(t, err)
+
+	// Only the tracker modules inside the archive are found
+	paths := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		paths = append(paths, track.FilePath)
+	}
+	assert.ElementsMatch(t, []string{
+		archivePath + "!/song.xm",
+		archivePath + "!/extra/tune.MOD",
+	}, paths)
+}
+
+func TestLibraryService_ScanFiles_Archive(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	archivePath := filepath.Join(t.TempDir(), "PACK.ZIP")
+	createTestArchive(t, archivePath, "song.it")
+
+	tracks, err := service.ScanFiles([]string{archivePath})
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, archivePath+"!/song.it", tracks[0].FilePath)
+
+	archive, entry, ok := domain.SplitArchivePath(tracks[0].FilePath)
+	require.True(t, ok)
+	assert.Equal(t, archivePath, archive)
+	assert.Equal(t, "song.it", entry)
+}
+
+func TestLibraryService_ScanFolder_CorruptArchive(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "broken.zip"), []byte("not a zip"), 0o644))
+
+	tracks, err := service.ScanFolder(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, tracks)
+}
+
+func TestLibraryService_ScanFolder_ReportsFailures(t *testing.T) {
+	engine := mock.NewEngine()
+	engine.Initialize(-1, 44100, 0)
+	bus := eventbus.NewSyncEventBus()
+	reports := &mockScanReportRepository{}
+	service := NewLibraryService(libTestLogger(), engine, newMockLibraryRepository(), reports, bus)
+	defer service.Shutdown()
+
+	tmpDir := createTestMusicFolder(t)
+	defer cleanupTestFolder(tmpDir)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "broken.zip"), []byte("not a zip"), 0o644))
+
+	corrupt := filepath.Join(tmpDir, "song2.flac")
+	denied := filepath.Join(tmpDir, "track.wav")
+	engine.SetMetadataError(corrupt, domain.NewAudioEngineError("load_stream", corrupt, 41, "unsupported file format", domain.ErrCorruptFile))
+	engine.SetMetadataError(denied, domain.NewAudioEngineError("read_metadata", denied, 0, "failed to open file", os.ErrPermission))
+
+	var completed domain.ScanCompletedEvent
+	bus.Subscribe(domain.EventScanCompleted, func(e domain.Event) {
+		completed = e.(domain.ScanCompletedEvent)
+	})
+
+	tracks, err := service.ScanFolder(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, tracks, 2)
+
+	reasons := make(map[string]domain.ScanFailureReason)
+	for _, failure := range completed.Failures {
+		reasons[filepath.Base(failure.Path)] = failure.Reason
+		assert.NotEmpty(t, failure.Message)
+	}
+	assert.Equal(t, map[string]domain.ScanFailureReason{
+		"broken.zip": domain.ScanFailureCorrupt,
+		"song2.flac": domain.ScanFailureCorrupt,
+		"track.wav":  domain.ScanFailurePermission,
+	}, reasons)
+
+	// The report is saved
+	report, err := service.GetLastScanReport()
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, tmpDir, report.Path)
+	assert.Equal(t, 4, report.FilesScanned)
+	assert.Equal(t, 2, report.TracksFound)
+	assert.Equal(t, completed.Failures, report.Failures)
+	assert.False(t, report.CompletedAt.Before(report.StartedAt))
+}
+
+func TestLibraryService_ScanFiles_ReportsFailures(t *testing.T) {
+	engine := mock.NewEngine()
+	engine.Initialize(-1, 44100, 0)
+	reports := &mockScanReportRepository{}
+	service := NewLibraryService(libTestLogger(), engine, newMockLibraryRepository(), reports, eventbus.NewSyncEventBus())
+	defer service.Shutdown()
+
+	engine.SetMetadataError("/music/gone.mp3", domain.ErrFileNotFound)
+
+	tracks, err := service.ScanFiles([]string{"/music/a.mp3", "/music/notes.txt", "/music/gone.mp3"})
+	require.NoError(t, err)
+	assert.Len(t, tracks, 1)
+
+	require.NotNil(t, reports.report)
+	assert.Empty(t, reports.report.Path)
+	assert.Equal(t, []domain.ScanFailure{
+		{Path: "/music/notes.txt", Reason: domain.ScanFailureUnsupported, Message: domain.ErrUnsupportedFormat.Error()},
+		{Path: "/music/gone.mp3", Reason: domain.ScanFailureNotFound, Message: domain.ErrFileNotFound.Error()},
+	}, reports.report.Failures)
+}
+
+func TestLibraryService_GetLastScanReport_None(t *testing.T) {
+	service, _ := newTestLibraryService()
+	defer service.Shutdown()
+
+	report, err := service.GetLastScanReport()
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
+
 func TestLibraryService_CancelScan(t *testing.T) {
 	service, _ := newTestLibraryService()
 	defer service.Shutdown()
@@ -328,6 +630,31 @@ func TestLibraryService_ScanFolder_ConcurrentScan(t *testing.T) {
 	_ = err2
 }
 
+func TestLibraryService_GetAlbumArt(t *testing.T) {
+	engine := mock.NewEngine()
+	service := NewLibraryService(libTestLogger(), engine, newMockLibraryRepository(), &mockScanReportRepository{}, eventbus.NewSyncEventBus())
+
+	// Artwork held in the metadata is used as it is
+	held := domain.MusicTrack{FilePath: "/music/a.mp3", Metadata: &domain.TrackMetadata{AlbumArt: []byte("held")}}
+	art, err := service.GetAlbumArt(held)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("held"), art)
+
+	// Otherwise it is read from the file
+	engine.SetAlbumArt("/music/b.mp3", []byte("from file"))
+	art, err = service.GetAlbumArt(domain.MusicTrack{FilePath: "/music/b.mp3"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("from file"), art)
+
+	art, err = service.GetAlbumArt(domain.MusicTrack{FilePath: "/music/c.mp3"})
+	require.NoError(t, err)
+	assert.Nil(t, art)
+
+	engine.SetMetadataError("/music/d.mp3", domain.ErrFileNotFound)
+	_, err = service.GetAlbumArt(domain.MusicTrack{FilePath: "/music/d.mp3"})
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+}
+
 func TestLibraryService_Shutdown(t *testing.T) {
 	service, _ := newTestLibraryService()
 
@@ -0,0 +1,262 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// minLoggedPlay is how long a track must be heard to be logged unless it played
+// to the end, so that tracks skipped within seconds are left out.
+const minLoggedPlay = 5 * time.Second
+
+// listeningPlay is the play of the current track, logged when it ends.
+type listeningPlay struct {
+	track     domain.MusicTrack
+	trackID   string
+	startedAt time.Time
+	position  time.Duration
+	played    time.Duration // Time heard, not counting seeks
+}
+
+// ListeningHistoryService keeps an append-only log of played tracks, with when
+// and how long they played and whether they completed. A play is logged when
+// the track completes, is stopped or another track starts.
+// All operations are thread-safe via sync.RWMutex.
+type ListeningHistoryService struct {
+	// Dependencies (injected)
+	logger     *slog.Logger
+	repository ports.HistoryRepository
+	bus        ports.EventBus
+
+	// Current state
+	play *listeningPlay
+
+	// Event subscriptions
+	subscriptions []domain.SubscriptionID
+
+	// Concurrency control
+	mu sync.RWMutex
+}
+
+// NewListeningHistoryService creates a new listening history service.
+func NewListeningHistoryService(
+	logger *slog.Logger,
+	repository ports.HistoryRepository,
+	bus ports.EventBus,
+) *ListeningHistoryService {
+	service := &ListeningHistoryService{
+		logger:     logger,
+		repository: repository,
+		bus:        bus,
+	}
+
+	logger.Debug("listening history service initialized")
+
+	service.subscriptions = []domain.SubscriptionID{
+		bus.Subscribe(domain.EventTrackStarted, service.handleTrackStarted),
+		bus.Subscribe(domain.EventTrackProgress, service.handleTrackProgress),
+		bus.Subscribe(domain.EventTrackCompleted, service.handleTrackCompleted),
+		bus.Subscribe(domain.EventTrackStopped, service.handleTrackStopped),
+	}
+
+	return service
+}
+
+// handleTrackStarted starts a play, unless the track is resumed after a pause.
+func (s *ListeningHistoryService) handleTrackStarted(event domain.Event) {
+	startedEvent, ok := event.(domain.TrackStartedEvent)
+	if !ok {
+		return
+	}
+	trackID := startedEvent.Track.StableID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.play != nil {
+		if s.play.trackID == trackID {
+			return
+		}
+		s.logPlayInternal(false)
+	}
+	s.play = &listeningPlay{
+		track:     startedEvent.Track,
+		trackID:   trackID,
+		startedAt: time.Now(),
+	}
+}
+
+// handleTrackProgress adds the time heard.
+func (s *ListeningHistoryService) handleTrackProgress(event domain.Event) {
+	progressEvent, ok := event.(domain.TrackProgressEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.play == nil {
+		return
+	}
+	if step := progressEvent.Position - s.play.position; step > 0 && step <= maxListenStep {
+		s.play.played += step
+	}
+	s.play.position = progressEvent.Position
+}
+
+// handleTrackCompleted logs a track that played to the end.
+func (s *ListeningHistoryService) handleTrackCompleted(event domain.Event) {
+	completedEvent, ok := event.(domain.TrackCompletedEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.play == nil || s.play.trackID != completedEvent.Track.StableID() {
+		return
+	}
+	s.logPlayInternal(true)
+}
+
+// handleTrackStopped logs a track that was stopped before its end.
+// Moving to another track stops the current one, so manual next and previous are covered too.
+func (s *ListeningHistoryService) handleTrackStopped(event domain.Event) {
+	stoppedEvent, ok := event.(domain.TrackStoppedEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.play == nil || s.play.trackID != stoppedEvent.Track.StableID() {
+		return
+	}
+	s.logPlayInternal(false)
+}
+
+// logPlayInternal ends the current play and appends it to the log, unless it was
+// skipped within minLoggedPlay, and publishes ListenLoggedEvent.
+// Must be called with lock held.
+func (s *ListeningHistoryService) logPlayInternal(completed bool) {
+	play := s.play
+	s.play = nil
+	if !completed && play.played < minLoggedPlay {
+		return
+	}
+
+	entry := domain.NewListeningEntry(play.track)
+	entry.StartedAt = play.startedAt
+	entry.EndedAt = time.Now()
+	entry.Played = play.played
+	entry.Completed = completed
+	if err := s.repository.AppendListen(entry); err != nil {
+		s.logger.Warn("failed to log listen",
+			slog.String("path", play.track.FilePath),
+			slog.Any("error", err))
+		return
+	}
+
+	s.bus.Publish(domain.NewListenLoggedEvent(entry))
+}
+
+// RecentlyPlayed returns the logged plays, most recent first.
+// A limit of 0 or less returns all of them.
+func (s *ListeningHistoryService) RecentlyPlayed(limit int) ([]domain.ListeningEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := s.repository.LoadListens()
+	if err != nil {
+		return nil, domain.NewServiceError("ListeningHistoryService", "RecentlyPlayed", "failed to load listens", err)
+	}
+
+	slices.Reverse(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Sessions returns the logged plays grouped into listening sessions, most recent first.
+// A pause longer than domain.ListeningSessionGap starts a new session.
+// A limit of 0 or less returns all of them.
+func (s *ListeningHistoryService) Sessions(limit int) ([]domain.ListeningSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := s.repository.LoadListens()
+	if err != nil {
+		return nil, domain.NewServiceError("ListeningHistoryService", "Sessions", "failed to load listens", err)
+	}
+
+	sessions := groupListeningSessions(entries)
+	slices.Reverse(sessions)
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// groupListeningSessions groups plays, oldest first, into sessions in the same order.
+func groupListeningSessions(entries []domain.ListeningEntry) []domain.ListeningSession {
+	sessions := make([]domain.ListeningSession, 0)
+	for _, entry := range entries {
+		last := len(sessions) - 1
+		if last < 0 || entry.StartedAt.Sub(sessions[last].EndedAt) > domain.ListeningSessionGap {
+			sessions = append(sessions, domain.ListeningSession{StartedAt: entry.StartedAt})
+			last++
+		}
+		sessions[last].Entries = append(sessions[last].Entries, entry)
+		if entry.EndedAt.After(sessions[last].EndedAt) {
+			sessions[last].EndedAt = entry.EndedAt
+		}
+	}
+	return sessions
+}
+
+// ClearHistory removes all logged plays.
+func (s *ListeningHistoryService) ClearHistory() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repository.ClearListens(); err != nil {
+		return domain.NewServiceError("ListeningHistoryService", "ClearHistory", "failed to clear listens", err)
+	}
+	return nil
+}
+
+// Shutdown logs the current play and unsubscribes from events.
+func (s *ListeningHistoryService) Shutdown() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.subscriptions {
+		s.bus.Unsubscribe(id)
+	}
+	s.subscriptions = nil
+
+	// Log the play in progress (the best effort)
+	if s.play != nil {
+		s.logPlayInternal(false)
+	}
+
+	return nil
+}
+
+// Verify that ListeningHistoryService implements the expected interface patterns
+var _ interface {
+	RecentlyPlayed(int) ([]domain.ListeningEntry, error)
+	Sessions(int) ([]domain.ListeningSession, error)
+	ClearHistory() error
+	Shutdown() error
+} = (*ListeningHistoryService)(nil)
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helper to create a test listening history service
+func newTestListeningHistoryService() (*ListeningHistoryService, *mockHistoryRepository, *eventbus.SyncEventBus) {
+	bus := eventbus.NewSyncEventBus()
+	repo := newMockHistoryRepository()
+	return NewListeningHistoryService(libTestLogger(), repo, bus), repo, bus
+}
+
+func TestListeningHistoryService_LogsCompletedPlay(t *testing.T) {
+	service, _, bus := newTestListeningHistoryService()
+	defer service.Shutdown()
+
+	var logged []domain.ListenLoggedEvent
+	bus.Subscribe(domain.EventListenLogged, func(e domain.Event) {
+		logged = append(logged, e.(domain.ListenLoggedEvent))
+	})
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 3*time.Minute, 3*time.Minute)
+	bus.Publish(domain.NewTrackCompletedEvent(track))
+
+	require.Len(t, logged, 1)
+	entry := logged[0].Entry
+	assert.Equal(t, track.FilePath, entry.FilePath)
+	assert.True(t, entry.Completed)
+	assert.Equal(t, 3*time.Minute, entry.Played)
+	assert.False(t, entry.StartedAt.After(entry.EndedAt))
+
+	entries, err := service.RecentlyPlayed(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entry, entries[0])
+}
+
+func TestListeningHistoryService_LogsStoppedPlay(t *testing.T) {
+	service, _, bus := newTestListeningHistoryService()
+	defer service.Shutdown()
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 40*time.Second, 3*time.Minute)
+
+	// Pausing and resuming keeps the same play
+	bus.Publish(domain.NewTrackPausedEvent(track, 40*time.Second))
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	entries, err := service.RecentlyPlayed(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.False(t, entries[0].Completed)
+	assert.Equal(t, 40*time.Second, entries[0].Played)
+}
+
+func TestListeningHistoryService_SkipsShortPlays(t *testing.T) {
+	service, _, bus := newTestListeningHistoryService()
+	defer service.Shutdown()
+
+	a := createTestTrack("1", "A", "/music/a.mp3")
+	b := createTestTrack("2", "B", "/music/b.mp3")
+
+	// Skipped within seconds
+	bus.Publish(domain.NewTrackStartedEvent(a))
+	listenTo(bus, 3*time.Second, 3*time.Minute)
+	bus.Publish(domain.NewTrackStoppedEvent(a))
+
+	// Seeks do not count as heard
+	bus.Publish(domain.NewTrackStartedEvent(a))
+	bus.Publish(domain.NewTrackProgressEvent(2*time.Minute, 3*time.Minute))
+	// Starting another track ends the play
+	bus.Publish(domain.NewTrackStartedEvent(b))
+
+	entries, err := service.RecentlyPlayed(0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A short track that played to the end is logged
+	bus.Publish(domain.NewTrackCompletedEvent(b))
+	entries, err = service.RecentlyPlayed(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, b.FilePath, entries[0].FilePath)
+	assert.True(t, entries[0].Completed)
+}
+
+func TestListeningHistoryService_RecentlyPlayed(t *testing.T) {
+	service, repo, _ := newTestListeningHistoryService()
+	defer service.Shutdown()
+
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	for i, path := range []string{"/music/a.mp3", "/music/b.mp3", "/music/c.mp3"} {
+		at := start.Add(time.Duration(i) * 3 * time.Minute)
+		entry := domain.NewListeningEntry(createTestTrack(path, path, path))
+		entry.StartedAt = at
+		entry.EndedAt = at.Add(3 * time.Minute)
+		require.NoError(t, repo.AppendListen(entry))
+	}
+
+	entries, err := service.RecentlyPlayed(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/music/c.mp3", entries[0].FilePath)
+	assert.Equal(t, "/music/b.mp3", entries[1].FilePath)
+
+	require.NoError(t, service.ClearHistory())
+	entries, err = service.RecentlyPlayed(0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestListeningHistoryService_Sessions(t *testing.T) {
+	service, repo, _ := newTestListeningHistoryService()
+	defer service.Shutdown()
+
+	a := createTestTrack("1", "A", "/music/a.mp3")
+	b := createTestTrack("2", "B", "/music/b.mp3")
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	listen := func(track domain.MusicTrack, at time.Time) {
+		entry := domain.NewListeningEntry(track)
+		entry.StartedAt = at
+		entry.EndedAt = at.Add(3 * time.Minute)
+		entry.Played = 3 * time.Minute
+		entry.Completed = true
+		require.NoError(t, repo.AppendListen(entry))
+	}
+
+	// First session: a, b, a with short pauses
+	listen(a, start)
+	listen(b, start.Add(3*time.Minute))
+	listen(a, start.Add(6*time.Minute+domain.ListeningSessionGap))
+	// Second session after a longer pause
+	listen(b, start.Add(10*time.Minute+2*domain.ListeningSessionGap))
+
+	sessions, err := service.Sessions(0)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	assert.Len(t, sessions[0].Entries, 1)
+	assert.Equal(t, start.Add(10*time.Minute+2*domain.ListeningSessionGap), sessions[0].StartedAt)
+
+	first := sessions[1]
+	require.Len(t, first.Entries, 3)
+	assert.Equal(t, start, first.StartedAt)
+	assert.Equal(t, start.Add(9*time.Minute+domain.ListeningSessionGap), first.EndedAt)
+	tracks := first.Tracks()
+	require.Len(t, tracks, 2)
+	assert.Equal(t, a.FilePath, tracks[0].FilePath)
+	assert.Equal(t, b.FilePath, tracks[1].FilePath)
+
+	sessions, err = service.Sessions(1)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestListeningHistoryService_Shutdown(t *testing.T) {
+	service, repo, bus := newTestListeningHistoryService()
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 30*time.Second, 3*time.Minute)
+
+	// The play in progress is logged on shutdown
+	require.NoError(t, service.Shutdown())
+	entries, err := repo.LoadListens()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.False(t, entries[0].Completed)
+
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 30*time.Second, 3*time.Minute)
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	entries, err = repo.LoadListens()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,144 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// LyricsService loads the lyrics of the current track and follows playback
+// through synced lyrics.
+// When a track is loaded, it publishes LyricsLoadedEvent; during playback it
+// publishes LyricsLineEvent whenever the position crosses a line timestamp.
+// All operations are thread-safe via sync.RWMutex.
+type LyricsService struct {
+	// Dependencies (injected)
+	logger *slog.Logger
+	reader ports.LyricsReader
+	bus    ports.EventBus
+
+	// Current state
+	lyrics     *domain.Lyrics
+	activeLine int
+
+	// Event subscriptions
+	loadedSub   domain.SubscriptionID
+	progressSub domain.SubscriptionID
+
+	// Concurrency control
+	mu sync.RWMutex
+}
+
+// NewLyricsService creates a new lyrics service.
+func NewLyricsService(
+	logger *slog.Logger,
+	reader ports.LyricsReader,
+	bus ports.EventBus,
+) *LyricsService {
+	service := &LyricsService{
+		logger:     logger,
+		reader:     reader,
+		bus:        bus,
+		activeLine: -1,
+	}
+
+	logger.Debug("lyrics service initialized")
+
+	service.loadedSub = bus.Subscribe(domain.EventTrackLoaded, service.handleTrackLoaded)
+	service.progressSub = bus.Subscribe(domain.EventTrackProgress, service.handleTrackProgress)
+
+	return service
+}
+
+// handleTrackLoaded loads the lyrics of the newly loaded track.
+func (s *LyricsService) handleTrackLoaded(event domain.Event) {
+	loadedEvent, ok := event.(domain.TrackLoadedEvent)
+	if !ok {
+		return
+	}
+	track := loadedEvent.Track
+
+	lyrics, err := s.reader.ReadLyrics(track.FilePath)
+	if err != nil {
+		if !errors.Is(err, domain.ErrLyricsNotFound) {
+			s.logger.Warn("failed to read lyrics",
+				slog.String("path", track.FilePath),
+				slog.Any("error", err))
+		}
+		lyrics = nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lyrics = lyrics
+	s.activeLine = -1
+
+	s.bus.Publish(domain.NewLyricsLoadedEvent(track, lyrics))
+}
+
+// handleTrackProgress publishes the active line when it changes.
+// Seeking backwards is handled as well, since the line is looked up from the position.
+func (s *LyricsService) handleTrackProgress(event domain.Event) {
+	progressEvent, ok := event.(domain.TrackProgressEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lyrics == nil || !s.lyrics.Synced {
+		return
+	}
+
+	index := s.lyrics.LineAt(progressEvent.Position)
+	if index == s.activeLine {
+		return
+	}
+	s.activeLine = index
+
+	var line domain.LyricsLine
+	if index >= 0 {
+		line = s.lyrics.Lines[index]
+	}
+	s.bus.Publish(domain.NewLyricsLineEvent(index, line))
+}
+
+// GetLyrics returns the lyrics of the current track, or nil if it has none.
+func (s *LyricsService) GetLyrics() *domain.Lyrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lyrics
+}
+
+// GetActiveLine returns the index of the synced line being sung, or -1 if there is none.
+func (s *LyricsService) GetActiveLine() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.activeLine
+}
+
+// Shutdown cleans up resources.
+func (s *LyricsService) Shutdown() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bus.Unsubscribe(s.loadedSub)
+	s.bus.Unsubscribe(s.progressSub)
+
+	return nil
+}
+
+// Verify that LyricsService implements the expected interface patterns
+var _ interface {
+	GetLyrics() *domain.Lyrics
+	GetActiveLine() int
+	Shutdown() error
+} = (*LyricsService)(nil)
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Mock lyrics reader for testing
+type mockLyricsReader struct {
+	lyrics map[string]*domain.Lyrics
+	err    error
+}
+
+func (m *mockLyricsReader) ReadLyrics(filePath string) (*domain.Lyrics, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	lyrics, ok := m.lyrics[filePath]
+	if !ok {
+		return nil, domain.ErrLyricsNotFound
+	}
+	return lyrics, nil
+}
+
+// Helper to create a test lyrics service
+func newTestLyricsService(reader *mockLyricsReader) (*LyricsService, *eventbus.SyncEventBus) {
+	bus := eventbus.NewSyncEventBus()
+	return NewLyricsService(libTestLogger(), reader, bus), bus
+}
+
+func TestLyricsService_LoadsLyricsForTrack(t *testing.T) {
+	plain := &domain.Lyrics{Lines: []domain.LyricsLine{{Text: "La la"}}}
+	service, bus := newTestLyricsService(&mockLyricsReader{lyrics: map[string]*domain.Lyrics{"/music/a.mp3": plain}})
+	defer service.Shutdown()
+
+	var loaded []domain.LyricsLoadedEvent
+	bus.Subscribe(domain.EventLyricsLoaded, func(e domain.Event) {
+		loaded = append(loaded, e.(domain.LyricsLoadedEvent))
+	})
+
+	bus.Publish(domain.NewTrackLoadedEvent(createTestTrack("1", "A", "/music/a.mp3"), 1, time.Minute, 0))
+	require.Len(t, loaded, 1)
+	assert.Equal(t, plain, loaded[0].Lyrics)
+	assert.Equal(t, plain, service.GetLyrics())
+
+	// Tracks without lyrics clear the previous ones
+	bus.Publish(domain.NewTrackLoadedEvent(createTestTrack("2", "B", "/music/b.mp3"), 2, time.Minute, 1))
+	require.Len(t, loaded, 2)
+	assert.Nil(t, loaded[1].Lyrics)
+	assert.Nil(t, service.GetLyrics())
+}
+
+func TestLyricsService_PublishesLineChanges(t *testing.T) {
+	synced := &domain.Lyrics{Synced: true, Lines: []domain.LyricsLine{
+		{Time: 1 * time.Second, Text: "One"},
+		{Time: 3 * time.Second, Text: "Two"},
+		{Time: 5 * time.Second, Text: "Three"},
+	}}
+	service, bus := newTestLyricsService(&mockLyricsReader{lyrics: map[string]*domain.Lyrics{"/music/a.mp3": synced}})
+	defer service.Shutdown()
+
+	var lines []domain.LyricsLineEvent
+	bus.Subscribe(domain.EventLyricsLine, func(e domain.Event) {
+		lines = append(lines, e.(domain.LyricsLineEvent))
+	})
+	bus.Publish(domain.NewTrackLoadedEvent(createTestTrack("1", "A", "/music/a.mp3"), 1, time.Minute, 0))
+
+	for _, position := range []time.Duration{500 * time.Millisecond, 1200 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second, 10 * time.Second} {
+		bus.Publish(domain.NewTrackProgressEvent(position, time.Minute))
+	}
+	require.Len(t, lines, 3, "Only changes of the active line are published")
+	assert.Equal(t, 0, lines[0].Index)
+	assert.Equal(t, "One", lines[0].Line.Text)
+	assert.Equal(t, 1, lines[1].Index)
+	assert.Equal(t, 2, lines[2].Index)
+	assert.Equal(t, 2, service.GetActiveLine())
+
+	// Seeking back before the first line
+	bus.Publish(domain.NewTrackProgressEvent(0, time.Minute))
+	require.Len(t, lines, 4)
+	assert.Equal(t, -1, lines[3].Index)
+}
+
+func TestLyricsService_UnsyncedLyricsPublishNoLines(t *testing.T) {
+	plain := &domain.Lyrics{Lines: []domain.LyricsLine{{Text: "La la"}}}
+	service, bus := newTestLyricsService(&mockLyricsReader{lyrics: map[string]*domain.Lyrics{"/music/a.mp3": plain}})
+	defer service.Shutdown()
+
+	published := false
+	bus.Subscribe(domain.EventLyricsLine, func(e domain.Event) {
+		published = true
+	})
+	bus.Publish(domain.NewTrackLoadedEvent(createTestTrack("1", "A", "/music/a.mp3"), 1, time.Minute, 0))
+	bus.Publish(domain.NewTrackProgressEvent(10*time.Second, time.Minute))
+
+	assert.False(t, published)
+	assert.Equal(t, -1, service.GetActiveLine())
+}
+
+func TestLyricsService_ReadErrorClearsLyrics(t *testing.T) {
+	service, bus := newTestLyricsService(&mockLyricsReader{err: errors.New("disk error")})
+	defer service.Shutdown()
+
+	var loaded *domain.LyricsLoadedEvent
+	bus.Subscribe(domain.EventLyricsLoaded, func(e domain.Event) {
+		event := e.(domain.LyricsLoadedEvent)
+		loaded = &event
+	})
+	bus.Publish(domain.NewTrackLoadedEvent(createTestTrack("1", "A", "/music/a.mp3"), 1, time.Minute, 0))
+
+	require.NotNil(t, loaded)
+	assert.Nil(t, loaded.Lyrics)
+}
@@ -11,7 +11,7 @@ import (
 )
 
 // PlaybackService orchestrates audio playback operations.
-// It manages the current playing track, volume, mute state, and loop mode.
+// It manages the current playing track, volume, mute state, and repeat mode.
 // All operations are thread-safe via sync.RWMutex.
 type PlaybackService struct {
 	// Dependencies (injected)
@@ -26,7 +26,7 @@ type PlaybackService struct {
 	volume         float64
 	savedVolume    float64 // Volume before mute
 	isMuted        bool
-	isLooping      bool
+	repeatMode     domain.RepeatMode
 	updateInterval time.Duration
 
 	// Concurrency control
@@ -50,7 +50,8 @@ func NewPlaybackService(
 		bus:            bus,
 		currentHandle:  domain.InvalidTrackHandle,
 		currentIndex:   -1,
-		volume:         0.8,                    // Default 80% volume
+		volume:         0.8, // Default 80% volume
+		repeatMode:     domain.RepeatOff,
 		updateInterval: 333 * time.Millisecond, // 3 times per second
 		stopUpdate:     make(chan struct{}),
 	}
@@ -309,28 +310,36 @@ func (s *PlaybackService) IsMuted() bool {
 	return s.isMuted
 }
 
-// SetLoop enables or disables loop mode.
-// When enabled, the current track will restart when it finishes.
-func (s *PlaybackService) SetLoop(loop bool) {
+// SetRepeatMode sets what plays when the current track finishes.
+// With RepeatOne the current track restarts; otherwise an auto-next event is
+// published and the playlist service picks the next track (wrapping with RepeatAll).
+// Returns a ValidationError if the mode is unknown.
+func (s *PlaybackService) SetRepeatMode(mode domain.RepeatMode) error {
+	if !mode.IsValid() {
+		return domain.NewValidationError("repeatMode", mode, "must be off, one or all")
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.isLooping == loop {
-		return
+	if s.repeatMode == mode {
+		return nil
 	}
 
-	s.isLooping = loop
+	s.repeatMode = mode
 
 	// Publish event
-	s.bus.Publish(domain.NewLoopToggledEvent(loop))
+	s.bus.Publish(domain.NewRepeatModeChangedEvent(mode))
+
+	return nil
 }
 
-// IsLooping returns true if loop mode is enabled.
-func (s *PlaybackService) IsLooping() bool {
+// GetRepeatMode returns what plays when the current track finishes.
+func (s *PlaybackService) GetRepeatMode() domain.RepeatMode {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.isLooping
+	return s.repeatMode
 }
 
 // Seek sets the playback position.
@@ -367,7 +376,7 @@ func (s *PlaybackService) GetState() domain.PlaybackState {
 		CurrentIndex: s.currentIndex,
 		Volume:       s.volume,
 		IsMuted:      s.isMuted,
-		IsLooping:    s.isLooping,
+		RepeatMode:   s.repeatMode,
 	}
 
 	// Get current track info
@@ -508,7 +517,7 @@ func (s *PlaybackService) handleTrackFinishedWithLock() {
 	}
 
 	track := *s.currentTrack
-	shouldLoop := s.isLooping
+	shouldLoop := s.repeatMode == domain.RepeatOne
 	index := s.currentIndex
 
 	// Reset state
@@ -574,8 +583,8 @@ var _ interface {
 	GetVolume() float64
 	Mute(bool) error
 	IsMuted() bool
-	SetLoop(bool)
-	IsLooping() bool
+	SetRepeatMode(domain.RepeatMode) error
+	GetRepeatMode() domain.RepeatMode
 	Seek(time.Duration) error
 	GetState() domain.PlaybackState
 	GetFFTData() []float32
@@ -388,7 +388,7 @@ func TestPlaybackService_Mute(t *testing.T) {
 	assert.Equal(t, 0.8, service.GetVolume())
 }
 
-func TestPlaybackService_SetLoop(t *testing.T) {
+func TestPlaybackService_SetRepeatMode(t *testing.T) {
 	service, engine, bus := newTestPlaybackService()
 	defer service.Shutdown()
 
@@ -396,20 +396,27 @@ func TestPlaybackService_SetLoop(t *testing.T) {
 	require.NoError(t, err)
 
 	// Subscribe to events
-	var loopEvent domain.LoopToggledEvent
-	bus.Subscribe(domain.EventLoopToggled, func(e domain.Event) {
-		loopEvent = e.(domain.LoopToggledEvent)
+	var repeatEvent domain.RepeatModeChangedEvent
+	bus.Subscribe(domain.EventRepeatModeChanged, func(e domain.Event) {
+		repeatEvent = e.(domain.RepeatModeChangedEvent)
 	})
 
-	// Enable loop
-	service.SetLoop(true)
-	assert.True(t, service.IsLooping())
-	assert.True(t, loopEvent.Enabled)
+	assert.Equal(t, domain.RepeatOff, service.GetRepeatMode())
 
-	// Disable loop
-	service.SetLoop(false)
-	assert.False(t, service.IsLooping())
-	assert.False(t, loopEvent.Enabled)
+	// Repeat the current track
+	require.NoError(t, service.SetRepeatMode(domain.RepeatOne))
+	assert.Equal(t, domain.RepeatOne, service.GetRepeatMode())
+	assert.Equal(t, domain.RepeatOne, repeatEvent.Mode)
+
+	// Repeat the queue
+	require.NoError(t, service.SetRepeatMode(domain.RepeatAll))
+	assert.Equal(t, domain.RepeatAll, service.GetRepeatMode())
+	assert.Equal(t, domain.RepeatAll, repeatEvent.Mode)
+
+	// Unknown modes are rejected
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, service.SetRepeatMode("twice"), &validationErr)
+	assert.Equal(t, domain.RepeatAll, service.GetRepeatMode())
 }
 
 func TestPlaybackService_Seek(t *testing.T) {
@@ -471,14 +478,14 @@ func TestPlaybackService_GetState(t *testing.T) {
 	// Load track
 	service.LoadTrack(track, 5)
 	service.SetVolume(0.6)
-	service.SetLoop(true)
+	service.SetRepeatMode(domain.RepeatOne)
 
 	state = service.GetState()
 	assert.NotNil(t, state.CurrentTrack)
 	assert.Equal(t, track.ID, state.CurrentTrack.ID)
 	assert.Equal(t, 5, state.CurrentIndex)
 	assert.Equal(t, 0.6, state.Volume)
-	assert.True(t, state.IsLooping)
+	assert.Equal(t, domain.RepeatOne, state.RepeatMode)
 }
 
 func TestPlaybackService_ProgressEvents(t *testing.T) {
@@ -513,7 +520,7 @@ func TestPlaybackService_ProgressEvents(t *testing.T) {
 	assert.Greater(t, count, 1)
 }
 
-func TestPlaybackService_TrackCompleted_WithLoop(t *testing.T) {
+func TestPlaybackService_TrackCompleted_RepeatOne(t *testing.T) {
 	service, engine, bus := newTestPlaybackService()
 	defer service.Shutdown()
 
@@ -528,8 +535,8 @@ func TestPlaybackService_TrackCompleted_WithLoop(t *testing.T) {
 		completedReceived = true
 	})
 
-	// Enable loop
-	service.SetLoop(true)
+	// Repeat the current track
+	service.SetRepeatMode(domain.RepeatOne)
 
 	// Load and play
 	service.LoadTrack(track, 0)
@@ -556,7 +563,7 @@ func TestPlaybackService_TrackCompleted_WithLoop(t *testing.T) {
 	_ = completedReceived
 }
 
-func TestPlaybackService_TrackCompleted_WithoutLoop(t *testing.T) {
+func TestPlaybackService_TrackCompleted_RepeatOff(t *testing.T) {
 	service, engine, bus := newTestPlaybackService()
 	defer service.Shutdown()
 
@@ -571,8 +578,8 @@ func TestPlaybackService_TrackCompleted_WithoutLoop(t *testing.T) {
 		autoNextReceived = true
 	})
 
-	// Disable loop
-	service.SetLoop(false)
+	// Do not repeat
+	service.SetRepeatMode(domain.RepeatOff)
 
 	// Load and play
 	service.LoadTrack(track, 0)
@@ -0,0 +1,257 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// PlaylistFileService imports and exports playlist files such as M3U.
+// Imported entries are matched to library tracks by path; files that are not
+// in the library are read from disk. Entries that cannot be played are reported
+// in the import result instead of being dropped silently.
+// All operations are serialized via sync.Mutex.
+type PlaylistFileService struct {
+	// Dependencies (injected)
+	logger   *slog.Logger
+	library  *LibraryService
+	playlist *PlaylistService
+	formats  []ports.PlaylistFileFormat
+
+	// Concurrency control
+	mu sync.Mutex
+}
+
+// NewPlaylistFileService creates a new playlist file service.
+func NewPlaylistFileService(
+	logger *slog.Logger,
+	library *LibraryService,
+	playlist *PlaylistService,
+	formats ...ports.PlaylistFileFormat,
+) *PlaylistFileService {
+	logger.Debug("playlist file service initialized")
+
+	return &PlaylistFileService{
+		logger:   logger,
+		library:  library,
+		playlist: playlist,
+		formats:  formats,
+	}
+}
+
+// SupportedExtensions returns the extensions of the playlist files that can be
+// imported and exported, e.g. ".m3u".
+func (s *PlaylistFileService) SupportedExtensions() []string {
+	extensions := make([]string, 0)
+	for _, format := range s.formats {
+		extensions = append(extensions, format.Extensions()...)
+	}
+	return extensions
+}
+
+// ImportToQueue adds the tracks of a playlist file to the queue.
+// Tracks already in the queue are skipped by the queue.
+func (s *PlaylistFileService) ImportToQueue(filePath string) (*domain.PlaylistImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.readInternal(filePath, "", "ImportToQueue")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Tracks) > 0 {
+		if err := s.playlist.AddTracks(result.Tracks, false); err != nil {
+			return nil, domain.NewServiceError("PlaylistFileService", "ImportToQueue", "failed to add tracks to the queue", err)
+		}
+	}
+
+	s.logImport(filePath, result)
+	return result, nil
+}
+
+// ImportAsPlaylist saves the tracks of a playlist file as a new playlist.
+// An empty name uses the file name without its extension.
+func (s *PlaylistFileService) ImportAsPlaylist(filePath, name string) (*domain.PlaylistImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.readInternal(filePath, name, "ImportAsPlaylist")
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := s.playlist.CreatePlaylist(result.Name, result.Tracks)
+	if err != nil {
+		return nil, err
+	}
+	result.PlaylistID = playlist.ID
+
+	s.logImport(filePath, result)
+	return result, nil
+}
+
+// ExportQueue writes the queue to a playlist file.
+// relative: write track paths relative to the playlist file where possible
+func (s *PlaylistFileService) ExportQueue(filePath string, relative bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writeInternal(filePath, s.playlist.GetQueue(), relative, "ExportQueue")
+}
+
+// ExportPlaylist writes a saved playlist (including smart playlists) to a playlist file.
+// relative: write track paths relative to the playlist file where possible
+func (s *PlaylistFileService) ExportPlaylist(id, filePath string, relative bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.playlist.GetPlaylist(id)
+	if err != nil {
+		return domain.NewServiceError("PlaylistFileService", "ExportPlaylist", "failed to load playlist", err)
+	}
+
+	return s.writeInternal(filePath, playlist.Tracks, relative, "ExportPlaylist")
+}
+
+// readInternal reads a playlist file and resolves its entries to tracks.
+func (s *PlaylistFileService) readInternal(filePath, name, op string) (*domain.PlaylistImport, error) {
+	format, err := s.formatFor(filePath)
+	if err != nil {
+		return nil, domain.NewServiceError("PlaylistFileService", op, "cannot import "+filepath.Base(filePath), err)
+	}
+
+	entries, err := format.Read(filePath)
+	if err != nil {
+		return nil, domain.NewServiceError("PlaylistFileService", op, "failed to read playlist file", err)
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	result := &domain.PlaylistImport{
+		Name:       strings.TrimSpace(name),
+		Tracks:     make([]domain.MusicTrack, 0, len(entries)),
+		Unresolved: make([]domain.UnresolvedEntry, 0),
+	}
+
+	library, err := s.library.GetLibrary()
+	if err != nil {
+		return nil, domain.NewServiceError("PlaylistFileService", op, "failed to load library", err)
+	}
+	byPath := make(map[string]domain.MusicTrack, len(library))
+	for _, track := range library {
+		byPath[filepath.Clean(track.FilePath)] = track
+	}
+
+	for _, entry := range entries {
+		track, reason := s.resolve(entry, byPath)
+		if reason != "" {
+			result.Unresolved = append(result.Unresolved, domain.UnresolvedEntry{Entry: entry, Reason: reason})
+			continue
+		}
+		result.Tracks = append(result.Tracks, track)
+	}
+	return result, nil
+}
+
+// resolve finds the track of a playlist entry.
+// Returns the reason if the entry does not lead to a playable track.
+func (s *PlaylistFileService) resolve(entry domain.PlaylistFileEntry, library map[string]domain.MusicTrack) (domain.MusicTrack, string) {
+	if entry.Path == "" || !filepath.IsAbs(entry.Path) {
+		return domain.MusicTrack{}, "not a local file"
+	}
+	if track, ok := library[filepath.Clean(entry.Path)]; ok {
+		return track, ""
+	}
+
+	track, err := s.library.ExtractMetadata(entry.Path)
+	switch {
+	case errors.Is(err, domain.ErrFileNotFound):
+		return domain.MusicTrack{}, "file not found"
+	case errors.Is(err, domain.ErrUnsupportedFormat):
+		return domain.MusicTrack{}, "unsupported format"
+	case err != nil:
+		return domain.MusicTrack{}, err.Error()
+	case track == nil:
+		return domain.MusicTrack{}, "no metadata"
+	}
+
+	// Untagged files get their title and length from the playlist
+	if entry.Title != "" && hasDefaultTitle(*track) {
+		track.Title = entry.Title
+	}
+	if track.Duration == 0 {
+		track.Duration = entry.Duration
+	}
+	return *track, ""
+}
+
+// writeInternal writes tracks to a playlist file.
+func (s *PlaylistFileService) writeInternal(filePath string, tracks []domain.MusicTrack, relative bool, op string) error {
+	format, err := s.formatFor(filePath)
+	if err != nil {
+		return domain.NewServiceError("PlaylistFileService", op, "cannot export "+filepath.Base(filePath), err)
+	}
+
+	entries := make([]domain.PlaylistFileEntry, len(tracks))
+	for i, track := range tracks {
+		entries[i] = domain.PlaylistFileEntry{
+			Path:     track.FilePath,
+			Title:    playlistEntryTitle(track),
+			Duration: track.Duration,
+		}
+	}
+
+	if err := format.Write(filePath, entries, relative); err != nil {
+		return domain.NewServiceError("PlaylistFileService", op, "failed to write playlist file", err)
+	}
+
+	s.logger.Info("playlist exported", slog.String("file", filePath), slog.Int("tracks", len(entries)))
+	return nil
+}
+
+// formatFor returns the format of a playlist file by its extension.
+func (s *PlaylistFileService) formatFor(filePath string) (ports.PlaylistFileFormat, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, format := range s.formats {
+		for _, formatExt := range format.Extensions() {
+			if ext == formatExt {
+				return format, nil
+			}
+		}
+	}
+	return nil, domain.ErrUnsupportedPlaylistFormat
+}
+
+// logImport logs the outcome of an import.
+func (s *PlaylistFileService) logImport(filePath string, result *domain.PlaylistImport) {
+	s.logger.Info("playlist imported",
+		slog.String("file", filePath),
+		slog.Int("tracks", len(result.Tracks)),
+		slog.Int("unresolved", len(result.Unresolved)))
+}
+
+// playlistEntryTitle returns the title written to playlist files: "Artist - Title",
+// or the title alone for tracks without an artist.
+func playlistEntryTitle(track domain.MusicTrack) string {
+	if track.Artist != "" && track.Title != "" {
+		return track.Artist + " - " + track.Title
+	}
+	return track.Title
+}
+
+// Verify that PlaylistFileService implements the expected interface patterns
+var _ interface {
+	SupportedExtensions() []string
+	ImportToQueue(string) (*domain.PlaylistImport, error)
+	ImportAsPlaylist(string, string) (*domain.PlaylistImport, error)
+	ExportQueue(string, bool) error
+	ExportPlaylist(string, string, bool) error
+} = (*PlaylistFileService)(nil)
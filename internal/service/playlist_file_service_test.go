@@ -0,0 +1,187 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/audio/mock"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// mockPlaylistFileFormat keeps playlist files in memory
+type mockPlaylistFileFormat struct {
+	mu         sync.Mutex
+	extensions []string
+	files      map[string][]domain.PlaylistFileEntry
+	relative   map[string]bool
+}
+
+func newMockPlaylistFileFormat(extensions ...string) *mockPlaylistFileFormat {
+	return &mockPlaylistFileFormat{
+		extensions: extensions,
+		files:      make(map[string][]domain.PlaylistFileEntry),
+		relative:   make(map[string]bool),
+	}
+}
+
+func (m *mockPlaylistFileFormat) Extensions() []string {
+	return m.extensions
+}
+
+func (m *mockPlaylistFileFormat) Read(filePath string) ([]domain.PlaylistFileEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries, ok := m.files[filePath]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return entries, nil
+}
+
+func (m *mockPlaylistFileFormat) Write(filePath string, entries []domain.PlaylistFileEntry, relative bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filePath] = entries
+	m.relative[filePath] = relative
+	return nil
+}
+
+// playlistFileTestEnv holds a playlist file service and its dependencies
+type playlistFileTestEnv struct {
+	service   *PlaylistFileService
+	playlist  *PlaylistService
+	library   *mockLibraryRepository
+	playlists *mockPlaylistRepository
+	format    *mockPlaylistFileFormat // .m3u and .m3u8
+	pls       *mockPlaylistFileFormat // .pls
+}
+
+// Helper to create a test playlist file service
+func newTestPlaylistFileService(t *testing.T) *playlistFileTestEnv {
+	engine := mock.NewEngine()
+	_ = engine.Initialize(-1, 44100, 0)
+
+	bus := eventbus.NewSyncEventBus()
+	log := playlistTestLogger()
+	playback := NewPlaybackService(log, engine, bus)
+	env := &playlistFileTestEnv{
+		library:   newMockLibraryRepository(),
+		playlists: newMockPlaylistRepository(),
+		format:    newMockPlaylistFileFormat(".m3u", ".m3u8"),
+		pls:       newMockPlaylistFileFormat(".pls"),
+	}
+	env.playlist = NewPlaylistService(log, playback, env.playlists, newMockHistoryRepository(), bus)
+	library := NewLibraryService(log, engine, env.library, &mockScanReportRepository{}, bus)
+	env.service = NewPlaylistFileService(log, library, env.playlist, env.format, env.pls)
+
+	t.Cleanup(func() {
+		_ = env.playlist.Shutdown()
+		_ = playback.Shutdown()
+	})
+	return env
+}
+
+func TestPlaylistFileService_ImportToQueue(t *testing.T) {
+	env := newTestPlaylistFileService(t)
+	dir := t.TempDir()
+
+	// One track is in the library, one only on disk
+	inLibrary := createTestTrack("lib", "Library Song", filepath.Join(dir, "library.mp3"))
+	require.NoError(t, env.library.SaveTracks([]domain.MusicTrack{inLibrary}))
+	onDisk := filepath.Join(dir, "untagged.mp3")
+	require.NoError(t, os.WriteFile(onDisk, []byte("audio"), 0600))
+
+	playlistPath := filepath.Join(dir, "Road Trip.m3u")
+	env.format.files[playlistPath] = []domain.PlaylistFileEntry{
+		{Path: inLibrary.FilePath, Line: 2},
+		{Path: filepath.Join(dir, "gone.mp3"), Line: 4},
+		{Path: onDisk, Title: "From Playlist", Duration: time.Minute, Line: 6},
+		{Path: "http://example.com/stream", Line: 8},
+		{Path: filepath.Join(dir, "notes.txt"), Line: 9},
+	}
+
+	result, err := env.service.ImportToQueue(playlistPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Road Trip", result.Name)
+	assert.Empty(t, result.PlaylistID)
+	require.Len(t, result.Tracks, 2)
+	assert.Equal(t, "Library Song", result.Tracks[0].Title)
+	assert.Equal(t, "From Playlist", result.Tracks[1].Title, "Untagged files use the playlist title")
+
+	reasons := make(map[int]string)
+	for _, unresolved := range result.Unresolved {
+		reasons[unresolved.Entry.Line] = unresolved.Reason
+	}
+	assert.Equal(t, map[int]string{4: "file not found", 8: "not a local file", 9: "unsupported format"}, reasons)
+
+	queue := env.playlist.GetQueue()
+	require.Len(t, queue, 2)
+	assert.Equal(t, inLibrary.FilePath, queue[0].FilePath)
+	assert.Equal(t, onDisk, queue[1].FilePath)
+}
+
+func TestPlaylistFileService_ImportAsPlaylist(t *testing.T) {
+	env := newTestPlaylistFileService(t)
+	track := createTestTrack("1", "Song", "/music/song.mp3")
+	require.NoError(t, env.library.SaveTracks([]domain.MusicTrack{track}))
+	env.format.files["/lists/mix.m3u8"] = []domain.PlaylistFileEntry{{Path: track.FilePath}}
+
+	result, err := env.service.ImportAsPlaylist("/lists/mix.m3u8", "  My Mix ")
+	require.NoError(t, err)
+
+	saved, err := env.playlists.Load(result.PlaylistID)
+	require.NoError(t, err)
+	assert.Equal(t, "My Mix", saved.Name)
+	assert.Equal(t, []domain.MusicTrack{track}, saved.Tracks)
+	assert.Empty(t, env.playlist.GetQueue(), "Importing a playlist leaves the queue alone")
+}
+
+func TestPlaylistFileService_Export(t *testing.T) {
+	env := newTestPlaylistFileService(t)
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "First", "/music/first.mp3"),
+		{ID: "2", Title: "Untitled", FilePath: "/music/second.ogg"},
+	}
+	require.NoError(t, env.playlist.AddTracks(tracks, false))
+	require.NoError(t, env.playlists.Save(&domain.Playlist{ID: "p1", Name: "Saved", Tracks: tracks[1:]}))
+
+	require.NoError(t, env.service.ExportQueue("/lists/queue.m3u", true))
+	assert.Equal(t, []domain.PlaylistFileEntry{
+		{Path: "/music/first.mp3", Title: "Test Artist - First", Duration: 3 * time.Minute},
+		{Path: "/music/second.ogg", Title: "Untitled"},
+	}, env.format.files["/lists/queue.m3u"])
+	assert.True(t, env.format.relative["/lists/queue.m3u"])
+
+	require.NoError(t, env.service.ExportPlaylist("p1", "/lists/saved.M3U8", false))
+	assert.Len(t, env.format.files["/lists/saved.M3U8"], 1)
+
+	_, err := env.service.ImportToQueue("/lists/queue.xspf")
+	assert.ErrorIs(t, err, domain.ErrUnsupportedPlaylistFormat)
+	assert.ErrorIs(t, env.service.ExportPlaylist("missing", "/lists/x.m3u", false), ports.ErrPlaylistNotFound)
+}
+
+func TestPlaylistFileService_FormatByExtension(t *testing.T) {
+	env := newTestPlaylistFileService(t)
+	track := createTestTrack("1", "Song", "/music/song.mp3")
+	require.NoError(t, env.library.SaveTracks([]domain.MusicTrack{track}))
+	require.NoError(t, env.playlist.AddTracks([]domain.MusicTrack{track}, false))
+
+	assert.Equal(t, []string{".m3u", ".m3u8", ".pls"}, env.service.SupportedExtensions())
+
+	require.NoError(t, env.service.ExportQueue("/lists/Queue.PLS", false))
+	assert.Len(t, env.pls.files["/lists/Queue.PLS"], 1)
+	assert.Empty(t, env.format.files)
+
+	result, err := env.service.ImportAsPlaylist("/lists/Queue.PLS", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Queue", result.Name)
+	assert.Len(t, result.Tracks, 1)
+}
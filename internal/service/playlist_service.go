@@ -2,8 +2,14 @@
 package service
 
 import (
+	"fmt"
 	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/tejashwikalptaru/gotune/internal/domain"
 	"github.com/tejashwikalptaru/gotune/internal/ports"
@@ -23,12 +29,17 @@ type PlaylistService struct {
 	// State
 	queue        []domain.MusicTrack
 	currentIndex int
+	shuffle      domain.ShuffleState
+	edits        *queueHistory // Queue edits that can be undone
+	upNext       []domain.MusicTrack
+	upNextTrack  string // File path of the playing Up Next track, empty when playing from the queue
 
 	// Concurrency control
 	mu sync.RWMutex
 
-	// Event subscription
-	autoNextSub domain.SubscriptionID
+	// Event subscriptions
+	autoNextSub     domain.SubscriptionID
+	trackUpdatedSub domain.SubscriptionID
 }
 
 // NewPlaylistService creates a new playlist service.
@@ -46,7 +57,9 @@ func NewPlaylistService(
 		history:      history,
 		bus:          bus,
 		queue:        make([]domain.MusicTrack, 0),
+		upNext:       make([]domain.MusicTrack, 0),
 		currentIndex: -1,
+		edits:        newQueueHistory(DefaultUndoDepth),
 	}
 
 	logger.Debug("playlist service initialized")
@@ -54,6 +67,9 @@ func NewPlaylistService(
 	// Subscribe to auto-next events from the playback service
 	service.autoNextSub = bus.Subscribe(domain.EventAutoNext, service.handleAutoNext)
 
+	// Subscribe to tag edits so queued tracks show the new metadata
+	service.trackUpdatedSub = bus.Subscribe(domain.EventTrackUpdated, service.handleTrackUpdated)
+
 	return service
 }
 
@@ -85,10 +101,12 @@ func (s *PlaylistService) AddTrack(track domain.MusicTrack, playImmediately bool
 
 	// Publish TrackAdded event
 	s.bus.Publish(domain.NewTrackAddedEvent(track, newIndex))
+	s.recordAddInternal("Add Track", newIndex, []domain.MusicTrack{track})
 
 	// Play immediately if requested
 	if playImmediately {
 		s.currentIndex = newIndex
+		s.upNextTrack = ""
 		if err := s.playback.LoadTrack(track, newIndex); err != nil {
 			return err
 		}
@@ -96,11 +114,11 @@ func (s *PlaylistService) AddTrack(track domain.MusicTrack, playImmediately bool
 			return err
 		}
 		// Publish playlist updated event with a NEW index
-		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 		return nil
 	} else {
 		// Not playing immediately, publish with an unchanged index
-		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 		return nil
 	}
 }
@@ -115,31 +133,19 @@ func (s *PlaylistService) AddTracks(tracks []domain.MusicTrack, playFirst bool)
 		return nil
 	}
 
-	// Filter out duplicate tracks
-	uniqueTracks := make([]domain.MusicTrack, 0, len(tracks))
-	for _, track := range tracks {
-		if !s.containsFilePath(track.FilePath) {
-			uniqueTracks = append(uniqueTracks, track)
-		}
-	}
+	// Add unique tracks
+	startIndex, uniqueTracks := s.appendTracksInternal(tracks)
 
 	// If all tracks were duplicates, return early
 	if len(uniqueTracks) == 0 {
 		return nil
 	}
-
-	// Add unique tracks
-	startIndex := len(s.queue)
-	s.queue = append(s.queue, uniqueTracks...)
-
-	// Publish events for each added track
-	for i, track := range uniqueTracks {
-		s.bus.Publish(domain.NewTrackAddedEvent(track, startIndex+i))
-	}
+	s.recordAddInternal("Add Tracks", startIndex, uniqueTracks)
 
 	// Play the first track if requested
 	if playFirst && len(uniqueTracks) > 0 {
 		s.currentIndex = startIndex
+		s.upNextTrack = ""
 		if err := s.playback.LoadTrack(uniqueTracks[0], startIndex); err != nil {
 			return err
 		}
@@ -147,15 +153,33 @@ func (s *PlaylistService) AddTracks(tracks []domain.MusicTrack, playFirst bool)
 			return err
 		}
 		// Publish playlist updated event with a NEW index
-		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 		return nil
 	} else {
 		// Not playing, publish with an unchanged index
-		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 		return nil
 	}
 }
 
+// appendTracksInternal appends the tracks that are not in the queue yet
+// and publishes TrackAdded for each of them.
+// Returns the queue index of the first added track and the added tracks.
+// Must be called with mutex lock held.
+func (s *PlaylistService) appendTracksInternal(tracks []domain.MusicTrack) (int, []domain.MusicTrack) {
+	startIndex := len(s.queue)
+	added := make([]domain.MusicTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if s.containsFilePath(track.FilePath) {
+			continue
+		}
+		s.queue = append(s.queue, track)
+		added = append(added, track)
+		s.bus.Publish(domain.NewTrackAddedEvent(track, len(s.queue)-1))
+	}
+	return startIndex, added
+}
+
 // RemoveTrack removes a track at the specified index.
 func (s *PlaylistService) RemoveTrack(index int) error {
 	s.mu.Lock()
@@ -166,10 +190,20 @@ func (s *PlaylistService) RemoveTrack(index int) error {
 	}
 
 	// Remove track
+	refs := s.edits.hold(s.queue[index : index+1])
 	s.queue = append(s.queue[:index], s.queue[index+1:]...)
+	s.recordEditInternal(queueEdit{
+		action: "Remove Track",
+		refs:   refs,
+		undo:   func() { s.insertTracksInternal(index, s.edits.resolve(refs)) },
+		redo:   func() { s.removeTracksInternal(index, 1) },
+	})
 
 	// Adjust the current index if needed
-	if s.currentIndex == index {
+	if s.currentIndex == index && s.upNextTrack != "" {
+		// An Up Next track is playing; the queue continues with the track after the removed one
+		s.currentIndex--
+	} else if s.currentIndex == index {
 		// Stopped playing the removed track
 		if err := s.playback.Stop(); err != nil {
 			s.logger.Warn("failed to stop playback", slog.Any("error", err))
@@ -181,8 +215,71 @@ func (s *PlaylistService) RemoveTrack(index int) error {
 	}
 
 	// Publish event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+
+	return nil
+}
+
+// RemoveTracks removes the tracks at the specified indexes as one change,
+// publishing a single update. Duplicate indexes are ignored.
+// Returns ErrTrackNotFound, and removes nothing, if any index is out of range.
+func (s *PlaylistService) RemoveTracks(indexes []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexes, err := s.queueIndexesInternal(indexes)
+	if err != nil || len(indexes) == 0 {
+		return err
+	}
+
+	removed := make([]domain.MusicTrack, len(indexes))
+	for i, index := range indexes {
+		removed[i] = s.queue[index]
+	}
+	refs := s.edits.hold(removed)
+	// Remove from the end so that the remaining indexes stay valid
+	for i := len(indexes) - 1; i >= 0; i-- {
+		s.removeTracksInternal(indexes[i], 1)
+	}
 
+	action := "Remove Tracks"
+	if len(indexes) == 1 {
+		action = "Remove Track"
+	}
+	s.recordEditInternal(queueEdit{
+		action: action,
+		refs:   refs,
+		undo: func() {
+			for i, index := range indexes {
+				s.insertTracksInternal(index, s.edits.resolve(refs[i:i+1]))
+			}
+		},
+		redo: func() {
+			for i := len(indexes) - 1; i >= 0; i-- {
+				s.removeTracksInternal(indexes[i], 1)
+			}
+		},
+	})
+
+	// Adjust the current index if needed
+	if s.currentIndex >= 0 {
+		position, found := slices.BinarySearch(indexes, s.currentIndex)
+		switch {
+		case found && s.upNextTrack != "":
+			// An Up Next track is playing; the queue continues with the track after the removed ones
+			s.currentIndex -= position + 1
+		case found:
+			// Stopped playing the removed track
+			if err := s.playback.Stop(); err != nil {
+				s.logger.Warn("failed to stop playback", slog.Any("error", err))
+			}
+			s.currentIndex = -1
+		default:
+			s.currentIndex -= position
+		}
+	}
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 	return nil
 }
 
@@ -197,12 +294,21 @@ func (s *PlaylistService) ClearQueue() error {
 	}
 
 	// Clear queue
+	if len(s.queue) > 0 {
+		s.recordReplaceInternal("Clear Queue", s.queue, s.shuffle, nil)
+	}
 	s.queue = make([]domain.MusicTrack, 0)
 	s.currentIndex = -1
 
 	// Publish event
 	s.bus.Publish(domain.NewQueueChangedEvent(s.queue))
 
+	// An empty queue has no order to restore
+	if s.shuffle.Mode != domain.ShuffleOff {
+		s.shuffle = domain.ShuffleState{}
+		s.bus.Publish(domain.NewShuffleChangedEvent(domain.ShuffleOff))
+	}
+
 	return nil
 }
 
@@ -216,11 +322,12 @@ func (s *PlaylistService) PlayTrackAt(index int) error {
 	}
 
 	s.currentIndex = index
+	s.upNextTrack = ""
 	track := s.queue[index]
 
 	// Load and play
 	if err := s.playback.LoadTrack(track, index); err != nil {
-		return err
+		return s.handleLoadFailure(index, err)
 	}
 
 	if err := s.playback.Play(); err != nil {
@@ -228,7 +335,7 @@ func (s *PlaylistService) PlayTrackAt(index int) error {
 	}
 
 	// Publish playlist updated event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 
 	return nil
 }
@@ -253,11 +360,12 @@ func (s *PlaylistService) PlayTrackByPath(filePath string) (int, error) {
 	}
 
 	s.currentIndex = index
+	s.upNextTrack = ""
 	track := s.queue[index]
 
 	// Load and play
 	if err := s.playback.LoadTrack(track, index); err != nil {
-		return index, err
+		return index, s.handleLoadFailure(index, err)
 	}
 
 	if err := s.playback.Play(); err != nil {
@@ -267,26 +375,42 @@ func (s *PlaylistService) PlayTrackByPath(filePath string) (int, error) {
 	return index, nil
 }
 
-// PlayNext plays the next track in the queue.
+// PlayNext plays the first Up Next track, or else the next track in the queue
+// (the first one after the last with RepeatAll).
 func (s *PlaylistService) PlayNext() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Up Next is played before the queue
+	if len(s.upNext) > 0 {
+		track := s.popUpNextInternal()
+		if err := s.playback.LoadTrack(track, -1); err != nil {
+			return err
+		}
+		if err := s.playback.Play(); err != nil {
+			return err
+		}
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+		return nil
+	}
+
 	if len(s.queue) == 0 {
 		return domain.ErrQueueEmpty
 	}
 
 	// Check if there's a next track
-	if s.currentIndex >= len(s.queue)-1 {
+	next, ok := s.nextIndex()
+	if !ok {
 		return domain.ErrEndOfQueue
 	}
 
-	s.currentIndex++
+	s.currentIndex = next
+	s.upNextTrack = ""
 	track := s.queue[s.currentIndex]
 
 	// Load and play
 	if err := s.playback.LoadTrack(track, s.currentIndex); err != nil {
-		return err
+		return s.handleLoadFailure(s.currentIndex, err)
 	}
 
 	if err := s.playback.Play(); err != nil {
@@ -294,12 +418,38 @@ func (s *PlaylistService) PlayNext() error {
 	}
 
 	// Publish playlist updated event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 
 	return nil
 }
 
-// PlayPrevious plays the previous track in the queue.
+// nextIndex returns the index of the track after the current one.
+// With RepeatAll the last track is followed by the first.
+// Returns false at the end of the queue. Expects the lock to be held.
+func (s *PlaylistService) nextIndex() (int, bool) {
+	if s.currentIndex < len(s.queue)-1 {
+		return s.currentIndex + 1, true
+	}
+	if len(s.queue) > 0 && s.playback.GetRepeatMode() == domain.RepeatAll {
+		return 0, true
+	}
+	return 0, false
+}
+
+// previousIndex returns the index of the track before the current one.
+// With RepeatAll the first track is preceded by the last.
+// Returns false at the start of the queue. Expects the lock to be held.
+func (s *PlaylistService) previousIndex() (int, bool) {
+	if s.currentIndex > 0 {
+		return s.currentIndex - 1, true
+	}
+	if len(s.queue) > 0 && s.playback.GetRepeatMode() == domain.RepeatAll {
+		return len(s.queue) - 1, true
+	}
+	return 0, false
+}
+
+// PlayPrevious plays the previous track in the queue, or the last one before the first with RepeatAll.
 func (s *PlaylistService) PlayPrevious() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -308,17 +458,23 @@ func (s *PlaylistService) PlayPrevious() error {
 		return domain.ErrQueueEmpty
 	}
 
-	// Check if there's a previous track
-	if s.currentIndex <= 0 {
+	// Check if there's a previous track; after Up Next tracks it is the
+	// queue track they were played after
+	previous, ok := s.previousIndex()
+	if s.upNextTrack != "" && s.currentIndex >= 0 && s.currentIndex < len(s.queue) {
+		previous, ok = s.currentIndex, true
+	}
+	if !ok {
 		return domain.ErrStartOfQueue
 	}
 
-	s.currentIndex--
+	s.currentIndex = previous
+	s.upNextTrack = ""
 	track := s.queue[s.currentIndex]
 
 	// Load and play
 	if err := s.playback.LoadTrack(track, s.currentIndex); err != nil {
-		return err
+		return s.handleLoadFailure(s.currentIndex, err)
 	}
 
 	if err := s.playback.Play(); err != nil {
@@ -326,11 +482,105 @@ func (s *PlaylistService) PlayPrevious() error {
 	}
 
 	// Publish playlist updated event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 
 	return nil
 }
 
+// handleLoadFailure marks the track at index as missing if its file no longer exists.
+// Returns domain.ErrFileNotFound for missing files, or the original error.
+// Must be called with mutex lock held.
+func (s *PlaylistService) handleLoadFailure(index int, err error) error {
+	if _, statErr := os.Stat(domain.PhysicalPath(s.queue[index].FilePath)); !os.IsNotExist(statErr) {
+		return err
+	}
+
+	s.logger.Warn("queued file is missing", slog.String("path", s.queue[index].FilePath))
+	if !s.queue[index].Missing {
+		s.queue[index].Missing = true
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	}
+	return domain.ErrFileNotFound
+}
+
+// UpdateTracks applies update to every track in the queue and Up Next.
+// update returns the new track and true if it changed the track; queue IDs are kept.
+// The original order of a shuffled queue follows tracks whose path changed.
+// If any track changed, the queue, Up Next and shuffle state are saved to the history repository.
+// Returns the number of changed tracks.
+func (s *PlaylistService) UpdateTracks(update func(domain.MusicTrack) (domain.MusicTrack, bool)) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	movedPaths := make(map[string]string) // New path by old path
+	queueChanged := updateTrackList(s.queue, update, movedPaths)
+	upNextChanged := updateTrackList(s.upNext, update, movedPaths)
+
+	// Tracks that undo or redo would put back change too
+	s.edits.updateTracks(update, movedPaths)
+	s.edits.recordMoves(movedPaths)
+
+	if queueChanged == 0 && upNextChanged == 0 {
+		return 0
+	}
+
+	// The playing Up Next track is known by its path
+	if newPath, ok := movedPaths[s.upNextTrack]; ok {
+		s.upNextTrack = newPath
+	}
+
+	if queueChanged > 0 {
+		if err := s.history.SaveQueue(s.queue); err != nil {
+			s.logger.Warn("failed to save updated queue", slog.Any("error", err))
+		}
+	}
+	if s.shuffle.Mode != domain.ShuffleOff && len(movedPaths) > 0 {
+		// Replace the slice; undo edits may share the old one
+		order := slices.Clone(s.shuffle.OriginalOrder)
+		for i, path := range order {
+			if newPath, ok := movedPaths[path]; ok {
+				order[i] = newPath
+			}
+		}
+		s.shuffle.OriginalOrder = order
+		if err := s.history.SaveShuffleState(s.shuffle); err != nil {
+			s.logger.Warn("failed to save updated shuffle state", slog.Any("error", err))
+		}
+	}
+	if upNextChanged > 0 {
+		if err := s.history.SaveUpNext(s.upNext); err != nil {
+			s.logger.Warn("failed to save updated Up Next", slog.Any("error", err))
+		}
+	}
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+
+	return queueChanged + upNextChanged
+}
+
+// updateTrackList applies update to tracks in place, keeping their IDs,
+// and adds the paths that changed to movedPaths.
+// Returns the number of changed tracks.
+func updateTrackList(
+	tracks []domain.MusicTrack,
+	update func(domain.MusicTrack) (domain.MusicTrack, bool),
+	movedPaths map[string]string,
+) int {
+	changed := 0
+	for i := range tracks {
+		track, ok := update(tracks[i])
+		if !ok {
+			continue
+		}
+		if track.FilePath != tracks[i].FilePath {
+			movedPaths[tracks[i].FilePath] = track.FilePath
+		}
+		track.ID = tracks[i].ID
+		tracks[i] = track
+		changed++
+	}
+	return changed
+}
+
 // GetQueue returns a copy of the current queue.
 func (s *PlaylistService) GetQueue() []domain.MusicTrack {
 	s.mu.RLock()
@@ -358,7 +608,7 @@ func (s *PlaylistService) GetQueueLength() int {
 	return len(s.queue)
 }
 
-// SaveQueue saves the current queue to the history repository.
+// SaveQueue saves the current queue and Up Next to the history repository.
 func (s *PlaylistService) SaveQueue() error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -367,14 +617,22 @@ func (s *PlaylistService) SaveQueue() error {
 		return err
 	}
 
+	if err := s.history.SaveUpNext(s.upNext); err != nil {
+		return err
+	}
+
 	if err := s.history.SaveCurrentIndex(s.currentIndex); err != nil {
 		return err
 	}
 
+	if err := s.history.SaveShuffleState(s.shuffle); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// LoadQueue loads the queue from the history repository.
+// LoadQueue loads the queue and Up Next from the history repository.
 func (s *PlaylistService) LoadQueue() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -385,6 +643,13 @@ func (s *PlaylistService) LoadQueue() error {
 		return err
 	}
 
+	// Load Up Next
+	upNext, err := s.history.LoadUpNext()
+	if err != nil {
+		s.logger.Warn("ignoring saved Up Next", slog.Any("error", err))
+		upNext = make([]domain.MusicTrack, 0)
+	}
+
 	// Load the current index
 	index, err := s.history.LoadCurrentIndex()
 	if err != nil {
@@ -392,12 +657,25 @@ func (s *PlaylistService) LoadQueue() error {
 		index = -1
 	}
 
-	// Update state
+	// Load the shuffle state
+	shuffle, err := s.history.LoadShuffleState()
+	if err != nil || !shuffle.Mode.IsValid() {
+		s.logger.Warn("ignoring saved shuffle state", slog.Any("error", err))
+		shuffle = domain.ShuffleState{}
+	}
+
+	// Update state; edits of the previous queue cannot be undone
 	s.queue = queue
+	s.upNext = upNext
+	s.upNextTrack = ""
 	s.currentIndex = index
+	s.shuffle = shuffle
+	s.edits.clear()
+	s.publishHistoryInternal()
 
-	// Publish event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	// Publish events
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	s.bus.Publish(domain.NewShuffleChangedEvent(s.shuffle.Mode))
 
 	return nil
 }
@@ -419,12 +697,12 @@ func (s *PlaylistService) MoveTrack(fromIndex, toIndex int) error {
 		return nil
 	}
 
-	// Remove track from the old position
-	track := s.queue[fromIndex]
-	s.queue = append(s.queue[:fromIndex], s.queue[fromIndex+1:]...)
-
-	// Insert at a new position
-	s.queue = append(s.queue[:toIndex], append([]domain.MusicTrack{track}, s.queue[toIndex:]...)...)
+	s.moveTrackInternal(fromIndex, toIndex)
+	s.recordEditInternal(queueEdit{
+		action: "Move Track",
+		undo:   func() { s.moveTrackInternal(toIndex, fromIndex) },
+		redo:   func() { s.moveTrackInternal(fromIndex, toIndex) },
+	})
 
 	// Adjust the current index if needed
 	switch {
@@ -437,24 +715,766 @@ func (s *PlaylistService) MoveTrack(fromIndex, toIndex int) error {
 	}
 
 	// Publish event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+
+	return nil
+}
+
+// MoveTracks moves the tracks at the specified indexes, keeping their order,
+// so that they form a block starting at toIndex in the resulting queue.
+// It is one change that publishes a single update. Duplicate indexes are ignored.
+// Returns ErrTrackNotFound if an index is out of range or the block would not fit at toIndex.
+func (s *PlaylistService) MoveTracks(indexes []int, toIndex int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexes, err := s.queueIndexesInternal(indexes)
+	if err != nil || len(indexes) == 0 {
+		return err
+	}
+	if toIndex < 0 || toIndex > len(s.queue)-len(indexes) {
+		return domain.ErrTrackNotFound
+	}
+
+	before := queuePaths(s.queue)
+	current := ""
+	if s.currentIndex >= 0 && s.currentIndex < len(s.queue) {
+		current = s.queue[s.currentIndex].FilePath
+	}
+
+	moved := make([]domain.MusicTrack, 0, len(indexes))
+	rest := make([]domain.MusicTrack, 0, len(s.queue)-len(indexes))
+	for i, track := range s.queue {
+		if _, found := slices.BinarySearch(indexes, i); found {
+			moved = append(moved, track)
+		} else {
+			rest = append(rest, track)
+		}
+	}
+	queue := slices.Concat(rest[:toIndex], moved, rest[toIndex:])
+	if slices.Equal(before, queuePaths(queue)) {
+		return nil // Already in place
+	}
+	s.queue = queue
+
+	action := "Move Tracks"
+	if len(indexes) == 1 {
+		action = "Move Track"
+	}
+	s.recordReorderInternal(action, before, s.shuffle)
+
+	// Follow the current track to its new position
+	if current != "" {
+		s.currentIndex = slices.IndexFunc(s.queue, func(track domain.MusicTrack) bool { return track.FilePath == current })
+	}
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	return nil
+}
+
+// queueIndexesInternal returns the indexes sorted and without duplicates.
+// Returns ErrTrackNotFound if any index is out of range.
+// Must be called with mutex lock held.
+func (s *PlaylistService) queueIndexesInternal(indexes []int) ([]int, error) {
+	for _, index := range indexes {
+		if index < 0 || index >= len(s.queue) {
+			return nil, domain.ErrTrackNotFound
+		}
+	}
+	indexes = slices.Clone(indexes)
+	slices.Sort(indexes)
+	return slices.Compact(indexes), nil
+}
+
+// SortBy sorts the queue by a track field. Tracks equal in the field are ordered
+// by album artist, year, album, disc, track number and title, so that e.g. sorting
+// by album artist lists each artist's albums in order. See SortByKeys.
+func (s *PlaylistService) SortBy(field domain.QueueSortField, direction domain.SortDirection) error {
+	return s.SortByKeys(queueSortKeysFor(field, direction)...)
+}
+
+// SortByKeys stably sorts the queue by several keys, comparing by each key in
+// turn until tracks differ. The current track keeps playing at its new position.
+// The sorted order replaces any shuffle, which is turned off.
+func (s *PlaylistService) SortByKeys(keys ...domain.QueueSortKey) error {
+	if err := validateQueueSortKeys(keys); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, beforeShuffle := queuePaths(s.queue), s.shuffle
+	current := ""
+	if s.currentIndex >= 0 && s.currentIndex < len(s.queue) {
+		current = s.queue[s.currentIndex].FilePath
+	}
+
+	queue := slices.Clone(s.queue)
+	sortQueue(queue, keys)
+	if slices.Equal(before, queuePaths(queue)) && s.shuffle.Mode == domain.ShuffleOff {
+		return nil // Already sorted
+	}
+	s.queue = queue
+	s.shuffle = domain.ShuffleState{}
+	s.recordReorderInternal("Sort Queue", before, beforeShuffle)
+
+	// Follow the current track to its new position
+	if current != "" {
+		s.currentIndex = slices.IndexFunc(s.queue, func(track domain.MusicTrack) bool { return track.FilePath == current })
+	}
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	if beforeShuffle.Mode != domain.ShuffleOff {
+		s.bus.Publish(domain.NewShuffleChangedEvent(domain.ShuffleOff))
+	}
+	return nil
+}
+
+// Shuffle randomizes the order of the queue.
+// ShuffleTracks keeps the current track in place and shuffles the others;
+// ShuffleAlbums shuffles whole albums and keeps the track order within them,
+// starting with the album of the current track.
+// The order before the first shuffle is kept, so shuffling again and then
+// calling Unshuffle restores it.
+func (s *PlaylistService) Shuffle(mode domain.ShuffleMode) error {
+	if mode != domain.ShuffleTracks && mode != domain.ShuffleAlbums {
+		return domain.NewValidationError("mode", mode, "must be tracks or albums")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, beforeShuffle := queuePaths(s.queue), s.shuffle
+	if s.shuffle.Mode == domain.ShuffleOff {
+		s.shuffle.OriginalOrder = before
+	}
+	s.shuffle.Mode = mode
+	s.queue, s.currentIndex = shuffleQueue(s.queue, s.currentIndex, mode)
+	s.recordReorderInternal("Shuffle", before, beforeShuffle)
+
+	// Publish events
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	s.bus.Publish(domain.NewShuffleChangedEvent(mode))
+
+	return nil
+}
+
+// Unshuffle restores the order the queue had before it was shuffled.
+// Tracks added since then are kept at the end, and the current track keeps playing.
+func (s *PlaylistService) Unshuffle() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shuffle.Mode == domain.ShuffleOff {
+		return nil
+	}
+
+	before, beforeShuffle := queuePaths(s.queue), s.shuffle
+	s.queue, s.currentIndex = unshuffleQueue(s.queue, s.shuffle.OriginalOrder, s.currentIndex)
+	s.shuffle = domain.ShuffleState{}
+	s.recordReorderInternal("Unshuffle", before, beforeShuffle)
+
+	// Publish events
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	s.bus.Publish(domain.NewShuffleChangedEvent(domain.ShuffleOff))
+
+	return nil
+}
+
+// GetShuffleMode returns how the queue is shuffled.
+func (s *PlaylistService) GetShuffleMode() domain.ShuffleMode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.shuffle.Mode
+}
+
+// AddToUpNext adds tracks to the end of Up Next, which is played after the
+// current track and before the rest of the queue. Tracks already in Up Next are skipped.
+func (s *PlaylistService) AddToUpNext(tracks []domain.MusicTrack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := 0
+	for _, track := range tracks {
+		if slices.ContainsFunc(s.upNext, func(t domain.MusicTrack) bool { return t.FilePath == track.FilePath }) {
+			continue
+		}
+		s.upNext = append(s.upNext, track)
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	return nil
+}
+
+// RemoveFromUpNext removes the Up Next track at the specified index.
+func (s *PlaylistService) RemoveFromUpNext(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index < 0 || index >= len(s.upNext) {
+		return domain.ErrTrackNotFound
+	}
+	s.upNext = slices.Delete(s.upNext, index, index+1)
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	return nil
+}
+
+// ClearUpNext removes all tracks from Up Next. The queue is not changed.
+func (s *PlaylistService) ClearUpNext() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.upNext) == 0 {
+		return
+	}
+	s.upNext = make([]domain.MusicTrack, 0)
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+}
+
+// GetUpNext returns a copy of the Up Next tracks.
+func (s *PlaylistService) GetUpNext() []domain.MusicTrack {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.upNext)
+}
+
+// popUpNextInternal removes the first Up Next track and marks it as playing.
+// The current queue index is kept so that the queue continues after it.
+// Must be called with mutex lock held and a non-empty Up Next.
+func (s *PlaylistService) popUpNextInternal() domain.MusicTrack {
+	track := s.upNext[0]
+	s.upNext = slices.Clone(s.upNext[1:])
+	s.upNextTrack = track.FilePath
+	return track
+}
+
+// Undo reverts the most recent queue edit (adding, removing, moving, clearing,
+// shuffling or loading a playlist). The current track keeps playing if it is
+// still in the queue. Returns ErrNothingToUndo if there is no edit to revert.
+func (s *PlaylistService) Undo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	edit, ok := s.edits.popUndo()
+	if !ok {
+		return domain.ErrNothingToUndo
+	}
+	s.applyEditInternal(edit.undo)
+	s.publishHistoryInternal()
+
+	s.logger.Debug("queue edit undone", slog.String("action", edit.action))
+	return nil
+}
+
+// Redo makes the most recently undone queue edit again.
+// Returns ErrNothingToRedo if no edit was undone since the last edit.
+func (s *PlaylistService) Redo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	edit, ok := s.edits.popRedo()
+	if !ok {
+		return domain.ErrNothingToRedo
+	}
+	s.applyEditInternal(edit.redo)
+	s.publishHistoryInternal()
+
+	s.logger.Debug("queue edit redone", slog.String("action", edit.action))
+	return nil
+}
 
+// GetUndoActions returns the actions that Undo and Redo would revert and make again,
+// e.g. "Remove Track". An empty action means there is nothing to undo or redo.
+func (s *PlaylistService) GetUndoActions() (string, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.edits.actions()
+}
+
+// SetUndoDepth sets how many queue edits can be undone (1 to MaxUndoDepth).
+// The oldest edits are forgotten if more are kept.
+func (s *PlaylistService) SetUndoDepth(depth int) error {
+	if depth < 1 || depth > MaxUndoDepth {
+		return domain.NewValidationError("depth", depth, fmt.Sprintf("must be between 1 and %d", MaxUndoDepth))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.edits.setDepth(depth)
+	s.publishHistoryInternal()
+	return nil
+}
+
+// recordEditInternal adds an edit that was just made to the undo history.
+// Must be called with mutex lock held.
+func (s *PlaylistService) recordEditInternal(edit queueEdit) {
+	s.edits.record(edit)
+	s.publishHistoryInternal()
+}
+
+// recordAddInternal records that tracks were inserted at index.
+// Must be called with mutex lock held.
+func (s *PlaylistService) recordAddInternal(action string, index int, tracks []domain.MusicTrack) {
+	refs := s.edits.hold(tracks)
+	s.recordEditInternal(queueEdit{
+		action: action,
+		refs:   refs,
+		undo:   func() { s.removeTracksInternal(index, len(refs)) },
+		redo:   func() { s.insertTracksInternal(index, s.edits.resolve(refs)) },
+	})
+}
+
+// recordReplaceInternal records that the queue was replaced by after.
+// The shuffle state of the new queue is off.
+// Must be called with mutex lock held.
+func (s *PlaylistService) recordReplaceInternal(action string, before []domain.MusicTrack, beforeShuffle domain.ShuffleState, after []domain.MusicTrack) {
+	beforeRefs := s.edits.hold(before)
+	afterRefs := s.edits.hold(after)
+	s.recordEditInternal(queueEdit{
+		action: action,
+		refs:   append(slices.Clone(beforeRefs), afterRefs...),
+		undo: func() {
+			s.queue = s.edits.resolve(beforeRefs)
+			s.shuffle = s.edits.currentShuffle(beforeShuffle)
+		},
+		redo: func() {
+			s.queue = s.edits.resolve(afterRefs)
+			s.shuffle = domain.ShuffleState{}
+		},
+	})
+}
+
+// recordReorderInternal records that the queue, whose file paths were before,
+// was put in a new order. Only the order is kept, not the tracks.
+// Must be called with mutex lock held.
+func (s *PlaylistService) recordReorderInternal(action string, before []string, beforeShuffle domain.ShuffleState) {
+	order := queuePermutation(before, s.queue)
+	afterShuffle := s.shuffle
+	s.recordEditInternal(queueEdit{
+		action: action,
+		undo: func() {
+			s.queue = permuteQueue(s.queue, invertPermutation(order))
+			s.shuffle = s.edits.currentShuffle(beforeShuffle)
+		},
+		redo: func() {
+			s.queue = permuteQueue(s.queue, order)
+			s.shuffle = s.edits.currentShuffle(afterShuffle)
+		},
+	})
+}
+
+// applyEditInternal undoes or redoes an edit and publishes the new queue.
+// The current track stays current if it is still in the queue; otherwise playback stops.
+// Must be called with mutex lock held.
+func (s *PlaylistService) applyEditInternal(apply func()) {
+	current := ""
+	if s.currentIndex >= 0 && s.currentIndex < len(s.queue) {
+		current = s.queue[s.currentIndex].FilePath
+	}
+	shuffleMode := s.shuffle.Mode
+
+	apply()
+
+	s.currentIndex = -1
+	for i, track := range s.queue {
+		if current != "" && track.FilePath == current {
+			s.currentIndex = i
+			break
+		}
+	}
+	if current != "" && s.currentIndex == -1 && s.upNextTrack == "" {
+		if err := s.playback.Stop(); err != nil {
+			s.logger.Warn("failed to stop playback", slog.Any("error", err))
+		}
+	}
+
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	if s.shuffle.Mode != shuffleMode {
+		s.bus.Publish(domain.NewShuffleChangedEvent(s.shuffle.Mode))
+	}
+}
+
+// publishHistoryInternal publishes what can be undone and redone.
+// Must be called with mutex lock held.
+func (s *PlaylistService) publishHistoryInternal() {
+	undoAction, redoAction := s.edits.actions()
+	s.bus.Publish(domain.NewQueueHistoryEvent(undoAction, redoAction))
+}
+
+// insertTracksInternal inserts tracks into the queue at index.
+// Must be called with mutex lock held.
+func (s *PlaylistService) insertTracksInternal(index int, tracks []domain.MusicTrack) {
+	s.queue = slices.Insert(s.queue, min(index, len(s.queue)), tracks...)
+}
+
+// removeTracksInternal removes count tracks from the queue starting at index.
+// Must be called with mutex lock held.
+func (s *PlaylistService) removeTracksInternal(index, count int) {
+	end := min(index+count, len(s.queue))
+	if index < end {
+		s.queue = slices.Delete(s.queue, index, end)
+	}
+}
+
+// moveTrackInternal moves the track at fromIndex to toIndex without adjusting the current index.
+// Must be called with mutex lock held.
+func (s *PlaylistService) moveTrackInternal(fromIndex, toIndex int) {
+	track := s.queue[fromIndex]
+	s.queue = slices.Delete(s.queue, fromIndex, fromIndex+1)
+	s.queue = slices.Insert(s.queue, toIndex, track)
+}
+
+// CreatePlaylist saves tracks as a new named playlist.
+func (s *PlaylistService) CreatePlaylist(name string, tracks []domain.MusicTrack) (*domain.Playlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", name, "cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.createPlaylistInternal(name, tracks, "CreatePlaylist")
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(domain.NewPlaylistCreatedEvent(playlist))
+	return playlist, nil
+}
+
+// SaveQueueAsPlaylist saves the tracks of the queue as a new named playlist.
+func (s *PlaylistService) SaveQueueAsPlaylist(name string) (*domain.Playlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", name, "cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.createPlaylistInternal(name, s.queue, "SaveQueueAsPlaylist")
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(domain.NewQueueSavedAsPlaylistEvent(playlist))
+	return playlist, nil
+}
+
+// RenamePlaylist changes the name of a named playlist.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID.
+func (s *PlaylistService) RenamePlaylist(id, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.NewValidationError("name", name, "cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.repository.Load(id)
+	if err != nil {
+		return err
+	}
+	if playlist.Name == name {
+		return nil
+	}
+
+	oldName := playlist.Name
+	playlist.Name = name
+	if err := s.savePlaylistInternal(playlist, "RenamePlaylist"); err != nil {
+		return err
+	}
+
+	s.bus.Publish(domain.NewPlaylistRenamedEvent(playlist, oldName))
+	return nil
+}
+
+// DuplicatePlaylist copies a named playlist, including the rules of a smart playlist.
+// An empty name uses the name of the original with " (copy)" appended.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID.
+func (s *PlaylistService) DuplicatePlaylist(id, name string) (*domain.Playlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.repository.Load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	now := time.Now()
+	playlist := &domain.Playlist{
+		ID:        generatePlaylistID(),
+		Name:      name,
+		Tracks:    slices.Clone(source.Tracks),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if source.Smart != nil {
+		rules := *source.Smart
+		rules.Rules = slices.Clone(source.Smart.Rules)
+		playlist.Smart = &rules
+	}
+	if err := s.repository.Save(playlist); err != nil {
+		return nil, domain.NewServiceError("PlaylistService", "DuplicatePlaylist", "failed to save playlist", err)
+	}
+
+	s.bus.Publish(domain.NewPlaylistDuplicatedEvent(playlist, source.ID))
+	return playlist, nil
+}
+
+// DeletePlaylist removes a named playlist. The queue is not changed.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID.
+func (s *PlaylistService) DeletePlaylist(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.repository.Load(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.Delete(id); err != nil {
+		return domain.NewServiceError("PlaylistService", "DeletePlaylist", "failed to delete playlist", err)
+	}
+
+	s.bus.Publish(domain.NewPlaylistDeletedEvent(playlist.ID, playlist.Name))
+	return nil
+}
+
+// LoadPlaylist puts the tracks of a named playlist into the queue.
+// replace: stop playback and replace the queue; otherwise the tracks are appended.
+// Tracks already in the queue are skipped.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID.
+func (s *PlaylistService) LoadPlaylist(id string, replace bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.repository.Load(id)
+	if err != nil {
+		return err
+	}
+
+	before, beforeShuffle := s.queue, s.shuffle
+	if replace {
+		if err := s.playback.Stop(); err != nil {
+			s.logger.Warn("failed to stop playback", slog.Any("error", err))
+		}
+		s.queue = make([]domain.MusicTrack, 0, len(playlist.Tracks))
+		s.currentIndex = -1
+		s.bus.Publish(domain.NewQueueChangedEvent(s.queue))
+
+		// The new queue has no order to restore
+		if s.shuffle.Mode != domain.ShuffleOff {
+			s.shuffle = domain.ShuffleState{}
+			s.bus.Publish(domain.NewShuffleChangedEvent(domain.ShuffleOff))
+		}
+	}
+
+	startIndex, added := s.appendTracksInternal(playlist.Tracks)
+	if replace {
+		s.recordReplaceInternal("Load Playlist", before, beforeShuffle, s.queue)
+	} else if len(added) > 0 {
+		s.recordAddInternal("Load Playlist", startIndex, added)
+	}
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistLoadedEvent(playlist, replace))
+
+	s.logger.Info("playlist loaded",
+		slog.String("name", playlist.Name),
+		slog.Int("tracks", len(playlist.Tracks)),
+		slog.Bool("replace", replace))
+	return nil
+}
+
+// AddTracksToPlaylist appends tracks to a named playlist without changing the queue.
+// Tracks already in the playlist are skipped.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID, or a
+// ValidationError for smart playlists, whose tracks follow their rules.
+func (s *PlaylistService) AddTracksToPlaylist(id string, tracks []domain.MusicTrack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addTracksToPlaylistInternal(id, tracks, "AddTracksToPlaylist")
+}
+
+// CopyTracksToPlaylist adds the queue tracks at the specified indexes to the
+// end of a saved playlist, in queue order. Tracks already in the playlist are skipped.
+// Returns ErrTrackNotFound, and copies nothing, if any index is out of range.
+func (s *PlaylistService) CopyTracksToPlaylist(indexes []int, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexes, err := s.queueIndexesInternal(indexes)
+	if err != nil {
+		return err
+	}
+	tracks := make([]domain.MusicTrack, len(indexes))
+	for i, index := range indexes {
+		tracks[i] = s.queue[index]
+	}
+
+	return s.addTracksToPlaylistInternal(id, tracks, "CopyTracksToPlaylist")
+}
+
+// addTracksToPlaylistInternal adds the tracks that are not in a saved playlist yet to its end.
+// Must be called with mutex lock held.
+func (s *PlaylistService) addTracksToPlaylistInternal(id string, tracks []domain.MusicTrack, op string) error {
+	playlist, err := s.loadStaticPlaylistInternal(id)
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[string]bool, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		paths[track.FilePath] = true
+	}
+	added := make([]domain.MusicTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if !paths[track.FilePath] {
+			paths[track.FilePath] = true
+			added = append(added, track)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	playlist.Tracks = append(playlist.Tracks, added...)
+	if err := s.savePlaylistInternal(playlist, op); err != nil {
+		return err
+	}
+
+	s.bus.Publish(domain.NewPlaylistTracksAddedEvent(playlist, added))
 	return nil
 }
 
-// Shuffle randomizes the order of tracks in the queue (except currently playing).
-func (s *PlaylistService) Shuffle() error {
+// RemoveTracksFromPlaylist removes the tracks at the given indexes from a named
+// playlist without changing the queue.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID, ErrInvalidIndex
+// if an index is out of range, or a ValidationError for smart playlists.
+func (s *PlaylistService) RemoveTracksFromPlaylist(id string, indexes []int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(s.queue) <= 1 {
+	playlist, err := s.loadStaticPlaylistInternal(id)
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= len(playlist.Tracks) {
+			return domain.ErrInvalidIndex
+		}
+		remove[index] = true
+	}
+	if len(remove) == 0 {
 		return nil
 	}
 
-	// TODO: Implement shuffle algorithm
-	// For now, just publish update event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	kept := make([]domain.MusicTrack, 0, len(playlist.Tracks)-len(remove))
+	removed := make([]domain.MusicTrack, 0, len(remove))
+	for i, track := range playlist.Tracks {
+		if remove[i] {
+			removed = append(removed, track)
+		} else {
+			kept = append(kept, track)
+		}
+	}
+
+	playlist.Tracks = kept
+	if err := s.savePlaylistInternal(playlist, "RemoveTracksFromPlaylist"); err != nil {
+		return err
+	}
 
+	s.bus.Publish(domain.NewPlaylistTracksRemovedEvent(playlist, removed))
+	return nil
+}
+
+// GetPlaylist returns a named playlist.
+// Returns ErrPlaylistNotFound if there is no playlist with the ID.
+func (s *PlaylistService) GetPlaylist(id string) (*domain.Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.repository.Load(id)
+}
+
+// GetPlaylists returns the named playlists, including smart playlists, sorted by name.
+func (s *PlaylistService) GetPlaylists() ([]*domain.Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	playlists, err := s.repository.LoadAll()
+	if err != nil {
+		return nil, domain.NewServiceError("PlaylistService", "GetPlaylists", "failed to load playlists", err)
+	}
+	sort.SliceStable(playlists, func(i, j int) bool {
+		return strings.ToLower(playlists[i].Name) < strings.ToLower(playlists[j].Name)
+	})
+	return playlists, nil
+}
+
+// createPlaylistInternal saves a copy of tracks as a new playlist.
+// Must be called with mutex lock held.
+func (s *PlaylistService) createPlaylistInternal(name string, tracks []domain.MusicTrack, op string) (*domain.Playlist, error) {
+	now := time.Now()
+	playlist := &domain.Playlist{
+		ID:        generatePlaylistID(),
+		Name:      name,
+		Tracks:    slices.Clone(tracks),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if playlist.Tracks == nil {
+		playlist.Tracks = make([]domain.MusicTrack, 0)
+	}
+
+	if err := s.repository.Save(playlist); err != nil {
+		return nil, domain.NewServiceError("PlaylistService", op, "failed to save playlist", err)
+	}
+
+	s.logger.Info("playlist created", slog.String("name", name), slog.Int("tracks", len(playlist.Tracks)))
+	return playlist, nil
+}
+
+// loadStaticPlaylistInternal loads a playlist whose tracks can be edited.
+// Must be called with mutex lock held.
+func (s *PlaylistService) loadStaticPlaylistInternal(id string) (*domain.Playlist, error) {
+	playlist, err := s.repository.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.IsSmart() {
+		return nil, domain.NewValidationError("id", id, "the tracks of a smart playlist follow its rules")
+	}
+	return playlist, nil
+}
+
+// savePlaylistInternal saves a changed playlist.
+// Must be called with mutex lock held.
+func (s *PlaylistService) savePlaylistInternal(playlist *domain.Playlist, op string) error {
+	playlist.UpdatedAt = time.Now()
+	if err := s.repository.Save(playlist); err != nil {
+		return domain.NewServiceError("PlaylistService", op, "failed to save playlist", err)
+	}
 	return nil
 }
 
@@ -468,13 +1488,39 @@ func (s *PlaylistService) handleAutoNext(event domain.Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Verify the event is for the current track
-	if autoNextEvent.CurrentIndex != s.currentIndex {
+	// Verify the event is for the current track, which may have moved
+	// in the queue since it was loaded (e.g. by shuffling)
+	if s.upNextTrack != "" {
+		if autoNextEvent.Track.FilePath != s.upNextTrack {
+			return
+		}
+	} else if autoNextEvent.CurrentIndex != s.currentIndex &&
+		(s.currentIndex < 0 || s.currentIndex >= len(s.queue) || s.queue[s.currentIndex].FilePath != autoNextEvent.Track.FilePath) {
+		return
+	}
+
+	// Up Next is played before the queue
+	if len(s.upNext) > 0 {
+		track := s.popUpNextInternal()
+		upNext := s.upNext
+
+		s.mu.Unlock()
+		if err := s.playback.LoadTrack(track, -1); err != nil {
+			s.logger.Warn("failed to load up next track", slog.Any("error", err))
+			s.mu.Lock()
+			return
+		}
+		if err := s.playback.Play(); err != nil {
+			s.logger.Warn("failed to play up next track", slog.Any("error", err))
+		}
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, upNext, s.currentIndex))
+		s.mu.Lock()
 		return
 	}
 
 	// Check if there's a next track
-	if s.currentIndex >= len(s.queue)-1 {
+	next, ok := s.nextIndex()
+	if !ok {
 		// End of queue - stop playback to clean up state
 		s.mu.Unlock()
 		if err := s.playback.Stop(); err != nil {
@@ -485,7 +1531,8 @@ func (s *PlaylistService) handleAutoNext(event domain.Event) {
 	}
 
 	// Play the next track
-	s.currentIndex++
+	s.currentIndex = next
+	s.upNextTrack = ""
 	track := s.queue[s.currentIndex]
 
 	// Load and play (unlock first to avoid deadlock)
@@ -500,11 +1547,50 @@ func (s *PlaylistService) handleAutoNext(event domain.Event) {
 	}
 
 	// Publish playlist updated event
-	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.currentIndex))
+	s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
 
 	s.mu.Lock()
 }
 
+// handleTrackUpdated is called when a track's tags were edited.
+// Queue entries for the same file are replaced, keeping their queue IDs.
+func (s *PlaylistService) handleTrackUpdated(event domain.Event) {
+	updatedEvent, ok := event.(domain.TrackUpdatedEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for i := range s.queue {
+		if s.queue[i].FilePath != updatedEvent.Track.FilePath {
+			continue
+		}
+		track := updatedEvent.Track
+		track.ID = s.queue[i].ID
+		s.queue[i] = track
+		changed = true
+	}
+	for i := range s.upNext {
+		if s.upNext[i].FilePath != updatedEvent.Track.FilePath {
+			continue
+		}
+		track := updatedEvent.Track
+		track.ID = s.upNext[i].ID
+		s.upNext[i] = track
+		changed = true
+	}
+	s.edits.updateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		return updatedEvent.Track, track.FilePath == updatedEvent.Track.FilePath
+	}, make(map[string]string))
+
+	if changed {
+		s.bus.Publish(domain.NewPlaylistUpdatedEvent(s.queue, s.upNext, s.currentIndex))
+	}
+}
+
 // Shutdown cleans up resources.
 func (s *PlaylistService) Shutdown() error {
 	s.mu.Lock()
@@ -512,14 +1598,21 @@ func (s *PlaylistService) Shutdown() error {
 
 	// Unsubscribe from events
 	s.bus.Unsubscribe(s.autoNextSub)
+	s.bus.Unsubscribe(s.trackUpdatedSub)
 
 	// Save queue before shutdown (the best effort)
 	if err := s.history.SaveQueue(s.queue); err != nil {
 		s.logger.Warn("failed to save queue on shutdown", slog.Any("error", err))
 	}
+	if err := s.history.SaveUpNext(s.upNext); err != nil {
+		s.logger.Warn("failed to save Up Next on shutdown", slog.Any("error", err))
+	}
 	if err := s.history.SaveCurrentIndex(s.currentIndex); err != nil {
 		s.logger.Warn("failed to save current index on shutdown", slog.Any("error", err))
 	}
+	if err := s.history.SaveShuffleState(s.shuffle); err != nil {
+		s.logger.Warn("failed to save shuffle state on shutdown", slog.Any("error", err))
+	}
 
 	return nil
 }
@@ -529,6 +1622,7 @@ var _ interface {
 	AddTrack(domain.MusicTrack, bool) error
 	AddTracks([]domain.MusicTrack, bool) error
 	RemoveTrack(int) error
+	RemoveTracks([]int) error
 	ClearQueue() error
 	PlayTrackAt(int) error
 	PlayTrackByPath(string) (int, error)
@@ -540,6 +1634,31 @@ var _ interface {
 	SaveQueue() error
 	LoadQueue() error
 	MoveTrack(int, int) error
-	Shuffle() error
+	MoveTracks([]int, int) error
+	Shuffle(domain.ShuffleMode) error
+	Unshuffle() error
+	SortBy(domain.QueueSortField, domain.SortDirection) error
+	SortByKeys(...domain.QueueSortKey) error
+	AddToUpNext([]domain.MusicTrack) error
+	RemoveFromUpNext(int) error
+	ClearUpNext()
+	GetUpNext() []domain.MusicTrack
+	GetShuffleMode() domain.ShuffleMode
+	Undo() error
+	Redo() error
+	GetUndoActions() (string, string)
+	SetUndoDepth(int) error
+	CreatePlaylist(string, []domain.MusicTrack) (*domain.Playlist, error)
+	SaveQueueAsPlaylist(string) (*domain.Playlist, error)
+	RenamePlaylist(string, string) error
+	DuplicatePlaylist(string, string) (*domain.Playlist, error)
+	DeletePlaylist(string) error
+	LoadPlaylist(string, bool) error
+	AddTracksToPlaylist(string, []domain.MusicTrack) error
+	CopyTracksToPlaylist([]int, string) error
+	RemoveTracksFromPlaylist(string, []int) error
+	GetPlaylist(string) (*domain.Playlist, error)
+	GetPlaylists() ([]*domain.Playlist, error)
+	UpdateTracks(func(domain.MusicTrack) (domain.MusicTrack, bool)) int
 	Shutdown() error
 } = (*PlaylistService)(nil)
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/tejashwikalptaru/gotune/internal/adapter/audio/mock"
 	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
 	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
 )
 
 // Mock repositories for testing
@@ -39,7 +44,7 @@ func (m *mockPlaylistRepository) Load(id string) (*domain.Playlist, error) {
 	defer m.mu.RUnlock()
 	playlist, ok := m.playlists[id]
 	if !ok {
-		return nil, domain.ErrPlaylistEmpty
+		return nil, ports.ErrPlaylistNotFound
 	}
 	return playlist, nil
 }
@@ -71,7 +76,10 @@ func (m *mockPlaylistRepository) Exists(id string) bool {
 type mockHistoryRepository struct {
 	mu           sync.RWMutex
 	queue        []domain.MusicTrack
+	upNext       []domain.MusicTrack
 	currentIndex int
+	shuffle      domain.ShuffleState
+	listens      []domain.ListeningEntry
 }
 
 func newMockHistoryRepository() *mockHistoryRepository {
@@ -94,6 +102,19 @@ func (m *mockHistoryRepository) LoadQueue() ([]domain.MusicTrack, error) {
 	return m.queue, nil
 }
 
+func (m *mockHistoryRepository) SaveUpNext(tracks []domain.MusicTrack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.upNext = tracks
+	return nil
+}
+
+func (m *mockHistoryRepository) LoadUpNext() ([]domain.MusicTrack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.upNext), nil
+}
+
 func (m *mockHistoryRepository) SaveCurrentIndex(index int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -107,11 +128,58 @@ func (m *mockHistoryRepository) LoadCurrentIndex() (int, error) {
 	return m.currentIndex, nil
 }
 
+func (m *mockHistoryRepository) SaveShuffleState(state domain.ShuffleState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shuffle = state
+	return nil
+}
+
+func (m *mockHistoryRepository) LoadShuffleState() (domain.ShuffleState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.shuffle, nil
+}
+
+func (m *mockHistoryRepository) AppendListen(entry domain.ListeningEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listens = append(m.listens, entry)
+	return nil
+}
+
+func (m *mockHistoryRepository) LoadListens() ([]domain.ListeningEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.listens), nil
+}
+
+func (m *mockHistoryRepository) RelocateListens(paths map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, entry := range m.listens {
+		if newPath, ok := paths[entry.FilePath]; ok {
+			m.listens[i] = entry.Relocated(newPath)
+		}
+	}
+	return nil
+}
+
+func (m *mockHistoryRepository) ClearListens() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listens = nil
+	return nil
+}
+
 func (m *mockHistoryRepository) Clear() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.queue = make([]domain.MusicTrack, 0)
+	m.upNext = nil
 	m.currentIndex = -1
+	m.shuffle = domain.ShuffleState{}
+	m.listens = nil
 	return nil
 }
 
@@ -664,6 +732,7 @@ func TestPlaylistService_SaveAndLoadQueue(t *testing.T) {
 	if err := service.PlayTrackAt(1); err != nil {
 		t.Fatalf("Failed to play track: %v", err)
 	}
+	require.NoError(t, service.AddToUpNext([]domain.MusicTrack{createTestTrack("3", "Song 3", "/test/song3.mp3")}))
 
 	// Save queue
 	err := service.SaveQueue()
@@ -698,6 +767,9 @@ func TestPlaylistService_SaveAndLoadQueue(t *testing.T) {
 	assert.Equal(t, "1", queue[0].ID)
 	assert.Equal(t, "2", queue[1].ID)
 	assert.Equal(t, 1, service2.GetCurrentIndex())
+	upNext := service2.GetUpNext()
+	require.Len(t, upNext, 1)
+	assert.Equal(t, "3", upNext[0].ID)
 }
 
 func TestPlaylistService_MoveTrack(t *testing.T) {
@@ -1210,3 +1282,951 @@ func TestPlaylistService_AutoNext_PublishesEvent(t *testing.T) {
 	assert.Equal(t, 1, updatedEvent.Index, "Event should contain correct index after auto-next")
 	assert.Equal(t, 2, len(updatedEvent.Playlist), "Event should contain full playlist")
 }
+
+func TestPlaylistService_TrackUpdated_ReplacesQueueEntry(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+
+	eventReceived := false
+	ts.bus.Subscribe(domain.EventPlaylistUpdated, func(e domain.Event) {
+		eventReceived = true
+	})
+
+	// The edited track comes from the library, so its ID differs from the queue entry
+	edited := createTestTrack("library-id", "Renamed", "/test/song2.mp3")
+	ts.bus.Publish(domain.NewTrackUpdatedEvent(edited))
+
+	queue := ts.playlist.GetQueue()
+	assert.True(t, eventReceived, "EventPlaylistUpdated should be published")
+	assert.Equal(t, "Song 1", queue[0].Title)
+	assert.Equal(t, "Renamed", queue[1].Title)
+	assert.Equal(t, "2", queue[1].ID, "Queue entry should keep its ID")
+
+	// Tracks not in the queue are ignored
+	eventReceived = false
+	ts.bus.Publish(domain.NewTrackUpdatedEvent(createTestTrack("3", "Other", "/test/other.mp3")))
+	assert.False(t, eventReceived)
+}
+
+func TestPlaylistService_PlayTrackAt_MarksMissingFile(t *testing.T) {
+	engine := mock.NewEngine()
+	_ = engine.Initialize(-1, 44100, 0)
+	bus := eventbus.NewSyncEventBus()
+	playback := NewPlaybackService(playlistTestLogger(), engine, bus)
+	defer playback.Shutdown()
+	playlist := NewPlaylistService(playlistTestLogger(), playback, newMockPlaylistRepository(), newMockHistoryRepository(), bus)
+	defer playlist.Shutdown()
+
+	existing := filepath.Join(t.TempDir(), "song.mp3")
+	require.NoError(t, os.WriteFile(existing, []byte("audio"), 0600))
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Gone", filepath.Join(t.TempDir(), "gone.mp3")),
+		createTestTrack("2", "Broken", existing),
+	}
+	require.NoError(t, playlist.AddTracks(tracks, false))
+	engine.SetFailLoad(true)
+
+	err := playlist.PlayTrackAt(0)
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+	assert.True(t, playlist.GetQueue()[0].Missing)
+
+	// Files that exist but fail to load are not marked missing
+	err = playlist.PlayTrackAt(1)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrFileNotFound)
+	assert.False(t, playlist.GetQueue()[1].Missing)
+}
+
+func TestPlaylistService_UpdateTracks(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/old/song1.mp3"),
+		createTestTrack("2", "Song 2", "/other/song2.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+
+	eventCount := 0
+	ts.bus.Subscribe(domain.EventPlaylistUpdated, func(e domain.Event) {
+		eventCount++
+	})
+
+	changed := ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		if !strings.HasPrefix(track.FilePath, "/old/") {
+			return track, false
+		}
+		track.ID = "ignored"
+		track.FilePath = "/new/" + strings.TrimPrefix(track.FilePath, "/old/")
+		return track, true
+	})
+
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, 1, eventCount)
+	queue := ts.playlist.GetQueue()
+	assert.Equal(t, "/new/song1.mp3", queue[0].FilePath)
+	assert.Equal(t, "1", queue[0].ID, "Queue entry should keep its ID")
+	assert.Equal(t, "/other/song2.mp3", queue[1].FilePath)
+
+	// Nothing changed, nothing published
+	assert.Zero(t, ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		return track, false
+	}))
+	assert.Equal(t, 1, eventCount)
+}
+
+func TestPlaylistService_UpdateTracks_UpNext(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+	history := ts.playlist.history.(*mockHistoryRepository)
+
+	require.NoError(t, ts.playlist.AddTracks([]domain.MusicTrack{createTestTrack("1", "Song 1", "/test/song1.mp3")}, true))
+	upNext := []domain.MusicTrack{
+		createTestTrack("a", "Next A", "/old/a.mp3"),
+		createTestTrack("b", "Next B", "/old/b.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddToUpNext(upNext))
+	require.NoError(t, ts.playlist.PlayNext())
+
+	changed := ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		if !strings.HasPrefix(track.FilePath, "/old/") {
+			return track, false
+		}
+		track.ID = "ignored"
+		track.FilePath = "/new/" + strings.TrimPrefix(track.FilePath, "/old/")
+		track.Missing = false
+		return track, true
+	})
+
+	assert.Equal(t, 1, changed)
+	remaining := ts.playlist.GetUpNext()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "/new/b.mp3", remaining[0].FilePath)
+	assert.Equal(t, "b", remaining[0].ID, "Up Next entry should keep its ID")
+	require.Len(t, history.upNext, 1)
+	assert.Equal(t, "/new/b.mp3", history.upNext[0].FilePath, "Up Next is saved")
+
+	// Auto-next plays the relocated file
+	ts.bus.Publish(domain.NewAutoNextEvent(upNext[0], -1))
+	assert.Equal(t, "/new/b.mp3", ts.playback.GetState().CurrentTrack.FilePath)
+}
+
+func TestPlaylistService_Shuffle(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 10; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(3))
+
+	var modes []domain.ShuffleMode
+	ts.bus.Subscribe(domain.EventShuffleChanged, func(e domain.Event) {
+		modes = append(modes, e.(domain.ShuffleChangedEvent).Mode)
+	})
+
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+	assert.Equal(t, domain.ShuffleTracks, ts.playlist.GetShuffleMode())
+	assert.Equal(t, 3, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, "/test/song3.mp3", ts.playlist.GetQueue()[3].FilePath)
+
+	// Shuffling again still restores the order from before the first shuffle
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+	require.NoError(t, ts.playlist.Unshuffle())
+	assert.Equal(t, domain.ShuffleOff, ts.playlist.GetShuffleMode())
+	assert.Equal(t, queuePaths(tracks), queuePaths(ts.playlist.GetQueue()))
+	assert.Equal(t, 3, ts.playlist.GetCurrentIndex())
+
+	assert.Equal(t, []domain.ShuffleMode{domain.ShuffleTracks, domain.ShuffleTracks, domain.ShuffleOff}, modes)
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, ts.playlist.Shuffle(domain.ShuffleOff), &validationErr)
+}
+
+func TestPlaylistService_Shuffle_UpdateTracksKeepsOriginalOrder(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+	history := ts.playlist.history.(*mockHistoryRepository)
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 10; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/old/song%d.mp3", i)))
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+
+	// Relocate the even tracks
+	ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		var i int
+		if _, err := fmt.Sscanf(track.FilePath, "/old/song%d.mp3", &i); err != nil || i%2 != 0 {
+			return track, false
+		}
+		track.FilePath = fmt.Sprintf("/new/song%d.mp3", i)
+		return track, true
+	})
+	assert.Equal(t, "/new/song0.mp3", history.shuffle.OriginalOrder[0], "The shuffle state is saved")
+
+	require.NoError(t, ts.playlist.Unshuffle())
+	ids := make([]string, 0)
+	for _, track := range ts.playlist.GetQueue() {
+		ids = append(ids, track.ID)
+	}
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, ids)
+}
+
+func TestPlaylistService_Shuffle_AutoNextFollowsCurrentTrack(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/a/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/b/song2.mp3"),
+		createTestTrack("3", "Song 3", "/test/a/song3.mp3"),
+	}
+	tracks[1].Album = "Other Album"
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(2))
+
+	// The album of the current track moves to the front
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleAlbums))
+	assert.Equal(t, 1, ts.playlist.GetCurrentIndex())
+
+	// The finished track was loaded at index 2, but is now at index 1
+	ts.bus.Publish(domain.NewAutoNextEvent(tracks[2], 2))
+	assert.Equal(t, 2, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, "/test/b/song2.mp3", ts.playlist.GetQueue()[2].FilePath)
+}
+
+func TestPlaylistService_Shuffle_Persists(t *testing.T) {
+	engine := mock.NewEngine()
+	_ = engine.Initialize(-1, 44100, 0)
+	bus := eventbus.NewSyncEventBus()
+	log := playlistTestLogger()
+	playback := NewPlaybackService(log, engine, bus)
+	defer func() { _ = playback.Shutdown() }()
+	history := newMockHistoryRepository()
+
+	playlist := NewPlaylistService(log, playback, newMockPlaylistRepository(), history, bus)
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+		createTestTrack("3", "Song 3", "/test/song3.mp3"),
+	}
+	require.NoError(t, playlist.AddTracks(tracks, false))
+	require.NoError(t, playlist.Shuffle(domain.ShuffleTracks))
+	require.NoError(t, playlist.Shutdown())
+
+	restored := NewPlaylistService(log, playback, newMockPlaylistRepository(), history, bus)
+	defer func() { _ = restored.Shutdown() }()
+	require.NoError(t, restored.LoadQueue())
+
+	assert.Equal(t, domain.ShuffleTracks, restored.GetShuffleMode())
+	require.NoError(t, restored.Unshuffle())
+	assert.Equal(t, queuePaths(tracks), queuePaths(restored.GetQueue()))
+
+	// Clearing the queue turns shuffle off
+	require.NoError(t, restored.Shuffle(domain.ShuffleAlbums))
+	require.NoError(t, restored.ClearQueue())
+	assert.Equal(t, domain.ShuffleOff, restored.GetShuffleMode())
+}
+
+func TestPlaylistService_RepeatAll_Wraps(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+		createTestTrack("3", "Song 3", "/test/song3.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(2))
+
+	// Without repeat the queue ends
+	assert.ErrorIs(t, ts.playlist.PlayNext(), domain.ErrEndOfQueue)
+
+	require.NoError(t, ts.playback.SetRepeatMode(domain.RepeatAll))
+
+	// The last track is followed by the first
+	require.NoError(t, ts.playlist.PlayNext())
+	assert.Equal(t, 0, ts.playlist.GetCurrentIndex())
+
+	// The first track is preceded by the last
+	require.NoError(t, ts.playlist.PlayPrevious())
+	assert.Equal(t, 2, ts.playlist.GetCurrentIndex())
+
+	// Auto-next at the end of the queue starts over
+	ts.bus.Publish(domain.NewAutoNextEvent(tracks[2], 2))
+	assert.Equal(t, 0, ts.playlist.GetCurrentIndex())
+}
+
+func TestPlaylistService_RepeatOne_DoesNotWrap(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, true))
+	require.NoError(t, ts.playback.SetRepeatMode(domain.RepeatOne))
+
+	// Skipping manually still moves through the queue, but does not wrap
+	assert.ErrorIs(t, ts.playlist.PlayPrevious(), domain.ErrStartOfQueue)
+	require.NoError(t, ts.playlist.PlayNext())
+	assert.Equal(t, 1, ts.playlist.GetCurrentIndex())
+	assert.ErrorIs(t, ts.playlist.PlayNext(), domain.ErrEndOfQueue)
+}
+
+func TestPlaylistService_NamedPlaylists(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	var events []domain.EventType
+	for _, eventType := range []domain.EventType{
+		domain.EventPlaylistCreated, domain.EventPlaylistRenamed, domain.EventPlaylistDuplicated,
+		domain.EventPlaylistDeleted, domain.EventQueueSavedAsPlaylist,
+	} {
+		ts.bus.Subscribe(eventType, func(e domain.Event) {
+			events = append(events, e.Type())
+		})
+	}
+
+	// Create
+	_, err := ts.playlist.CreatePlaylist("  ", nil)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+	}
+	created, err := ts.playlist.CreatePlaylist(" Road Trip ", tracks)
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", created.Name)
+	assert.Empty(t, ts.playlist.GetQueue(), "Creating a playlist leaves the queue alone")
+
+	// Rename
+	require.NoError(t, ts.playlist.RenamePlaylist(created.ID, "Summer"))
+	renamed, err := ts.playlist.GetPlaylist(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Summer", renamed.Name)
+	assert.ErrorIs(t, ts.playlist.RenamePlaylist("missing", "Name"), ports.ErrPlaylistNotFound)
+
+	// Duplicate
+	copied, err := ts.playlist.DuplicatePlaylist(created.ID, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, created.ID, copied.ID)
+	assert.Equal(t, "Summer (copy)", copied.Name)
+	assert.Equal(t, tracks, copied.Tracks)
+
+	// Save the queue
+	require.NoError(t, ts.playlist.AddTracks(tracks[:1], false))
+	saved, err := ts.playlist.SaveQueueAsPlaylist("Queue")
+	require.NoError(t, err)
+	assert.Equal(t, tracks[:1], saved.Tracks)
+
+	playlists, err := ts.playlist.GetPlaylists()
+	require.NoError(t, err)
+	names := make([]string, len(playlists))
+	for i, playlist := range playlists {
+		names[i] = playlist.Name
+	}
+	assert.Equal(t, []string{"Queue", "Summer", "Summer (copy)"}, names)
+
+	// Delete
+	require.NoError(t, ts.playlist.DeletePlaylist(copied.ID))
+	_, err = ts.playlist.GetPlaylist(copied.ID)
+	assert.ErrorIs(t, err, ports.ErrPlaylistNotFound)
+	assert.ErrorIs(t, ts.playlist.DeletePlaylist(copied.ID), ports.ErrPlaylistNotFound)
+
+	assert.Equal(t, []domain.EventType{
+		domain.EventPlaylistCreated, domain.EventPlaylistRenamed, domain.EventPlaylistDuplicated,
+		domain.EventQueueSavedAsPlaylist, domain.EventPlaylistDeleted,
+	}, events)
+}
+
+func TestPlaylistService_LoadPlaylist(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	queued := createTestTrack("1", "Song 1", "/test/song1.mp3")
+	require.NoError(t, ts.playlist.AddTracks([]domain.MusicTrack{queued}, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(0))
+
+	playlist, err := ts.playlist.CreatePlaylist("Mix", []domain.MusicTrack{
+		queued,
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+	})
+	require.NoError(t, err)
+
+	var loaded []domain.PlaylistLoadedEvent
+	ts.bus.Subscribe(domain.EventPlaylistLoaded, func(e domain.Event) {
+		loaded = append(loaded, e.(domain.PlaylistLoadedEvent))
+	})
+
+	// Append skips tracks already queued and keeps playing
+	require.NoError(t, ts.playlist.LoadPlaylist(playlist.ID, false))
+	assert.Len(t, ts.playlist.GetQueue(), 2)
+	assert.Equal(t, 0, ts.playlist.GetCurrentIndex())
+
+	// Replace starts over with the playlist
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+	require.NoError(t, ts.playlist.LoadPlaylist(playlist.ID, true))
+	assert.Equal(t, playlist.Tracks, ts.playlist.GetQueue())
+	assert.Equal(t, -1, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, domain.ShuffleOff, ts.playlist.GetShuffleMode())
+
+	require.Len(t, loaded, 2)
+	assert.False(t, loaded[0].Replace)
+	assert.True(t, loaded[1].Replace)
+	assert.Equal(t, playlist.ID, loaded[1].Playlist.ID)
+
+	assert.ErrorIs(t, ts.playlist.LoadPlaylist("missing", true), ports.ErrPlaylistNotFound)
+	assert.Len(t, ts.playlist.GetQueue(), 2, "A failed load leaves the queue alone")
+}
+
+func TestPlaylistService_EditPlaylistTracks(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 4; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	playlist, err := ts.playlist.CreatePlaylist("Mix", tracks[:2])
+	require.NoError(t, err)
+
+	var added, removed []domain.MusicTrack
+	ts.bus.Subscribe(domain.EventPlaylistTracksAdded, func(e domain.Event) {
+		added = e.(domain.PlaylistTracksAddedEvent).Tracks
+	})
+	ts.bus.Subscribe(domain.EventPlaylistTracksRemoved, func(e domain.Event) {
+		removed = e.(domain.PlaylistTracksRemovedEvent).Tracks
+	})
+
+	// Add skips tracks already in the playlist
+	require.NoError(t, ts.playlist.AddTracksToPlaylist(playlist.ID, tracks[1:]))
+	assert.Equal(t, tracks[2:], added)
+
+	require.NoError(t, ts.playlist.RemoveTracksFromPlaylist(playlist.ID, []int{3, 0, 3}))
+	assert.Equal(t, []domain.MusicTrack{tracks[0], tracks[3]}, removed)
+
+	updated, err := ts.playlist.GetPlaylist(playlist.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MusicTrack{tracks[1], tracks[2]}, updated.Tracks)
+	assert.Empty(t, ts.playlist.GetQueue(), "Editing a playlist leaves the queue alone")
+
+	assert.ErrorIs(t, ts.playlist.RemoveTracksFromPlaylist(playlist.ID, []int{2}), domain.ErrInvalidIndex)
+	assert.ErrorIs(t, ts.playlist.AddTracksToPlaylist("missing", tracks), ports.ErrPlaylistNotFound)
+
+	// Smart playlists follow their rules
+	smart, err := ts.playlist.CreatePlaylist("Smart", nil)
+	require.NoError(t, err)
+	smart.Smart = &domain.SmartPlaylistRules{}
+	require.NoError(t, ts.playlist.repository.Save(smart))
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, ts.playlist.AddTracksToPlaylist(smart.ID, tracks), &validationErr)
+
+	copied, err := ts.playlist.DuplicatePlaylist(smart.ID, "Smart 2")
+	require.NoError(t, err)
+	assert.True(t, copied.IsSmart())
+}
+
+func TestPlaylistService_UndoRedo(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	var history []domain.QueueHistoryEvent
+	ts.bus.Subscribe(domain.EventQueueHistory, func(e domain.Event) {
+		history = append(history, e.(domain.QueueHistoryEvent))
+	})
+
+	assert.ErrorIs(t, ts.playlist.Undo(), domain.ErrNothingToUndo)
+	assert.ErrorIs(t, ts.playlist.Redo(), domain.ErrNothingToRedo)
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 6; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	paths := func() []string {
+		return queuePaths(ts.playlist.GetQueue())
+	}
+
+	require.NoError(t, ts.playlist.AddTracks(tracks[:4], false))
+	require.NoError(t, ts.playlist.AddTrack(tracks[4], false))
+	require.NoError(t, ts.playlist.PlayTrackAt(2))
+	original := paths()
+
+	require.NoError(t, ts.playlist.RemoveTrack(0))
+	require.NoError(t, ts.playlist.MoveTrack(0, 3))
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+	edited := paths()
+
+	undoAction, redoAction := ts.playlist.GetUndoActions()
+	assert.Equal(t, "Shuffle", undoAction)
+	assert.Empty(t, redoAction)
+
+	// Undo everything back to the queue before the edits
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, domain.ShuffleOff, ts.playlist.GetShuffleMode())
+	require.NoError(t, ts.playlist.Undo())
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, original, paths())
+	assert.Equal(t, 2, ts.playlist.GetCurrentIndex(), "The current track stays current")
+
+	// Redo everything
+	require.NoError(t, ts.playlist.Redo())
+	require.NoError(t, ts.playlist.Redo())
+	require.NoError(t, ts.playlist.Redo())
+	assert.Equal(t, edited, paths())
+	assert.Equal(t, domain.ShuffleTracks, ts.playlist.GetShuffleMode())
+	assert.Equal(t, "/test/song2.mp3", ts.playlist.GetQueue()[ts.playlist.GetCurrentIndex()].FilePath)
+	assert.ErrorIs(t, ts.playlist.Redo(), domain.ErrNothingToRedo)
+
+	// Undoing the adds removes the tracks and stops the current one
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ts.playlist.Undo())
+	}
+	undoAction, redoAction = ts.playlist.GetUndoActions()
+	assert.Equal(t, "Add Track", undoAction)
+	assert.Equal(t, "Remove Track", redoAction)
+	require.NoError(t, ts.playlist.Undo())
+	require.NoError(t, ts.playlist.Undo())
+	assert.Empty(t, ts.playlist.GetQueue())
+	assert.Equal(t, -1, ts.playlist.GetCurrentIndex())
+
+	last := history[len(history)-1]
+	assert.Empty(t, last.UndoAction)
+	assert.Equal(t, "Add Tracks", last.RedoAction)
+}
+
+func TestPlaylistService_UndoClearQueue(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 300; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleAlbums))
+	shuffled := ts.playlist.GetQueue()
+
+	require.NoError(t, ts.playlist.ClearQueue())
+	assert.Empty(t, ts.playlist.GetQueue())
+
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, shuffled, ts.playlist.GetQueue())
+	assert.Equal(t, domain.ShuffleAlbums, ts.playlist.GetShuffleMode())
+
+	// Unshuffling still restores the order from before the shuffle
+	require.NoError(t, ts.playlist.Unshuffle())
+	assert.Equal(t, tracks, ts.playlist.GetQueue())
+
+	require.NoError(t, ts.playlist.Undo())
+	require.NoError(t, ts.playlist.Redo())
+	assert.Equal(t, tracks, ts.playlist.GetQueue())
+
+	// Edits of a loaded queue start a new history
+	require.NoError(t, ts.playlist.LoadQueue())
+	assert.ErrorIs(t, ts.playlist.Undo(), domain.ErrNothingToUndo)
+}
+
+func TestPlaylistService_UndoDepth(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, ts.playlist.SetUndoDepth(0), &validationErr)
+	assert.ErrorAs(t, ts.playlist.SetUndoDepth(MaxUndoDepth+1), &validationErr)
+	require.NoError(t, ts.playlist.SetUndoDepth(2))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ts.playlist.AddTrack(createTestTrack(fmt.Sprint(i), "Song", fmt.Sprintf("/test/song%d.mp3", i)), false))
+	}
+	require.NoError(t, ts.playlist.Undo())
+	require.NoError(t, ts.playlist.Undo())
+	assert.ErrorIs(t, ts.playlist.Undo(), domain.ErrNothingToUndo)
+	assert.Len(t, ts.playlist.GetQueue(), 1)
+}
+
+func TestPlaylistService_UndoLoadPlaylist(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	queued := []domain.MusicTrack{createTestTrack("1", "Song 1", "/test/song1.mp3")}
+	require.NoError(t, ts.playlist.AddTracks(queued, false))
+	playlist, err := ts.playlist.CreatePlaylist("Mix", []domain.MusicTrack{createTestTrack("2", "Song 2", "/test/song2.mp3")})
+	require.NoError(t, err)
+
+	require.NoError(t, ts.playlist.LoadPlaylist(playlist.ID, true))
+	assert.Equal(t, playlist.Tracks, ts.playlist.GetQueue())
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, queued, ts.playlist.GetQueue())
+
+	require.NoError(t, ts.playlist.LoadPlaylist(playlist.ID, false))
+	assert.Len(t, ts.playlist.GetQueue(), 2)
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, queued, ts.playlist.GetQueue())
+}
+
+func TestPlaylistService_UndoAfterTrackChanges(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/old/song1.mp3"),
+		createTestTrack("2", "Song 2", "/old/song2.mp3"),
+		createTestTrack("3", "Song 3", "/old/song3.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.RemoveTrack(0))
+	require.NoError(t, ts.playlist.ClearQueue())
+
+	// The removed tracks are relocated and one is retagged while out of the queue
+	ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		if !strings.HasPrefix(track.FilePath, "/old/") {
+			return track, false
+		}
+		track.FilePath = "/new/" + strings.TrimPrefix(track.FilePath, "/old/")
+		return track, true
+	})
+	ts.bus.Publish(domain.NewTrackUpdatedEvent(createTestTrack("library-id", "Renamed", "/new/song1.mp3")))
+
+	require.NoError(t, ts.playlist.Undo())
+	queue := ts.playlist.GetQueue()
+	require.Len(t, queue, 2)
+	assert.Equal(t, "/new/song2.mp3", queue[0].FilePath)
+	assert.Equal(t, "/new/song3.mp3", queue[1].FilePath)
+
+	require.NoError(t, ts.playlist.Undo())
+	queue = ts.playlist.GetQueue()
+	require.Len(t, queue, 3)
+	assert.Equal(t, "/new/song1.mp3", queue[0].FilePath)
+	assert.Equal(t, "Renamed", queue[0].Title)
+	assert.Equal(t, "1", queue[0].ID, "Restored entry should keep its queue ID")
+
+	require.NoError(t, ts.playlist.Redo())
+	require.NoError(t, ts.playlist.Redo())
+	assert.Empty(t, ts.playlist.GetQueue())
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, "/new/song2.mp3", ts.playlist.GetQueue()[0].FilePath)
+}
+
+func TestPlaylistService_UndoShuffleAfterRelocation(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 20; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/old/song%d.mp3", i)))
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+	require.NoError(t, ts.playlist.ClearQueue())
+
+	ts.playlist.UpdateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		track.FilePath = "/new/" + strings.TrimPrefix(track.FilePath, "/old/")
+		return track, true
+	})
+
+	// The restored shuffle state unshuffles the relocated tracks
+	require.NoError(t, ts.playlist.Undo())
+	require.NoError(t, ts.playlist.Unshuffle())
+	queue := ts.playlist.GetQueue()
+	require.Len(t, queue, len(tracks))
+	for i, track := range queue {
+		assert.Equal(t, fmt.Sprintf("/new/song%d.mp3", i), track.FilePath)
+	}
+}
+
+func TestPlaylistService_UpNext(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+	}
+	upNext := []domain.MusicTrack{
+		createTestTrack("a", "Next A", "/test/a.mp3"),
+		createTestTrack("b", "Next B", "/test/b.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, true))
+
+	var updated domain.PlaylistUpdatedEvent
+	ts.bus.Subscribe(domain.EventPlaylistUpdated, func(e domain.Event) {
+		updated = e.(domain.PlaylistUpdatedEvent)
+	})
+
+	// Tracks already in Up Next are skipped
+	require.NoError(t, ts.playlist.AddToUpNext(upNext))
+	require.NoError(t, ts.playlist.AddToUpNext(upNext[:1]))
+	assert.Equal(t, upNext, ts.playlist.GetUpNext())
+	assert.Equal(t, upNext, updated.UpNext)
+
+	// Up Next is played first without changing the queue position
+	require.NoError(t, ts.playlist.PlayNext())
+	assert.Equal(t, "a", ts.playback.GetState().CurrentTrack.ID)
+	assert.Equal(t, 0, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, upNext[1:], ts.playlist.GetUpNext())
+	assert.Equal(t, upNext[1:], updated.UpNext)
+
+	// Auto-next consumes Up Next too
+	ts.bus.Publish(domain.NewAutoNextEvent(upNext[0], -1))
+	assert.Equal(t, "b", ts.playback.GetState().CurrentTrack.ID)
+	assert.Empty(t, ts.playlist.GetUpNext())
+
+	// Then the queue continues after the track played before Up Next
+	ts.bus.Publish(domain.NewAutoNextEvent(upNext[1], -1))
+	assert.Equal(t, "2", ts.playback.GetState().CurrentTrack.ID)
+	assert.Equal(t, 1, ts.playlist.GetCurrentIndex())
+	assert.Len(t, ts.playlist.GetQueue(), 2)
+}
+
+func TestPlaylistService_UpNext_Edit(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Song 1", "/test/song1.mp3"),
+		createTestTrack("2", "Song 2", "/test/song2.mp3"),
+		createTestTrack("3", "Song 3", "/test/song3.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks[:2], false))
+	require.NoError(t, ts.playlist.PlayTrackAt(1))
+
+	require.NoError(t, ts.playlist.AddToUpNext(tracks))
+	require.NoError(t, ts.playlist.RemoveFromUpNext(1))
+	assert.Equal(t, []domain.MusicTrack{tracks[0], tracks[2]}, ts.playlist.GetUpNext())
+	assert.ErrorIs(t, ts.playlist.RemoveFromUpNext(2), domain.ErrTrackNotFound)
+
+	// Up Next plays even at the end of the queue
+	require.NoError(t, ts.playlist.PlayNext())
+	assert.Equal(t, "1", ts.playback.GetState().CurrentTrack.ID)
+
+	// Previous returns to the queue track Up Next was played after
+	require.NoError(t, ts.playlist.PlayPrevious())
+	assert.Equal(t, "2", ts.playback.GetState().CurrentTrack.ID)
+	assert.Equal(t, 1, ts.playlist.GetCurrentIndex())
+
+	ts.playlist.ClearUpNext()
+	assert.Empty(t, ts.playlist.GetUpNext())
+	assert.ErrorIs(t, ts.playlist.PlayNext(), domain.ErrEndOfQueue)
+	assert.Len(t, ts.playlist.GetQueue(), 2, "Up Next does not change the queue")
+}
+
+func TestPlaylistService_BatchEdits(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 6; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	paths := func() []string {
+		return queuePaths(ts.playlist.GetQueue())
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(3))
+	original := paths()
+
+	updates := 0
+	ts.bus.Subscribe(domain.EventPlaylistUpdated, func(domain.Event) {
+		updates++
+	})
+
+	// Moving 1, 4 and 5 to the front is a single change
+	require.NoError(t, ts.playlist.MoveTracks([]int{5, 1, 4, 1}, 0))
+	assert.Equal(t, []string{original[1], original[4], original[5], original[0], original[2], original[3]}, paths())
+	assert.Equal(t, 5, ts.playlist.GetCurrentIndex(), "The current track is followed")
+	assert.Equal(t, 1, updates)
+
+	undoAction, _ := ts.playlist.GetUndoActions()
+	assert.Equal(t, "Move Tracks", undoAction)
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, original, paths())
+	assert.Equal(t, 3, ts.playlist.GetCurrentIndex())
+
+	// Removing is a single change too
+	updates = 0
+	require.NoError(t, ts.playlist.RemoveTracks([]int{4, 0, 2}))
+	assert.Equal(t, []string{original[1], original[3], original[5]}, paths())
+	assert.Equal(t, 1, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, 1, updates)
+
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, original, paths())
+	require.NoError(t, ts.playlist.Redo())
+	assert.Equal(t, []string{original[1], original[3], original[5]}, paths())
+
+	// Invalid batches change nothing
+	assert.ErrorIs(t, ts.playlist.RemoveTracks([]int{0, 3}), domain.ErrTrackNotFound)
+	assert.ErrorIs(t, ts.playlist.MoveTracks([]int{0, 1}, 2), domain.ErrTrackNotFound)
+	assert.Len(t, ts.playlist.GetQueue(), 3)
+
+	// Removing the current track stops playback
+	require.NoError(t, ts.playlist.RemoveTracks([]int{1, 2}))
+	assert.Equal(t, -1, ts.playlist.GetCurrentIndex())
+	assert.Equal(t, []string{original[1]}, paths())
+}
+
+func TestPlaylistService_CopyTracksToPlaylist(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := make([]domain.MusicTrack, 0)
+	for i := 0; i < 4; i++ {
+		tracks = append(tracks, createTestTrack(fmt.Sprint(i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/test/song%d.mp3", i)))
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	playlist, err := ts.playlist.CreatePlaylist("Mix", tracks[2:3])
+	require.NoError(t, err)
+
+	// Tracks are copied in queue order, skipping those already in the playlist
+	require.NoError(t, ts.playlist.CopyTracksToPlaylist([]int{3, 0, 2}, playlist.ID))
+	saved, err := ts.playlist.GetPlaylist(playlist.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MusicTrack{tracks[2], tracks[0], tracks[3]}, saved.Tracks)
+
+	assert.ErrorIs(t, ts.playlist.CopyTracksToPlaylist([]int{4}, playlist.ID), domain.ErrTrackNotFound)
+	assert.ErrorIs(t, ts.playlist.CopyTracksToPlaylist([]int{0}, "missing"), ports.ErrPlaylistNotFound)
+	assert.Len(t, ts.playlist.GetQueue(), 4, "Copying leaves the queue alone")
+}
+
+func TestPlaylistService_SortBy(t *testing.T) {
+	ts := newTestPlaylistService()
+	defer func() {
+		if err := ts.Shutdown(); err != nil {
+			t.Errorf("Failed to shutdown services: %v", err)
+		}
+	}()
+
+	tracks := []domain.MusicTrack{
+		createTestTrack("1", "Charlie", "/test/c.mp3"),
+		createTestTrack("2", "Alpha", "/test/a.mp3"),
+		createTestTrack("3", "Bravo", "/test/b.mp3"),
+	}
+	require.NoError(t, ts.playlist.AddTracks(tracks, false))
+	require.NoError(t, ts.playlist.PlayTrackAt(0))
+	require.NoError(t, ts.playlist.Shuffle(domain.ShuffleTracks))
+
+	var shuffleModes []domain.ShuffleMode
+	ts.bus.Subscribe(domain.EventShuffleChanged, func(e domain.Event) {
+		shuffleModes = append(shuffleModes, e.(domain.ShuffleChangedEvent).Mode)
+	})
+	titles := func() []string {
+		return sortedTitles(ts.playlist.GetQueue())
+	}
+
+	require.NoError(t, ts.playlist.SortBy(domain.QueueSortTitle, domain.SortAscending))
+	assert.Equal(t, []string{"Alpha", "Bravo", "Charlie"}, titles())
+	assert.Equal(t, 2, ts.playlist.GetCurrentIndex(), "The current track is followed")
+	assert.Equal(t, domain.ShuffleOff, ts.playlist.GetShuffleMode())
+	assert.Equal(t, []domain.ShuffleMode{domain.ShuffleOff}, shuffleModes)
+
+	require.NoError(t, ts.playlist.SortBy(domain.QueueSortTitle, domain.SortDescending))
+	assert.Equal(t, []string{"Charlie", "Bravo", "Alpha"}, titles())
+	assert.Equal(t, 0, ts.playlist.GetCurrentIndex())
+
+	undoAction, _ := ts.playlist.GetUndoActions()
+	assert.Equal(t, "Sort Queue", undoAction)
+	require.NoError(t, ts.playlist.Undo())
+	assert.Equal(t, []string{"Alpha", "Bravo", "Charlie"}, titles())
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, ts.playlist.SortBy("mood", domain.SortAscending), &validationErr)
+}
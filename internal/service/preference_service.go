@@ -3,6 +3,8 @@ package service
 
 import (
 	"log/slog"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/tejashwikalptaru/gotune/internal/domain"
@@ -19,11 +21,15 @@ type PreferenceService struct {
 
 	// Cached preferences (for performance)
 	volume            float64
-	loopEnabled       bool
+	repeatMode        domain.RepeatMode
 	visualizerEnabled bool
 	visualizerType    string
 	theme             string
 	lastFolder        string
+	scanPaths         []string
+	scanOptions       domain.ScanOptions
+	playThreshold     float64
+	tagCharset        domain.Charset
 	cacheValid        bool
 
 	// Concurrency control
@@ -40,9 +46,12 @@ func NewPreferenceService(
 		logger:         logger,
 		repository:     repository,
 		bus:            bus,
-		volume:         0.8,             // Default volume
+		volume:         0.8, // Default volume
+		repeatMode:     domain.RepeatOff,
 		theme:          "dark",          // Default theme
 		visualizerType: "spectrum_bars", // Default visualizer type
+		playThreshold:  domain.DefaultPlayThreshold,
+		tagCharset:     domain.DefaultTagCharset,
 		cacheValid:     false,
 	}
 
@@ -64,9 +73,29 @@ func (s *PreferenceService) loadPreferences() {
 		s.volume = vol
 	}
 
-	// Load loop mode
-	if loop, err := s.repository.LoadLoopMode(); err == nil {
-		s.loopEnabled = loop
+	// Load repeat mode
+	if mode, err := s.repository.LoadRepeatMode(); err == nil && mode.IsValid() {
+		s.repeatMode = mode
+	}
+
+	// Load scan paths
+	if paths, err := s.repository.LoadScanPaths(); err == nil {
+		s.scanPaths = paths
+	}
+
+	// Load scan options
+	if options, err := s.repository.LoadScanOptions(); err == nil {
+		s.scanOptions = options
+	}
+
+	// Load play threshold
+	if fraction, err := s.repository.LoadPlayThreshold(); err == nil {
+		s.playThreshold = fraction
+	}
+
+	// Load tag charset (an unknown saved charset falls back to the default)
+	if charset, err := s.repository.LoadTagCharset(); err == nil && charset.IsValid() {
+		s.tagCharset = charset
 	}
 
 	s.cacheValid = true
@@ -105,29 +134,34 @@ func (s *PreferenceService) SetVolume(volume float64) error {
 	return nil
 }
 
-// GetLoopMode returns the saved loop mode preference.
-func (s *PreferenceService) GetLoopMode() bool {
+// GetRepeatMode returns the saved repeat mode preference.
+func (s *PreferenceService) GetRepeatMode() domain.RepeatMode {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if !s.cacheValid {
 		// Try to load from the repository
-		if loop, err := s.repository.LoadLoopMode(); err == nil {
-			return loop
+		if mode, err := s.repository.LoadRepeatMode(); err == nil && mode.IsValid() {
+			return mode
 		}
 	}
 
-	return s.loopEnabled
+	return s.repeatMode
 }
 
-// SetLoopMode saves the loop mode preference.
-func (s *PreferenceService) SetLoopMode(enabled bool) error {
+// SetRepeatMode saves the repeat mode preference.
+// Returns a ValidationError if the mode is unknown.
+func (s *PreferenceService) SetRepeatMode(mode domain.RepeatMode) error {
+	if !mode.IsValid() {
+		return domain.NewValidationError("repeatMode", mode, "must be off, one or all")
+	}
+
 	s.mu.Lock()
-	s.loopEnabled = enabled
+	s.repeatMode = mode
 	s.mu.Unlock()
 
 	// Save to repository
-	if err := s.repository.SaveLoopMode(enabled); err != nil {
+	if err := s.repository.SaveRepeatMode(mode); err != nil {
 		return err
 	}
 
@@ -188,6 +222,151 @@ func (s *PreferenceService) SetLastFolder(path string) error {
 	return nil
 }
 
+// GetScanPaths returns the folders that have been scanned for music.
+func (s *PreferenceService) GetScanPaths() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := make([]string, len(s.scanPaths))
+	copy(paths, s.scanPaths)
+	return paths
+}
+
+// AddScanPath records a scanned folder.
+// Folders inside an already recorded folder are not added, and recorded
+// folders inside the new one are replaced by it.
+func (s *PreferenceService) AddScanPath(path string) error {
+	if path == "" {
+		return domain.NewValidationError("path", path, "cannot be empty")
+	}
+	path = filepath.Clean(path)
+
+	s.mu.Lock()
+	paths := make([]string, 0, len(s.scanPaths)+1)
+	for _, existing := range s.scanPaths {
+		if isWithinPath(path, existing) {
+			s.mu.Unlock()
+			return nil
+		}
+		if !isWithinPath(existing, path) {
+			paths = append(paths, existing)
+		}
+	}
+	paths = append(paths, path)
+	s.scanPaths = paths
+	s.mu.Unlock()
+
+	return s.repository.SaveScanPaths(paths)
+}
+
+// isWithinPath reports whether path is dir or inside dir.
+func isWithinPath(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// GetScanOptions returns the exclude patterns and limits used by folder scans.
+func (s *PreferenceService) GetScanOptions() domain.ScanOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return copyScanOptions(s.scanOptions)
+}
+
+// SetScanOptions saves the exclude patterns and limits used by folder scans.
+// Blank patterns are dropped; returns a ValidationError for invalid exclude
+// or tag patterns or a negative depth.
+func (s *PreferenceService) SetScanOptions(options domain.ScanOptions) error {
+	if options.MaxDepth < 0 {
+		return domain.NewValidationError("maxDepth", options.MaxDepth, "cannot be negative")
+	}
+
+	patterns := make([]string, 0, len(options.ExcludePatterns))
+	for _, pattern := range options.ExcludePatterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, _, err := compileIgnorePattern(pattern); err != nil {
+			return domain.NewValidationError("excludePatterns", pattern, "invalid pattern")
+		}
+		patterns = append(patterns, pattern)
+	}
+	options.ExcludePatterns = patterns
+
+	var tagPatterns []string
+	for _, pattern := range options.TagPatterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := CompileTagPattern(pattern); err != nil {
+			return err
+		}
+		tagPatterns = append(tagPatterns, pattern)
+	}
+	options.TagPatterns = tagPatterns
+
+	s.mu.Lock()
+	s.scanOptions = copyScanOptions(options)
+	s.mu.Unlock()
+
+	return s.repository.SaveScanOptions(options)
+}
+
+// copyScanOptions returns options with its own copy of the patterns.
+func copyScanOptions(options domain.ScanOptions) domain.ScanOptions {
+	options.ExcludePatterns = append([]string(nil), options.ExcludePatterns...)
+	options.TagPatterns = append([]string(nil), options.TagPatterns...)
+	return options
+}
+
+// GetPlayThreshold returns the fraction of a track that must be heard for it to count as played.
+func (s *PreferenceService) GetPlayThreshold() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.playThreshold
+}
+
+// SetPlayThreshold saves the fraction of a track that must be heard for it to count as played.
+// Returns a ValidationError if the fraction is not above 0 and at most 1.
+func (s *PreferenceService) SetPlayThreshold(fraction float64) error {
+	if fraction <= 0 || fraction > 1 {
+		return domain.NewValidationError("playThreshold", fraction, "must be above 0 and at most 1")
+	}
+
+	s.mu.Lock()
+	s.playThreshold = fraction
+	s.mu.Unlock()
+
+	return s.repository.SavePlayThreshold(fraction)
+}
+
+// GetTagCharset returns the fallback charset for legacy tags.
+func (s *PreferenceService) GetTagCharset() domain.Charset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tagCharset
+}
+
+// SetTagCharset saves the fallback charset for legacy tags.
+// Returns a ValidationError if the charset is not supported.
+func (s *PreferenceService) SetTagCharset(charset domain.Charset) error {
+	if !charset.IsValid() {
+		return domain.NewValidationError("tagCharset", charset, "unsupported charset")
+	}
+
+	s.mu.Lock()
+	s.tagCharset = charset
+	s.mu.Unlock()
+
+	return s.repository.SaveTagCharset(charset)
+}
+
 // GetVisualizerEnabled returns the saved visualizer enabled preference.
 func (s *PreferenceService) GetVisualizerEnabled() bool {
 	s.mu.RLock()
@@ -235,7 +414,7 @@ func (s *PreferenceService) SetVisualizerType(visType string) error {
 func (s *PreferenceService) ResetToDefaults() error {
 	s.mu.Lock()
 	s.volume = 0.8
-	s.loopEnabled = false
+	s.repeatMode = domain.RepeatOff
 	s.theme = "dark"
 	s.lastFolder = ""
 	s.mu.Unlock()
@@ -245,7 +424,7 @@ func (s *PreferenceService) ResetToDefaults() error {
 		return err
 	}
 
-	if err := s.repository.SaveLoopMode(false); err != nil {
+	if err := s.repository.SaveRepeatMode(domain.RepeatOff); err != nil {
 		return err
 	}
 
@@ -259,7 +438,7 @@ func (s *PreferenceService) GetAllPreferences() map[string]interface{} {
 
 	return map[string]interface{}{
 		"volume":          s.volume,
-		"loop":            s.loopEnabled,
+		"repeat":          s.repeatMode,
 		"visualizer":      s.visualizerEnabled,
 		"visualizer_type": s.visualizerType,
 		"theme":           s.theme,
@@ -277,8 +456,8 @@ func (s *PreferenceService) Shutdown() error {
 var _ interface {
 	GetVolume() float64
 	SetVolume(float64) error
-	GetLoopMode() bool
-	SetLoopMode(bool) error
+	GetRepeatMode() domain.RepeatMode
+	SetRepeatMode(domain.RepeatMode) error
 	GetVisualizerEnabled() bool
 	SetVisualizerEnabled(bool) error
 	GetVisualizerType() string
@@ -287,6 +466,14 @@ var _ interface {
 	SetTheme(string) error
 	GetLastFolder() string
 	SetLastFolder(string) error
+	GetScanPaths() []string
+	AddScanPath(string) error
+	GetScanOptions() domain.ScanOptions
+	SetScanOptions(domain.ScanOptions) error
+	GetPlayThreshold() float64
+	SetPlayThreshold(float64) error
+	GetTagCharset() domain.Charset
+	SetTagCharset(domain.Charset) error
 	ResetToDefaults() error
 	GetAllPreferences() map[string]interface{}
 	Shutdown() error
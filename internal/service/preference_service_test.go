@@ -3,6 +3,7 @@ package service
 import (
 	"io"
 	"log/slog"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -14,17 +15,21 @@ import (
 
 // Mock preferences repository for testing
 type mockPreferencesRepository struct {
-	mu        sync.RWMutex
-	volume    float64
-	loop      bool
-	theme     string
-	scanPaths []string
+	mu          sync.RWMutex
+	volume      float64
+	repeat      domain.RepeatMode
+	theme       string
+	scanPaths   []string
+	scanOptions domain.ScanOptions
+
+	playThreshold float64
+	tagCharset    domain.Charset
 }
 
 func newMockPreferencesRepository() *mockPreferencesRepository {
 	return &mockPreferencesRepository{
 		volume: 0.8, // Default
-		loop:   false,
+		repeat: domain.RepeatOff,
 	}
 }
 
@@ -41,17 +46,17 @@ func (m *mockPreferencesRepository) LoadVolume() (float64, error) {
 	return m.volume, nil
 }
 
-func (m *mockPreferencesRepository) SaveLoopMode(enabled bool) error {
+func (m *mockPreferencesRepository) SaveRepeatMode(mode domain.RepeatMode) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.loop = enabled
+	m.repeat = mode
 	return nil
 }
 
-func (m *mockPreferencesRepository) LoadLoopMode() (bool, error) {
+func (m *mockPreferencesRepository) LoadRepeatMode() (domain.RepeatMode, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.loop, nil
+	return m.repeat, nil
 }
 
 func (m *mockPreferencesRepository) SaveTheme(theme string) error {
@@ -86,13 +91,61 @@ func (m *mockPreferencesRepository) LoadScanPaths() ([]string, error) {
 	return m.scanPaths, nil
 }
 
+func (m *mockPreferencesRepository) SaveScanOptions(options domain.ScanOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scanOptions = options
+	return nil
+}
+
+func (m *mockPreferencesRepository) LoadScanOptions() (domain.ScanOptions, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.scanOptions, nil
+}
+
+func (m *mockPreferencesRepository) SavePlayThreshold(fraction float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.playThreshold = fraction
+	return nil
+}
+
+func (m *mockPreferencesRepository) LoadPlayThreshold() (float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.playThreshold == 0 {
+		return domain.DefaultPlayThreshold, nil
+	}
+	return m.playThreshold, nil
+}
+
+func (m *mockPreferencesRepository) SaveTagCharset(charset domain.Charset) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tagCharset = charset
+	return nil
+}
+
+func (m *mockPreferencesRepository) LoadTagCharset() (domain.Charset, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.tagCharset == "" {
+		return domain.DefaultTagCharset, nil
+	}
+	return m.tagCharset, nil
+}
+
 func (m *mockPreferencesRepository) Clear() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.volume = 0.8
-	m.loop = false
+	m.repeat = domain.RepeatOff
 	m.theme = ""
 	m.scanPaths = nil
+	m.scanOptions = domain.ScanOptions{}
+	m.playThreshold = 0
+	m.tagCharset = ""
 	return nil
 }
 
@@ -166,34 +219,47 @@ func TestPreferenceService_SetVolume_BoundaryValues(t *testing.T) {
 	assert.Equal(t, 1.0, service.GetVolume())
 }
 
-func TestPreferenceService_GetLoopMode_Default(t *testing.T) {
+func TestPreferenceService_GetRepeatMode_Default(t *testing.T) {
 	service, _ := newTestPreferenceService()
 	defer service.Shutdown()
 
-	// Should return default (false)
-	assert.False(t, service.GetLoopMode())
+	// Should return default (off)
+	assert.Equal(t, domain.RepeatOff, service.GetRepeatMode())
 }
 
-func TestPreferenceService_SetLoopMode(t *testing.T) {
+func TestPreferenceService_SetRepeatMode(t *testing.T) {
 	service, repo := newTestPreferenceService()
 	defer service.Shutdown()
 
-	// Enable loop
-	err := service.SetLoopMode(true)
+	// Repeat the queue
+	err := service.SetRepeatMode(domain.RepeatAll)
 	require.NoError(t, err)
 
 	// Verify cached value
-	assert.True(t, service.GetLoopMode())
+	assert.Equal(t, domain.RepeatAll, service.GetRepeatMode())
 
 	// Verify persisted value
-	savedLoop, _ := repo.LoadLoopMode()
-	assert.True(t, savedLoop)
+	savedMode, _ := repo.LoadRepeatMode()
+	assert.Equal(t, domain.RepeatAll, savedMode)
 
-	// Disable loop
-	err = service.SetLoopMode(false)
+	// Turn repeat off
+	err = service.SetRepeatMode(domain.RepeatOff)
 	require.NoError(t, err)
 
-	assert.False(t, service.GetLoopMode())
+	assert.Equal(t, domain.RepeatOff, service.GetRepeatMode())
+}
+
+func TestPreferenceService_SetRepeatMode_Invalid(t *testing.T) {
+	service, repo := newTestPreferenceService()
+	defer service.Shutdown()
+
+	err := service.SetRepeatMode("sometimes")
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, domain.RepeatOff, service.GetRepeatMode())
+	savedMode, _ := repo.LoadRepeatMode()
+	assert.Equal(t, domain.RepeatOff, savedMode)
 }
 
 func TestPreferenceService_GetTheme_Default(t *testing.T) {
@@ -255,7 +321,7 @@ func TestPreferenceService_ResetToDefaults(t *testing.T) {
 
 	// Change all preferences
 	service.SetVolume(0.5)
-	service.SetLoopMode(true)
+	service.SetRepeatMode(domain.RepeatOne)
 	service.SetTheme("light")
 	service.SetLastFolder("/some/path")
 
@@ -265,7 +331,7 @@ func TestPreferenceService_ResetToDefaults(t *testing.T) {
 
 	// Verify defaults
 	assert.Equal(t, 0.8, service.GetVolume())
-	assert.False(t, service.GetLoopMode())
+	assert.Equal(t, domain.RepeatOff, service.GetRepeatMode())
 	assert.Equal(t, "dark", service.GetTheme())
 	assert.Equal(t, "", service.GetLastFolder())
 
@@ -273,8 +339,8 @@ func TestPreferenceService_ResetToDefaults(t *testing.T) {
 	savedVolume, _ := repo.LoadVolume()
 	assert.Equal(t, 0.8, savedVolume)
 
-	savedLoop, _ := repo.LoadLoopMode()
-	assert.False(t, savedLoop)
+	savedMode, _ := repo.LoadRepeatMode()
+	assert.Equal(t, domain.RepeatOff, savedMode)
 }
 
 func TestPreferenceService_GetAllPreferences(t *testing.T) {
@@ -283,7 +349,7 @@ func TestPreferenceService_GetAllPreferences(t *testing.T) {
 
 	// Set some preferences
 	service.SetVolume(0.7)
-	service.SetLoopMode(true)
+	service.SetRepeatMode(domain.RepeatOne)
 	service.SetTheme("light")
 	service.SetLastFolder("/music")
 
@@ -292,7 +358,7 @@ func TestPreferenceService_GetAllPreferences(t *testing.T) {
 
 	// Verify all values
 	assert.Equal(t, 0.7, prefs["volume"])
-	assert.Equal(t, true, prefs["loop"])
+	assert.Equal(t, domain.RepeatOne, prefs["repeat"])
 	assert.Equal(t, "light", prefs["theme"])
 	assert.Equal(t, "/music", prefs["last_folder"])
 }
@@ -305,7 +371,7 @@ func TestPreferenceService_Persistence(t *testing.T) {
 	// First service instance
 	service1 := NewPreferenceService(testLogger, repo, bus)
 	service1.SetVolume(0.6)
-	service1.SetLoopMode(true)
+	service1.SetRepeatMode(domain.RepeatAll)
 	service1.Shutdown()
 
 	// Second service instance with the same repository
@@ -314,7 +380,7 @@ func TestPreferenceService_Persistence(t *testing.T) {
 
 	// Should load saved preferences
 	assert.Equal(t, 0.6, service2.GetVolume())
-	assert.True(t, service2.GetLoopMode())
+	assert.Equal(t, domain.RepeatAll, service2.GetRepeatMode())
 }
 
 // Thread safety tests
@@ -393,12 +459,143 @@ func TestPreferenceService_ConcurrentMixedOperations(t *testing.T) {
 	assert.LessOrEqual(t, volume, 1.0)
 }
 
+func TestPreferenceService_AddScanPath(t *testing.T) {
+	service, repo := newTestPreferenceService()
+	defer service.Shutdown()
+
+	music := filepath.Join("music", "rock")
+	require.NoError(t, service.AddScanPath(music))
+	require.NoError(t, service.AddScanPath(filepath.Join("other")+string(filepath.Separator)))
+
+	// Folders inside a recorded folder are already covered
+	require.NoError(t, service.AddScanPath(filepath.Join(music, "album")))
+	assert.Equal(t, []string{music, "other"}, service.GetScanPaths())
+
+	// A parent folder replaces the folders inside it
+	require.NoError(t, service.AddScanPath("music"))
+	assert.Equal(t, []string{"other", "music"}, service.GetScanPaths())
+
+	saved, _ := repo.LoadScanPaths()
+	assert.Equal(t, []string{"other", "music"}, saved)
+
+	// A folder that only shares a name prefix is separate
+	require.NoError(t, service.AddScanPath("musical"))
+	assert.Len(t, service.GetScanPaths(), 3)
+
+	assert.Error(t, service.AddScanPath(""))
+}
+
+func TestPreferenceService_ScanPathsLoaded(t *testing.T) {
+	repo := newMockPreferencesRepository()
+	require.NoError(t, repo.SaveScanPaths([]string{"music"}))
+
+	service := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	defer service.Shutdown()
+
+	assert.Equal(t, []string{"music"}, service.GetScanPaths())
+}
+
+func TestPreferenceService_ScanOptions(t *testing.T) {
+	repo := newMockPreferencesRepository()
+	service := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	defer service.Shutdown()
+
+	assert.Equal(t, domain.ScanOptions{}, service.GetScanOptions())
+
+	err := service.SetScanOptions(domain.ScanOptions{
+		ExcludePatterns: []string{" *.tmp ", "", "Samples/"},
+		MaxDepth:        2,
+		SkipHidden:      true,
+	})
+	require.NoError(t, err)
+
+	want := domain.ScanOptions{ExcludePatterns: []string{"*.tmp", "Samples/"}, MaxDepth: 2, SkipHidden: true}
+	assert.Equal(t, want, service.GetScanOptions())
+	assert.Equal(t, want, repo.scanOptions)
+
+	// Loaded by a new service
+	reloaded := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	assert.Equal(t, want, reloaded.GetScanOptions())
+}
+
+func TestPreferenceService_SetScanOptions_Invalid(t *testing.T) {
+	service, _ := newTestPreferenceService()
+	defer service.Shutdown()
+
+	var validationErr *domain.ValidationError
+	err := service.SetScanOptions(domain.ScanOptions{MaxDepth: -1})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "maxDepth", validationErr.Field)
+
+	err = service.SetScanOptions(domain.ScanOptions{ExcludePatterns: []string{"[z-a]"}})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "excludePatterns", validationErr.Field)
+
+	assert.Empty(t, service.GetScanOptions().ExcludePatterns)
+
+	err = service.SetScanOptions(domain.ScanOptions{TagPatterns: []string{"%composer% - %title%"}})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "tagPattern", validationErr.Field)
+}
+
+func TestPreferenceService_ScanOptions_TagPatterns(t *testing.T) {
+	service, _ := newTestPreferenceService()
+	defer service.Shutdown()
+
+	err := service.SetScanOptions(domain.ScanOptions{TagPatterns: []string{" %artist% - %title% ", ""}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"%artist% - %title%"}, service.GetScanOptions().TagPatterns)
+}
+
+func TestPreferenceService_PlayThreshold(t *testing.T) {
+	repo := newMockPreferencesRepository()
+	service := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	defer service.Shutdown()
+
+	assert.Equal(t, domain.DefaultPlayThreshold, service.GetPlayThreshold())
+
+	require.NoError(t, service.SetPlayThreshold(0.3))
+	assert.Equal(t, 0.3, service.GetPlayThreshold())
+
+	reloaded := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	assert.Equal(t, 0.3, reloaded.GetPlayThreshold())
+
+	var validationErr *domain.ValidationError
+	for _, fraction := range []float64{0, -0.5, 1.5} {
+		require.ErrorAs(t, service.SetPlayThreshold(fraction), &validationErr)
+	}
+	assert.Equal(t, 0.3, service.GetPlayThreshold())
+}
+
+func TestPreferenceService_TagCharset(t *testing.T) {
+	repo := newMockPreferencesRepository()
+	service := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	defer service.Shutdown()
+
+	assert.Equal(t, domain.DefaultTagCharset, service.GetTagCharset())
+
+	require.NoError(t, service.SetTagCharset(domain.CharsetWindows1251))
+	assert.Equal(t, domain.CharsetWindows1251, service.GetTagCharset())
+
+	reloaded := NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	assert.Equal(t, domain.CharsetWindows1251, reloaded.GetTagCharset())
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, service.SetTagCharset("ebcdic"), &validationErr)
+	assert.Equal(t, domain.CharsetWindows1251, service.GetTagCharset())
+
+	// An unknown saved charset is ignored
+	repo.tagCharset = "ebcdic"
+	reloaded = NewPreferenceService(prefTestLogger(), repo, eventbus.NewSyncEventBus())
+	assert.Equal(t, domain.DefaultTagCharset, reloaded.GetTagCharset())
+}
+
 func TestPreferenceService_Shutdown(t *testing.T) {
 	service, _ := newTestPreferenceService()
 
 	// Set some preferences
 	service.SetVolume(0.9)
-	service.SetLoopMode(true)
+	service.SetRepeatMode(domain.RepeatOne)
 
 	// Shutdown
 	err := service.Shutdown()
@@ -0,0 +1,423 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// CompileQuery parses a structured search query and compiles it into a predicate.
+//
+// A query is a whitespace-separated list of terms that must all match:
+//
+//	pink floyd                 bare words match path, title, artist, album artist or album
+//	"dark side"                quoted phrases are matched as a whole
+//	artist:"pink floyd"        field terms match a single field (substring)
+//	genre:=rock                '=' requires an exact (case-insensitive) match
+//	year:>=1975 year:<1980     numeric fields accept =, >, >=, < and <=
+//	year:1970..1979            inclusive numeric range
+//	rating:>=4                 star rating (0 for unrated tracks)
+//	duration:<5m               durations accept 5m, 90s, 1h2m, 4:33 or seconds
+//	format:flac                file format, with or without the leading dot
+//	albumartist:various        album artist ("Various Artists" for compilations
+//	compilation:1              without one); compilation is 1 or 0
+//	codec:=aac bitdepth:>=24   codec and technical properties (bitrate in kbps,
+//	                           samplerate in Hz, bitdepth, channels)
+//	-live                      a leading '-' negates any term
+//
+// An empty query matches every track. Syntax errors are returned as
+// *domain.QuerySyntaxError with the column of the offending term.
+func CompileQuery(query string) (domain.TrackPredicate, error) {
+	parser := &queryParser{input: query}
+
+	predicates, err := parser.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(track domain.MusicTrack) bool {
+		for _, predicate := range predicates {
+			if !predicate(track) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// FilterTracks returns the tracks that satisfy the predicate, preserving order.
+// A nil predicate matches every track.
+func FilterTracks(tracks []domain.MusicTrack, predicate domain.TrackPredicate) []domain.MusicTrack {
+	filtered := make([]domain.MusicTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if predicate == nil || predicate(track) {
+			filtered = append(filtered, track)
+		}
+	}
+	return filtered
+}
+
+// queryFieldKind determines how a field value is parsed and compared.
+type queryFieldKind int
+
+const (
+	queryFieldText queryFieldKind = iota
+	queryFieldNumber
+	queryFieldDuration
+	queryFieldFormat
+)
+
+// queryField describes a searchable track field.
+type queryField struct {
+	kind   queryFieldKind
+	text   func(track domain.MusicTrack) string
+	number func(track domain.MusicTrack) float64
+}
+
+// metadataOf returns the track metadata, or an empty value if none was extracted.
+func metadataOf(track domain.MusicTrack) domain.TrackMetadata {
+	if track.Metadata == nil {
+		return domain.TrackMetadata{}
+	}
+	return *track.Metadata
+}
+
+// queryFields maps field names (as typed by the user) to their accessors.
+var queryFields = map[string]queryField{
+	"title":       {kind: queryFieldText, text: func(t domain.MusicTrack) string { return t.Title }},
+	"artist":      {kind: queryFieldText, text: func(t domain.MusicTrack) string { return t.Artist }},
+	"album":       {kind: queryFieldText, text: func(t domain.MusicTrack) string { return t.Album }},
+	"albumartist": {kind: queryFieldText, text: func(t domain.MusicTrack) string { return t.EffectiveAlbumArtist() }},
+	"compilation": {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 {
+		if t.Compilation {
+			return 1
+		}
+		return 0
+	}},
+	"path":       {kind: queryFieldText, text: func(t domain.MusicTrack) string { return t.FilePath }},
+	"genre":      {kind: queryFieldText, text: func(t domain.MusicTrack) string { return metadataOf(t).Genre }},
+	"composer":   {kind: queryFieldText, text: func(t domain.MusicTrack) string { return metadataOf(t).Composer }},
+	"comment":    {kind: queryFieldText, text: func(t domain.MusicTrack) string { return metadataOf(t).Comment }},
+	"format":     {kind: queryFieldFormat, text: func(t domain.MusicTrack) string { return t.FileFormat }},
+	"year":       {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).Year) }},
+	"track":      {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).TrackNumber) }},
+	"disc":       {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).DiscNumber) }},
+	"codec":      {kind: queryFieldText, text: func(t domain.MusicTrack) string { return metadataOf(t).Codec }},
+	"bitrate":    {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).BitRate) }},
+	"samplerate": {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).SampleRate) }},
+	"bitdepth":   {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).BitDepth) }},
+	"channels":   {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).Channels) }},
+	"rating":     {kind: queryFieldNumber, number: func(t domain.MusicTrack) float64 { return float64(metadataOf(t).Rating) }},
+	"duration":   {kind: queryFieldDuration, number: func(t domain.MusicTrack) float64 { return t.Duration.Seconds() }},
+}
+
+// queryParser is a single-pass parser over the query text.
+// It reads the input a rune at a time, so words with non-ASCII letters are kept whole.
+type queryParser struct {
+	input string
+	pos   int // Byte offset of the next rune
+}
+
+// parse parses every term in the input.
+func (p *queryParser) parse() ([]domain.TrackPredicate, error) {
+	predicates := make([]domain.TrackPredicate, 0)
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return predicates, nil
+		}
+
+		predicate, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+}
+
+// parseTerm parses one (optionally negated) bare or field term.
+func (p *queryParser) parseTerm() (domain.TrackPredicate, error) {
+	start := p.pos
+
+	negate := false
+	if p.peek() == '-' {
+		negate = true
+		p.pos++
+		if p.eof() || unicode.IsSpace(p.peek()) {
+			return nil, p.errorAt(start, "expected a search term after '-'")
+		}
+	}
+
+	var predicate domain.TrackPredicate
+	var err error
+
+	if p.peek() == '"' {
+		var phrase string
+		phrase, err = p.parseQuoted()
+		if err == nil {
+			predicate = anyFieldContains(phrase)
+		}
+	} else {
+		predicate, err = p.parseWordOrField()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if negate {
+		return func(track domain.MusicTrack) bool { return !predicate(track) }, nil
+	}
+	return predicate, nil
+}
+
+// parseWordOrField parses either a bare word or a "field:value" term.
+func (p *queryParser) parseWordOrField() (domain.TrackPredicate, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != ':' && !unicode.IsSpace(p.peek()) {
+		p.next()
+	}
+	word := p.input[start:p.pos]
+
+	if p.eof() || p.peek() != ':' {
+		return anyFieldContains(word), nil
+	}
+
+	// Field term
+	name := strings.ToLower(word)
+	field, ok := queryFields[name]
+	if !ok {
+		if name == "" {
+			return nil, p.errorAt(start, "expected a field name before ':'")
+		}
+		return nil, p.errorAt(start, fmt.Sprintf("unknown field %q", word))
+	}
+	p.pos++ // Skip ':'
+
+	opPos := p.pos
+	op := p.parseOperator()
+
+	valuePos := p.pos
+	var value string
+	if p.peek() == '"' {
+		quoted, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		value = quoted
+	} else {
+		for !p.eof() && !unicode.IsSpace(p.peek()) {
+			p.next()
+		}
+		value = p.input[valuePos:p.pos]
+	}
+
+	if value == "" {
+		return nil, p.errorAt(valuePos, fmt.Sprintf("missing value for field %q", name))
+	}
+
+	switch field.kind {
+	case queryFieldText, queryFieldFormat:
+		return p.compileText(name, field, op, opPos, value)
+	default:
+		return p.compileNumeric(name, field, op, value, valuePos)
+	}
+}
+
+// parseOperator consumes a comparison operator, returning "" for the default.
+func (p *queryParser) parseOperator() string {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// parseQuoted consumes a double-quoted phrase. Inside quotes, \" and \\ are escapes.
+func (p *queryParser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // Skip the opening quote
+
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '\\' && p.pos+1 < len(p.input):
+			p.pos++ // Skip the backslash
+			sb.WriteString(p.next())
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteString(p.next())
+		}
+	}
+
+	return "", p.errorAt(start, "unterminated quoted phrase")
+}
+
+// compileText builds a predicate for a text or format field.
+func (p *queryParser) compileText(name string, field queryField, op string, opPos int, value string) (domain.TrackPredicate, error) {
+	if op != "" && op != "=" {
+		return nil, p.errorAt(opPos, fmt.Sprintf("operator %q cannot be used with text field %q", op, name))
+	}
+
+	if field.kind == queryFieldFormat {
+		want := normalizeFormat(value)
+		return func(track domain.MusicTrack) bool {
+			return normalizeFormat(field.text(track)) == want
+		}, nil
+	}
+
+	want := strings.ToLower(value)
+	if op == "=" {
+		return func(track domain.MusicTrack) bool {
+			return strings.ToLower(field.text(track)) == want
+		}, nil
+	}
+	return func(track domain.MusicTrack) bool {
+		return strings.Contains(strings.ToLower(field.text(track)), want)
+	}, nil
+}
+
+// compileNumeric builds a predicate for a number or duration field.
+func (p *queryParser) compileNumeric(name string, field queryField, op string, value string, valuePos int) (domain.TrackPredicate, error) {
+	parse := parseQueryNumber
+	if field.kind == queryFieldDuration {
+		parse = parseQueryDuration
+	}
+
+	// Inclusive range: year:1970..1979
+	if lo, hi, isRange := strings.Cut(value, ".."); isRange {
+		if op != "" {
+			return nil, p.errorAt(valuePos, fmt.Sprintf("a range for field %q cannot be combined with %q", name, op))
+		}
+		low, err := parse(lo)
+		if err != nil {
+			return nil, p.errorAt(valuePos, fmt.Sprintf("invalid range start for field %q: %v", name, err))
+		}
+		high, err := parse(hi)
+		if err != nil {
+			return nil, p.errorAt(valuePos+len(lo)+2, fmt.Sprintf("invalid range end for field %q: %v", name, err))
+		}
+		return func(track domain.MusicTrack) bool {
+			n := field.number(track)
+			return n >= low && n <= high
+		}, nil
+	}
+
+	want, err := parse(value)
+	if err != nil {
+		return nil, p.errorAt(valuePos, fmt.Sprintf("invalid value for field %q: %v", name, err))
+	}
+
+	var compare func(n float64) bool
+	switch op {
+	case ">":
+		compare = func(n float64) bool { return n > want }
+	case ">=":
+		compare = func(n float64) bool { return n >= want }
+	case "<":
+		compare = func(n float64) bool { return n < want }
+	case "<=":
+		compare = func(n float64) bool { return n <= want }
+	default:
+		compare = func(n float64) bool { return n == want }
+	}
+
+	return func(track domain.MusicTrack) bool {
+		return compare(field.number(track))
+	}, nil
+}
+
+// errorAt creates a syntax error at the given byte offset, reported as a column in runes.
+func (p *queryParser) errorAt(offset int, message string) error {
+	return domain.NewQuerySyntaxError(p.input, utf8.RuneCountInString(p.input[:offset])+1, message)
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+// peek returns the next rune without consuming it, or 0 at the end of the input.
+func (p *queryParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r
+}
+
+// next consumes the next rune and returns its text.
+// Invalid UTF-8 is consumed a byte at a time and returned unchanged.
+func (p *queryParser) next() string {
+	_, size := utf8.DecodeRuneInString(p.input[p.pos:])
+	start := p.pos
+	p.pos += size
+	return p.input[start:p.pos]
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.next()
+	}
+}
+
+// anyFieldContains matches a phrase against the fields searched by a bare term.
+func anyFieldContains(phrase string) domain.TrackPredicate {
+	want := strings.ToLower(phrase)
+	return func(track domain.MusicTrack) bool {
+		for _, text := range []string{track.FilePath, track.Title, track.Artist, track.AlbumArtist, track.Album} {
+			if strings.Contains(strings.ToLower(text), want) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// normalizeFormat lowercases a file format and strips its leading dot.
+func normalizeFormat(format string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(format)), ".")
+}
+
+// parseQueryNumber parses a plain numeric value.
+func parseQueryNumber(value string) (float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	return n, nil
+}
+
+// parseQueryDuration parses a duration as Go syntax (5m, 1h2m), m:ss, or plain seconds.
+// The result is in seconds.
+func parseQueryDuration(value string) (float64, error) {
+	if minutes, seconds, ok := strings.Cut(value, ":"); ok {
+		m, errM := strconv.Atoi(minutes)
+		s, errS := strconv.Atoi(seconds)
+		if errM != nil || errS != nil || m < 0 || s < 0 || s >= 60 {
+			return 0, fmt.Errorf("%q is not a valid m:ss duration", value)
+		}
+		return float64(m*60 + s), nil
+	}
+
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return n, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid duration", value)
+	}
+	return d.Seconds(), nil
+}
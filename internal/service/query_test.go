@@ -0,0 +1,204 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// queryTestTracks returns a small library used by the query tests.
+func queryTestTracks() []domain.MusicTrack {
+	return []domain.MusicTrack{
+		{
+			FilePath:   "/music/pink floyd/time.flac",
+			Title:      "Time",
+			Artist:     "Pink Floyd",
+			Album:      "The Dark Side of the Moon",
+			Duration:   6*time.Minute + 53*time.Second,
+			FileFormat: ".flac",
+			Metadata:   &domain.TrackMetadata{Genre: "Rock", Year: 1973, Codec: "FLAC", SampleRate: 96000, BitDepth: 24, Channels: 2},
+		},
+		{
+			FilePath:   "/music/pink floyd/wish.flac",
+			Title:      "Wish You Were Here",
+			Artist:     "Pink Floyd",
+			Album:      "Wish You Were Here",
+			Duration:   5*time.Minute + 34*time.Second,
+			FileFormat: ".flac",
+			Metadata:   &domain.TrackMetadata{Genre: "Progressive Rock", Year: 1975},
+		},
+		{
+			FilePath:   "/music/pink floyd/live.mp3",
+			Title:      "Comfortably Numb (Live)",
+			Artist:     "Pink Floyd",
+			Album:      "Pulse",
+			Duration:   9 * time.Minute,
+			FileFormat: ".mp3",
+			Metadata:   &domain.TrackMetadata{Genre: "Rock", Year: 1995, Codec: "MP3", BitRate: 320, SampleRate: 44100, Channels: 2},
+		},
+		{
+			FilePath:   "/music/other/song.mp3",
+			Title:      "Short Song",
+			Artist:     "Someone Else",
+			Duration:   2*time.Minute + 10*time.Second,
+			FileFormat: ".mp3",
+		},
+	}
+}
+
+// searchTitles compiles the query and returns the titles of matching tracks.
+func searchTitles(t *testing.T, query string) []string {
+	t.Helper()
+
+	predicate, err := CompileQuery(query)
+	require.NoError(t, err)
+
+	titles := make([]string, 0)
+	for _, track := range FilterTracks(queryTestTracks(), predicate) {
+		titles = append(titles, track.Title)
+	}
+	return titles
+}
+
+func TestCompileQuery_Empty(t *testing.T) {
+	assert.Len(t, searchTitles(t, ""), 4)
+	assert.Len(t, searchTitles(t, "   "), 4)
+}
+
+func TestCompileQuery_BareWords(t *testing.T) {
+	assert.Equal(t, []string{"Time"}, searchTitles(t, "dark"))
+	assert.Equal(t, []string{"Short Song"}, searchTitles(t, "other"))
+	assert.Len(t, searchTitles(t, "PINK floyd"), 3)
+	assert.Equal(t, []string{"Time"}, searchTitles(t, `"dark side"`))
+}
+
+func TestCompileQuery_Fields(t *testing.T) {
+	assert.Len(t, searchTitles(t, `artist:"pink floyd"`), 3)
+	assert.Len(t, searchTitles(t, "genre:rock"), 3)
+	assert.Equal(t, []string{"Time", "Comfortably Numb (Live)"}, searchTitles(t, "genre:=rock"))
+	assert.Equal(t, []string{"Wish You Were Here"}, searchTitles(t, "album:wish"))
+}
+
+func TestCompileQuery_AlbumArtist(t *testing.T) {
+	tracks := []domain.MusicTrack{
+		{Title: "Summer Hit", Artist: "Band", Album: "Hits of the Year", Compilation: true},
+		{Title: "Duet", Artist: "Singer feat. Guest", AlbumArtist: "Singer", Album: "Songs"},
+		{Title: "Solo", Artist: "Singer", Album: "Songs"},
+	}
+	titles := func(query string) []string {
+		predicate, err := CompileQuery(query)
+		require.NoError(t, err)
+		result := make([]string, 0)
+		for _, track := range FilterTracks(tracks, predicate) {
+			result = append(result, track.Title)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Summer Hit"}, titles("albumartist:various"))
+	assert.Equal(t, []string{"Summer Hit"}, titles("compilation:1"))
+	assert.Equal(t, []string{"Duet", "Solo"}, titles("compilation:0 albumartist:=singer"))
+}
+
+func TestCompileQuery_NonASCII(t *testing.T) {
+	tracks := []domain.MusicTrack{
+		{Title: "Déjà Vu", Artist: "Beyoncé", FilePath: "/music/déjà vu.mp3"},
+		{Title: "Ångström", Artist: "Ölfusá", Album: "Ἀρχή"},
+		{Title: "夜に駆ける", Artist: "YOASOBI", Album: "THE BOOK"},
+		{Title: "Plain", Artist: "Someone"},
+	}
+	titles := func(query string) []string {
+		predicate, err := CompileQuery(query)
+		require.NoError(t, err)
+		result := make([]string, 0)
+		for _, track := range FilterTracks(tracks, predicate) {
+			result = append(result, track.Title)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Déjà Vu"}, titles("déjà"))
+	assert.Equal(t, []string{"Déjà Vu"}, titles("DÉJÀ vu"))
+	assert.Equal(t, []string{"Ångström"}, titles("Ångström"))
+	assert.Equal(t, []string{"Ångström"}, titles("artist:=ölfusá"))
+	assert.Equal(t, []string{"夜に駆ける"}, titles("駆ける"))
+	assert.Equal(t, []string{"夜に駆ける"}, titles(`title:"夜に駆ける"`))
+	assert.Equal(t, []string{"Déjà Vu", "Plain"}, titles("-Ångström -夜"))
+
+	// Columns are counted in runes
+	_, err := CompileQuery("déjà bpm:1")
+	var syntaxErr *domain.QuerySyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	assert.Equal(t, 6, syntaxErr.Position)
+}
+
+func TestCompileQuery_Negation(t *testing.T) {
+	assert.Equal(t, []string{"Time", "Wish You Were Here", "Short Song"}, searchTitles(t, "-live"))
+	assert.Equal(t, []string{"Short Song"}, searchTitles(t, `-artist:"pink floyd"`))
+}
+
+func TestCompileQuery_Numbers(t *testing.T) {
+	assert.Equal(t, []string{"Wish You Were Here", "Comfortably Numb (Live)"}, searchTitles(t, "year:>=1975"))
+	assert.Equal(t, []string{"Time"}, searchTitles(t, "year:1973"))
+	assert.Equal(t, []string{"Time", "Wish You Were Here"}, searchTitles(t, "year:1970..1979"))
+	assert.Equal(t, []string{"Short Song"}, searchTitles(t, "year:<1"))
+}
+
+func TestCompileQuery_Durations(t *testing.T) {
+	assert.Equal(t, []string{"Short Song"}, searchTitles(t, "duration:<5m"))
+	assert.Equal(t, []string{"Wish You Were Here", "Short Song"}, searchTitles(t, "duration:<6:00"))
+	assert.Equal(t, []string{"Comfortably Numb (Live)"}, searchTitles(t, "duration:>=540"))
+	assert.Equal(t, []string{"Time", "Wish You Were Here"}, searchTitles(t, "duration:5m..7m"))
+}
+
+func TestCompileQuery_Format(t *testing.T) {
+	assert.Len(t, searchTitles(t, "format:flac"), 2)
+	assert.Len(t, searchTitles(t, "format:.MP3"), 2)
+	assert.Empty(t, searchTitles(t, "format:fla"))
+}
+
+func TestCompileQuery_TechnicalFields(t *testing.T) {
+	assert.Equal(t, []string{"Time"}, searchTitles(t, "bitdepth:>=24"))
+	assert.Equal(t, []string{"Time"}, searchTitles(t, "samplerate:>48000"))
+	assert.Equal(t, []string{"Comfortably Numb (Live)"}, searchTitles(t, "codec:=mp3 bitrate:320"))
+	assert.Len(t, searchTitles(t, "channels:2"), 2)
+}
+
+func TestCompileQuery_Combined(t *testing.T) {
+	titles := searchTitles(t, `artist:"pink floyd" year:>=1975 genre:rock -live duration:<6m format:flac`)
+	assert.Equal(t, []string{"Wish You Were Here"}, titles)
+}
+
+func TestCompileQuery_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{`bpm:120`, 1, `unknown field "bpm"`},
+		{`rock year:`, 11, `missing value for field "year"`},
+		{`artist:"pink floyd`, 8, "unterminated quoted phrase"},
+		{`title:>abc`, 7, `operator ">" cannot be used with text field "title"`},
+		{`year:>=abc`, 8, `"abc" is not a number`},
+		{`duration:<fast`, 11, `"fast" is not a valid duration`},
+		{`year:1970..x`, 12, "invalid range end"},
+		{`rock -`, 6, "expected a search term after '-'"},
+		{`:rock`, 1, "expected a field name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := CompileQuery(tt.query)
+			require.Error(t, err)
+
+			var syntaxErr *domain.QuerySyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.query, syntaxErr.Query)
+			assert.Equal(t, tt.position, syntaxErr.Position)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+const (
+	// DefaultUndoDepth is the number of queue edits that can be undone by default.
+	DefaultUndoDepth = 100
+
+	// MaxUndoDepth is the largest number of queue edits that can be kept.
+	MaxUndoDepth = 1000
+)
+
+// queueEdit is a reversible change to the queue.
+// Edits store indexes and the tracks they removed rather than copies of the
+// whole queue, so that a long history of a large queue stays small.
+// Tracks are held by the history (see queueHistory.hold) and looked up when
+// the edit is applied, so that they are put back as they are now.
+type queueEdit struct {
+	action string   // Shown to the user, e.g. "Remove Track"
+	refs   []string // Keys of the tracks held for the edit
+	undo   func()
+	redo   func()
+}
+
+// queueHistory keeps the queue edits that can be undone and redone.
+// Not thread-safe: the PlaylistService lock protects it.
+type queueHistory struct {
+	depth int
+	undo  []queueEdit
+	redo  []queueEdit
+
+	// Tracks held for the edits, by their file path when they were held.
+	// They follow tag edits and relocations, so undoing an edit does not
+	// bring back old tags or paths.
+	tracks map[string]domain.MusicTrack
+	moved  map[string]string // Current path by the path when an edit was recorded
+}
+
+// newQueueHistory creates an empty history keeping up to depth edits.
+func newQueueHistory(depth int) *queueHistory {
+	return &queueHistory{
+		depth:  depth,
+		tracks: make(map[string]domain.MusicTrack),
+		moved:  make(map[string]string),
+	}
+}
+
+// record adds an edit that was just made. Edits that were undone can no longer be redone.
+func (h *queueHistory) record(edit queueEdit) {
+	h.undo = append(h.undo, edit)
+	dropped := h.trim() || len(h.redo) > 0
+	h.redo = nil
+	if dropped {
+		h.release()
+	}
+}
+
+// hold keeps tracks for an edit about to be recorded.
+// Returns the keys to look them up with; they go in the edit's refs.
+func (h *queueHistory) hold(tracks []domain.MusicTrack) []string {
+	refs := make([]string, len(tracks))
+	for i, track := range tracks {
+		refs[i] = track.FilePath
+		h.tracks[track.FilePath] = track
+	}
+	return refs
+}
+
+// resolve returns the held tracks for refs, as they are now.
+func (h *queueHistory) resolve(refs []string) []domain.MusicTrack {
+	tracks := make([]domain.MusicTrack, 0, len(refs))
+	for _, ref := range refs {
+		if track, ok := h.tracks[ref]; ok {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// updateTracks applies update to the held tracks, keeping their queue IDs,
+// and adds the paths that changed to movedPaths.
+func (h *queueHistory) updateTracks(update func(domain.MusicTrack) (domain.MusicTrack, bool), movedPaths map[string]string) {
+	for ref, track := range h.tracks {
+		updated, ok := update(track)
+		if !ok {
+			continue
+		}
+		if updated.FilePath != track.FilePath {
+			movedPaths[track.FilePath] = updated.FilePath
+		}
+		updated.ID = track.ID
+		h.tracks[ref] = updated
+	}
+}
+
+// recordMoves notes that files were moved, from the keys of movedPaths to their values,
+// so that shuffle states of past edits can be brought up to date (see currentShuffle).
+func (h *queueHistory) recordMoves(movedPaths map[string]string) {
+	for from, to := range h.moved {
+		if newPath, ok := movedPaths[to]; ok {
+			h.moved[from] = newPath
+		}
+	}
+	for from, to := range movedPaths {
+		h.moved[from] = to
+	}
+}
+
+// currentShuffle returns a shuffle state of a past edit with the original order
+// pointing at the current paths of moved files.
+func (h *queueHistory) currentShuffle(state domain.ShuffleState) domain.ShuffleState {
+	if len(h.moved) == 0 || len(state.OriginalOrder) == 0 {
+		return state
+	}
+	order := make([]string, len(state.OriginalOrder))
+	for i, path := range state.OriginalOrder {
+		if newPath, ok := h.moved[path]; ok {
+			path = newPath
+		}
+		order[i] = path
+	}
+	state.OriginalOrder = order
+	return state
+}
+
+// release forgets the held tracks that no edit refers to anymore.
+func (h *queueHistory) release() {
+	used := make(map[string]bool)
+	for _, edits := range [][]queueEdit{h.undo, h.redo} {
+		for _, edit := range edits {
+			for _, ref := range edit.refs {
+				used[ref] = true
+			}
+		}
+	}
+	for ref := range h.tracks {
+		if !used[ref] {
+			delete(h.tracks, ref)
+		}
+	}
+}
+
+// popUndo returns the most recent edit and moves it to the redo stack.
+func (h *queueHistory) popUndo() (queueEdit, bool) {
+	if len(h.undo) == 0 {
+		return queueEdit{}, false
+	}
+	edit := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, edit)
+	return edit, true
+}
+
+// popRedo returns the most recently undone edit and moves it back to the undo stack.
+func (h *queueHistory) popRedo() (queueEdit, bool) {
+	if len(h.redo) == 0 {
+		return queueEdit{}, false
+	}
+	edit := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, edit)
+	return edit, true
+}
+
+// setDepth changes how many edits are kept, dropping the oldest if needed.
+func (h *queueHistory) setDepth(depth int) {
+	h.depth = depth
+	if h.trim() {
+		h.release()
+	}
+}
+
+// trim drops the oldest edits beyond the depth.
+// Returns true if any edit was dropped.
+func (h *queueHistory) trim() bool {
+	excess := len(h.undo) - h.depth
+	if excess <= 0 {
+		return false
+	}
+	h.undo = append([]queueEdit(nil), h.undo[excess:]...)
+	return true
+}
+
+// clear forgets all edits.
+func (h *queueHistory) clear() {
+	h.undo = nil
+	h.redo = nil
+	clear(h.tracks)
+	clear(h.moved)
+}
+
+// actions returns the actions of the next edit to undo and to redo (empty if none).
+func (h *queueHistory) actions() (string, string) {
+	var undoAction, redoAction string
+	if len(h.undo) > 0 {
+		undoAction = h.undo[len(h.undo)-1].action
+	}
+	if len(h.redo) > 0 {
+		redoAction = h.redo[len(h.redo)-1].action
+	}
+	return undoAction, redoAction
+}
+
+// queuePermutation returns the order of after in terms of the file paths of the
+// queue before it was reordered: after[i] was at position order[i].
+// Both must hold the same tracks, which are told apart by file path.
+func queuePermutation(before []string, after []domain.MusicTrack) []int {
+	positions := make(map[string]int, len(before))
+	for i, path := range before {
+		positions[path] = i
+	}
+	order := make([]int, len(after))
+	for i, track := range after {
+		order[i] = positions[track.FilePath]
+	}
+	return order
+}
+
+// permuteQueue returns the tracks of queue in the given order: result[i] is queue[order[i]].
+func permuteQueue(queue []domain.MusicTrack, order []int) []domain.MusicTrack {
+	result := make([]domain.MusicTrack, len(order))
+	for i, from := range order {
+		result[i] = queue[from]
+	}
+	return result
+}
+
+// invertPermutation returns the order that undoes order.
+func invertPermutation(order []int) []int {
+	inverse := make([]int, len(order))
+	for i, from := range order {
+		inverse[from] = i
+	}
+	return inverse
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestQueueHistory_UndoRedo(t *testing.T) {
+	h := newQueueHistory(2)
+
+	_, ok := h.popUndo()
+	assert.False(t, ok)
+
+	h.record(queueEdit{action: "First"})
+	h.record(queueEdit{action: "Second"})
+	h.record(queueEdit{action: "Third"})
+
+	undoAction, redoAction := h.actions()
+	assert.Equal(t, "Third", undoAction)
+	assert.Empty(t, redoAction)
+
+	// The depth keeps the two most recent edits
+	edit, ok := h.popUndo()
+	assert.True(t, ok)
+	assert.Equal(t, "Third", edit.action)
+	edit, ok = h.popUndo()
+	assert.True(t, ok)
+	assert.Equal(t, "Second", edit.action)
+	_, ok = h.popUndo()
+	assert.False(t, ok)
+
+	edit, ok = h.popRedo()
+	assert.True(t, ok)
+	assert.Equal(t, "Second", edit.action)
+
+	// A new edit cannot be followed by redoing older ones
+	h.record(queueEdit{action: "Fourth"})
+	_, ok = h.popRedo()
+	assert.False(t, ok)
+
+	h.setDepth(1)
+	assert.Len(t, h.undo, 1)
+	h.clear()
+	undoAction, redoAction = h.actions()
+	assert.Empty(t, undoAction)
+	assert.Empty(t, redoAction)
+}
+
+func TestQueueHistory_HeldTracks(t *testing.T) {
+	h := newQueueHistory(1)
+	first := domain.MusicTrack{ID: "1", FilePath: "/old/a.mp3"}
+	second := domain.MusicTrack{ID: "2", FilePath: "/old/b.mp3"}
+
+	refs := h.hold([]domain.MusicTrack{first})
+	h.record(queueEdit{action: "First", refs: refs})
+
+	moved := make(map[string]string)
+	h.updateTracks(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		track.ID = "library-id"
+		track.FilePath = "/new/a.mp3"
+		return track, true
+	}, moved)
+	h.recordMoves(moved)
+
+	tracks := h.resolve(refs)
+	assert.Equal(t, []domain.MusicTrack{{ID: "1", FilePath: "/new/a.mp3"}}, tracks)
+	state := h.currentShuffle(domain.ShuffleState{Mode: domain.ShuffleTracks, OriginalOrder: []string{"/old/a.mp3", "/old/c.mp3"}})
+	assert.Equal(t, []string{"/new/a.mp3", "/old/c.mp3"}, state.OriginalOrder)
+
+	// Tracks of dropped edits are released
+	h.record(queueEdit{action: "Second", refs: h.hold([]domain.MusicTrack{second})})
+	assert.Empty(t, h.resolve(refs))
+	assert.Len(t, h.tracks, 1)
+
+	h.clear()
+	assert.Empty(t, h.tracks)
+	assert.Empty(t, h.moved)
+}
+
+func TestQueuePermutation(t *testing.T) {
+	before := []domain.MusicTrack{{FilePath: "/a"}, {FilePath: "/b"}, {FilePath: "/c"}}
+	after := []domain.MusicTrack{{FilePath: "/c"}, {FilePath: "/a"}, {FilePath: "/b"}}
+
+	order := queuePermutation(queuePaths(before), after)
+	assert.Equal(t, []int{2, 0, 1}, order)
+	assert.Equal(t, after, permuteQueue(before, order))
+	assert.Equal(t, before, permuteQueue(after, invertPermutation(order)))
+}
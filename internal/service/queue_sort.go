@@ -0,0 +1,94 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// queueSortKeys maps sort fields to a comparison of two tracks.
+// Text is compared case-folded, using sort tags where the files have them.
+var queueSortKeys = map[domain.QueueSortField]func(a, b domain.MusicTrack) int{
+	domain.QueueSortTitle:       func(a, b domain.MusicTrack) int { return cmp.Compare(a.SortTitle(), b.SortTitle()) },
+	domain.QueueSortArtist:      func(a, b domain.MusicTrack) int { return cmp.Compare(a.SortArtist(), b.SortArtist()) },
+	domain.QueueSortAlbumArtist: func(a, b domain.MusicTrack) int { return cmp.Compare(a.SortAlbumArtist(), b.SortAlbumArtist()) },
+	domain.QueueSortAlbum:       func(a, b domain.MusicTrack) int { return cmp.Compare(a.SortAlbum(), b.SortAlbum()) },
+	domain.QueueSortYear: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(metadataOf(a).Year, metadataOf(b).Year)
+	},
+	domain.QueueSortDisc: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(metadataOf(a).DiscNumber, metadataOf(b).DiscNumber)
+	},
+	domain.QueueSortTrack: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(metadataOf(a).TrackNumber, metadataOf(b).TrackNumber)
+	},
+	domain.QueueSortGenre: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(strings.ToLower(metadataOf(a).Genre), strings.ToLower(metadataOf(b).Genre))
+	},
+	domain.QueueSortDuration: func(a, b domain.MusicTrack) int { return cmp.Compare(a.Duration, b.Duration) },
+	domain.QueueSortRating: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(metadataOf(a).Rating, metadataOf(b).Rating)
+	},
+	domain.QueueSortFormat: func(a, b domain.MusicTrack) int {
+		return cmp.Compare(normalizeFormat(a.FileFormat), normalizeFormat(b.FileFormat))
+	},
+	domain.QueueSortPath: func(a, b domain.MusicTrack) int { return cmp.Compare(a.FilePath, b.FilePath) },
+}
+
+// queueSortTieBreakers order tracks that are equal in the chosen field, so that
+// sorting by e.g. album artist lists each artist's albums by year and in track order.
+var queueSortTieBreakers = []domain.QueueSortField{
+	domain.QueueSortAlbumArtist,
+	domain.QueueSortYear,
+	domain.QueueSortAlbum,
+	domain.QueueSortDisc,
+	domain.QueueSortTrack,
+	domain.QueueSortTitle,
+}
+
+// queueSortKeysFor returns the keys SortBy sorts with: the field in the
+// direction, then the tie breakers other than the field in ascending order.
+func queueSortKeysFor(field domain.QueueSortField, direction domain.SortDirection) []domain.QueueSortKey {
+	keys := []domain.QueueSortKey{{Field: field, Direction: direction}}
+	for _, tieBreaker := range queueSortTieBreakers {
+		if tieBreaker != field {
+			keys = append(keys, domain.QueueSortKey{Field: tieBreaker, Direction: domain.SortAscending})
+		}
+	}
+	return keys
+}
+
+// validateQueueSortKeys returns a ValidationError if the queue cannot be sorted by the keys.
+func validateQueueSortKeys(keys []domain.QueueSortKey) error {
+	if len(keys) == 0 {
+		return domain.NewValidationError("keys", keys, "at least one sort key is required")
+	}
+	for _, key := range keys {
+		if _, ok := queueSortKeys[key.Field]; !ok {
+			return domain.NewValidationError("field", key.Field, "unknown sort field")
+		}
+		if !key.Direction.IsValid() {
+			return domain.NewValidationError("direction", key.Direction, "must be asc or desc")
+		}
+	}
+	return nil
+}
+
+// sortQueue stably sorts tracks by the keys, comparing by each key in turn
+// until tracks differ. Tracks equal in all keys keep their order. Expects valid keys.
+func sortQueue(tracks []domain.MusicTrack, keys []domain.QueueSortKey) {
+	slices.SortStableFunc(tracks, func(a, b domain.MusicTrack) int {
+		for _, key := range keys {
+			result := queueSortKeys[key.Field](a, b)
+			if key.Direction == domain.SortDescending {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	})
+}
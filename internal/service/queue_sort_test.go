@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// sortTestTrack creates a track with an album artist, year, disc and track number.
+func sortTestTrack(title, albumArtist, album string, year, disc, track int) domain.MusicTrack {
+	return domain.MusicTrack{
+		FilePath:    "/music/" + title + ".mp3",
+		Title:       title,
+		Artist:      albumArtist,
+		AlbumArtist: albumArtist,
+		Album:       album,
+		Metadata:    &domain.TrackMetadata{Year: year, DiscNumber: disc, TrackNumber: track},
+	}
+}
+
+func sortedTitles(tracks []domain.MusicTrack) []string {
+	titles := make([]string, len(tracks))
+	for i, track := range tracks {
+		titles[i] = track.Title
+	}
+	return titles
+}
+
+func TestSortQueue_TieBreakers(t *testing.T) {
+	tracks := []domain.MusicTrack{
+		sortTestTrack("Late 1", "beta", "Later", 2001, 1, 1),
+		sortTestTrack("Early 2", "Beta", "Earlier", 1990, 2, 1),
+		sortTestTrack("Alpha", "Alpha", "Only", 2010, 1, 1),
+		sortTestTrack("Early 1", "Beta", "Earlier", 1990, 1, 3),
+	}
+
+	// Album artist, then year, then disc, then track
+	sortQueue(tracks, queueSortKeysFor(domain.QueueSortAlbumArtist, domain.SortAscending))
+	assert.Equal(t, []string{"Alpha", "Early 1", "Early 2", "Late 1"}, sortedTitles(tracks))
+
+	// Only the chosen field is reversed
+	sortQueue(tracks, queueSortKeysFor(domain.QueueSortAlbumArtist, domain.SortDescending))
+	assert.Equal(t, []string{"Early 1", "Early 2", "Late 1", "Alpha"}, sortedTitles(tracks))
+}
+
+func TestSortQueue_Stable(t *testing.T) {
+	tracks := []domain.MusicTrack{
+		{Title: "B", FilePath: "/b.mp3", FileFormat: "MP3"},
+		{Title: "A", FilePath: "/a.flac", FileFormat: "flac"},
+		{Title: "C", FilePath: "/c.mp3", FileFormat: ".mp3"},
+	}
+
+	// Tracks equal in all keys keep their order
+	sortQueue(tracks, []domain.QueueSortKey{{Field: domain.QueueSortFormat, Direction: domain.SortDescending}})
+	assert.Equal(t, []string{"B", "C", "A"}, sortedTitles(tracks))
+}
+
+func TestValidateQueueSortKeys(t *testing.T) {
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, validateQueueSortKeys(nil), &validationErr)
+	assert.ErrorAs(t, validateQueueSortKeys([]domain.QueueSortKey{{Field: "mood", Direction: domain.SortAscending}}), &validationErr)
+	assert.ErrorAs(t, validateQueueSortKeys([]domain.QueueSortKey{{Field: domain.QueueSortYear, Direction: "up"}}), &validationErr)
+	assert.NoError(t, validateQueueSortKeys(queueSortKeysFor(domain.QueueSortPath, domain.SortDescending)))
+}
@@ -0,0 +1,420 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// RelocationService finds tracks whose files have been moved or deleted and
+// points them at their new location.
+// Changes are written to the queue (and through it the history repository),
+// the library and the saved playlists. The statistics and listening history of
+// relocated tracks are moved over to their new paths.
+// All operations are serialized via sync.Mutex.
+type RelocationService struct {
+	// Dependencies (injected)
+	logger      *slog.Logger
+	playlist    *PlaylistService
+	preferences *PreferenceService
+	library     ports.LibraryRepository
+	playlists   ports.PlaylistRepository
+	stats       ports.StatsRepository
+	history     ports.HistoryRepository
+
+	// Concurrency control
+	mu sync.Mutex
+}
+
+// NewRelocationService creates a new relocation service.
+func NewRelocationService(
+	logger *slog.Logger,
+	playlist *PlaylistService,
+	preferences *PreferenceService,
+	library ports.LibraryRepository,
+	playlists ports.PlaylistRepository,
+	stats ports.StatsRepository,
+	history ports.HistoryRepository,
+) *RelocationService {
+	logger.Debug("relocation service initialized")
+
+	return &RelocationService{
+		logger:      logger,
+		playlist:    playlist,
+		preferences: preferences,
+		library:     library,
+		playlists:   playlists,
+		stats:       stats,
+		history:     history,
+	}
+}
+
+// CheckMissing checks every track in the queue, library and saved playlists,
+// and sets or clears its Missing flag.
+// Returns the missing tracks, one per file path.
+func (s *RelocationService) CheckMissing() ([]domain.MusicTrack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists := newFileChecker()
+	missing := make([]domain.MusicTrack, 0)
+	seen := make(map[string]bool)
+
+	_, err := s.updateAll(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		isMissing := !exists(track.FilePath)
+		changed := track.Missing != isMissing
+		track.Missing = isMissing
+		if isMissing && !seen[track.FilePath] {
+			seen[track.FilePath] = true
+			missing = append(missing, track)
+		}
+		return track, changed
+	})
+
+	s.logger.Info("checked for missing files", slog.Int("missing", len(missing)))
+
+	return missing, err
+}
+
+// RelocatePrefix replaces oldPrefix with newPrefix in the paths of all tracks.
+// The prefix must match whole path elements, and a track is only changed if
+// its file exists at the new path.
+// Returns the number of relocated files.
+func (s *RelocationService) RelocatePrefix(oldPrefix, newPrefix string) (int, error) {
+	if oldPrefix == "" {
+		return 0, domain.NewValidationError("old prefix", oldPrefix, "cannot be empty")
+	}
+	if newPrefix == "" {
+		return 0, domain.NewValidationError("new prefix", newPrefix, "cannot be empty")
+	}
+	oldPrefix = filepath.Clean(oldPrefix)
+	newPrefix = filepath.Clean(newPrefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists := newFileChecker()
+	relocated, err := s.updateAll(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		if !isWithinPath(track.FilePath, oldPrefix) {
+			return track, false
+		}
+		newPath := filepath.Join(newPrefix, strings.TrimPrefix(track.FilePath, oldPrefix))
+		if newPath == track.FilePath || !exists(newPath) {
+			return track, false
+		}
+		return relocateTrack(track, newPath), true
+	})
+
+	s.logger.Info("relocated files by prefix",
+		slog.String("old", oldPrefix),
+		slog.String("new", newPrefix),
+		slog.Int("relocated", relocated))
+
+	return relocated, err
+}
+
+// RelocateByFilename searches the scanned folders for missing files.
+// A file is a match if it has the same name (ignoring case) and, when the
+// original size is known, the same size. Tracks with several matches are left alone.
+// Tracks inside archives are matched by the name of their archive.
+// Returns the number of relocated files.
+func (s *RelocationService) RelocateByFilename() (int, error) {
+	scanPaths := s.preferences.GetScanPaths()
+	if len(scanPaths) == 0 {
+		return 0, domain.NewValidationError("scan paths", scanPaths, "no folders have been scanned")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := indexFilesByName(scanPaths)
+	exists := newFileChecker()
+	relocated, err := s.updateAll(func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		if exists(track.FilePath) {
+			return track, false
+		}
+
+		match, ok := matchByFilename(track, candidates)
+		if !ok {
+			return track, false
+		}
+		return relocateTrack(track, match), true
+	})
+
+	s.logger.Info("relocated files by name", slog.Int("relocated", relocated))
+
+	return relocated, err
+}
+
+// matchByFilename returns the new path of a track if exactly one candidate file matches it.
+// A track inside an archive is matched by the name of the archive and keeps its entry
+// path inside it; its size is the size of the entry, so it is not compared.
+func matchByFilename(track domain.MusicTrack, candidates map[string][]fileCandidate) (string, bool) {
+	archivePath, entryName, inArchive := domain.SplitArchivePath(track.FilePath)
+	name := filepath.Base(track.FilePath)
+	if inArchive {
+		name = filepath.Base(archivePath)
+	}
+
+	var match string
+	for _, candidate := range candidates[strings.ToLower(name)] {
+		if !inArchive && track.FileSize > 0 && candidate.size != track.FileSize {
+			continue
+		}
+		if match != "" {
+			return "", false // Ambiguous
+		}
+		match = candidate.path
+	}
+	if match == "" {
+		return "", false
+	}
+	if inArchive {
+		return domain.ArchiveEntryPath(match, entryName), true
+	}
+	return match, true
+}
+
+// updateAll applies update to the tracks of the queue, the library and the saved playlists,
+// then moves the statistics and listening history of tracks whose path changed.
+// update must give the same result for tracks with the same file path.
+// Returns the number of distinct file paths that were changed.
+// Must be called with mutex lock held.
+func (s *RelocationService) updateAll(update func(domain.MusicTrack) (domain.MusicTrack, bool)) (int, error) {
+	changedPaths := make(map[string]bool)
+	movedPaths := make(map[string]string) // New path by old path
+	tracked := func(track domain.MusicTrack) (domain.MusicTrack, bool) {
+		updated, ok := update(track)
+		if ok {
+			changedPaths[track.FilePath] = true
+			if updated.FilePath != track.FilePath {
+				movedPaths[track.FilePath] = updated.FilePath
+			}
+		}
+		return updated, ok
+	}
+
+	s.playlist.UpdateTracks(tracked)
+
+	failures := make([]error, 0)
+	if err := s.updateLibrary(tracked); err != nil {
+		failures = append(failures, err)
+	}
+	if err := s.updatePlaylists(tracked); err != nil {
+		failures = append(failures, err)
+	}
+
+	if len(movedPaths) > 0 {
+		if err := s.moveStats(movedPaths); err != nil {
+			failures = append(failures, err)
+		}
+		if err := s.history.RelocateListens(movedPaths); err != nil {
+			failures = append(failures, domain.NewServiceError("RelocationService", "updateAll",
+				"failed to relocate listening history", err))
+		}
+	}
+
+	return len(changedPaths), errors.Join(failures...)
+}
+
+// moveStats moves the statistics of moved files to the stable IDs of their new paths.
+// Stats are keyed by a hash of the path, so they would otherwise be lost on relocation.
+// If the new path already has stats, the two are merged.
+// Must be called with mutex lock held.
+func (s *RelocationService) moveStats(movedPaths map[string]string) error {
+	moved := make([]domain.TrackStats, 0, len(movedPaths))
+	oldIDs := make([]string, 0, len(movedPaths))
+	for oldPath, newPath := range movedPaths {
+		oldID := domain.MusicTrack{FilePath: oldPath}.StableID()
+		old, err := s.stats.LoadStats(oldID)
+		if err != nil {
+			return domain.NewServiceError("RelocationService", "moveStats", "failed to load stats", err)
+		}
+		if old == nil {
+			continue
+		}
+
+		stats := *old
+		stats.TrackID = domain.MusicTrack{FilePath: newPath}.StableID()
+		stats.FilePath = newPath
+		existing, err := s.stats.LoadStats(stats.TrackID)
+		if err != nil {
+			return domain.NewServiceError("RelocationService", "moveStats", "failed to load stats", err)
+		}
+		if existing != nil {
+			stats = mergeTrackStats(stats, *existing)
+		}
+
+		moved = append(moved, stats)
+		oldIDs = append(oldIDs, oldID)
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+
+	if err := s.stats.SaveStats(moved); err != nil {
+		return domain.NewServiceError("RelocationService", "moveStats", "failed to save stats", err)
+	}
+	if err := s.stats.RemoveStats(oldIDs); err != nil {
+		return domain.NewServiceError("RelocationService", "moveStats", "failed to remove stats", err)
+	}
+	return nil
+}
+
+// mergeTrackStats adds the counts of other to stats and keeps the earliest
+// and latest times. Zero times are ignored.
+func mergeTrackStats(stats, other domain.TrackStats) domain.TrackStats {
+	earliest := func(a, b time.Time) time.Time {
+		if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+			return b
+		}
+		return a
+	}
+
+	stats.PlayCount += other.PlayCount
+	stats.SkipCount += other.SkipCount
+	stats.AddedAt = earliest(stats.AddedAt, other.AddedAt)
+	stats.FirstPlayedAt = earliest(stats.FirstPlayedAt, other.FirstPlayedAt)
+	if other.LastPlayedAt.After(stats.LastPlayedAt) {
+		stats.LastPlayedAt = other.LastPlayedAt
+	}
+	return stats
+}
+
+// updateLibrary applies update to every library track and saves the library if anything changed.
+// Must be called with mutex lock held.
+func (s *RelocationService) updateLibrary(update func(domain.MusicTrack) (domain.MusicTrack, bool)) error {
+	tracks, err := s.library.LoadAll()
+	if err != nil {
+		return domain.NewServiceError("RelocationService", "updateLibrary", "failed to load library", err)
+	}
+
+	changed := false
+	for i := range tracks {
+		if track, ok := update(tracks[i]); ok {
+			track.ID = tracks[i].ID
+			tracks[i] = track
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	// The library is keyed by path, so relocated tracks replace the whole library
+	if err := s.library.ReplaceAll(tracks); err != nil {
+		return domain.NewServiceError("RelocationService", "updateLibrary", "failed to save library", err)
+	}
+	return nil
+}
+
+// updatePlaylists applies update to the tracks of every saved playlist and saves the changed ones.
+// Must be called with mutex lock held.
+func (s *RelocationService) updatePlaylists(update func(domain.MusicTrack) (domain.MusicTrack, bool)) error {
+	playlists, err := s.playlists.LoadAll()
+	if err != nil {
+		return domain.NewServiceError("RelocationService", "updatePlaylists", "failed to load playlists", err)
+	}
+
+	failures := make([]error, 0)
+	for _, playlist := range playlists {
+		changed := false
+		for i := range playlist.Tracks {
+			if track, ok := update(playlist.Tracks[i]); ok {
+				track.ID = playlist.Tracks[i].ID
+				playlist.Tracks[i] = track
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		playlist.UpdatedAt = time.Now()
+		if err := s.playlists.Save(playlist); err != nil {
+			failures = append(failures, domain.NewServiceError("RelocationService", "updatePlaylists",
+				"failed to save playlist "+playlist.Name, err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// relocateTrack returns the track pointing at its new path.
+func relocateTrack(track domain.MusicTrack, newPath string) domain.MusicTrack {
+	track.FilePath = newPath
+	track.Missing = false
+	return track
+}
+
+// newFileChecker returns a function that reports whether a regular file exists.
+// Tracks inside archives are checked by their archive.
+// Results are cached, as the same file is usually in the queue, library and playlists.
+func newFileChecker() func(path string) bool {
+	cache := make(map[string]bool)
+	return func(path string) bool {
+		path = domain.PhysicalPath(path)
+		exists, ok := cache[path]
+		if !ok {
+			info, err := os.Stat(path)
+			exists = err == nil && info.Mode().IsRegular()
+			cache[path] = exists
+		}
+		return exists
+	}
+}
+
+// fileCandidate is a file found while searching the scanned folders.
+type fileCandidate struct {
+	path string
+	size int64
+}
+
+// indexFilesByName lists the files in the given folders by lowercase file name.
+// Folders that cannot be read are skipped.
+func indexFilesByName(dirs []string) map[string][]fileCandidate {
+	index := make(map[string][]fileCandidate)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if entry != nil && entry.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			name := strings.ToLower(entry.Name())
+			index[name] = append(index[name], fileCandidate{path: path, size: info.Size()})
+			return nil
+		})
+	}
+	return index
+}
+
+// Shutdown cleans up resources.
+func (s *RelocationService) Shutdown() error {
+	// No cleanup needed for relocation service
+	return nil
+}
+
+// Verify that RelocationService implements the expected interface patterns
+var _ interface {
+	CheckMissing() ([]domain.MusicTrack, error)
+	RelocatePrefix(string, string) (int, error)
+	RelocateByFilename() (int, error)
+	Shutdown() error
+} = (*RelocationService)(nil)
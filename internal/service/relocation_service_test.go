@@ -0,0 +1,283 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/audio/mock"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// relocationTestEnv holds a relocation service and the stores it updates
+type relocationTestEnv struct {
+	service     *RelocationService
+	playlist    *PlaylistService
+	preferences *PreferenceService
+	library     *mockLibraryRepository
+	playlists   *mockPlaylistRepository
+	history     *mockHistoryRepository
+	stats       *mockStatsRepository
+}
+
+// Helper to create a test relocation service
+func newTestRelocationService(t *testing.T) *relocationTestEnv {
+	engine := mock.NewEngine()
+	_ = engine.Initialize(-1, 44100, 0)
+
+	bus := eventbus.NewSyncEventBus()
+	log := playlistTestLogger()
+	playback := NewPlaybackService(log, engine, bus)
+	env := &relocationTestEnv{
+		preferences: NewPreferenceService(log, newMockPreferencesRepository(), bus),
+		library:     newMockLibraryRepository(),
+		playlists:   newMockPlaylistRepository(),
+		history:     newMockHistoryRepository(),
+		stats:       newMockStatsRepository(),
+	}
+	env.playlist = NewPlaylistService(log, playback, env.playlists, env.history, bus)
+	env.service = NewRelocationService(log, env.playlist, env.preferences, env.library, env.playlists, env.stats, env.history)
+
+	t.Cleanup(func() {
+		_ = env.service.Shutdown()
+		_ = env.playlist.Shutdown()
+		_ = playback.Shutdown()
+	})
+	return env
+}
+
+// addTracks puts the tracks into the queue, the library and a saved playlist
+func (env *relocationTestEnv) addTracks(t *testing.T, tracks ...domain.MusicTrack) {
+	require.NoError(t, env.playlist.AddTracks(tracks, false))
+	require.NoError(t, env.library.SaveTracks(tracks))
+	require.NoError(t, env.playlists.Save(&domain.Playlist{ID: "p1", Name: "Saved", Tracks: tracks}))
+}
+
+// writeRelocationTestFile writes a file with the given contents, creating its directory
+func writeRelocationTestFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
+
+// libraryPaths returns the file paths in the library
+func (env *relocationTestEnv) libraryPaths(t *testing.T) []string {
+	tracks, err := env.library.LoadAll()
+	require.NoError(t, err)
+	paths := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		paths = append(paths, track.FilePath)
+	}
+	return paths
+}
+
+func TestRelocationService_CheckMissing(t *testing.T) {
+	env := newTestRelocationService(t)
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.mp3")
+	gone := filepath.Join(dir, "gone.mp3")
+	writeRelocationTestFile(t, present, "audio")
+	env.addTracks(t, createTestTrack("1", "Present", present), createTestTrack("2", "Gone", gone))
+
+	missing, err := env.service.CheckMissing()
+	require.NoError(t, err)
+	require.Len(t, missing, 1, "A file in several stores is reported once")
+	assert.Equal(t, gone, missing[0].FilePath)
+	assert.True(t, missing[0].Missing)
+
+	queue := env.playlist.GetQueue()
+	assert.False(t, queue[0].Missing)
+	assert.True(t, queue[1].Missing)
+	saved, _ := env.playlists.Load("p1")
+	assert.True(t, saved.Tracks[1].Missing)
+	library, _ := env.library.LoadAll()
+	for _, track := range library {
+		assert.Equal(t, track.FilePath == gone, track.Missing)
+	}
+	assert.True(t, env.history.queue[1].Missing, "Queue changes are saved to history")
+
+	// The flag is cleared when the file comes back
+	writeRelocationTestFile(t, gone, "audio")
+	missing, err = env.service.CheckMissing()
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.False(t, env.playlist.GetQueue()[1].Missing)
+}
+
+func TestRelocationService_RelocatePrefix(t *testing.T) {
+	env := newTestRelocationService(t)
+	oldRoot := filepath.Join(t.TempDir(), "mnt", "music")
+	newRoot := filepath.Join(t.TempDir(), "media", "music")
+	writeRelocationTestFile(t, filepath.Join(newRoot, "album", "a.mp3"), "a")
+	env.addTracks(t,
+		createTestTrack("1", "A", filepath.Join(oldRoot, "album", "a.mp3")),
+		createTestTrack("2", "B", filepath.Join(oldRoot, "album", "b.mp3")), // Not at the new location
+		createTestTrack("3", "C", oldRoot+"2/c.mp3"),                        // Only shares a name prefix
+	)
+
+	relocated, err := env.service.RelocatePrefix(oldRoot+string(filepath.Separator), newRoot)
+	require.NoError(t, err)
+	assert.Equal(t, 1, relocated)
+
+	want := filepath.Join(newRoot, "album", "a.mp3")
+	queue := env.playlist.GetQueue()
+	assert.Equal(t, want, queue[0].FilePath)
+	assert.Equal(t, "1", queue[0].ID)
+	assert.Equal(t, filepath.Join(oldRoot, "album", "b.mp3"), queue[1].FilePath)
+	assert.Equal(t, oldRoot+"2/c.mp3", queue[2].FilePath)
+	assert.Equal(t, want, env.history.queue[0].FilePath)
+
+	assert.Contains(t, env.libraryPaths(t), want)
+	assert.Len(t, env.libraryPaths(t), 3)
+	saved, _ := env.playlists.Load("p1")
+	assert.Equal(t, want, saved.Tracks[0].FilePath)
+	assert.False(t, saved.UpdatedAt.IsZero())
+}
+
+func TestRelocationService_LibrarySaveFailureKeepsLibrary(t *testing.T) {
+	env := newTestRelocationService(t)
+	oldPath := filepath.Join(t.TempDir(), "a.mp3")
+	newDir := t.TempDir()
+	writeRelocationTestFile(t, filepath.Join(newDir, "a.mp3"), "a")
+	env.addTracks(t, createTestTrack("1", "A", oldPath), createTestTrack("2", "B", filepath.Join(newDir, "b.mp3")))
+
+	env.library.saveErr = errors.New("disk full")
+	_, err := env.service.RelocatePrefix(filepath.Dir(oldPath), newDir)
+	assert.Error(t, err)
+	assert.Equal(t, []string{oldPath, filepath.Join(newDir, "b.mp3")}, env.libraryPaths(t),
+		"The library is not erased when saving fails")
+}
+
+func TestRelocationService_KeepsStatsAndHistory(t *testing.T) {
+	env := newTestRelocationService(t)
+	oldPath := filepath.Join(t.TempDir(), "a.mp3")
+	newDir := t.TempDir()
+	newPath := filepath.Join(newDir, "a.mp3")
+	writeRelocationTestFile(t, newPath, "a")
+	track := createTestTrack("1", "A", oldPath)
+	env.addTracks(t, track)
+
+	played := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	require.NoError(t, env.stats.SaveStats([]domain.TrackStats{{
+		TrackID:       track.StableID(),
+		FilePath:      oldPath,
+		PlayCount:     3,
+		SkipCount:     1,
+		FirstPlayedAt: played,
+		LastPlayedAt:  played.Add(time.Hour),
+	}}))
+	// The file was also scanned at its new location before being relocated
+	moved := relocateTrack(track, newPath)
+	require.NoError(t, env.stats.SaveStats([]domain.TrackStats{{
+		TrackID:  moved.StableID(),
+		FilePath: newPath,
+		AddedAt:  played.Add(24 * time.Hour),
+	}}))
+	entry := domain.NewListeningEntry(track)
+	entry.StartedAt = played
+	require.NoError(t, env.history.AppendListen(entry))
+
+	relocated, err := env.service.RelocatePrefix(filepath.Dir(oldPath), newDir)
+	require.NoError(t, err)
+	require.Equal(t, 1, relocated)
+
+	old, err := env.stats.LoadStats(track.StableID())
+	require.NoError(t, err)
+	assert.Nil(t, old, "Stats are moved off the old path")
+	stats, err := env.stats.LoadStats(moved.StableID())
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, newPath, stats.FilePath)
+	assert.Equal(t, 3, stats.PlayCount)
+	assert.Equal(t, 1, stats.SkipCount)
+	assert.Equal(t, played, stats.FirstPlayedAt)
+	assert.Equal(t, played.Add(time.Hour), stats.LastPlayedAt)
+	assert.Equal(t, played.Add(24*time.Hour), stats.AddedAt)
+
+	listens, err := env.history.LoadListens()
+	require.NoError(t, err)
+	require.Len(t, listens, 1)
+	assert.Equal(t, newPath, listens[0].FilePath)
+	assert.Equal(t, moved.StableID(), listens[0].TrackID)
+	assert.Equal(t, played, listens[0].StartedAt)
+}
+
+func TestRelocationService_RelocatePrefix_Validation(t *testing.T) {
+	env := newTestRelocationService(t)
+
+	var validationErr *domain.ValidationError
+	_, err := env.service.RelocatePrefix("", "/new")
+	assert.True(t, errors.As(err, &validationErr))
+	_, err = env.service.RelocatePrefix("/old", "")
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestRelocationService_RelocateByFilename(t *testing.T) {
+	env := newTestRelocationService(t)
+	scanRoot := t.TempDir()
+	require.NoError(t, env.preferences.AddScanPath(scanRoot))
+
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "moved", "Song.MP3"), "12345")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "x", "dup.mp3"), "dup")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "y", "dup.mp3"), "dup")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "z", "sized.mp3"), "12")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "w", "sized.mp3"), "1234")
+
+	gone := t.TempDir()
+	song := createTestTrack("1", "Song", filepath.Join(gone, "song.mp3"))
+	dup := createTestTrack("2", "Dup", filepath.Join(gone, "dup.mp3"))
+	sized := createTestTrack("3", "Sized", filepath.Join(gone, "sized.mp3"))
+	sized.FileSize = 4
+	wrongSize := createTestTrack("4", "Song", filepath.Join(gone, "other", "song.mp3"))
+	wrongSize.FileSize = 99
+	env.addTracks(t, song, dup, sized, wrongSize)
+
+	relocated, err := env.service.RelocateByFilename()
+	require.NoError(t, err)
+	assert.Equal(t, 2, relocated)
+
+	queue := env.playlist.GetQueue()
+	assert.Equal(t, filepath.Join(scanRoot, "moved", "Song.MP3"), queue[0].FilePath)
+	assert.Equal(t, dup.FilePath, queue[1].FilePath, "Ambiguous matches are left alone")
+	assert.Equal(t, filepath.Join(scanRoot, "w", "sized.mp3"), queue[2].FilePath, "The size picks the match")
+	assert.Equal(t, wrongSize.FilePath, queue[3].FilePath)
+	assert.Contains(t, env.libraryPaths(t), filepath.Join(scanRoot, "moved", "Song.MP3"))
+}
+
+func TestRelocationService_RelocateByFilename_Archives(t *testing.T) {
+	env := newTestRelocationService(t)
+	scanRoot := t.TempDir()
+	require.NoError(t, env.preferences.AddScanPath(scanRoot))
+
+	// Loose files named like the archive entries must not be matched
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "loose", "song.xm"), "song")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "loose", "tune.it"), "tune")
+	writeRelocationTestFile(t, filepath.Join(scanRoot, "moved", "Pack.ZIP"), "zip")
+
+	gone := t.TempDir()
+	inPack := createTestTrack("1", "Song", domain.ArchiveEntryPath(filepath.Join(gone, "pack.zip"), "mods/song.xm"))
+	inPack.FileSize = 1234 // The size of the entry, not of the archive
+	inOther := createTestTrack("2", "Tune", domain.ArchiveEntryPath(filepath.Join(gone, "other.zip"), "tune.it"))
+	env.addTracks(t, inPack, inOther)
+
+	relocated, err := env.service.RelocateByFilename()
+	require.NoError(t, err)
+	assert.Equal(t, 1, relocated)
+
+	queue := env.playlist.GetQueue()
+	assert.Equal(t, domain.ArchiveEntryPath(filepath.Join(scanRoot, "moved", "Pack.ZIP"), "mods/song.xm"), queue[0].FilePath,
+		"The entry path is kept inside the relocated archive")
+	assert.Equal(t, inOther.FilePath, queue[1].FilePath, "An entry is not relinked to a loose file")
+}
+
+func TestRelocationService_RelocateByFilename_NoScanPaths(t *testing.T) {
+	env := newTestRelocationService(t)
+
+	_, err := env.service.RelocateByFilename()
+	var validationErr *domain.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
@@ -0,0 +1,84 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// newScanFailure records a file or folder that could not be read during a scan.
+func newScanFailure(path string, err error) domain.ScanFailure {
+	return domain.ScanFailure{
+		Path:    path,
+		Reason:  classifyScanError(err),
+		Message: err.Error(),
+	}
+}
+
+// classifyScanError returns the reason a file could not be read.
+func classifyScanError(err error) domain.ScanFailureReason {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return domain.ScanFailurePermission
+	case errors.Is(err, domain.ErrFileNotFound), errors.Is(err, fs.ErrNotExist):
+		return domain.ScanFailureNotFound
+	case errors.Is(err, domain.ErrUnsupportedFormat), errors.Is(err, domain.ErrUnsupportedCodec):
+		return domain.ScanFailureUnsupported
+	case errors.Is(err, domain.ErrCorruptFile):
+		return domain.ScanFailureCorrupt
+	default:
+		return domain.ScanFailureUnknown
+	}
+}
+
+// ExportScanReportText writes a scan report as plain text, one failure per line.
+func ExportScanReportText(w io.Writer, report *domain.ScanReport) error {
+	if report == nil {
+		return domain.NewValidationError("report", nil, "cannot be nil")
+	}
+
+	path := report.Path
+	if path == "" {
+		path = "(selected files)"
+	}
+
+	_, err := fmt.Fprintf(w, "Scan of %s\nStarted:   %s\nCompleted: %s\nFiles scanned: %d\nTracks found:  %d\nFailures:      %d\n",
+		path,
+		report.StartedAt.Format(time.RFC3339),
+		report.CompletedAt.Format(time.RFC3339),
+		report.FilesScanned,
+		report.TracksFound,
+		len(report.Failures))
+	if err != nil {
+		return err
+	}
+
+	if len(report.Failures) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	for _, failure := range report.Failures {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", failure.Reason, failure.Path, failure.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportScanReportJSON writes a scan report as indented JSON.
+func ExportScanReportJSON(w io.Writer, report *domain.ScanReport) error {
+	if report == nil {
+		return domain.NewValidationError("report", nil, "cannot be nil")
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
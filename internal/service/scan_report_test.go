@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestClassifyScanError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want domain.ScanFailureReason
+	}{
+		{"permission", fmt.Errorf("open: %w", os.ErrPermission), domain.ScanFailurePermission},
+		{"not found", domain.ErrFileNotFound, domain.ScanFailureNotFound},
+		{"os not exist", os.ErrNotExist, domain.ScanFailureNotFound},
+		{"unsupported format", domain.ErrUnsupportedFormat, domain.ScanFailureUnsupported},
+		{"unsupported codec", domain.NewAudioEngineError("load_stream", "/a.mp3", 44, "codec", domain.ErrUnsupportedCodec), domain.ScanFailureUnsupported},
+		{"corrupt", domain.NewAudioEngineError("load_music", "/a.xm", 41, "format", domain.ErrCorruptFile), domain.ScanFailureCorrupt},
+		{"other", errors.New("boom"), domain.ScanFailureUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyScanError(tt.err))
+		})
+	}
+}
+
+// testScanReport returns a report with one failure.
+func testScanReport() *domain.ScanReport {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return &domain.ScanReport{
+		Path:         "/music",
+		StartedAt:    started,
+		CompletedAt:  started.Add(90 * time.Second),
+		FilesScanned: 10,
+		TracksFound:  9,
+		Failures: []domain.ScanFailure{
+			{Path: "/music/bad.xm", Reason: domain.ScanFailureCorrupt, Message: "corrupt or invalid file"},
+		},
+	}
+}
+
+func TestExportScanReportText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ExportScanReportText(&buf, testScanReport()))
+
+	text := buf.String()
+	assert.Contains(t, text, "Scan of /music\n")
+	assert.Contains(t, text, "Started:   2024-05-01T10:00:00Z\n")
+	assert.Contains(t, text, "Failures:      1\n")
+	assert.Contains(t, text, "corrupt\t/music/bad.xm\tcorrupt or invalid file\n")
+}
+
+func TestExportScanReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ExportScanReportJSON(&buf, testScanReport()))
+
+	var decoded domain.ScanReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "/music", decoded.Path)
+	assert.Equal(t, 9, decoded.TracksFound)
+	assert.Equal(t, testScanReport().Failures, decoded.Failures)
+}
+
+func TestExportScanReport_Nil(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, ExportScanReportText(&buf, nil))
+	assert.Error(t, ExportScanReportJSON(&buf, nil))
+}
@@ -0,0 +1,140 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	mathrand "math/rand/v2"
+	"path/filepath"
+	"slices"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// shuffleQueue returns the queue in random order and the new index of the current track.
+//
+// ShuffleTracks keeps the current track at its index and shuffles the others around it.
+// ShuffleAlbums shuffles albums, keeping the queue order of tracks within each album;
+// the album of the current track is moved to the front so that it plays to the end.
+func shuffleQueue(queue []domain.MusicTrack, current int, mode domain.ShuffleMode) ([]domain.MusicTrack, int) {
+	if current < 0 || current >= len(queue) {
+		current = -1
+	}
+
+	switch mode {
+	case domain.ShuffleTracks:
+		others := make([]domain.MusicTrack, 0, len(queue))
+		for i, track := range queue {
+			if i != current {
+				others = append(others, track)
+			}
+		}
+		mathrand.Shuffle(len(others), func(i, j int) {
+			others[i], others[j] = others[j], others[i]
+		})
+
+		if current < 0 {
+			return others, current
+		}
+		shuffled := make([]domain.MusicTrack, 0, len(queue))
+		shuffled = append(shuffled, others[:current]...)
+		shuffled = append(shuffled, queue[current])
+		shuffled = append(shuffled, others[current:]...)
+		return shuffled, current
+
+	case domain.ShuffleAlbums:
+		albums, currentAlbum := groupQueueByAlbum(queue, current)
+		var leading []domain.MusicTrack
+		if currentAlbum >= 0 {
+			leading = albums[currentAlbum]
+			albums = slices.Delete(albums, currentAlbum, currentAlbum+1)
+		}
+		mathrand.Shuffle(len(albums), func(i, j int) {
+			albums[i], albums[j] = albums[j], albums[i]
+		})
+
+		if leading == nil {
+			return slices.Concat(albums...), -1
+		}
+		currentPath := queue[current].FilePath
+		newIndex := slices.IndexFunc(leading, func(track domain.MusicTrack) bool {
+			return track.FilePath == currentPath
+		})
+		return slices.Concat(append([][]domain.MusicTrack{leading}, albums...)...), newIndex
+	}
+
+	return slices.Clone(queue), current
+}
+
+// groupQueueByAlbum groups queued tracks by album in order of first appearance.
+// Tracks without an album tag are grouped by folder.
+// Also returns the group holding the current track, or -1.
+func groupQueueByAlbum(queue []domain.MusicTrack, current int) ([][]domain.MusicTrack, int) {
+	albums := make([][]domain.MusicTrack, 0)
+	index := make(map[string]int)
+	currentAlbum := -1
+	for i, track := range queue {
+		key := shuffleAlbumKey(track)
+		a, ok := index[key]
+		if !ok {
+			a = len(albums)
+			index[key] = a
+			albums = append(albums, nil)
+		}
+		albums[a] = append(albums[a], track)
+		if i == current {
+			currentAlbum = a
+		}
+	}
+	return albums, currentAlbum
+}
+
+// shuffleAlbumKey returns the album a track is shuffled with: its album tag,
+// or the folder (or archive) it is in.
+func shuffleAlbumKey(track domain.MusicTrack) string {
+	if key := track.AlbumKey(); key != "" {
+		return key
+	}
+	if archivePath, _, ok := domain.SplitArchivePath(track.FilePath); ok {
+		return "\x00" + archivePath
+	}
+	return "\x00" + filepath.Dir(track.FilePath)
+}
+
+// unshuffleQueue restores the original order of a shuffled queue and returns
+// the new index of the current track. Tracks that were queued after shuffling
+// are not in the original order and keep their order after the others.
+func unshuffleQueue(queue []domain.MusicTrack, originalOrder []string, current int) ([]domain.MusicTrack, int) {
+	position := make(map[string]int, len(originalOrder))
+	for i, filePath := range originalOrder {
+		if _, ok := position[filePath]; !ok {
+			position[filePath] = i
+		}
+	}
+	positionOf := func(track domain.MusicTrack) int {
+		if i, ok := position[track.FilePath]; ok {
+			return i
+		}
+		return len(originalOrder)
+	}
+
+	restored := slices.Clone(queue)
+	slices.SortStableFunc(restored, func(a, b domain.MusicTrack) int {
+		return positionOf(a) - positionOf(b)
+	})
+
+	if current < 0 || current >= len(queue) {
+		return restored, -1
+	}
+	currentPath := queue[current].FilePath
+	return restored, slices.IndexFunc(restored, func(track domain.MusicTrack) bool {
+		return track.FilePath == currentPath
+	})
+}
+
+// queuePaths returns the file paths of the queued tracks.
+func queuePaths(queue []domain.MusicTrack) []string {
+	paths := make([]string, len(queue))
+	for i, track := range queue {
+		paths[i] = track.FilePath
+	}
+	return paths
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// shuffleTestQueue creates a queue of albums with the given number of tracks each.
+func shuffleTestQueue(albumSizes ...int) []domain.MusicTrack {
+	queue := make([]domain.MusicTrack, 0)
+	for a, size := range albumSizes {
+		for i := 0; i < size; i++ {
+			track := createTestTrack(fmt.Sprintf("%d-%d", a, i), fmt.Sprintf("Song %d", i), fmt.Sprintf("/music/album%d/%02d.mp3", a, i))
+			track.Album = fmt.Sprintf("Album %d", a)
+			queue = append(queue, track)
+		}
+	}
+	return queue
+}
+
+func TestShuffleQueue_TracksKeepsCurrentInPlace(t *testing.T) {
+	queue := shuffleTestQueue(20)
+
+	shuffled, current := shuffleQueue(queue, 7, domain.ShuffleTracks)
+
+	assert.Equal(t, 7, current)
+	assert.Equal(t, queue[7].FilePath, shuffled[7].FilePath)
+	assert.ElementsMatch(t, queuePaths(queue), queuePaths(shuffled))
+	assert.Equal(t, "/music/album0/00.mp3", queue[0].FilePath, "The input queue should not be modified")
+}
+
+func TestShuffleQueue_AlbumsKeepTrackOrder(t *testing.T) {
+	queue := shuffleTestQueue(3, 4, 2, 5)
+
+	shuffled, current := shuffleQueue(queue, 5, domain.ShuffleAlbums)
+	require.ElementsMatch(t, queuePaths(queue), queuePaths(shuffled))
+
+	// The album of the current track comes first
+	assert.Equal(t, 2, current)
+	assert.Equal(t, queue[5].FilePath, shuffled[current].FilePath)
+	assert.Equal(t, queuePaths(queue[3:7]), queuePaths(shuffled[:4]))
+
+	// Each album stays together, in its original order
+	albums, _ := groupQueueByAlbum(shuffled, -1)
+	require.Len(t, albums, 4)
+	for _, album := range albums {
+		assert.True(t, slices.IsSortedFunc(album, func(a, b domain.MusicTrack) int {
+			return slices.Index(queuePaths(queue), a.FilePath) - slices.Index(queuePaths(queue), b.FilePath)
+		}))
+	}
+}
+
+func TestShuffleQueue_AlbumsGroupUntaggedTracksByFolder(t *testing.T) {
+	queue := []domain.MusicTrack{
+		{FilePath: "/music/a/1.mp3"},
+		{FilePath: "/music/b/1.mp3"},
+		{FilePath: "/music/a/2.mp3"},
+	}
+
+	albums, current := groupQueueByAlbum(queue, 2)
+
+	require.Len(t, albums, 2)
+	assert.Equal(t, 0, current)
+	assert.Equal(t, []string{"/music/a/1.mp3", "/music/a/2.mp3"}, queuePaths(albums[0]))
+}
+
+func TestUnshuffleQueue(t *testing.T) {
+	queue := shuffleTestQueue(10)
+	original := queuePaths(queue)
+
+	shuffled, current := shuffleQueue(queue, 4, domain.ShuffleTracks)
+	added := createTestTrack("new", "New", "/music/new.mp3")
+	shuffled = append(shuffled, added)
+
+	restored, current := unshuffleQueue(shuffled, original, current)
+
+	assert.Equal(t, append(original, added.FilePath), queuePaths(restored))
+	assert.Equal(t, 4, current)
+}
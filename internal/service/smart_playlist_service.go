@@ -0,0 +1,524 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	mathrand "math/rand/v2"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// smartRefreshDelay is how long a refresh of the smart playlists waits for more changes,
+// so that bursts of events (a batch tag edit, plays counted in a row) are evaluated once.
+const smartRefreshDelay = 250 * time.Millisecond
+
+// smartRuleKind determines how a smart rule is matched.
+type smartRuleKind int
+
+const (
+	smartRuleText smartRuleKind = iota
+	smartRuleRange
+)
+
+// smartRuleKinds lists the known rule fields.
+var smartRuleKinds = map[domain.SmartRuleField]smartRuleKind{
+	domain.SmartFieldGenre:      smartRuleText,
+	domain.SmartFieldYear:       smartRuleRange,
+	domain.SmartFieldRating:     smartRuleRange,
+	domain.SmartFieldPlayCount:  smartRuleRange,
+	domain.SmartFieldLastPlayed: smartRuleRange,
+	domain.SmartFieldFormat:     smartRuleText,
+	domain.SmartFieldPath:       smartRuleText,
+}
+
+// smartTrack is a library track with its statistics, as seen by rules and sorting.
+type smartTrack struct {
+	track domain.MusicTrack
+	stats domain.TrackStats
+}
+
+// number returns the value of a range field.
+func (t smartTrack) number(field domain.SmartRuleField, now time.Time) float64 {
+	switch field {
+	case domain.SmartFieldYear:
+		return float64(metadataOf(t.track).Year)
+	case domain.SmartFieldRating:
+		return float64(metadataOf(t.track).Rating)
+	case domain.SmartFieldPlayCount:
+		return float64(t.stats.PlayCount)
+	case domain.SmartFieldLastPlayed:
+		if t.stats.LastPlayedAt.IsZero() {
+			return math.Inf(1)
+		}
+		return now.Sub(t.stats.LastPlayedAt).Hours() / 24
+	default:
+		return 0
+	}
+}
+
+// matches reports whether the track satisfies the rule.
+func (t smartTrack) matches(rule domain.SmartRule, now time.Time) bool {
+	var matched bool
+	switch rule.Field {
+	case domain.SmartFieldGenre:
+		matched = strings.EqualFold(strings.TrimSpace(metadataOf(t.track).Genre), strings.TrimSpace(rule.Value))
+	case domain.SmartFieldFormat:
+		matched = strings.EqualFold(strings.TrimPrefix(t.track.FileFormat, "."), strings.TrimPrefix(rule.Value, "."))
+	case domain.SmartFieldPath:
+		folder := filepath.Clean(rule.Value)
+		path := filepath.Clean(t.track.FilePath)
+		matched = path == folder || strings.HasPrefix(path, strings.TrimSuffix(folder, string(filepath.Separator))+string(filepath.Separator))
+	default:
+		value := t.number(rule.Field, now)
+		matched = (rule.Min == nil || value >= *rule.Min) && (rule.Max == nil || value <= *rule.Max)
+	}
+	return matched != rule.Negate
+}
+
+// smartSortKeys maps sort fields to a comparison of two tracks.
+var smartSortKeys = map[domain.SmartSortField]func(a, b smartTrack) int{
+	domain.SmartSortTitle:  func(a, b smartTrack) int { return strings.Compare(a.track.SortTitle(), b.track.SortTitle()) },
+	domain.SmartSortArtist: func(a, b smartTrack) int { return strings.Compare(a.track.SortArtist(), b.track.SortArtist()) },
+	domain.SmartSortAlbum:  func(a, b smartTrack) int { return strings.Compare(a.track.SortAlbum(), b.track.SortAlbum()) },
+	domain.SmartSortYear: func(a, b smartTrack) int {
+		return metadataOf(a.track).Year - metadataOf(b.track).Year
+	},
+	domain.SmartSortRating: func(a, b smartTrack) int {
+		return metadataOf(a.track).Rating - metadataOf(b.track).Rating
+	},
+	domain.SmartSortPlayCount:  func(a, b smartTrack) int { return a.stats.PlayCount - b.stats.PlayCount },
+	domain.SmartSortLastPlayed: func(a, b smartTrack) int { return a.stats.LastPlayedAt.Compare(b.stats.LastPlayedAt) },
+	domain.SmartSortAdded:      func(a, b smartTrack) int { return a.stats.AddedAt.Compare(b.stats.AddedAt) },
+}
+
+// validateSmartRules returns a ValidationError if the rules cannot be evaluated.
+func validateSmartRules(rules domain.SmartPlaylistRules) error {
+	for _, rule := range rules.Rules {
+		kind, ok := smartRuleKinds[rule.Field]
+		if !ok {
+			return domain.NewValidationError("field", rule.Field, "unknown rule field")
+		}
+		switch kind {
+		case smartRuleText:
+			if strings.TrimSpace(rule.Value) == "" {
+				return domain.NewValidationError("value", rule.Value, fmt.Sprintf("%s rule needs a value", rule.Field))
+			}
+		case smartRuleRange:
+			if rule.Min == nil && rule.Max == nil {
+				return domain.NewValidationError("range", rule.Field, "rule needs a minimum or maximum")
+			}
+			if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+				return domain.NewValidationError("range", rule.Field, "minimum is above maximum")
+			}
+		}
+	}
+
+	if _, ok := smartSortKeys[rules.SortBy]; !ok && rules.SortBy != "" && rules.SortBy != domain.SmartSortRandom {
+		return domain.NewValidationError("sortBy", rules.SortBy, "unknown sort field")
+	}
+	if rules.Limit < 0 {
+		return domain.NewValidationError("limit", rules.Limit, "cannot be negative")
+	}
+	return nil
+}
+
+// evaluateSmartRules returns the library tracks selected by the rules, sorted and limited.
+// Random order is derived from seed, so a playlist keeps its selection until the library changes.
+func evaluateSmartRules(
+	rules domain.SmartPlaylistRules,
+	library []domain.MusicTrack,
+	stats map[string]domain.TrackStats,
+	now time.Time,
+	seed uint64,
+) []domain.MusicTrack {
+	selected := make([]smartTrack, 0, len(library))
+	for _, track := range library {
+		candidate := smartTrack{track: track, stats: stats[track.StableID()]}
+
+		matched := !rules.MatchAny || len(rules.Rules) == 0
+		for _, rule := range rules.Rules {
+			if candidate.matches(rule, now) == rules.MatchAny {
+				matched = rules.MatchAny
+				break
+			}
+		}
+		if matched {
+			selected = append(selected, candidate)
+		}
+	}
+
+	if rules.SortBy == domain.SmartSortRandom {
+		random := mathrand.New(mathrand.NewPCG(seed, uint64(len(selected))))
+		random.Shuffle(len(selected), func(i, j int) {
+			selected[i], selected[j] = selected[j], selected[i]
+		})
+	} else if compare, ok := smartSortKeys[rules.SortBy]; ok {
+		sort.SliceStable(selected, func(i, j int) bool {
+			if rules.Descending {
+				return compare(selected[j], selected[i]) < 0
+			}
+			return compare(selected[i], selected[j]) < 0
+		})
+	}
+
+	if rules.Limit > 0 && len(selected) > rules.Limit {
+		selected = selected[:rules.Limit]
+	}
+
+	tracks := make([]domain.MusicTrack, len(selected))
+	for i, candidate := range selected {
+		tracks[i] = candidate.track
+	}
+	return tracks
+}
+
+// sameTracks reports whether two track lists hold the same tracks with the same tags, in the same order.
+func sameTracks(a, b []domain.MusicTrack) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+// generatePlaylistID generates a unique ID for a playlist.
+func generatePlaylistID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// If random generation fails, fall back to a timestamp-based ID
+		return fmt.Sprintf("playlist-%d", time.Now().UnixNano())
+	}
+	return "playlist-" + hex.EncodeToString(b)
+}
+
+// SmartPlaylistService manages smart playlists: playlists whose tracks are
+// selected from the library by rules over metadata and listening statistics.
+// Definitions are stored through the playlist repository next to static
+// playlists, together with the result of the last evaluation. Smart playlists
+// are re-evaluated shortly after the library, track tags or statistics change,
+// once for a burst of changes, and SmartPlaylistUpdatedEvent is published when the result differs.
+// All operations are thread-safe via sync.Mutex.
+type SmartPlaylistService struct {
+	// Dependencies (injected)
+	logger    *slog.Logger
+	library   ports.LibraryRepository
+	stats     ports.StatsRepository
+	playlists ports.PlaylistRepository
+	bus       ports.EventBus
+
+	// Event subscriptions
+	subscriptions []domain.SubscriptionID
+
+	// Pending refresh after change events
+	refreshDelay time.Duration
+	refreshTimer *time.Timer
+	refreshWg    sync.WaitGroup // Counts scheduled and running refreshes
+
+	// Concurrency control
+	mu sync.Mutex
+}
+
+// NewSmartPlaylistService creates a new smart playlist service.
+func NewSmartPlaylistService(
+	logger *slog.Logger,
+	library ports.LibraryRepository,
+	stats ports.StatsRepository,
+	playlists ports.PlaylistRepository,
+	bus ports.EventBus,
+) *SmartPlaylistService {
+	service := &SmartPlaylistService{
+		logger:       logger,
+		library:      library,
+		stats:        stats,
+		playlists:    playlists,
+		bus:          bus,
+		refreshDelay: smartRefreshDelay,
+	}
+
+	logger.Debug("smart playlist service initialized")
+
+	service.subscriptions = []domain.SubscriptionID{
+		bus.Subscribe(domain.EventLibraryChanged, service.handleLibraryChanged),
+		bus.Subscribe(domain.EventTrackUpdated, service.handleLibraryChanged),
+		bus.Subscribe(domain.EventTrackStatsUpdated, service.handleLibraryChanged),
+	}
+
+	return service
+}
+
+// handleLibraryChanged schedules a re-evaluation of the smart playlists after refreshDelay.
+// Changes published while a refresh is pending are covered by that refresh.
+func (s *SmartPlaylistService) handleLibraryChanged(domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscriptions == nil || s.refreshTimer != nil {
+		return
+	}
+	s.refreshWg.Add(1)
+	s.refreshTimer = time.AfterFunc(s.refreshDelay, s.runScheduledRefresh)
+}
+
+// runScheduledRefresh re-evaluates the smart playlists when a scheduled refresh is due.
+func (s *SmartPlaylistService) runScheduledRefresh() {
+	defer s.refreshWg.Done()
+
+	s.mu.Lock()
+	s.refreshTimer = nil
+	stopped := s.subscriptions == nil
+	s.mu.Unlock()
+	if stopped {
+		return
+	}
+
+	if err := s.Refresh(); err != nil {
+		s.logger.Warn("failed to refresh smart playlists", slog.Any("error", err))
+	}
+}
+
+// CreateSmartPlaylist creates and evaluates a smart playlist.
+// Returns a ValidationError if the name is empty or the rules are invalid.
+func (s *SmartPlaylistService) CreateSmartPlaylist(name string, rules domain.SmartPlaylistRules) (*domain.Playlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", name, "cannot be empty")
+	}
+	if err := validateSmartRules(rules); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	playlist := &domain.Playlist{
+		ID:        generatePlaylistID(),
+		Name:      name,
+		CreatedAt: now,
+		Smart:     &rules,
+	}
+	if err := s.evaluateAndSaveInternal(playlist, "CreateSmartPlaylist"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("smart playlist created",
+		slog.String("name", name),
+		slog.Int("tracks", len(playlist.Tracks)))
+
+	return playlist, nil
+}
+
+// UpdateSmartPlaylist changes the name and rules of a smart playlist and re-evaluates it.
+// Returns ErrPlaylistNotFound if there is no smart playlist with the ID.
+func (s *SmartPlaylistService) UpdateSmartPlaylist(id, name string, rules domain.SmartPlaylistRules) (*domain.Playlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", name, "cannot be empty")
+	}
+	if err := validateSmartRules(rules); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlist, err := s.loadSmartInternal(id)
+	if err != nil {
+		return nil, err
+	}
+	playlist.Name = name
+	playlist.Smart = &rules
+
+	if err := s.evaluateAndSaveInternal(playlist, "UpdateSmartPlaylist"); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// DeleteSmartPlaylist removes a smart playlist.
+// Returns ErrPlaylistNotFound if there is no smart playlist with the ID.
+func (s *SmartPlaylistService) DeleteSmartPlaylist(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.loadSmartInternal(id); err != nil {
+		return err
+	}
+	if err := s.playlists.Delete(id); err != nil {
+		return domain.NewServiceError("SmartPlaylistService", "DeleteSmartPlaylist", "failed to delete playlist", err)
+	}
+	return nil
+}
+
+// GetSmartPlaylists returns the smart playlists with the tracks of their last evaluation.
+func (s *SmartPlaylistService) GetSmartPlaylists() ([]*domain.Playlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadAllSmartInternal("GetSmartPlaylists")
+}
+
+// Preview returns the tracks that a smart playlist with the rules would hold.
+func (s *SmartPlaylistService) Preview(rules domain.SmartPlaylistRules) ([]domain.MusicTrack, error) {
+	if err := validateSmartRules(rules); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	library, stats, err := s.loadLibraryInternal("Preview")
+	if err != nil {
+		return nil, err
+	}
+	return evaluateSmartRules(rules, library, stats, time.Now(), 0), nil
+}
+
+// Refresh re-evaluates every smart playlist, saving those whose tracks changed.
+func (s *SmartPlaylistService) Refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists, err := s.loadAllSmartInternal("Refresh")
+	if err != nil || len(playlists) == 0 {
+		return err
+	}
+
+	library, stats, err := s.loadLibraryInternal("Refresh")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, playlist := range playlists {
+		tracks := evaluateSmartRules(*playlist.Smart, library, stats, now, playlistSeed(playlist))
+		if sameTracks(tracks, playlist.Tracks) {
+			continue
+		}
+		playlist.Tracks = tracks
+		playlist.UpdatedAt = now
+		if err := s.playlists.Save(playlist); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.bus.Publish(domain.NewSmartPlaylistUpdatedEvent(playlist))
+	}
+	if len(errs) > 0 {
+		return domain.NewServiceError("SmartPlaylistService", "Refresh", "failed to save playlists", errors.Join(errs...))
+	}
+	return nil
+}
+
+// evaluateAndSaveInternal evaluates a smart playlist, saves it and publishes SmartPlaylistUpdatedEvent.
+// Must be called with lock held.
+func (s *SmartPlaylistService) evaluateAndSaveInternal(playlist *domain.Playlist, op string) error {
+	library, stats, err := s.loadLibraryInternal(op)
+	if err != nil {
+		return err
+	}
+
+	playlist.Tracks = evaluateSmartRules(*playlist.Smart, library, stats, time.Now(), playlistSeed(playlist))
+	playlist.UpdatedAt = time.Now()
+	if err := s.playlists.Save(playlist); err != nil {
+		return domain.NewServiceError("SmartPlaylistService", op, "failed to save playlist", err)
+	}
+
+	s.bus.Publish(domain.NewSmartPlaylistUpdatedEvent(playlist))
+	return nil
+}
+
+// loadLibraryInternal loads the library and the statistics keyed by track ID.
+// Must be called with lock held.
+func (s *SmartPlaylistService) loadLibraryInternal(op string) ([]domain.MusicTrack, map[string]domain.TrackStats, error) {
+	library, err := s.library.LoadAll()
+	if err != nil {
+		return nil, nil, domain.NewServiceError("SmartPlaylistService", op, "failed to load library", err)
+	}
+
+	all, err := s.stats.LoadAll()
+	if err != nil {
+		return nil, nil, domain.NewServiceError("SmartPlaylistService", op, "failed to load stats", err)
+	}
+	stats := make(map[string]domain.TrackStats, len(all))
+	for _, entry := range all {
+		stats[entry.TrackID] = entry
+	}
+
+	return library, stats, nil
+}
+
+// loadSmartInternal loads a smart playlist by ID.
+// Must be called with lock held.
+func (s *SmartPlaylistService) loadSmartInternal(id string) (*domain.Playlist, error) {
+	playlist, err := s.playlists.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if !playlist.IsSmart() {
+		return nil, ports.ErrPlaylistNotFound
+	}
+	return playlist, nil
+}
+
+// loadAllSmartInternal loads the smart playlists.
+// Must be called with lock held.
+func (s *SmartPlaylistService) loadAllSmartInternal(op string) ([]*domain.Playlist, error) {
+	playlists, err := s.playlists.LoadAll()
+	if err != nil {
+		return nil, domain.NewServiceError("SmartPlaylistService", op, "failed to load playlists", err)
+	}
+
+	smart := make([]*domain.Playlist, 0, len(playlists))
+	for _, playlist := range playlists {
+		if playlist.IsSmart() {
+			smart = append(smart, playlist)
+		}
+	}
+	return smart, nil
+}
+
+// playlistSeed returns the random order seed of a playlist.
+func playlistSeed(playlist *domain.Playlist) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(playlist.ID))
+	return hash.Sum64()
+}
+
+// Shutdown unsubscribes from events, cancels a pending refresh and waits for a running one.
+func (s *SmartPlaylistService) Shutdown() error {
+	s.mu.Lock()
+	for _, id := range s.subscriptions {
+		s.bus.Unsubscribe(id)
+	}
+	s.subscriptions = nil
+
+	if s.refreshTimer != nil && s.refreshTimer.Stop() {
+		s.refreshWg.Done()
+	}
+	s.refreshTimer = nil
+	s.mu.Unlock()
+
+	s.refreshWg.Wait()
+	return nil
+}
+
+// Verify that SmartPlaylistService implements the expected interface patterns
+var _ interface {
+	CreateSmartPlaylist(string, domain.SmartPlaylistRules) (*domain.Playlist, error)
+	UpdateSmartPlaylist(string, string, domain.SmartPlaylistRules) (*domain.Playlist, error)
+	DeleteSmartPlaylist(string) error
+	GetSmartPlaylists() ([]*domain.Playlist, error)
+	Preview(domain.SmartPlaylistRules) ([]domain.MusicTrack, error)
+	Refresh() error
+	Shutdown() error
+} = (*SmartPlaylistService)(nil)
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Helper to create a test smart playlist service
+func newTestSmartPlaylistService() (*SmartPlaylistService, *mockLibraryRepository, *mockStatsRepository, *mockPlaylistRepository, *eventbus.SyncEventBus) {
+	bus := eventbus.NewSyncEventBus()
+	library := newMockLibraryRepository()
+	stats := newMockStatsRepository()
+	playlists := newMockPlaylistRepository()
+	service := NewSmartPlaylistService(libTestLogger(), library, stats, playlists, bus)
+	service.refreshDelay = time.Millisecond
+	return service, library, stats, playlists, bus
+}
+
+// smartTestTrack creates a library track with the given tags.
+func smartTestTrack(path, genre string, year, rating int) domain.MusicTrack {
+	track := createTestTrack(path, path, path)
+	track.FileFormat = ".mp3"
+	track.Metadata = &domain.TrackMetadata{Genre: genre, Year: year, Rating: rating}
+	return track
+}
+
+// trackPaths returns the file paths of tracks.
+func trackPaths(tracks []domain.MusicTrack) []string {
+	paths := make([]string, len(tracks))
+	for i, track := range tracks {
+		paths[i] = track.FilePath
+	}
+	return paths
+}
+
+// floatPtr returns a pointer to value, for rule bounds.
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func TestEvaluateSmartRules(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rock70s := smartTestTrack("/music/rock/a.mp3", "Rock", 1975, 5)
+	rock90s := smartTestTrack("/music/rock/b.mp3", "rock", 1994, 3)
+	jazz := smartTestTrack("/music/jazz/c.mp3", "Jazz", 1959, 0)
+	flac := smartTestTrack("/music/rock-live/d.flac", "Rock", 1977, 4)
+	flac.FileFormat = ".flac"
+	library := []domain.MusicTrack{rock70s, rock90s, jazz, flac}
+
+	stats := map[string]domain.TrackStats{
+		rock70s.StableID(): {PlayCount: 10, LastPlayedAt: now.Add(-2 * 24 * time.Hour)},
+		rock90s.StableID(): {PlayCount: 1, LastPlayedAt: now.Add(-60 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		name  string
+		rules domain.SmartPlaylistRules
+		want  []string
+	}{
+		{"no rules", domain.SmartPlaylistRules{}, trackPaths(library)},
+		{"genre", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "ROCK"}}},
+			[]string{rock70s.FilePath, rock90s.FilePath, flac.FilePath}},
+		{"year range", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldYear, Min: floatPtr(1970), Max: floatPtr(1979)}}},
+			[]string{rock70s.FilePath, flac.FilePath}},
+		{"rating", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldRating, Min: floatPtr(4)}}},
+			[]string{rock70s.FilePath, flac.FilePath}},
+		{"never played", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldPlayCount, Max: floatPtr(0)}}},
+			[]string{jazz.FilePath, flac.FilePath}},
+		{"played this week", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldLastPlayed, Max: floatPtr(7)}}},
+			[]string{rock70s.FilePath}},
+		{"not played for a month", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldLastPlayed, Min: floatPtr(30)}}},
+			[]string{rock90s.FilePath, jazz.FilePath, flac.FilePath}},
+		{"format", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldFormat, Value: "flac"}}},
+			[]string{flac.FilePath}},
+		{"path prefix", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldPath, Value: "/music/rock/"}}},
+			[]string{rock70s.FilePath, rock90s.FilePath}},
+		{"negated", domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "rock", Negate: true}}},
+			[]string{jazz.FilePath}},
+		{"all rules", domain.SmartPlaylistRules{Rules: []domain.SmartRule{
+			{Field: domain.SmartFieldGenre, Value: "rock"},
+			{Field: domain.SmartFieldFormat, Value: ".mp3"},
+			{Field: domain.SmartFieldYear, Max: floatPtr(1980)},
+		}}, []string{rock70s.FilePath}},
+		{"any rule", domain.SmartPlaylistRules{MatchAny: true, Rules: []domain.SmartRule{
+			{Field: domain.SmartFieldGenre, Value: "jazz"},
+			{Field: domain.SmartFieldFormat, Value: "flac"},
+		}}, []string{jazz.FilePath, flac.FilePath}},
+		{"sorted and limited", domain.SmartPlaylistRules{SortBy: domain.SmartSortPlayCount, Descending: true, Limit: 2},
+			[]string{rock70s.FilePath, rock90s.FilePath}},
+		{"sorted by year", domain.SmartPlaylistRules{SortBy: domain.SmartSortYear},
+			[]string{jazz.FilePath, rock70s.FilePath, flac.FilePath, rock90s.FilePath}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateSmartRules(tt.rules, library, stats, now, 0)
+			assert.Equal(t, tt.want, trackPaths(got))
+		})
+	}
+}
+
+func TestEvaluateSmartRules_RandomIsStablePerSeed(t *testing.T) {
+	library := make([]domain.MusicTrack, 0, 20)
+	for i := 0; i < 20; i++ {
+		library = append(library, smartTestTrack("/music/"+string(rune('a'+i))+".mp3", "", 0, 0))
+	}
+	rules := domain.SmartPlaylistRules{SortBy: domain.SmartSortRandom, Limit: 5}
+
+	first := evaluateSmartRules(rules, library, nil, time.Now(), 42)
+	assert.Len(t, first, 5)
+	assert.Equal(t, first, evaluateSmartRules(rules, library, nil, time.Now(), 42))
+}
+
+func TestValidateSmartRules(t *testing.T) {
+	invalid := []domain.SmartPlaylistRules{
+		{Rules: []domain.SmartRule{{Field: "mood", Value: "happy"}}},
+		{Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre}}},
+		{Rules: []domain.SmartRule{{Field: domain.SmartFieldYear}}},
+		{Rules: []domain.SmartRule{{Field: domain.SmartFieldYear, Min: floatPtr(2000), Max: floatPtr(1990)}}},
+		{SortBy: "color"},
+		{Limit: -1},
+	}
+	for _, rules := range invalid {
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, validateSmartRules(rules), &validationErr, "%+v", rules)
+	}
+
+	assert.NoError(t, validateSmartRules(domain.SmartPlaylistRules{
+		Rules:  []domain.SmartRule{{Field: domain.SmartFieldRating, Min: floatPtr(3)}},
+		SortBy: domain.SmartSortRandom,
+		Limit:  25,
+	}))
+}
+
+func TestSmartPlaylistService_CreateSmartPlaylist(t *testing.T) {
+	service, library, _, playlists, bus := newTestSmartPlaylistService()
+	defer service.Shutdown()
+
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{
+		smartTestTrack("/music/a.mp3", "Rock", 1975, 0),
+		smartTestTrack("/music/b.mp3", "Jazz", 1959, 0),
+	}))
+
+	var updates []domain.SmartPlaylistUpdatedEvent
+	bus.Subscribe(domain.EventSmartPlaylistUpdated, func(e domain.Event) {
+		updates = append(updates, e.(domain.SmartPlaylistUpdatedEvent))
+	})
+
+	rules := domain.SmartPlaylistRules{Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "rock"}}}
+	playlist, err := service.CreateSmartPlaylist(" Rock ", rules)
+	require.NoError(t, err)
+	assert.Equal(t, "Rock", playlist.Name)
+	assert.True(t, playlist.IsSmart())
+	assert.Equal(t, []string{"/music/a.mp3"}, trackPaths(playlist.Tracks))
+	require.Len(t, updates, 1)
+
+	// Stored next to static playlists
+	stored, err := playlists.Load(playlist.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rules, *stored.Smart)
+
+	_, err = service.CreateSmartPlaylist("", rules)
+	assert.Error(t, err)
+}
+
+func TestSmartPlaylistService_RefreshesOnLibraryChange(t *testing.T) {
+	service, library, _, _, bus := newTestSmartPlaylistService()
+	defer service.Shutdown()
+
+	playlist, err := service.CreateSmartPlaylist("Jazz", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "jazz"}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, playlist.Tracks)
+
+	var updates []domain.SmartPlaylistUpdatedEvent
+	bus.Subscribe(domain.EventSmartPlaylistUpdated, func(e domain.Event) {
+		updates = append(updates, e.(domain.SmartPlaylistUpdatedEvent))
+	})
+
+	added := []domain.MusicTrack{smartTestTrack("/music/b.mp3", "Jazz", 1959, 0)}
+	require.NoError(t, library.SaveTracks(added))
+	bus.Publish(domain.NewLibraryChangedEvent(added))
+	service.refreshWg.Wait()
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, []string{"/music/b.mp3"}, trackPaths(updates[0].Playlist.Tracks))
+
+	// Unchanged results are not saved or published again
+	bus.Publish(domain.NewLibraryChangedEvent(added))
+	service.refreshWg.Wait()
+	assert.Len(t, updates, 1)
+}
+
+func TestSmartPlaylistService_CoalescesRefreshes(t *testing.T) {
+	service, library, _, _, bus := newTestSmartPlaylistService()
+	defer service.Shutdown()
+	service.refreshDelay = 50 * time.Millisecond
+
+	_, err := service.CreateSmartPlaylist("Jazz", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "jazz"}},
+	})
+	require.NoError(t, err)
+
+	var updates []domain.SmartPlaylistUpdatedEvent
+	bus.Subscribe(domain.EventSmartPlaylistUpdated, func(e domain.Event) {
+		updates = append(updates, e.(domain.SmartPlaylistUpdatedEvent))
+	})
+
+	// A batch tag edit publishes one event per track
+	for _, path := range []string{"/music/a.mp3", "/music/b.mp3", "/music/c.mp3"} {
+		track := smartTestTrack(path, "Jazz", 1959, 0)
+		require.NoError(t, library.SaveTracks([]domain.MusicTrack{track}))
+		bus.Publish(domain.NewTrackUpdatedEvent(track))
+	}
+	service.refreshWg.Wait()
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, []string{"/music/a.mp3", "/music/b.mp3", "/music/c.mp3"}, trackPaths(updates[0].Playlist.Tracks))
+}
+
+func TestSmartPlaylistService_ShutdownCancelsRefresh(t *testing.T) {
+	service, library, _, _, bus := newTestSmartPlaylistService()
+	service.refreshDelay = time.Hour
+
+	_, err := service.CreateSmartPlaylist("Jazz", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "jazz"}},
+	})
+	require.NoError(t, err)
+
+	added := []domain.MusicTrack{smartTestTrack("/music/b.mp3", "Jazz", 1959, 0)}
+	require.NoError(t, library.SaveTracks(added))
+	bus.Publish(domain.NewLibraryChangedEvent(added))
+
+	// Does not wait for the pending refresh
+	require.NoError(t, service.Shutdown())
+	playlists, err := service.GetSmartPlaylists()
+	require.NoError(t, err)
+	assert.Empty(t, playlists[0].Tracks)
+}
+
+func TestSmartPlaylistService_RefreshesOnStatsChange(t *testing.T) {
+	service, library, stats, _, bus := newTestSmartPlaylistService()
+	defer service.Shutdown()
+
+	track := smartTestTrack("/music/a.mp3", "Rock", 1975, 0)
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{track}))
+
+	playlist, err := service.CreateSmartPlaylist("Favourites", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldPlayCount, Min: floatPtr(3)}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, playlist.Tracks)
+
+	updated := domain.TrackStats{TrackID: track.StableID(), FilePath: track.FilePath, PlayCount: 3}
+	require.NoError(t, stats.SaveStats([]domain.TrackStats{updated}))
+	bus.Publish(domain.NewTrackStatsUpdatedEvent(updated))
+	service.refreshWg.Wait()
+
+	playlists, err := service.GetSmartPlaylists()
+	require.NoError(t, err)
+	require.Len(t, playlists, 1)
+	assert.Equal(t, []string{"/music/a.mp3"}, trackPaths(playlists[0].Tracks))
+}
+
+func TestSmartPlaylistService_UpdateAndDelete(t *testing.T) {
+	service, library, _, playlists, _ := newTestSmartPlaylistService()
+	defer service.Shutdown()
+
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{
+		smartTestTrack("/music/a.mp3", "Rock", 1975, 0),
+		smartTestTrack("/music/b.flac", "Jazz", 1959, 0),
+	}))
+	require.NoError(t, playlists.Save(&domain.Playlist{ID: "static", Name: "Static"}))
+
+	playlist, err := service.CreateSmartPlaylist("Rock", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldGenre, Value: "rock"}},
+	})
+	require.NoError(t, err)
+
+	updated, err := service.UpdateSmartPlaylist(playlist.ID, "Old", domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldYear, Max: floatPtr(1970)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Old", updated.Name)
+	assert.Equal(t, []string{"/music/b.flac"}, trackPaths(updated.Tracks))
+
+	// Static playlists are not managed here
+	_, err = service.UpdateSmartPlaylist("static", "Static", domain.SmartPlaylistRules{})
+	assert.Error(t, err)
+	assert.Error(t, service.DeleteSmartPlaylist("static"))
+
+	require.NoError(t, service.DeleteSmartPlaylist(playlist.ID))
+	smart, err := service.GetSmartPlaylists()
+	require.NoError(t, err)
+	assert.Empty(t, smart)
+	assert.True(t, playlists.Exists("static"))
+}
+
+func TestSmartPlaylistService_Preview(t *testing.T) {
+	service, library, _, playlists, _ := newTestSmartPlaylistService()
+	defer service.Shutdown()
+
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{smartTestTrack("/music/a.mp3", "Rock", 1975, 4)}))
+
+	tracks, err := service.Preview(domain.SmartPlaylistRules{
+		Rules: []domain.SmartRule{{Field: domain.SmartFieldRating, Min: floatPtr(4)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/music/a.mp3"}, trackPaths(tracks))
+
+	all, err := playlists.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, all, "Previews are not saved")
+}
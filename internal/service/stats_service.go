@@ -0,0 +1,379 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// maxListenStep is the largest position change between progress events that
+// counts as listening. Larger jumps are seeks and do not count towards the play threshold.
+const maxListenStep = 2 * time.Second
+
+// listenSession is the playback of the current track since it was started.
+type listenSession struct {
+	track    domain.MusicTrack
+	trackID  string
+	position time.Duration
+	duration time.Duration
+	listened time.Duration // Time heard, not counting seeks
+	counted  bool          // The track was already counted as played
+}
+
+// StatsService records play counts, skip counts and play times of tracks.
+// A track counts as played once the threshold fraction of it (at most
+// domain.MaxPlayThresholdTime) was heard, or when it plays to the end.
+// Stopping it or moving to another track before that counts as a skip.
+// All operations are thread-safe via sync.RWMutex.
+type StatsService struct {
+	// Dependencies (injected)
+	logger     *slog.Logger
+	repository ports.StatsRepository
+	bus        ports.EventBus
+
+	// Current state
+	threshold float64
+	session   *listenSession
+
+	// Event subscriptions
+	subscriptions []domain.SubscriptionID
+
+	// Concurrency control
+	mu sync.RWMutex
+}
+
+// NewStatsService creates a new statistics service.
+func NewStatsService(
+	logger *slog.Logger,
+	repository ports.StatsRepository,
+	bus ports.EventBus,
+) *StatsService {
+	service := &StatsService{
+		logger:     logger,
+		repository: repository,
+		bus:        bus,
+		threshold:  domain.DefaultPlayThreshold,
+	}
+
+	logger.Debug("stats service initialized")
+
+	service.subscriptions = []domain.SubscriptionID{
+		bus.Subscribe(domain.EventTrackStarted, service.handleTrackStarted),
+		bus.Subscribe(domain.EventTrackProgress, service.handleTrackProgress),
+		bus.Subscribe(domain.EventTrackCompleted, service.handleTrackCompleted),
+		bus.Subscribe(domain.EventTrackStopped, service.handleTrackStopped),
+		bus.Subscribe(domain.EventLibraryChanged, service.handleLibraryChanged),
+	}
+
+	return service
+}
+
+// SetPlayThreshold sets the fraction of a track that must be heard for it to count as played.
+// Returns a ValidationError if the fraction is not above 0 and at most 1.
+func (s *StatsService) SetPlayThreshold(fraction float64) error {
+	if fraction <= 0 || fraction > 1 {
+		return domain.NewValidationError("playThreshold", fraction, "must be above 0 and at most 1")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.threshold = fraction
+	return nil
+}
+
+// GetPlayThreshold returns the fraction of a track that must be heard for it to count as played.
+func (s *StatsService) GetPlayThreshold() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.threshold
+}
+
+// handleTrackStarted starts a listening session, unless the track is resumed after a pause.
+func (s *StatsService) handleTrackStarted(event domain.Event) {
+	startedEvent, ok := event.(domain.TrackStartedEvent)
+	if !ok {
+		return
+	}
+	trackID := startedEvent.Track.StableID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session != nil {
+		if s.session.trackID == trackID {
+			return
+		}
+		s.endSessionInternal()
+	}
+	s.session = &listenSession{
+		track:    startedEvent.Track,
+		trackID:  trackID,
+		duration: startedEvent.Track.Duration,
+	}
+}
+
+// handleTrackProgress adds the time heard and counts the play when the threshold is reached.
+func (s *StatsService) handleTrackProgress(event domain.Event) {
+	progressEvent, ok := event.(domain.TrackProgressEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil {
+		return
+	}
+	session := s.session
+
+	if step := progressEvent.Position - session.position; step > 0 && step <= maxListenStep {
+		session.listened += step
+	}
+	session.position = progressEvent.Position
+	if progressEvent.Duration > 0 {
+		session.duration = progressEvent.Duration
+	}
+
+	if !session.counted && session.listened >= s.thresholdTime(session.duration) {
+		session.counted = true
+		s.recordInternal(session.track, true)
+	}
+}
+
+// handleTrackCompleted counts a track that played to the end.
+func (s *StatsService) handleTrackCompleted(event domain.Event) {
+	completedEvent, ok := event.(domain.TrackCompletedEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil || s.session.trackID != completedEvent.Track.StableID() {
+		return
+	}
+	if !s.session.counted {
+		s.recordInternal(s.session.track, true)
+	}
+	s.session = nil
+}
+
+// handleTrackStopped counts a skip if the track was stopped before the threshold.
+// Moving to another track stops the current one, so manual next and previous are covered too.
+func (s *StatsService) handleTrackStopped(event domain.Event) {
+	stoppedEvent, ok := event.(domain.TrackStoppedEvent)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session == nil || s.session.trackID != stoppedEvent.Track.StableID() {
+		return
+	}
+	s.endSessionInternal()
+}
+
+// handleLibraryChanged records when tracks were first added to the library.
+func (s *StatsService) handleLibraryChanged(event domain.Event) {
+	changedEvent, ok := event.(domain.LibraryChangedEvent)
+	if !ok || len(changedEvent.Tracks) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.repository.LoadAll()
+	if err != nil {
+		s.logger.Warn("failed to load stats", slog.Any("error", err))
+		return
+	}
+	known := make(map[string]bool, len(all))
+	for _, stats := range all {
+		known[stats.TrackID] = true
+	}
+
+	now := time.Now()
+	added := make([]domain.TrackStats, 0)
+	for _, track := range changedEvent.Tracks {
+		trackID := track.StableID()
+		if known[trackID] {
+			continue
+		}
+		known[trackID] = true
+		added = append(added, domain.TrackStats{TrackID: trackID, FilePath: track.FilePath, AddedAt: now})
+	}
+
+	if err := s.repository.SaveStats(added); err != nil {
+		s.logger.Warn("failed to save stats", slog.Any("error", err))
+	}
+}
+
+// endSessionInternal ends the current session, counting a skip if the track was not played.
+// Must be called with lock held.
+func (s *StatsService) endSessionInternal() {
+	if !s.session.counted {
+		s.recordInternal(s.session.track, false)
+	}
+	s.session = nil
+}
+
+// thresholdTime returns how long a track of the given duration must be heard to count as played.
+// Must be called with lock held.
+func (s *StatsService) thresholdTime(duration time.Duration) time.Duration {
+	threshold := time.Duration(float64(duration) * s.threshold)
+	if duration <= 0 || threshold > domain.MaxPlayThresholdTime {
+		return domain.MaxPlayThresholdTime
+	}
+	return threshold
+}
+
+// recordInternal counts a play or a skip of the track and publishes TrackStatsUpdatedEvent.
+// Must be called with lock held.
+func (s *StatsService) recordInternal(track domain.MusicTrack, played bool) {
+	trackID := track.StableID()
+	stored, err := s.repository.LoadStats(trackID)
+	if err != nil {
+		s.logger.Warn("failed to load stats",
+			slog.String("path", track.FilePath),
+			slog.Any("error", err))
+		return
+	}
+
+	now := time.Now()
+	stats := domain.TrackStats{TrackID: trackID, AddedAt: now}
+	if stored != nil {
+		stats = *stored
+	}
+	stats.FilePath = track.FilePath
+
+	if played {
+		stats.PlayCount++
+		if stats.FirstPlayedAt.IsZero() {
+			stats.FirstPlayedAt = now
+		}
+		stats.LastPlayedAt = now
+	} else {
+		stats.SkipCount++
+	}
+
+	if err := s.repository.SaveStats([]domain.TrackStats{stats}); err != nil {
+		s.logger.Warn("failed to save stats",
+			slog.String("path", track.FilePath),
+			slog.Any("error", err))
+		return
+	}
+
+	s.logger.Debug("track stats updated",
+		slog.String("path", track.FilePath),
+		slog.Bool("played", played),
+		slog.Int("play_count", stats.PlayCount),
+		slog.Int("skip_count", stats.SkipCount))
+
+	s.bus.Publish(domain.NewTrackStatsUpdatedEvent(stats))
+}
+
+// GetStats returns the statistics of a track, or nil if it was never added or played.
+func (s *StatsService) GetStats(track domain.MusicTrack) (*domain.TrackStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats, err := s.repository.LoadStats(track.StableID())
+	if err != nil {
+		return nil, domain.NewServiceError("StatsService", "GetStats", "failed to load stats", err)
+	}
+	return stats, nil
+}
+
+// MostPlayed returns the stats of played tracks, most played first.
+// Ties are ordered by the most recent play. A limit of 0 or less returns all of them.
+func (s *StatsService) MostPlayed(limit int) ([]domain.TrackStats, error) {
+	return s.query("MostPlayed", limit,
+		func(stats domain.TrackStats) bool { return stats.PlayCount > 0 },
+		func(a, b domain.TrackStats) bool {
+			if a.PlayCount != b.PlayCount {
+				return a.PlayCount > b.PlayCount
+			}
+			return a.LastPlayedAt.After(b.LastPlayedAt)
+		})
+}
+
+// RecentlyAdded returns the stats of tracks, most recently added first.
+// A limit of 0 or less returns all of them.
+func (s *StatsService) RecentlyAdded(limit int) ([]domain.TrackStats, error) {
+	return s.query("RecentlyAdded", limit,
+		func(stats domain.TrackStats) bool { return !stats.AddedAt.IsZero() },
+		func(a, b domain.TrackStats) bool { return a.AddedAt.After(b.AddedAt) })
+}
+
+// query returns the stats matching keep, ordered by less and cut to limit.
+// Ties are ordered by file path, so lists are stable.
+func (s *StatsService) query(
+	op string,
+	limit int,
+	keep func(domain.TrackStats) bool,
+	less func(a, b domain.TrackStats) bool,
+) ([]domain.TrackStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all, err := s.repository.LoadAll()
+	if err != nil {
+		return nil, domain.NewServiceError("StatsService", op, "failed to load stats", err)
+	}
+
+	result := make([]domain.TrackStats, 0, len(all))
+	for _, stats := range all {
+		if keep(stats) {
+			result = append(result, stats)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if less(result[i], result[j]) {
+			return true
+		}
+		if less(result[j], result[i]) {
+			return false
+		}
+		return result[i].FilePath < result[j].FilePath
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// Shutdown cleans up resources.
+func (s *StatsService) Shutdown() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.subscriptions {
+		s.bus.Unsubscribe(id)
+	}
+	s.subscriptions = nil
+
+	return nil
+}
+
+// Verify that StatsService implements the expected interface patterns
+var _ interface {
+	SetPlayThreshold(float64) error
+	GetPlayThreshold() float64
+	GetStats(domain.MusicTrack) (*domain.TrackStats, error)
+	MostPlayed(int) ([]domain.TrackStats, error)
+	RecentlyAdded(int) ([]domain.TrackStats, error)
+	Shutdown() error
+} = (*StatsService)(nil)
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Mock statistics repository for testing
+type mockStatsRepository struct {
+	mu    sync.RWMutex
+	stats map[string]domain.TrackStats
+}
+
+func newMockStatsRepository() *mockStatsRepository {
+	return &mockStatsRepository{stats: make(map[string]domain.TrackStats)}
+}
+
+func (m *mockStatsRepository) SaveStats(stats []domain.TrackStats) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range stats {
+		m.stats[entry.TrackID] = entry
+	}
+	return nil
+}
+
+func (m *mockStatsRepository) LoadStats(trackID string) (*domain.TrackStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.stats[trackID]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (m *mockStatsRepository) LoadAll() ([]domain.TrackStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	all := make([]domain.TrackStats, 0, len(m.stats))
+	for _, entry := range m.stats {
+		all = append(all, entry)
+	}
+	return all, nil
+}
+
+func (m *mockStatsRepository) RemoveStats(trackIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, trackID := range trackIDs {
+		delete(m.stats, trackID)
+	}
+	return nil
+}
+
+func (m *mockStatsRepository) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = make(map[string]domain.TrackStats)
+	return nil
+}
+
+// Helper to create a test stats service
+func newTestStatsService() (*StatsService, *mockStatsRepository, *eventbus.SyncEventBus) {
+	bus := eventbus.NewSyncEventBus()
+	repo := newMockStatsRepository()
+	return NewStatsService(libTestLogger(), repo, bus), repo, bus
+}
+
+// listenTo publishes progress events from the start of the track up to position.
+func listenTo(bus *eventbus.SyncEventBus, position, duration time.Duration) {
+	for p := time.Duration(0); p <= position; p += time.Second {
+		bus.Publish(domain.NewTrackProgressEvent(p, duration))
+	}
+}
+
+func TestStatsService_CountsPlayAtThreshold(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	var updates []domain.TrackStatsUpdatedEvent
+	bus.Subscribe(domain.EventTrackStatsUpdated, func(e domain.Event) {
+		updates = append(updates, e.(domain.TrackStatsUpdatedEvent))
+	})
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 89*time.Second, 3*time.Minute)
+	assert.Empty(t, updates)
+
+	listenTo(bus, 90*time.Second, 3*time.Minute)
+	require.Len(t, updates, 1)
+	assert.Equal(t, 1, updates[0].Stats.PlayCount)
+
+	// Completing the track does not count it twice
+	bus.Publish(domain.NewTrackCompletedEvent(track))
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.PlayCount)
+	assert.Equal(t, 0, stats.SkipCount)
+	assert.False(t, stats.FirstPlayedAt.IsZero())
+	assert.Equal(t, stats.FirstPlayedAt, stats.LastPlayedAt)
+	assert.Len(t, updates, 1)
+}
+
+func TestStatsService_CountsSkipOnStop(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 10*time.Second, 3*time.Minute)
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 0, stats.PlayCount)
+	assert.Equal(t, 1, stats.SkipCount)
+	assert.True(t, stats.LastPlayedAt.IsZero())
+
+	// A stop after the track was counted is not a skip
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 100*time.Second, 3*time.Minute)
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	stats, err = service.GetStats(track)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.PlayCount)
+	assert.Equal(t, 1, stats.SkipCount)
+}
+
+func TestStatsService_CompletedCountsAsPlayed(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	// Seeking to the end does not reach the threshold, but completing the track counts
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	bus.Publish(domain.NewTrackProgressEvent(170*time.Second, 3*time.Minute))
+	bus.Publish(domain.NewTrackCompletedEvent(track))
+
+	// The stop before the next track loads is ignored
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.PlayCount)
+	assert.Equal(t, 0, stats.SkipCount)
+}
+
+func TestStatsService_SeeksDoNotCount(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	bus.Publish(domain.NewTrackProgressEvent(time.Second, 3*time.Minute))
+	bus.Publish(domain.NewTrackProgressEvent(150*time.Second, 3*time.Minute))
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.PlayCount)
+	assert.Equal(t, 1, stats.SkipCount)
+}
+
+func TestStatsService_PauseResumeKeepsSession(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 60*time.Second, 3*time.Minute)
+
+	// Resuming after a pause publishes TrackStartedEvent again
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	for p := 61 * time.Second; p <= 90*time.Second; p += time.Second {
+		bus.Publish(domain.NewTrackProgressEvent(p, 3*time.Minute))
+	}
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.PlayCount)
+}
+
+func TestStatsService_StartingAnotherTrackSkips(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	first := createTestTrack("1", "A", "/music/a.mp3")
+	second := createTestTrack("2", "B", "/music/b.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(first))
+	bus.Publish(domain.NewTrackStartedEvent(second))
+
+	stats, err := service.GetStats(first)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.SkipCount)
+}
+
+func TestStatsService_PlayThreshold(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	assert.Equal(t, domain.DefaultPlayThreshold, service.GetPlayThreshold())
+	require.NoError(t, service.SetPlayThreshold(0.1))
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, 18*time.Second, 3*time.Minute)
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.PlayCount)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, service.SetPlayThreshold(0), &validationErr)
+	require.ErrorAs(t, service.SetPlayThreshold(1.5), &validationErr)
+	assert.Equal(t, 0.1, service.GetPlayThreshold())
+}
+
+func TestStatsService_LongTracksCapThreshold(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	track := createTestTrack("1", "Mix", "/music/mix.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	listenTo(bus, domain.MaxPlayThresholdTime, time.Hour)
+
+	stats, err := service.GetStats(track)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.PlayCount)
+}
+
+func TestStatsService_StableAcrossScans(t *testing.T) {
+	service, _, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	// A re-scan gives the track a new ID, but the stats follow its path
+	bus.Publish(domain.NewTrackStartedEvent(createTestTrack("1", "A", "/music/a.mp3")))
+	bus.Publish(domain.NewTrackStoppedEvent(createTestTrack("1", "A", "/music/a.mp3")))
+
+	stats, err := service.GetStats(createTestTrack("rescanned", "A", "/music/a.mp3"))
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.SkipCount)
+}
+
+func TestStatsService_LibraryChangeRecordsAddedTime(t *testing.T) {
+	service, repo, bus := newTestStatsService()
+	defer service.Shutdown()
+
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := createTestTrack("1", "A", "/music/a.mp3")
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{
+		{TrackID: existing.StableID(), FilePath: existing.FilePath, AddedAt: old, PlayCount: 3},
+	}))
+
+	added := createTestTrack("2", "B", "/music/b.mp3")
+	bus.Publish(domain.NewLibraryChangedEvent([]domain.MusicTrack{existing, added}))
+
+	stats, err := service.GetStats(existing)
+	require.NoError(t, err)
+	assert.True(t, old.Equal(stats.AddedAt), "Re-scans keep the original added time")
+	assert.Equal(t, 3, stats.PlayCount)
+
+	stats, err = service.GetStats(added)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.True(t, stats.AddedAt.After(old))
+	assert.Equal(t, 0, stats.PlayCount)
+}
+
+func TestStatsService_MostPlayed(t *testing.T) {
+	service, repo, _ := newTestStatsService()
+	defer service.Shutdown()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{
+		{TrackID: "a", FilePath: "/music/a.mp3", PlayCount: 2, LastPlayedAt: day},
+		{TrackID: "b", FilePath: "/music/b.mp3", PlayCount: 5, LastPlayedAt: day},
+		{TrackID: "c", FilePath: "/music/c.mp3", PlayCount: 2, LastPlayedAt: day.Add(time.Hour)},
+		{TrackID: "d", FilePath: "/music/d.mp3", SkipCount: 4},
+	}))
+
+	stats, err := service.MostPlayed(0)
+	require.NoError(t, err)
+	require.Len(t, stats, 3, "Tracks never played are not listed")
+	assert.Equal(t, "b", stats[0].TrackID)
+	assert.Equal(t, "c", stats[1].TrackID, "Ties are ordered by the most recent play")
+	assert.Equal(t, "a", stats[2].TrackID)
+
+	stats, err = service.MostPlayed(1)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "b", stats[0].TrackID)
+}
+
+func TestStatsService_RecentlyAdded(t *testing.T) {
+	service, repo, _ := newTestStatsService()
+	defer service.Shutdown()
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveStats([]domain.TrackStats{
+		{TrackID: "a", FilePath: "/music/a.mp3", AddedAt: day},
+		{TrackID: "b", FilePath: "/music/b.mp3", AddedAt: day.Add(48 * time.Hour)},
+		{TrackID: "c", FilePath: "/music/c.mp3", AddedAt: day.Add(24 * time.Hour)},
+	}))
+
+	stats, err := service.RecentlyAdded(2)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, "b", stats[0].TrackID)
+	assert.Equal(t, "c", stats[1].TrackID)
+}
+
+func TestStatsService_Shutdown(t *testing.T) {
+	service, repo, bus := newTestStatsService()
+	require.NoError(t, service.Shutdown())
+
+	track := createTestTrack("1", "A", "/music/a.mp3")
+	bus.Publish(domain.NewTrackStartedEvent(track))
+	bus.Publish(domain.NewTrackStoppedEvent(track))
+
+	all, err := repo.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -0,0 +1,246 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// tagPatternFields maps the placeholders of tag patterns to the regular
+// expressions matching them. Placeholders never match across folders.
+var tagPatternFields = map[string]string{
+	"artist":      `[^/]+?`,
+	"albumartist": `[^/]+?`,
+	"album":       `[^/]+?`,
+	"title":       `[^/]+?`,
+	"genre":       `[^/]+?`,
+	"year":        `\d{4}`,
+	"track":       `\d{1,3}`,
+	"disc":        `\d{1,2}`,
+	"ignore":      `[^/]*?`,
+}
+
+// tagPatternPlaceholder matches a %field% placeholder.
+var tagPatternPlaceholder = regexp.MustCompile(`%([a-z]*)%`)
+
+// TagPattern infers tags from the path of a track.
+type TagPattern struct {
+	source string
+	re     *regexp.Regexp
+	fields []string // Field of each capture group
+}
+
+// CompileTagPattern parses a pattern that describes how tags appear in file paths.
+//
+// Placeholders are %artist%, %albumartist%, %album%, %title%, %genre%, %year%,
+// %track% and %disc%; %ignore% skips any text. Everything else is matched literally.
+// A '/' separates folders, and the pattern matches the end of the path without the
+// file extension, so "%artist%/%album%/%track% - %title%" matches
+// "/music/Artist/Album/01 - Title.mp3". Tracks inside archives are matched as if
+// the archive was a folder named after the archive without its extension.
+// Returns a ValidationError for unknown placeholders or patterns without any.
+func CompileTagPattern(pattern string) (*TagPattern, error) {
+	source := strings.Trim(strings.ReplaceAll(strings.TrimSpace(pattern), `\`, "/"), "/")
+	if source == "" {
+		return nil, domain.NewValidationError("tagPattern", pattern, "cannot be empty")
+	}
+
+	var expr strings.Builder
+	expr.WriteString(`(?i)(?:^|/)`)
+	fields := make([]string, 0)
+	last := 0
+	for _, loc := range tagPatternPlaceholder.FindAllStringSubmatchIndex(source, -1) {
+		field := source[loc[2]:loc[3]]
+		fieldExpr, ok := tagPatternFields[field]
+		if !ok {
+			return nil, domain.NewValidationError("tagPattern", pattern, "unknown placeholder %"+field+"%")
+		}
+		expr.WriteString(regexp.QuoteMeta(source[last:loc[0]]))
+		if field == "ignore" {
+			expr.WriteString(fieldExpr)
+		} else {
+			expr.WriteString("(" + fieldExpr + ")")
+			fields = append(fields, field)
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(source[last:]))
+	expr.WriteString(`$`)
+
+	if len(fields) == 0 {
+		return nil, domain.NewValidationError("tagPattern", pattern, "must contain a placeholder such as %title%")
+	}
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, domain.NewValidationError("tagPattern", pattern, "invalid pattern")
+	}
+	return &TagPattern{source: source, re: re, fields: fields}, nil
+}
+
+// CompileTagPatterns compiles several patterns, skipping blank ones.
+func CompileTagPatterns(patterns []string) ([]*TagPattern, error) {
+	compiled := make([]*TagPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		tagPattern, err := CompileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, tagPattern)
+	}
+	return compiled, nil
+}
+
+// String returns the pattern source.
+func (p *TagPattern) String() string {
+	return p.source
+}
+
+// Match returns the tags inferred from the path of a track.
+// Returns false if the path does not match the pattern.
+func (p *TagPattern) Match(filePath string) (domain.TagEdit, bool) {
+	match := p.re.FindStringSubmatch(tagPatternSubject(filePath))
+	if match == nil {
+		return domain.TagEdit{}, false
+	}
+
+	var edit domain.TagEdit
+	for i, field := range p.fields {
+		value := strings.TrimSpace(match[i+1])
+		if value == "" {
+			continue
+		}
+		switch field {
+		case "artist":
+			edit.Artist = &value
+		case "albumartist":
+			edit.AlbumArtist = &value
+		case "album":
+			edit.Album = &value
+		case "title":
+			edit.Title = &value
+		case "genre":
+			edit.Genre = &value
+		case "year", "track", "disc":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch field {
+			case "year":
+				edit.Year = &number
+			case "track":
+				edit.TrackNumber = &number
+			case "disc":
+				edit.DiscNumber = &number
+			}
+		}
+	}
+	return edit, true
+}
+
+// tagPatternSubject returns the path that patterns are matched against:
+// slash-separated and without the file extension.
+func tagPatternSubject(filePath string) string {
+	subject := filepath.ToSlash(filePath)
+	if archivePath, entryName, ok := domain.SplitArchivePath(filePath); ok {
+		archivePath = filepath.ToSlash(archivePath)
+		subject = strings.TrimSuffix(archivePath, path.Ext(archivePath)) + "/" + entryName
+	}
+	return strings.TrimSuffix(subject, path.Ext(subject))
+}
+
+// GuessTags returns the tags inferred by the first pattern matching the path.
+// Returns false if no pattern matches.
+func GuessTags(patterns []*TagPattern, filePath string) (domain.TagEdit, bool) {
+	for _, pattern := range patterns {
+		if edit, ok := pattern.Match(filePath); ok {
+			return edit, true
+		}
+	}
+	return domain.TagEdit{}, false
+}
+
+// missingTags keeps the fields of the edit that the track has no value for.
+// A title that is just the file name, with or without extension, counts as missing.
+func missingTags(track domain.MusicTrack, edit domain.TagEdit) domain.TagEdit {
+	metadata := metadataOf(track)
+	if !hasDefaultTitle(track) {
+		edit.Title = nil
+	}
+	if track.Artist != "" {
+		edit.Artist = nil
+	}
+	if track.Album != "" {
+		edit.Album = nil
+	}
+	if track.AlbumArtist != "" {
+		edit.AlbumArtist = nil
+	}
+	if metadata.Genre != "" {
+		edit.Genre = nil
+	}
+	if metadata.Year != 0 {
+		edit.Year = nil
+	}
+	if metadata.TrackNumber != 0 {
+		edit.TrackNumber = nil
+	}
+	if metadata.DiscNumber != 0 {
+		edit.DiscNumber = nil
+	}
+	return edit
+}
+
+// changedTags keeps the fields of the edit that differ from the track.
+func changedTags(track domain.MusicTrack, edit domain.TagEdit) domain.TagEdit {
+	metadata := metadataOf(track)
+	if edit.Title != nil && *edit.Title == track.Title {
+		edit.Title = nil
+	}
+	if edit.Artist != nil && *edit.Artist == track.Artist {
+		edit.Artist = nil
+	}
+	if edit.Album != nil && *edit.Album == track.Album {
+		edit.Album = nil
+	}
+	if edit.AlbumArtist != nil && *edit.AlbumArtist == track.AlbumArtist {
+		edit.AlbumArtist = nil
+	}
+	if edit.Genre != nil && *edit.Genre == metadata.Genre {
+		edit.Genre = nil
+	}
+	if edit.Year != nil && *edit.Year == metadata.Year {
+		edit.Year = nil
+	}
+	if edit.TrackNumber != nil && *edit.TrackNumber == metadata.TrackNumber {
+		edit.TrackNumber = nil
+	}
+	if edit.DiscNumber != nil && *edit.DiscNumber == metadata.DiscNumber {
+		edit.DiscNumber = nil
+	}
+	return edit
+}
+
+// hasDefaultTitle reports whether the track has no title tag: its title is
+// empty or its file name, with or without extension.
+func hasDefaultTitle(track domain.MusicTrack) bool {
+	fileName := defaultTitle(track)
+	return track.Title == "" || track.Title == fileName || track.Title == strings.TrimSuffix(fileName, path.Ext(fileName))
+}
+
+// defaultTitle returns the title a track gets when it has no title tag: its file name.
+func defaultTitle(track domain.MusicTrack) string {
+	if _, entryName, ok := domain.SplitArchivePath(track.FilePath); ok {
+		return path.Base(entryName)
+	}
+	return filepath.Base(track.FilePath)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+func TestCompileTagPattern_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    domain.MusicTrack
+	}{
+		{
+			name:    "folders and file name",
+			pattern: "%artist%/%album%/%track% - %title%",
+			path:    "/music/Pink Floyd/Animals/02 - Dogs.mp3",
+			want:    domain.MusicTrack{Artist: "Pink Floyd", Album: "Animals", Title: "Dogs", Metadata: &domain.TrackMetadata{TrackNumber: 2}},
+		},
+		{
+			name:    "file name only",
+			pattern: "%artist% - %title%",
+			path:    "/music/misc/Purple Motion - Satellite One.s3m",
+			want:    domain.MusicTrack{Artist: "Purple Motion", Title: "Satellite One", Metadata: &domain.TrackMetadata{}},
+		},
+		{
+			name:    "year and ignored text",
+			pattern: "%album% (%year%)/%ignore%%track% %title%",
+			path:    "/music/Bootlegs (1994)/CD1-07 Encore.flac",
+			want:    domain.MusicTrack{Album: "Bootlegs", Title: "Encore", Metadata: &domain.TrackMetadata{Year: 1994, TrackNumber: 7}},
+		},
+		{
+			name:    "archive entry",
+			pattern: "%album%/%artist% - %title%",
+			path:    domain.ArchiveEntryPath("/mods/Assembly 94.zip", "Skaven - Catch That Goblin.xm"),
+			want:    domain.MusicTrack{Album: "Assembly 94", Artist: "Skaven", Title: "Catch That Goblin", Metadata: &domain.TrackMetadata{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileTagPattern(tt.pattern)
+			require.NoError(t, err)
+
+			edit, ok := pattern.Match(tt.path)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, edit.Apply(domain.MusicTrack{}))
+		})
+	}
+}
+
+func TestCompileTagPattern_NoMatch(t *testing.T) {
+	pattern, err := CompileTagPattern("%artist%/%album%/%track% - %title%")
+	require.NoError(t, err)
+
+	_, ok := pattern.Match("/music/Dogs.mp3")
+	assert.False(t, ok)
+
+	// Placeholders do not match across folders
+	_, ok = pattern.Match("/Animals/02 - Dogs.mp3")
+	assert.False(t, ok)
+}
+
+func TestCompileTagPattern_Invalid(t *testing.T) {
+	for _, pattern := range []string{"", "  ", "%composer% - %title%", "no placeholders", "%ignore% - x"} {
+		_, err := CompileTagPattern(pattern)
+
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr, pattern)
+	}
+}
+
+func TestGuessTags_FirstMatchingPattern(t *testing.T) {
+	patterns, err := CompileTagPatterns([]string{"%artist%/%album%/%track% - %title%", "", "%artist% - %title%"})
+	require.NoError(t, err)
+	require.Len(t, patterns, 2)
+
+	edit, ok := GuessTags(patterns, "/music/Artist/Album/01 - Song.mp3")
+	require.True(t, ok)
+	assert.Equal(t, "Album", *edit.Album)
+
+	edit, ok = GuessTags(patterns, "/music/Artist - Song.mp3")
+	require.True(t, ok)
+	assert.Nil(t, edit.Album)
+	assert.Equal(t, "Artist", *edit.Artist)
+
+	_, ok = GuessTags(patterns, "/music/Song.mp3")
+	assert.False(t, ok)
+}
+
+func TestMissingTags(t *testing.T) {
+	pattern, err := CompileTagPattern("%artist%/%album%/%track% - %title%")
+	require.NoError(t, err)
+	edit, ok := pattern.Match("/music/Artist/Album/03 - Song.mp3")
+	require.True(t, ok)
+
+	// Title is the file name, so it counts as missing
+	track := domain.MusicTrack{
+		FilePath: "/music/Artist/Album/03 - Song.mp3",
+		Title:    "03 - Song.mp3",
+		Artist:   "Tagged Artist",
+		Metadata: &domain.TrackMetadata{TrackNumber: 5},
+	}
+	filled := missingTags(track, edit).Apply(track)
+
+	assert.Equal(t, "Song", filled.Title)
+	assert.Equal(t, "Tagged Artist", filled.Artist)
+	assert.Equal(t, "Album", filled.Album)
+	assert.Equal(t, 5, filled.Metadata.TrackNumber)
+}
@@ -0,0 +1,243 @@
+// Package service provides business logic for the GoTune application.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+	"github.com/tejashwikalptaru/gotune/internal/ports"
+)
+
+// TagService edits track tags and writes them back to the audio files.
+// Successful edits are saved to the library and published as TrackUpdatedEvent,
+// which the playlist service uses to refresh the queue.
+// All edits are serialized via sync.Mutex.
+type TagService struct {
+	// Dependencies (injected)
+	logger  *slog.Logger
+	writer  ports.TagWriter
+	library ports.LibraryRepository
+	bus     ports.EventBus
+
+	// Concurrency control
+	mu sync.Mutex
+}
+
+// NewTagService creates a new tag service.
+func NewTagService(
+	logger *slog.Logger,
+	writer ports.TagWriter,
+	library ports.LibraryRepository,
+	bus ports.EventBus,
+) *TagService {
+	logger.Debug("tag service initialized")
+
+	return &TagService{
+		logger:  logger,
+		writer:  writer,
+		library: library,
+		bus:     bus,
+	}
+}
+
+// CanEdit returns true if the tags of the track can be written.
+// Tracker modules and formats without a tag writer are read-only.
+func (s *TagService) CanEdit(track domain.MusicTrack) bool {
+	return !track.IsMOD && s.writer.SupportsFormat(filepath.Ext(track.FilePath))
+}
+
+// EditTags applies the edit to every track and writes it to the files.
+// Each track is handled independently, so one failure does not stop the rest.
+// Returns the updated tracks and, if any track failed, an error joining the failures.
+func (s *TagService) EditTags(tracks []domain.MusicTrack, edit domain.TagEdit) ([]domain.MusicTrack, error) {
+	if err := validateTagEdit(edit); err != nil {
+		return nil, err
+	}
+
+	updated := make([]domain.MusicTrack, 0, len(tracks))
+	if edit.IsEmpty() {
+		return updated, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failures := make([]error, 0)
+	for _, track := range tracks {
+		if !s.CanEdit(track) {
+			failures = append(failures, domain.NewServiceError("TagService", "EditTags",
+				fmt.Sprintf("cannot edit tags of %s", track.FilePath), domain.ErrTagsNotWritable))
+			continue
+		}
+
+		if err := s.writer.WriteTags(track.FilePath, edit); err != nil {
+			s.logger.Warn("failed to write tags",
+				slog.String("path", track.FilePath),
+				slog.Any("error", err))
+			failures = append(failures, domain.NewServiceError("TagService", "EditTags",
+				fmt.Sprintf("failed to write tags of %s", track.FilePath), err))
+			continue
+		}
+
+		updated = append(updated, edit.Apply(track))
+	}
+
+	s.updateLibrary(updated)
+
+	for _, track := range updated {
+		s.bus.Publish(domain.NewTrackUpdatedEvent(track))
+	}
+
+	s.logger.Info("tags edited",
+		slog.Int("updated", len(updated)),
+		slog.Int("failed", len(failures)))
+
+	return updated, errors.Join(failures...)
+}
+
+// GuessTags infers tags for the tracks from their paths using the first matching pattern.
+// With onlyMissing, only tags the tracks do not have are guessed; otherwise
+// guesses may replace existing tags. Tracks whose path matches no pattern, or
+// whose tags would not change, are left out. The guesses are not applied.
+// Returns a ValidationError if a pattern is invalid.
+func (s *TagService) GuessTags(tracks []domain.MusicTrack, patterns []string, onlyMissing bool) ([]domain.TagGuess, error) {
+	compiled, err := CompileTagPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(compiled) == 0 {
+		return nil, domain.NewValidationError("tagPatterns", patterns, "at least one pattern is required")
+	}
+
+	guesses := make([]domain.TagGuess, 0, len(tracks))
+	for _, track := range tracks {
+		for _, pattern := range compiled {
+			edit, ok := pattern.Match(track.FilePath)
+			if !ok {
+				continue
+			}
+			if onlyMissing {
+				edit = missingTags(track, edit)
+			}
+			if edit = changedTags(track, edit); !edit.IsEmpty() {
+				guesses = append(guesses, domain.TagGuess{Track: track, Edit: edit, Pattern: pattern.String()})
+			}
+			break
+		}
+	}
+	return guesses, nil
+}
+
+// ApplyTagGuesses applies reviewed tag guesses.
+// Tags are written to the files where possible; tracks whose tags cannot be
+// written (e.g. tracker modules) are only updated in the library.
+// Each guess is handled independently, so one failure does not stop the rest.
+// Returns the updated tracks and, if any track failed, an error joining the failures.
+func (s *TagService) ApplyTagGuesses(guesses []domain.TagGuess) ([]domain.MusicTrack, error) {
+	for _, guess := range guesses {
+		if err := validateTagEdit(guess.Edit); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := make([]domain.MusicTrack, 0, len(guesses))
+	failures := make([]error, 0)
+	for _, guess := range guesses {
+		if guess.Edit.IsEmpty() {
+			continue
+		}
+
+		if s.CanEdit(guess.Track) {
+			if err := s.writer.WriteTags(guess.Track.FilePath, guess.Edit); err != nil {
+				s.logger.Warn("failed to write tags",
+					slog.String("path", guess.Track.FilePath),
+					slog.Any("error", err))
+				failures = append(failures, domain.NewServiceError("TagService", "ApplyTagGuesses",
+					fmt.Sprintf("failed to write tags of %s", guess.Track.FilePath), err))
+				continue
+			}
+		}
+
+		updated = append(updated, guess.Edit.Apply(guess.Track))
+	}
+
+	s.updateLibrary(updated)
+
+	for _, track := range updated {
+		s.bus.Publish(domain.NewTrackUpdatedEvent(track))
+	}
+
+	s.logger.Info("tag guesses applied",
+		slog.Int("updated", len(updated)),
+		slog.Int("failed", len(failures)))
+
+	return updated, errors.Join(failures...)
+}
+
+// updateLibrary replaces library entries for the edited tracks.
+// Tracks that are not in the library are not added to it.
+// Must be called with mutex lock held.
+func (s *TagService) updateLibrary(updated []domain.MusicTrack) {
+	if len(updated) == 0 {
+		return
+	}
+
+	library, err := s.library.LoadAll()
+	if err != nil {
+		s.logger.Warn("failed to load library", slog.Any("error", err))
+		return
+	}
+
+	byPath := make(map[string]domain.MusicTrack, len(updated))
+	for _, track := range updated {
+		byPath[track.FilePath] = track
+	}
+
+	changed := make([]domain.MusicTrack, 0, len(updated))
+	for _, entry := range library {
+		if track, ok := byPath[entry.FilePath]; ok {
+			track.ID = entry.ID
+			changed = append(changed, track)
+		}
+	}
+
+	if err := s.library.SaveTracks(changed); err != nil {
+		s.logger.Warn("failed to update library", slog.Any("error", err))
+	}
+}
+
+// validateTagEdit checks the numeric fields of an edit.
+func validateTagEdit(edit domain.TagEdit) error {
+	if edit.Year != nil && (*edit.Year < 0 || *edit.Year > 9999) {
+		return domain.NewValidationError("year", *edit.Year, "must be between 0 and 9999")
+	}
+	if edit.TrackNumber != nil && *edit.TrackNumber < 0 {
+		return domain.NewValidationError("track number", *edit.TrackNumber, "must not be negative")
+	}
+	if edit.DiscNumber != nil && *edit.DiscNumber < 0 {
+		return domain.NewValidationError("disc number", *edit.DiscNumber, "must not be negative")
+	}
+	return nil
+}
+
+// Shutdown cleans up resources.
+func (s *TagService) Shutdown() error {
+	// No cleanup needed for tag service
+	return nil
+}
+
+// Verify that TagService implements the expected interface patterns
+var _ interface {
+	CanEdit(domain.MusicTrack) bool
+	EditTags([]domain.MusicTrack, domain.TagEdit) ([]domain.MusicTrack, error)
+	GuessTags([]domain.MusicTrack, []string, bool) ([]domain.TagGuess, error)
+	ApplyTagGuesses([]domain.TagGuess) ([]domain.MusicTrack, error)
+	Shutdown() error
+} = (*TagService)(nil)
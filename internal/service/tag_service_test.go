@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tejashwikalptaru/gotune/internal/adapter/eventbus"
+	"github.com/tejashwikalptaru/gotune/internal/domain"
+)
+
+// Mock tag writer for testing
+type mockTagWriter struct {
+	mu      sync.Mutex
+	written map[string]domain.TagEdit
+	failFor string
+}
+
+func newMockTagWriter() *mockTagWriter {
+	return &mockTagWriter{written: make(map[string]domain.TagEdit)}
+}
+
+func (m *mockTagWriter) SupportsFormat(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".mp3", ".flac", ".ogg", ".m4a":
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *mockTagWriter) WriteTags(filePath string, edit domain.TagEdit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if filePath == m.failFor {
+		return domain.NewTagError("write", filePath, "corrupt tag", nil)
+	}
+	if !m.SupportsFormat(filepath.Ext(filePath)) {
+		return domain.ErrTagsNotWritable
+	}
+	m.written[filePath] = edit
+	return nil
+}
+
+// Helper to create a test tag service
+func newTestTagService() (*TagService, *mockTagWriter, *mockLibraryRepository, *eventbus.SyncEventBus) {
+	writer := newMockTagWriter()
+	library := newMockLibraryRepository()
+	bus := eventbus.NewSyncEventBus()
+	return NewTagService(libTestLogger(), writer, library, bus), writer, library, bus
+}
+
+func TestTagService_CanEdit(t *testing.T) {
+	service, _, _, _ := newTestTagService()
+	defer service.Shutdown()
+
+	assert.True(t, service.CanEdit(domain.MusicTrack{FilePath: "/music/song.MP3"}))
+	assert.False(t, service.CanEdit(domain.MusicTrack{FilePath: "/music/song.wav"}))
+	assert.False(t, service.CanEdit(domain.MusicTrack{FilePath: "/music/tune.mod", IsMOD: true}))
+}
+
+func TestTagService_EditTags(t *testing.T) {
+	service, writer, library, bus := newTestTagService()
+	defer service.Shutdown()
+
+	inLibrary := domain.MusicTrack{ID: "lib-1", FilePath: "/music/a.mp3", Title: "A", Artist: "Old"}
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{inLibrary}))
+
+	published := make([]domain.MusicTrack, 0)
+	bus.Subscribe(domain.EventTrackUpdated, func(e domain.Event) {
+		if evt, ok := e.(domain.TrackUpdatedEvent); ok {
+			published = append(published, evt.Track)
+		}
+	})
+
+	tracks := []domain.MusicTrack{
+		{ID: "q-1", FilePath: "/music/a.mp3", Title: "A", Artist: "Old"},
+		{ID: "q-2", FilePath: "/music/b.flac", Title: "B", Artist: "Old"},
+	}
+	artist := "New Artist"
+	updated, err := service.EditTags(tracks, domain.TagEdit{Artist: &artist})
+	require.NoError(t, err)
+
+	require.Len(t, updated, 2)
+	assert.Equal(t, "New Artist", updated[0].Artist)
+	assert.Equal(t, "A", updated[0].Title)
+	assert.Equal(t, "B", updated[1].Title)
+	assert.Len(t, writer.written, 2)
+	assert.Len(t, published, 2)
+
+	// Only tracks already in the library are updated there
+	saved, err := library.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "New Artist", saved[0].Artist)
+	assert.Equal(t, "lib-1", saved[0].ID)
+}
+
+func TestTagService_EditTags_PartialFailure(t *testing.T) {
+	service, writer, _, _ := newTestTagService()
+	defer service.Shutdown()
+	writer.failFor = "/music/bad.mp3"
+
+	tracks := []domain.MusicTrack{
+		{FilePath: "/music/bad.mp3"},
+		{FilePath: "/music/good.mp3"},
+		{FilePath: "/music/tune.xm", IsMOD: true},
+	}
+	title := "Title"
+	updated, err := service.EditTags(tracks, domain.TagEdit{Title: &title})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrTagsNotWritable))
+	var tagErr *domain.TagError
+	assert.True(t, errors.As(err, &tagErr))
+
+	require.Len(t, updated, 1)
+	assert.Equal(t, "/music/good.mp3", updated[0].FilePath)
+	assert.Equal(t, "Title", updated[0].Title)
+}
+
+func TestTagService_EditTags_Validation(t *testing.T) {
+	service, writer, _, _ := newTestTagService()
+	defer service.Shutdown()
+
+	year := 12345
+	_, err := service.EditTags([]domain.MusicTrack{{FilePath: "/music/a.mp3"}}, domain.TagEdit{Year: &year})
+
+	var validationErr *domain.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Empty(t, writer.written)
+}
+
+func TestTagService_EditTags_EmptyEdit(t *testing.T) {
+	service, writer, _, _ := newTestTagService()
+	defer service.Shutdown()
+
+	updated, err := service.EditTags([]domain.MusicTrack{{FilePath: "/music/a.mp3"}}, domain.TagEdit{})
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+	assert.Empty(t, writer.written)
+}
+
+func TestTagService_GuessTags(t *testing.T) {
+	service, writer, _, _ := newTestTagService()
+	defer service.Shutdown()
+
+	tracks := []domain.MusicTrack{
+		{FilePath: "/music/Artist/Album/01 - Song.mp3", Title: "01 - Song.mp3", Artist: "Tagged"},
+		{FilePath: "/music/Artist/Album/02 - Other.mp3", Title: "Other", Artist: "Artist", Album: "Album",
+			Metadata: &domain.TrackMetadata{TrackNumber: 2}},
+		{FilePath: "/music/loose.mp3", Title: "loose.mp3"},
+	}
+	patterns := []string{"%artist%/%album%/%track% - %title%"}
+
+	guesses, err := service.GuessTags(tracks, patterns, true)
+	require.NoError(t, err)
+	require.Len(t, guesses, 1, "unchanged and unmatched tracks are left out")
+	assert.Equal(t, tracks[0].FilePath, guesses[0].Track.FilePath)
+	assert.Equal(t, patterns[0], guesses[0].Pattern)
+	assert.Nil(t, guesses[0].Edit.Artist, "existing tags are kept")
+	assert.Equal(t, "Song", *guesses[0].Edit.Title)
+	assert.Equal(t, "Album", *guesses[0].Edit.Album)
+
+	guesses, err = service.GuessTags(tracks, patterns, false)
+	require.NoError(t, err)
+	require.Len(t, guesses, 1)
+	assert.Equal(t, "Artist", *guesses[0].Edit.Artist)
+
+	assert.Empty(t, writer.written, "guesses are not applied")
+}
+
+func TestTagService_GuessTags_InvalidPattern(t *testing.T) {
+	service, _, _, _ := newTestTagService()
+	defer service.Shutdown()
+
+	var validationErr *domain.ValidationError
+	_, err := service.GuessTags([]domain.MusicTrack{{FilePath: "/music/a.mp3"}}, []string{"%bogus%"}, true)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = service.GuessTags([]domain.MusicTrack{{FilePath: "/music/a.mp3"}}, []string{" "}, true)
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestTagService_ApplyTagGuesses(t *testing.T) {
+	service, writer, library, bus := newTestTagService()
+	defer service.Shutdown()
+
+	mod := domain.MusicTrack{ID: "lib-mod", FilePath: "/mods/Skaven - Goblin.xm", Title: "Skaven - Goblin.xm", IsMOD: true}
+	mp3 := domain.MusicTrack{ID: "lib-mp3", FilePath: "/music/Artist - Song.mp3", Title: "Artist - Song.mp3"}
+	require.NoError(t, library.SaveTracks([]domain.MusicTrack{mod, mp3}))
+
+	published := 0
+	bus.Subscribe(domain.EventTrackUpdated, func(domain.Event) { published++ })
+
+	guesses, err := service.GuessTags([]domain.MusicTrack{mod, mp3}, []string{"%artist% - %title%"}, true)
+	require.NoError(t, err)
+	require.Len(t, guesses, 2)
+
+	updated, err := service.ApplyTagGuesses(guesses)
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	assert.Equal(t, "Goblin", updated[0].Title)
+	assert.Equal(t, "Artist", updated[1].Artist)
+	assert.Equal(t, 2, published)
+
+	// Tracker modules are only updated in the library
+	assert.Len(t, writer.written, 1)
+	assert.Contains(t, writer.written, mp3.FilePath)
+
+	saved, err := library.LoadAll()
+	require.NoError(t, err)
+	titles := make([]string, 0, len(saved))
+	for _, track := range saved {
+		titles = append(titles, track.Title)
+	}
+	assert.ElementsMatch(t, []string{"Goblin", "Song"}, titles)
+}